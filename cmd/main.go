@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"log/slog"
 	"net/http"
@@ -11,10 +12,13 @@ import (
 	"time"
 
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/config"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/email"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/handlers"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/jwt"
 	loggerConstructor "github.com/byoverr/PR-Reviewer-Assignment-Service/internal/logger"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/worker"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -45,16 +49,119 @@ func main() {
 	teamRepo := repository.NewTeamRepo(db)
 	userRepo := repository.NewUserRepo(db)
 	prRepo := repository.NewPRRepo(db)
+	absenceRepo := repository.NewAbsenceRepo(db)
+	repoRepo := repository.NewRepositoryRepo(db)
+	auditRepo := repository.NewAuditRepo(db)
+	txManager := repository.NewTxManager(db)
+	tokenRepo := repository.NewTokenRepo(db)
+	roleRepo := repository.NewRoleRepo(db)
+	accountRepo := repository.NewAccountRepo(db)
+	subscriptionRepo := repository.NewSubscriptionRepo(db)
+	outboxRepo := repository.NewOutboxRepo(db)
+	slackRepo := repository.NewSlackRepo(db)
+
+	authSvc := services.NewAuthService(tokenRepo, logger)
+	accessSvc := services.NewAccessService(roleRepo, userRepo, teamRepo, prRepo, absenceRepo, logger)
+
+	// Management subcommands
+	if len(os.Args) > 1 && (os.Args[1] == "token" || os.Args[1] == "role") {
+		var cmdErr error
+		if os.Args[1] == "token" {
+			cmdErr = runTokenCommand(ctx, authSvc, os.Args[2:], os.Stdout)
+		} else {
+			cmdErr = runRoleCommand(ctx, accessSvc, os.Args[2:], os.Stdout)
+		}
+		if cmdErr != nil {
+			fmt.Fprintln(os.Stderr, cmdErr)
+			db.Close()
+			os.Exit(1)
+		}
+		return
+	}
 
 	// Services
-	teamSvc := services.NewTeamService(teamRepo, userRepo, logger)
-	userSvc := services.NewUserService(userRepo, prRepo, logger)
-	prSvc := services.NewPRService(prRepo, userRepo, logger)
+	selector, err := services.NewReviewerSelector(cfg.ReviewerStrategy, prRepo)
+	if err != nil {
+		logger.Error("failed to create reviewer selector", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	subscriptionSvc := services.NewSubscriptionService(subscriptionRepo, &http.Client{Timeout: cfg.WebhookTimeout},
+		cfg.WebhookAttempts, cfg.WebhookBackoff, logger)
+	slackSvc := services.NewSlackService(slackRepo, userRepo, teamRepo, &http.Client{Timeout: cfg.WebhookTimeout},
+		cfg.SlackBaseURL, logger)
+	// Each sink takes an event once: a retry after another sink failed skips the sinks that took it.
+	sinks := []services.EventSink{subscriptionSvc}
+	var emailSvc *services.EmailService
+	if cfg.SMTPAddr != "" {
+		templates, tmplErr := email.LoadTemplates(cfg.EmailTemplates)
+		if tmplErr != nil {
+			logger.Error("failed to load email templates", slog.String("error", tmplErr.Error()))
+			os.Exit(1)
+		}
+		mailer := email.NewMailer(email.Config{
+			Addr:     cfg.SMTPAddr,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.EmailFrom,
+			Timeout:  cfg.SMTPTimeout,
+		})
+		emailSvc = services.NewEmailService(userRepo, prRepo, mailer, templates, cfg.DigestHour, logger)
+		sinks = append(sinks, emailSvc)
+	}
+	sinks = append(sinks, slackSvc)
+	outboxSvc := services.NewOutboxService(outboxRepo, txManager, cfg.OutboxAttempts, cfg.OutboxBackoff, logger,
+		sinks...)
+	auditSvc := services.NewAuditService(auditRepo, txManager, logger, outboxSvc)
+	teamSvc := services.NewTeamService(teamRepo, userRepo, auditSvc, cfg.DefaultReviewers, logger)
+	userSvc := services.NewUserService(
+		userRepo, prRepo, teamRepo, absenceRepo, repoRepo, auditSvc, selector, cfg.DefaultReviewers, logger)
+	prSvc := services.NewPRService(
+		prRepo, userRepo, teamRepo, repoRepo, auditSvc, selector, cfg.DefaultReviewers, logger)
+	repoSvc := services.NewRepositoryService(repoRepo, cfg.DefaultReviewers, logger)
+	accountSvc := services.NewAccountService(accountRepo, userRepo, auditSvc, logger)
+	webhookSvc := services.NewWebhookService(prSvc, accountSvc, cfg.GitHubSecret, cfg.GitLabToken, logger)
+	if cfg.GitHubSecret == "" {
+		logger.Warn("GITHUB_WEBHOOK_SECRET is not set, GitHub webhooks will be rejected")
+	}
+	if cfg.GitLabToken == "" {
+		logger.Warn("GITLAB_WEBHOOK_TOKEN is not set, GitLab webhooks will be rejected")
+	}
+
+	var oidcSvc *services.OIDCService
+	if cfg.JWKSSource != "" {
+		oidcSvc, err = newOIDCService(ctx, cfg, accessSvc, logger)
+		if err != nil {
+			logger.Error("failed to set up JWT authentication", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+	}
+
+	// Background jobs
+	workerCtx, stopWorkers := context.WithCancel(ctx)
+	defer stopWorkers()
+	go worker.NewAbsenceWorker(userSvc, cfg.AbsenceInterval, logger).Run(workerCtx)
+	go worker.NewOutboxWorker(outboxSvc, cfg.OutboxInterval, logger).Run(workerCtx)
+	go worker.NewDeliveryWorker(subscriptionSvc, cfg.WebhookInterval, logger).Run(workerCtx)
+	if cfg.ReminderAfter > 0 {
+		reminderSvc := services.NewReminderService(prRepo, txManager, outboxSvc, cfg.ReminderAfter, logger)
+		go worker.NewReminderWorker(reminderSvc, cfg.ReminderInterval, logger).Run(workerCtx)
+	}
+	if emailSvc != nil {
+		go worker.NewDigestWorker(emailSvc, cfg.DigestInterval, logger).Run(workerCtx)
+	}
 
 	// Handlers
 	teamHandler := handlers.NewTeamHandler(teamSvc, logger)
 	userHandler := handlers.NewUserHandler(userSvc, logger)
 	prHandler := handlers.NewPRHandler(prSvc, logger)
+	repoHandler := handlers.NewRepositoryHandler(repoSvc, logger)
+	auditHandler := handlers.NewAuditHandler(auditSvc, logger)
+	accountHandler := handlers.NewAccountHandler(accountSvc, logger)
+	webhookHandler := handlers.NewWebhookHandler(webhookSvc, logger)
+	subscriptionHandler := handlers.NewSubscriptionHandler(subscriptionSvc, logger)
+	slackHandler := handlers.NewSlackHandler(slackSvc, logger)
+	authMiddleware := handlers.NewAuthMiddleware(authSvc, accessSvc, oidcSvc, logger)
 
 	// Gin
 	gin.SetMode(gin.ReleaseMode)
@@ -64,7 +171,8 @@ func main() {
 	router.Use(gin.Recovery())
 
 	// Routes
-	handlers.SetupRoutes(router, prHandler, teamHandler, userHandler)
+	handlers.SetupRoutes(router, prHandler, teamHandler, userHandler, repoHandler, auditHandler,
+		accountHandler, webhookHandler, subscriptionHandler, slackHandler, authMiddleware)
 
 	// Server
 	const shutdownTimeout = 5 * time.Second
@@ -91,6 +199,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	logger.Info("shutting down server...")
+	stopWorkers()
 
 	ctxShutdown, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
@@ -102,3 +211,25 @@ func main() {
 	logger.Info("timeout of 5 seconds, server exiting")
 	logger.Info("server exiting")
 }
+
+// jwksTimeout bounds a single JWKS download.
+const jwksTimeout = 10 * time.Second
+
+// newOIDCService loads the JWKS named in cfg and builds the JWT authenticator.
+func newOIDCService(
+	ctx context.Context,
+	cfg *config.Config,
+	accessSvc *services.AccessService,
+	logger *slog.Logger,
+) (*services.OIDCService, error) {
+	groupRoles, err := services.ParseGroupRoles(cfg.JWTGroupRoles)
+	if err != nil {
+		return nil, err
+	}
+	keys := jwt.NewKeySource(cfg.JWKSSource, &http.Client{Timeout: jwksTimeout}, cfg.JWKSRefresh)
+	if err = keys.Load(ctx); err != nil {
+		return nil, err
+	}
+	verifier := jwt.NewVerifier(keys, cfg.JWTIssuer, cfg.JWTAudience)
+	return services.NewOIDCService(verifier, accessSvc, cfg.JWTUserClaim, cfg.JWTGroupsClaim, groupRoles, logger), nil
+}
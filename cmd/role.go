@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+)
+
+const roleUsage = `usage:
+  main role grant <user_id> <admin|team_lead|member|bot> [team_name]
+  main role revoke <user_id> <role> [team_name]
+  main role list [user_id]`
+
+// runRoleCommand manages role bindings: main role grant|revoke|list. Without team_name a role applies to
+// every team.
+func runRoleCommand(ctx context.Context, svc *services.AccessService, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(roleUsage)
+	}
+
+	switch {
+	case args[0] == "grant" && (len(args) == 3 || len(args) == 4):
+		team := optionalArg(args, 3)
+		if _, err := svc.GrantRole(ctx, args[1], args[2], team); err != nil {
+			return fmt.Errorf("grant %s to %q: %w", args[2], args[1], err)
+		}
+		_, _ = fmt.Fprintf(out, "granted %s to %q%s\n", args[2], args[1], scopeSuffix(team))
+		return nil
+
+	case args[0] == "revoke" && (len(args) == 3 || len(args) == 4):
+		team := optionalArg(args, 3)
+		if err := svc.RevokeRole(ctx, args[1], args[2], team); err != nil {
+			return fmt.Errorf("revoke %s from %q: %w", args[2], args[1], err)
+		}
+		_, _ = fmt.Fprintf(out, "revoked %s from %q%s\n", args[2], args[1], scopeSuffix(team))
+		return nil
+
+	case args[0] == "list" && len(args) <= 2:
+		bindings, err := svc.ListRoleBindings(ctx, optionalArg(args, 1))
+		if err != nil {
+			return fmt.Errorf("list roles: %w", err)
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "USER\tROLE\tTEAM")
+		for _, b := range bindings {
+			team := b.TeamName
+			if team == "" {
+				team = "*"
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", b.UserID, b.Role, team)
+		}
+		return w.Flush()
+
+	default:
+		return errors.New(roleUsage)
+	}
+}
+
+func optionalArg(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return ""
+}
+
+func scopeSuffix(team string) string {
+	if team == "" {
+		return ""
+	}
+	return fmt.Sprintf(" in team %q", team)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+)
+
+const tokenUsage = `usage:
+  main token create <name> <user_id>
+  main token list
+  main token revoke <name>`
+
+// runTokenCommand manages API tokens: main token create|list|revoke.
+func runTokenCommand(ctx context.Context, svc *services.AuthService, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(tokenUsage)
+	}
+
+	switch {
+	case args[0] == "create" && len(args) == 3:
+		raw, token, err := svc.CreateToken(ctx, args[1], args[2])
+		if err != nil {
+			return fmt.Errorf("create token %q: %w", args[1], err)
+		}
+		_, _ = fmt.Fprintf(out, "created token %q for %q; it is shown only once:\n%s\n", token.Name, token.UserID, raw)
+		return nil
+
+	case args[0] == "list" && len(args) == 1:
+		tokens, err := svc.ListTokens(ctx)
+		if err != nil {
+			return fmt.Errorf("list tokens: %w", err)
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "NAME\tUSER\tCREATED\tREVOKED")
+		for _, t := range tokens {
+			revoked := "-"
+			if t.RevokedAt != nil {
+				revoked = t.RevokedAt.Format(time.RFC3339)
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", t.Name, t.UserID, t.CreatedAt.Format(time.RFC3339), revoked)
+		}
+		return w.Flush()
+
+	case args[0] == "revoke" && len(args) == 2:
+		if err := svc.RevokeToken(ctx, args[1]); err != nil {
+			return fmt.Errorf("revoke token %q: %w", args[1], err)
+		}
+		_, _ = fmt.Fprintf(out, "revoked token %q\n", args[1])
+		return nil
+
+	default:
+		return errors.New(tokenUsage)
+	}
+}
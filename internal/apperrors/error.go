@@ -1,18 +1,51 @@
 package apperrors
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 var (
-	ErrNotFound     = errors.New("resource not found")           // NOT_FOUND
-	ErrTeamExists   = errors.New("team already exists")          // TEAM_EXISTS
-	ErrPRExists     = errors.New("PR already exists")            // PR_EXISTS
-	ErrPRMerged     = errors.New("cannot modify merged PR")      // PR_MERGED
-	ErrNotAssigned  = errors.New("reviewer not assigned to PR")  // NOT_ASSIGNED
-	ErrNoCandidate  = errors.New("no active candidates in team") // NO_CANDIDATE
-	ErrInvalidInput = errors.New("invalid input")                // INVALID_INPUT
-	ErrInternal     = errors.New("internal error")               // INTERNAL_ERROR
+	ErrNotFound     = errors.New("resource not found")             // NOT_FOUND
+	ErrTeamExists   = errors.New("team already exists")            // TEAM_EXISTS
+	ErrPRExists     = errors.New("PR already exists")              // PR_EXISTS
+	ErrPRMerged     = errors.New("cannot modify merged PR")        // PR_MERGED
+	ErrPRClosed     = errors.New("cannot modify closed PR")        // PR_CLOSED
+	ErrNotAssigned  = errors.New("reviewer not assigned to PR")    // NOT_ASSIGNED
+	ErrNoCandidate  = errors.New("no active candidates in team")   // NO_CANDIDATE
+	ErrMergeBlocked = errors.New("merge blocked by review policy") // MERGE_BLOCKED
+	ErrTokenExists  = errors.New("token already exists")           // TOKEN_EXISTS
+	ErrUnauthorized = errors.New("missing or invalid token")       // UNAUTHORIZED
+	ErrForbidden    = errors.New("permission denied")              // FORBIDDEN
+	ErrRoleExists   = errors.New("role already granted")           // ROLE_EXISTS
+	ErrNoAccount    = errors.New("login not linked to a user")     // NO_ACCOUNT
+	ErrInvalidInput = errors.New("invalid input")                  // INVALID_INPUT
+	ErrInternal     = errors.New("internal error")                 // INTERNAL_ERROR
 )
 
+// MergeBlockedError reports why the merge policy blocks a PR. It matches ErrMergeBlocked.
+type MergeBlockedError struct {
+	RequiredApprovals  int
+	Approvals          int
+	PendingReviewers   []string
+	ChangesRequestedBy []string
+}
+
+func (e *MergeBlockedError) Error() string {
+	msg := ErrMergeBlocked.Error()
+	if len(e.PendingReviewers) > 0 {
+		msg += "; pending: " + strings.Join(e.PendingReviewers, ", ")
+	}
+	if len(e.ChangesRequestedBy) > 0 {
+		msg += "; changes requested by: " + strings.Join(e.ChangesRequestedBy, ", ")
+	}
+	return msg
+}
+
+func (e *MergeBlockedError) Unwrap() error {
+	return ErrMergeBlocked
+}
+
 func Wrap(err error, msg string) error {
 	if err == nil {
 		return nil
@@ -0,0 +1,139 @@
+// Package codeowners parses CODEOWNERS files and matches changed paths against their rules.
+//
+// Patterns follow the GitHub flavour of gitignore syntax: "*" matches within a path segment, "**" across
+// segments, a leading or inner "/" anchors the pattern to the repository root and a trailing "/" matches
+// directory contents only. When several rules match a path, the last one wins.
+package codeowners
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidFile is returned when a CODEOWNERS file contains an unsupported pattern.
+var ErrInvalidFile = errors.New("invalid CODEOWNERS file")
+
+// Rule is a single CODEOWNERS line.
+type Rule struct {
+	Pattern string
+	Owners  []string
+	re      *regexp.Regexp
+}
+
+// File is a parsed CODEOWNERS file.
+type File struct {
+	Rules []Rule
+}
+
+// Parse parses CODEOWNERS content. Blank lines and comments are skipped; a pattern without owners
+// marks matching paths as unowned.
+func Parse(content string) (*File, error) {
+	f := &File{Rules: []Rule{}}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := stripComment(scanner.Text())
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		re, err := compile(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidFile, lineNo, err)
+		}
+		f.Rules = append(f.Rules, Rule{Pattern: fields[0], Owners: fields[1:], re: re})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Join(ErrInvalidFile, err)
+	}
+	return f, nil
+}
+
+// Owners returns the owners of the last rule matching path, or nil when no rule matches.
+func (f *File) Owners(path string) []string {
+	path = strings.TrimPrefix(path, "/")
+	for i := len(f.Rules) - 1; i >= 0; i-- {
+		if f.Rules[i].re.MatchString(path) {
+			return f.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// OwnerSets returns the distinct, non-empty owner lists of the given paths in order of first appearance.
+func (f *File) OwnerSets(paths []string) [][]string {
+	seen := map[string]bool{}
+	sets := [][]string{}
+	for _, p := range paths {
+		owners := f.Owners(p)
+		key := strings.Join(owners, " ")
+		if len(owners) == 0 || seen[key] {
+			continue
+		}
+		seen[key] = true
+		sets = append(sets, owners)
+	}
+	return sets
+}
+
+// stripComment removes a trailing "#" comment; "\#" is a literal hash.
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] != '\\') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// compile converts a CODEOWNERS pattern into an anchored regular expression.
+func compile(pattern string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(pattern, "!") || strings.ContainsAny(pattern, "[]") {
+		return nil, fmt.Errorf("unsupported pattern %q", pattern)
+	}
+	pattern = strings.ReplaceAll(pattern, `\#`, "#")
+
+	dirOnly := strings.HasSuffix(pattern, "/")
+	trimmed := strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(trimmed, "/")
+	trimmed = strings.TrimPrefix(trimmed, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored && trimmed != "" {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case trimmed[i] == '*':
+			b.WriteString("[^/]*")
+		case trimmed[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(trimmed[i : i+1]))
+		}
+	}
+	switch {
+	case trimmed == "":
+		b.WriteString(".*")
+	case dirOnly:
+		b.WriteString("/.*")
+	case strings.HasSuffix(trimmed, "*"):
+		// "docs/*" owns the files directly in docs/, not those in its subdirectories.
+	default:
+		b.WriteString("(?:/.*)?")
+	}
+	b.WriteString("$")
+
+	return regexp.Compile(b.String())
+}
@@ -1,18 +1,50 @@
 package config
 
 import (
+	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	DBURL       string `env:"DB_URL"        env-required:"true" env-description:"PostgreSQL"`
-	Port        string `env:"PORT"                              env-description:"HTTP server port"                   env-default:"8080"`
-	LogLevel    string `env:"LOG_LEVEL"                         env-description:"Logging level"                      env-default:"info"`
-	LogOutput   string `env:"LOG_OUTPUT"                        env-description:"Log output: stdout or file"         env-default:"stdout"`
-	LogFilePath string `env:"LOG_FILE_PATH"                     env-description:"Log file path (if LOG_OUTPUT=file)" env-default:"./app.log"`
+	DBURL            string        `env:"DB_URL"                  env-required:"true" env-description:"PostgreSQL"`
+	Port             string        `env:"PORT"                                        env-description:"HTTP server port"                   env-default:"8080"`
+	LogLevel         string        `env:"LOG_LEVEL"                                   env-description:"Logging level"                      env-default:"info"`
+	LogOutput        string        `env:"LOG_OUTPUT"                                  env-description:"Log output: stdout or file"         env-default:"stdout"`
+	LogFilePath      string        `env:"LOG_FILE_PATH"                               env-description:"Log file path (if LOG_OUTPUT=file)" env-default:"./app.log"`
+	ReviewerStrategy string        `env:"REVIEWER_STRATEGY"                           env-description:"Reviewer selection strategy"        env-default:"random"`
+	DefaultReviewers int           `env:"DEFAULT_REVIEWERS_COUNT"                     env-description:"Default reviewers per PR"           env-default:"2"`
+	AbsenceInterval  time.Duration `env:"ABSENCE_CHECK_INTERVAL"                      env-description:"How often absences are checked"     env-default:"1m"`
+	JWKSSource       string        `env:"JWT_JWKS"                                    env-description:"JWKS file or URL; enables JWT auth"`
+	JWKSRefresh      time.Duration `env:"JWT_JWKS_MIN_REFRESH"                        env-description:"Min interval between JWKS reloads"  env-default:"1m"`
+	JWTIssuer        string        `env:"JWT_ISSUER"                                  env-description:"Expected iss claim of JWTs"`
+	JWTAudience      string        `env:"JWT_AUDIENCE"                                env-description:"Expected aud claim of JWTs"`
+	JWTUserClaim     string        `env:"JWT_USER_CLAIM"                              env-description:"JWT claim naming the user"          env-default:"sub"`
+	JWTGroupsClaim   string        `env:"JWT_GROUPS_CLAIM"                            env-description:"JWT claim listing the groups"       env-default:"groups"`
+	JWTGroupRoles    string        `env:"JWT_GROUP_ROLES"                             env-description:"Groups to roles: g=role[@team],..."`
+	GitHubSecret     string        `env:"GITHUB_WEBHOOK_SECRET"                       env-description:"Secret of GitHub webhook signatures"`
+	GitLabToken      string        `env:"GITLAB_WEBHOOK_TOKEN"                        env-description:"Secret token of GitLab webhooks"`
+	WebhookAttempts  int           `env:"WEBHOOK_MAX_ATTEMPTS"                        env-description:"Attempts per outbound delivery"     env-default:"8"`
+	WebhookBackoff   time.Duration `env:"WEBHOOK_RETRY_BACKOFF"                       env-description:"Wait before the first retry"        env-default:"30s"`
+	WebhookTimeout   time.Duration `env:"WEBHOOK_TIMEOUT"                             env-description:"Timeout of an outbound delivery"    env-default:"10s"`
+	WebhookInterval  time.Duration `env:"WEBHOOK_POLL_INTERVAL"                       env-description:"How often due deliveries are sent"  env-default:"5s"`
+	OutboxInterval   time.Duration `env:"OUTBOX_POLL_INTERVAL"                        env-description:"How often the outbox is dispatched" env-default:"1s"`
+	OutboxBackoff    time.Duration `env:"OUTBOX_RETRY_BACKOFF"                        env-description:"Wait before an event is retried"    env-default:"10s"`
+	OutboxAttempts   int           `env:"OUTBOX_MAX_ATTEMPTS"                         env-description:"Attempts to dispatch an event"      env-default:"10"`
+	SlackBaseURL     string        `env:"SLACK_WEBHOOK_BASE_URL"                      env-description:"Slack incoming webhooks base URL"   env-default:"https://hooks.slack.com"`
+	ReminderAfter    time.Duration `env:"REVIEW_REMINDER_AFTER"                       env-description:"Review age to remind at, 0 = never" env-default:"24h"`
+	ReminderInterval time.Duration `env:"REVIEW_REMINDER_INTERVAL"                    env-description:"How often stale reviews are found"  env-default:"15m"`
+	SMTPAddr         string        `env:"SMTP_ADDR"                                   env-description:"SMTP host:port; enables email"`
+	SMTPUsername     string        `env:"SMTP_USERNAME"                               env-description:"SMTP user, empty = no auth"`
+	SMTPPassword     string        `env:"SMTP_PASSWORD"                               env-description:"SMTP password"`
+	SMTPTimeout      time.Duration `env:"SMTP_TIMEOUT"                                env-description:"Timeout of sending an email"        env-default:"10s"`
+	EmailFrom        string        `env:"EMAIL_FROM"                                  env-description:"Sender address of the emails"`
+	EmailTemplates   string        `env:"EMAIL_TEMPLATES_DIR"                         env-description:"Directory of template overrides"`
+	DigestHour       int           `env:"EMAIL_DIGEST_HOUR"                           env-description:"Hour (UTC) of the daily digest"     env-default:"9"`
+	DigestInterval   time.Duration `env:"EMAIL_DIGEST_INTERVAL"                       env-description:"How often due digests are sent"     env-default:"10m"`
 }
 
 func Load() (*Config, error) {
@@ -27,5 +59,49 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	if cfg.DefaultReviewers < 1 {
+		return nil, errors.New("DEFAULT_REVIEWERS_COUNT must be at least 1")
+	}
+	if cfg.AbsenceInterval <= 0 {
+		return nil, errors.New("ABSENCE_CHECK_INTERVAL must be positive")
+	}
+	if cfg.WebhookInterval <= 0 {
+		return nil, errors.New("WEBHOOK_POLL_INTERVAL must be positive")
+	}
+	if cfg.WebhookAttempts < 1 {
+		return nil, errors.New("WEBHOOK_MAX_ATTEMPTS must be at least 1")
+	}
+	if cfg.OutboxInterval <= 0 {
+		return nil, errors.New("OUTBOX_POLL_INTERVAL must be positive")
+	}
+	if cfg.OutboxAttempts < 1 {
+		return nil, errors.New("OUTBOX_MAX_ATTEMPTS must be at least 1")
+	}
+	if cfg.ReminderAfter < 0 {
+		return nil, errors.New("REVIEW_REMINDER_AFTER must not be negative")
+	}
+	if cfg.ReminderAfter > 0 && cfg.ReminderInterval <= 0 {
+		return nil, errors.New("REVIEW_REMINDER_INTERVAL must be positive")
+	}
+	if cfg.SMTPAddr != "" {
+		if cfg.EmailFrom == "" {
+			return nil, errors.New("EMAIL_FROM is required when SMTP_ADDR is set")
+		}
+		if cfg.DigestHour < 0 || cfg.DigestHour > 23 {
+			return nil, errors.New("EMAIL_DIGEST_HOUR must be between 0 and 23")
+		}
+		if cfg.DigestInterval <= 0 {
+			return nil, errors.New("EMAIL_DIGEST_INTERVAL must be positive")
+		}
+	}
+	if cfg.JWKSSource != "" {
+		if cfg.JWTIssuer == "" || cfg.JWTAudience == "" {
+			return nil, errors.New("JWT_ISSUER and JWT_AUDIENCE are required when JWT_JWKS is set")
+		}
+		if cfg.JWKSRefresh <= 0 {
+			return nil, errors.New("JWT_JWKS_MIN_REFRESH must be positive")
+		}
+	}
+
 	return &cfg, nil
 }
@@ -0,0 +1,128 @@
+// Package email renders notification emails from templates and sends them over SMTP. Messages are plain
+// text, UTF-8 and quoted-printable encoded.
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Config is how the Mailer reaches the SMTP server. Without Username the server is used without
+// authentication. STARTTLS is used whenever the server offers it.
+type Config struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+// Message is an email to send.
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer sends messages through one SMTP server, opening a connection per message.
+type Mailer struct {
+	cfg Config
+}
+
+func NewMailer(cfg Config) *Mailer {
+	return &Mailer{cfg: cfg}
+}
+
+// Send delivers msg to its recipients.
+func (m *Mailer) Send(ctx context.Context, msg Message) error {
+	if len(msg.To) == 0 {
+		return errors.New("no recipients")
+	}
+	host, _, err := net.SplitHostPort(m.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid smtp address: %w", err)
+	}
+
+	dialer := net.Dialer{Timeout: m.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", m.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	if m.cfg.Timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(m.cfg.Timeout))
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if m.cfg.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err = client.Mail(m.cfg.From); err != nil {
+		return err
+	}
+	for _, to := range msg.To {
+		if err = client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(m.compose(msg)); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// compose returns msg with its headers, ready for the DATA command.
+func (m *Mailer) compose(msg Message) []byte {
+	var buf bytes.Buffer
+	header := func(name, value string) {
+		buf.WriteString(name + ": " + value + "\r\n")
+	}
+	header("From", m.cfg.From)
+	header("To", strings.Join(msg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", strings.Join(strings.Fields(msg.Subject), " ")))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	buf.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	_, _ = qp.Write([]byte(msg.Body))
+	_ = qp.Close()
+	return buf.Bytes()
+}
+
+// Permanent reports whether err is a permanent failure reply (5xx) of the SMTP server, such as an unknown
+// recipient, so sending the message again would fail the same way.
+func Permanent(err error) bool {
+	var reply *textproto.Error
+	return errors.As(err, &reply) && reply.Code >= 500
+}
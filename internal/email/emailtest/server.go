@@ -0,0 +1,197 @@
+// Package emailtest provides an in-process SMTP server for tests. It speaks enough SMTP for net/smtp:
+// EHLO, AUTH PLAIN, MAIL, RCPT, DATA, RSET, NOOP and QUIT, without TLS.
+package emailtest
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// Message is an email received by the Server, with its subject and body decoded.
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Body    string
+	Header  mail.Header
+}
+
+// Server is an SMTP server on a loopback port that keeps the messages it receives.
+type Server struct {
+	Addr string
+
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	messages []Message
+	rejected map[string]int
+}
+
+// NewServer starts a Server. It panics when no port can be listened on, like httptest.NewServer.
+func NewServer() *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("emailtest: failed to listen: %v", err))
+	}
+	s := &Server{Addr: listener.Addr().String(), listener: listener, rejected: map[string]int{}}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Close stops the server and waits for its connections to end.
+func (s *Server) Close() {
+	_ = s.listener.Close()
+	s.wg.Wait()
+}
+
+// Messages returns the messages received so far.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// Reject makes the server answer RCPT TO addr with code, such as 550 for an unknown mailbox or 451 for a
+// temporary failure.
+func (s *Server) Reject(addr string, code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rejected[strings.ToLower(addr)] = code
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			s.session(textproto.NewConn(conn))
+		}()
+	}
+}
+
+func (s *Server) session(conn *textproto.Conn) {
+	reply := func(code int, text string) bool {
+		return conn.PrintfLine("%d %s", code, text) == nil
+	}
+	if !reply(220, "emailtest ready") {
+		return
+	}
+
+	var from string
+	var to []string
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		ok := true
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			ok = conn.PrintfLine("250-emailtest") == nil && reply(250, "AUTH PLAIN")
+		case "HELO", "NOOP":
+			ok = reply(250, "OK")
+		case "AUTH":
+			ok = reply(235, "authenticated")
+		case "MAIL":
+			from, to = address(arg), nil
+			ok = reply(250, "OK")
+		case "RCPT":
+			addr := address(arg)
+			s.mu.Lock()
+			code := s.rejected[strings.ToLower(addr)]
+			s.mu.Unlock()
+			if code != 0 {
+				ok = reply(code, "rejected")
+				break
+			}
+			to = append(to, addr)
+			ok = reply(250, "OK")
+		case "DATA":
+			if from == "" || len(to) == 0 {
+				ok = reply(503, "need MAIL and RCPT first")
+				break
+			}
+			if !reply(354, "go ahead") {
+				return
+			}
+			data, err := conn.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg, err := parse(data)
+			if err != nil {
+				ok = reply(554, err.Error())
+				break
+			}
+			msg.From, msg.To = from, to
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			from, to = "", nil
+			ok = reply(250, "OK")
+		case "RSET":
+			from, to = "", nil
+			ok = reply(250, "OK")
+		case "QUIT":
+			reply(221, "bye")
+			return
+		default:
+			ok = reply(502, "not implemented")
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+// address returns the address of a "FROM:<addr>" or "TO:<addr>" argument.
+func address(arg string) string {
+	start, end := strings.IndexByte(arg, '<'), strings.IndexByte(arg, '>')
+	if start < 0 || end < start {
+		return ""
+	}
+	return arg[start+1 : end]
+}
+
+func parse(data []byte) (Message, error) {
+	m, err := mail.ReadMessage(bufio.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		return Message{}, err
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if err != nil {
+		return Message{}, err
+	}
+	body := m.Body
+	if strings.EqualFold(m.Header.Get("Content-Transfer-Encoding"), "quoted-printable") {
+		body = quotedprintable.NewReader(body)
+	}
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return Message{}, errors.New("invalid body")
+	}
+	return Message{
+		Subject: subject,
+		Body:    strings.ReplaceAll(string(raw), "\r\n", "\n"),
+		Header:  m.Header,
+	}, nil
+}
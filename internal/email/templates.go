@@ -0,0 +1,110 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+)
+
+// Names of the templates. Each template defines a "subject" and a "body".
+const (
+	TemplateReviewerAssigned   = "reviewer_assigned"
+	TemplateReviewerReassigned = "reviewer_reassigned"
+	TemplateReviewStale        = "review_stale"
+	TemplateDigest             = "digest"
+)
+
+var templateNames = []string{
+	TemplateReviewerAssigned, TemplateReviewerReassigned, TemplateReviewStale, TemplateDigest,
+}
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// EventData is what the templates of events are executed with.
+type EventData struct {
+	Recipient   models.User
+	Event       models.Event
+	PullRequest models.PullRequest
+}
+
+// DigestData is what the digest template is executed with.
+type DigestData struct {
+	Recipient    models.User
+	Date         time.Time
+	PullRequests []models.PullRequestShort
+}
+
+// Templates renders the subject and body of the emails.
+type Templates struct {
+	byName map[string]*template.Template
+}
+
+// LoadTemplates parses the built-in templates, replacing those for which dir holds a <name>.tmpl file.
+// An empty dir uses the built-in templates only.
+func LoadTemplates(dir string) (*Templates, error) {
+	t := &Templates{byName: make(map[string]*template.Template, len(templateNames))}
+	for _, name := range templateNames {
+		file := name + ".tmpl"
+		src, err := defaultTemplates.ReadFile("templates/" + file)
+		if err != nil {
+			return nil, err
+		}
+		if dir != "" {
+			override, err := os.ReadFile(filepath.Join(dir, file))
+			switch {
+			case err == nil:
+				src = override
+			case !errors.Is(err, os.ErrNotExist):
+				return nil, err
+			}
+		}
+
+		tmpl, err := template.New(name).Option("missingkey=error").Funcs(template.FuncMap{"ref": ref}).
+			Parse(string(src))
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", name, err)
+		}
+		for _, part := range []string{"subject", "body"} {
+			if tmpl.Lookup(part) == nil {
+				return nil, fmt.Errorf("template %s does not define %q", name, part)
+			}
+		}
+		t.byName[name] = tmpl
+	}
+	return t, nil
+}
+
+// Render executes the template name with data.
+func (t *Templates) Render(name string, data any) (subject, body string, err error) {
+	tmpl, ok := t.byName[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown template %s", name)
+	}
+	var buf bytes.Buffer
+	if err = tmpl.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return "", "", err
+	}
+	subject = buf.String()
+	buf.Reset()
+	if err = tmpl.ExecuteTemplate(&buf, "body", data); err != nil {
+		return "", "", err
+	}
+	return subject, strings.TrimLeft(buf.String(), "\n"), nil
+}
+
+// ref names a PR the way its repository does, falling back to its ID.
+func ref(repository, id string) string {
+	if repository == "" {
+		return id
+	}
+	return repository + "#" + id
+}
@@ -0,0 +1,113 @@
+// Package github verifies and decodes GitHub webhook deliveries: the X-Hub-Signature-256 HMAC and the
+// parts of the pull_request event payload needed to mirror pull requests.
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Headers GitHub sets on webhook deliveries.
+const (
+	EventHeader     = "X-GitHub-Event"
+	DeliveryHeader  = "X-GitHub-Delivery"
+	SignatureHeader = "X-Hub-Signature-256"
+)
+
+// Event types.
+const (
+	EventPing        = "ping"
+	EventPullRequest = "pull_request"
+)
+
+// Pull request event actions the service acts on. GitHub reports merges as closed with merged set.
+const (
+	ActionOpened         = "opened"
+	ActionReadyForReview = "ready_for_review"
+	ActionClosed         = "closed"
+	ActionReopened       = "reopened"
+)
+
+var (
+	// ErrInvalidSignature is returned when a delivery is not signed with the shared secret.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrInvalidPayload is returned when a delivery body is not a pull_request event.
+	ErrInvalidPayload = errors.New("invalid webhook payload")
+)
+
+// VerifySignature checks header, formatted as "sha256=<hex HMAC-SHA256 of body>", against secret.
+// An empty secret accepts nothing.
+func VerifySignature(secret, body []byte, header string) error {
+	if len(secret) == 0 {
+		return fmt.Errorf("%w: no secret configured", ErrInvalidSignature)
+	}
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return fmt.Errorf("%w: missing sha256 signature", ErrInvalidSignature)
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	if !hmac.Equal(got, Sign(secret, body)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Sign returns the HMAC-SHA256 of body under secret.
+func Sign(secret, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// User is a GitHub account.
+type User struct {
+	Login string `json:"login"`
+}
+
+// PullRequest is the pull_request object of the event.
+type PullRequest struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Draft  bool   `json:"draft"`
+	Merged bool   `json:"merged"`
+	User   User   `json:"user"`
+}
+
+// Repository is the repository the event happened in.
+type Repository struct {
+	FullName string `json:"full_name"`
+}
+
+// PullRequestEvent is the payload of a pull_request event.
+type PullRequestEvent struct {
+	Action      string      `json:"action"`
+	PullRequest PullRequest `json:"pull_request"`
+	Repository  Repository  `json:"repository"`
+	Sender      User        `json:"sender"`
+}
+
+// ID returns the pull request number as the service's PR ID. Numbers are unique within a repository.
+func (e *PullRequestEvent) ID() string {
+	return strconv.Itoa(e.PullRequest.Number)
+}
+
+// ParsePullRequestEvent decodes a pull_request event payload.
+func ParsePullRequestEvent(body []byte) (*PullRequestEvent, error) {
+	var event PullRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+	if event.Action == "" || event.PullRequest.Number <= 0 || event.Repository.FullName == "" {
+		return nil, fmt.Errorf("%w: missing action, pull request number or repository", ErrInvalidPayload)
+	}
+	return &event, nil
+}
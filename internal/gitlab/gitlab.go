@@ -0,0 +1,154 @@
+// Package gitlab verifies and decodes GitLab webhook deliveries: the X-Gitlab-Token secret and the parts
+// of the Merge Request Hook payload needed to mirror merge requests.
+package gitlab
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Headers GitLab sets on webhook deliveries.
+const (
+	EventHeader     = "X-Gitlab-Event"
+	EventUUIDHeader = "X-Gitlab-Event-UUID"
+	TokenHeader     = "X-Gitlab-Token"
+)
+
+// EventMergeRequest is the X-Gitlab-Event value of merge request events.
+const EventMergeRequest = "Merge Request Hook"
+
+// Merge request event actions the service acts on.
+const (
+	ActionOpen   = "open"
+	ActionUpdate = "update"
+	ActionMerge  = "merge"
+	ActionClose  = "close"
+	ActionReopen = "reopen"
+)
+
+var (
+	// ErrInvalidToken is returned when a delivery does not carry the shared secret token.
+	ErrInvalidToken = errors.New("invalid webhook token")
+	// ErrInvalidPayload is returned when a delivery body is not a merge request event.
+	ErrInvalidPayload = errors.New("invalid webhook payload")
+)
+
+// VerifyToken checks the X-Gitlab-Token header against secret in constant time. An empty secret accepts
+// nothing.
+func VerifyToken(secret []byte, header string) error {
+	if len(secret) == 0 {
+		return fmt.Errorf("%w: no secret configured", ErrInvalidToken)
+	}
+	if subtle.ConstantTimeCompare(secret, []byte(header)) != 1 {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// User is a GitLab account.
+type User struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
+
+// Project is the project the event happened in.
+type Project struct {
+	PathWithNamespace string `json:"path_with_namespace"`
+}
+
+// MergeRequest is the object_attributes of the event.
+type MergeRequest struct {
+	IID      int    `json:"iid"`
+	AuthorID int    `json:"author_id"`
+	Title    string `json:"title"`
+	State    string `json:"state"`
+	Action   string `json:"action"`
+	Draft    bool   `json:"draft"`
+	// WorkInProgress is the pre-15.0 name of Draft; GitLab still sends both.
+	WorkInProgress bool `json:"work_in_progress"`
+}
+
+// BoolChange is a changed boolean attribute of an update event.
+type BoolChange struct {
+	Previous bool `json:"previous"`
+	Current  bool `json:"current"`
+}
+
+// Changes lists the attributes an update event changed. Only the ones the service mirrors are decoded.
+type Changes struct {
+	Draft *BoolChange `json:"draft"`
+}
+
+// MergeRequestEvent is the payload of a Merge Request Hook. User is who triggered the event, which need
+// not be the merge request's author; see Author.
+type MergeRequestEvent struct {
+	ObjectKind       string       `json:"object_kind"`
+	User             User         `json:"user"`
+	Project          Project      `json:"project"`
+	ObjectAttributes MergeRequest `json:"object_attributes"`
+	Changes          Changes      `json:"changes"`
+	Assignees        []User       `json:"assignees"`
+	Reviewers        []User       `json:"reviewers"`
+}
+
+// ID returns the merge request IID as the service's PR ID. IIDs are unique within a project.
+func (e *MergeRequestEvent) ID() string {
+	return strconv.Itoa(e.ObjectAttributes.IID)
+}
+
+// Author returns the username of the merge request's author. The payload names the author only by ID, so
+// the username comes from the users the payload describes: the one who triggered the event, the assignees
+// and the reviewers. It reports false when none of them is the author.
+func (e *MergeRequestEvent) Author() (string, bool) {
+	users := append([]User{e.User}, e.Assignees...)
+	users = append(users, e.Reviewers...)
+	for _, u := range users {
+		if u.ID != 0 && u.ID == e.ObjectAttributes.AuthorID {
+			return u.Username, true
+		}
+	}
+	return "", false
+}
+
+// Draft reports whether the merge request is a draft.
+func (e *MergeRequestEvent) Draft() bool {
+	return e.ObjectAttributes.Draft || e.ObjectAttributes.WorkInProgress
+}
+
+// draftPrefixes are the title prefixes GitLab reads as marking a draft.
+var draftPrefixes = []string{"draft:", "[draft]", "(draft)"}
+
+// Title returns the merge request title without its draft prefix; whether it is a draft is in Draft.
+func (e *MergeRequestEvent) Title() string {
+	title := strings.TrimSpace(e.ObjectAttributes.Title)
+	for _, prefix := range draftPrefixes {
+		if len(title) >= len(prefix) && strings.EqualFold(title[:len(prefix)], prefix) {
+			return strings.TrimSpace(title[len(prefix):])
+		}
+	}
+	return title
+}
+
+// MarkedReady reports whether an update event took the merge request out of draft.
+func (e *MergeRequestEvent) MarkedReady() bool {
+	return e.Changes.Draft != nil && e.Changes.Draft.Previous && !e.Changes.Draft.Current
+}
+
+// ParseMergeRequestEvent decodes a Merge Request Hook payload.
+func ParseMergeRequestEvent(body []byte) (*MergeRequestEvent, error) {
+	var event MergeRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+	if event.ObjectKind != "merge_request" {
+		return nil, fmt.Errorf("%w: object_kind %q", ErrInvalidPayload, event.ObjectKind)
+	}
+	if event.ObjectAttributes.IID <= 0 || event.Project.PathWithNamespace == "" {
+		return nil, fmt.Errorf("%w: missing merge request iid or project", ErrInvalidPayload)
+	}
+	return &event, nil
+}
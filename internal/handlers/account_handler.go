@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountHandler struct {
+	svc *services.AccountService
+	log *slog.Logger
+}
+
+func NewAccountHandler(svc *services.AccountService, log *slog.Logger) *AccountHandler {
+	return &AccountHandler{svc: svc, log: log}
+}
+
+// accountRequest is the body of POST /users/linkAccount and POST /users/unlinkAccount.
+type accountRequest struct {
+	UserID   string `json:"user_id"  binding:"required"`
+	Provider string `json:"provider" binding:"required"`
+	Login    string `json:"login"    binding:"required"`
+}
+
+// LinkAccount handles POST /users/linkAccount.
+func (h *AccountHandler) LinkAccount(c *gin.Context) {
+	var req accountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("invalid link account request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	account, err := h.svc.LinkAccount(c.Request.Context(), req.Provider, req.Login, req.UserID)
+	if err != nil {
+		h.log.Error("link account failed",
+			slog.String("provider", req.Provider),
+			slog.String("login", req.Login),
+			slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"account": account})
+}
+
+// UnlinkAccount handles POST /users/unlinkAccount.
+func (h *AccountHandler) UnlinkAccount(c *gin.Context) {
+	var req accountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("invalid unlink account request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	if err := h.svc.UnlinkAccount(c.Request.Context(), req.Provider, req.Login, req.UserID); err != nil {
+		h.log.Error("unlink account failed",
+			slog.String("provider", req.Provider),
+			slog.String("login", req.Login),
+			slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "account unlinked successfully"})
+}
+
+// GetAccounts handles GET /users/getAccounts?user_id=...
+func (h *AccountHandler) GetAccounts(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		h.log.Warn("missing user_id query param")
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	accounts, err := h.svc.ListAccounts(c.Request.Context(), userID)
+	if err != nil {
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user_id": userID, "accounts": accounts})
+}
+
+func (h *AccountHandler) mapErrorToResponse(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	code := ErrorCodeInternalError
+	msg := ErrorMessageInternalError
+
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound):
+		status = http.StatusNotFound
+		code = ErrorCodeNotFound
+		msg = ErrorMessageNotFound
+	case errors.Is(err, apperrors.ErrInvalidInput):
+		status = http.StatusBadRequest
+		code = ErrorCodeInvalidInput
+		msg = ErrorMessageInvalidInput
+	default:
+		h.log.Error("unexpected error", slog.String("error", err.Error()))
+	}
+
+	c.JSON(status, gin.H{"error": gin.H{"code": code, "message": msg}})
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	svc *services.AuditService
+	log *slog.Logger
+}
+
+func NewAuditHandler(svc *services.AuditService, log *slog.Logger) *AuditHandler {
+	return &AuditHandler{svc: svc, log: log}
+}
+
+// ListEvents handles GET /audit. All query params are optional: actor, action, entity_type, entity_id,
+// request_id, from and to (RFC 3339), limit and offset.
+func (h *AuditHandler) ListEvents(c *gin.Context) {
+	filter := models.AuditFilter{
+		Actor:      c.Query("actor"),
+		Action:     c.Query("action"),
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+		RequestID:  c.Query("request_id"),
+	}
+
+	var err error
+	if filter.From, err = queryTime(c, "from"); err != nil {
+		h.log.Warn("invalid from query param", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+	if filter.To, err = queryTime(c, "to"); err != nil {
+		h.log.Warn("invalid to query param", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		h.log.Warn("invalid limit query param", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+	if filter.Offset, err = queryInt(c, "offset"); err != nil {
+		h.log.Warn("invalid offset query param", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	page, err := h.svc.ListEvents(c.Request.Context(), filter)
+	if err != nil {
+		h.log.Error("list audit events failed", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// queryTime parses an optional RFC 3339 query param.
+func queryTime(c *gin.Context, key string) (*time.Time, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil //nolint:nilnil // an absent param is not an error
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// queryInt parses an optional integer query param, returning 0 when it is absent.
+func queryInt(c *gin.Context, key string) (int, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(raw)
+}
+
+func (h *AuditHandler) mapErrorToResponse(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	code := ErrorCodeInternalError
+	msg := ErrorMessageInternalError
+
+	switch {
+	case errors.Is(err, apperrors.ErrInvalidInput):
+		status = http.StatusBadRequest
+		code = ErrorCodeInvalidInput
+		msg = ErrorMessageInvalidInput
+	default:
+		h.log.Error("unexpected error", slog.String("error", err.Error()))
+	}
+
+	c.JSON(status, gin.H{"error": gin.H{"code": code, "message": msg}})
+}
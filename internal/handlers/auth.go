@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/jwt"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/reqctx"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// principalKey is the gin context key of the authenticated *models.Principal.
+const principalKey = "principal"
+
+// AuthMiddleware authenticates bearer tokens and checks the caller's permissions before the handlers run.
+// oidc is nil when JWT authentication is not configured.
+type AuthMiddleware struct {
+	auth   *services.AuthService
+	access *services.AccessService
+	oidc   *services.OIDCService
+	log    *slog.Logger
+}
+
+func NewAuthMiddleware(
+	auth *services.AuthService,
+	access *services.AccessService,
+	oidc *services.OIDCService,
+	log *slog.Logger,
+) *AuthMiddleware {
+	return &AuthMiddleware{auth: auth, access: access, oidc: oidc, log: log}
+}
+
+// Authenticate rejects requests without a valid "Authorization: Bearer <token>" header and loads the
+// principal the token acts as. The token is either a service token or, when configured, an SSO JWT.
+// Every change the request makes is attributed to the principal, whatever ActorHeader says.
+func (m *AuthMiddleware) Authenticate(c *gin.Context) {
+	raw, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok {
+		raw = ""
+	}
+	raw = strings.TrimSpace(raw)
+
+	var principal *models.Principal
+	var err error
+	if m.oidc != nil && jwt.LooksLikeJWT(raw) {
+		principal, err = m.oidc.Principal(c.Request.Context(), raw)
+	} else {
+		principal, err = m.tokenPrincipal(c, raw)
+	}
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUnauthorized) {
+			m.log.Warn("unauthenticated request", slog.String("path", c.FullPath()))
+		}
+		m.abort(c, err)
+		return
+	}
+
+	c.Set(principalKey, principal)
+	c.Request = c.Request.WithContext(reqctx.WithActor(c.Request.Context(), principal.UserID))
+	c.Next()
+}
+
+func (m *AuthMiddleware) tokenPrincipal(c *gin.Context, raw string) (*models.Principal, error) {
+	token, err := m.auth.Authenticate(c.Request.Context(), raw)
+	if err != nil {
+		return nil, err
+	}
+	return m.access.Principal(c.Request.Context(), token.UserID)
+}
+
+// Require lets the request through only when the principal holds perm for the team that owns what the
+// request acts on. It must run after Authenticate.
+func (m *AuthMiddleware) Require(perm string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, _ := c.Value(principalKey).(*models.Principal)
+		if principal == nil {
+			m.abort(c, apperrors.ErrUnauthorized)
+			return
+		}
+
+		var target models.AccessTarget
+		if perm != models.PermRead {
+			target = requestTarget(c)
+		}
+		if err := m.access.Authorize(c.Request.Context(), principal, perm, target); err != nil {
+			m.abort(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+// targetBody holds every body field that names what a request acts on.
+type targetBody struct {
+	TeamName      string `json:"team_name"`
+	UserID        string `json:"user_id"`
+	AuthorID      string `json:"author_id"`
+	Repository    string `json:"repository"`
+	PullRequestID string `json:"pull_request_id"`
+	AbsenceID     int64  `json:"absence_id"`
+	Force         bool   `json:"force"`
+	Member        struct {
+		UserID string `json:"user_id"`
+	} `json:"member"`
+}
+
+func teamTarget(b targetBody) models.AccessTarget {
+	return models.AccessTarget{TeamName: b.TeamName}
+}
+
+func userTarget(b targetBody) models.AccessTarget {
+	return models.AccessTarget{UserID: b.UserID}
+}
+
+func prTarget(b targetBody) models.AccessTarget {
+	return models.AccessTarget{Repository: b.Repository, PullRequestID: b.PullRequestID}
+}
+
+// routeTargets maps each team-scoped route to the fields its handler binds, so that the permission is
+// checked against exactly what the handler will change. Routes missing here act on nothing team-scoped,
+// which only global bindings match.
+var routeTargets = map[string]func(targetBody) models.AccessTarget{
+	"/team/add-member": func(b targetBody) models.AccessTarget {
+		return models.AccessTarget{TeamName: b.TeamName, UserID: b.Member.UserID}
+	},
+	"/team/set-settings":       teamTarget,
+	"/team/set-fallbacks":      teamTarget,
+	"/team/set-slack-channel":  teamTarget,
+	"/users/deactivateByTeam":  teamTarget,
+	"/users/setIsActive":       userTarget,
+	"/users/setMaxOpenReviews": userTarget,
+	"/users/setEmail":          userTarget,
+	"/users/addAbsence":        userTarget,
+	"/users/linkAccount":       userTarget,
+	"/users/unlinkAccount":     userTarget,
+	"/users/setSlackHandle":    userTarget,
+	"/users/deleteAbsence": func(b targetBody) models.AccessTarget {
+		return models.AccessTarget{AbsenceID: b.AbsenceID}
+	},
+	"/users/importAbsences": func(b targetBody) models.AccessTarget {
+		return models.AccessTarget{TeamName: b.TeamName, UserID: b.UserID}
+	},
+	"/pullRequest/create": func(b targetBody) models.AccessTarget {
+		return models.AccessTarget{Repository: b.Repository, PullRequestID: b.PullRequestID, UserID: b.AuthorID}
+	},
+	"/pullRequest/review":   prTarget,
+	"/pullRequest/ready":    prTarget,
+	"/pullRequest/merge":    prTarget,
+	"/pullRequest/close":    prTarget,
+	"/pullRequest/reopen":   prTarget,
+	"/pullRequest/reassign": prTarget,
+}
+
+// RequireForced checks perm like Require, but only for requests whose body sets "force": true. It must
+// run after Authenticate.
+func (m *AuthMiddleware) RequireForced(perm string) gin.HandlerFunc {
+	require := m.Require(perm)
+	return func(c *gin.Context) {
+		if body, ok := readTargetBody(c); ok && !body.Force {
+			c.Next()
+			return
+		}
+		require(c)
+	}
+}
+
+// requestTarget reads the fields the route's handler binds from the request body. A body that does not
+// parse yields an empty target, which only global bindings match; the handler then rejects the body itself.
+func requestTarget(c *gin.Context) models.AccessTarget {
+	fields, ok := routeTargets[c.FullPath()]
+	if !ok {
+		return models.AccessTarget{}
+	}
+	body, ok := readTargetBody(c)
+	if !ok {
+		return models.AccessTarget{}
+	}
+	return fields(body)
+}
+
+// readTargetBody parses the JSON or multipart form body and leaves it in place for the handler.
+func readTargetBody(c *gin.Context) (targetBody, bool) {
+	var body targetBody
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		body.TeamName, body.UserID = c.PostForm("team_name"), c.PostForm("user_id")
+		return body, true
+	}
+
+	data, err := c.GetRawData()
+	if err != nil {
+		return body, false
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+	return body, json.Unmarshal(data, &body) == nil
+}
+
+func (m *AuthMiddleware) abort(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	code := ErrorCodeInternalError
+	msg := ErrorMessageInternalError
+
+	switch {
+	case errors.Is(err, apperrors.ErrUnauthorized):
+		status = http.StatusUnauthorized
+		code = ErrorCodeUnauthorized
+		msg = err.Error()
+		c.Header("WWW-Authenticate", "Bearer")
+	case errors.Is(err, apperrors.ErrForbidden):
+		status = http.StatusForbidden
+		code = ErrorCodeForbidden
+		msg = err.Error()
+	default:
+		m.log.Error("unexpected error", slog.String("error", err.Error()))
+	}
+
+	c.AbortWithStatusJSON(status, gin.H{"error": gin.H{"code": code, "message": msg}})
+}
@@ -7,6 +7,10 @@ const (
 	ErrorCodeNotFound = "NOT_FOUND"
 	// ErrorCodeInvalidInput is the error code for invalid input errors.
 	ErrorCodeInvalidInput = "INVALID_INPUT"
+	// ErrorCodeUnauthorized is the error code for requests without a valid token.
+	ErrorCodeUnauthorized = "UNAUTHORIZED"
+	// ErrorCodeForbidden is the error code for requests the caller has no permission for.
+	ErrorCodeForbidden = "FORBIDDEN"
 	// ErrorMessageInternalError is the error message for internal server errors.
 	ErrorMessageInternalError = "Internal server error"
 	// ErrorMessageNotFound is the error message for not found errors.
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/reqctx"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the ID that ties a request to its log lines and audit events.
+const RequestIDHeader = "X-Request-ID"
+
+// ActorHeader names the person on whose behalf a caller, such as a bot, makes a request. It is recorded
+// next to the actor for information only: changes are always attributed to the authenticated caller.
+const ActorHeader = "X-Actor"
+
+// anonymousActor is recorded for API calls made without authentication.
+const anonymousActor = "anonymous"
+
+// ActorMiddleware attributes the request to anonymousActor until Authenticate names the caller, and
+// stores ActorHeader in the request context as the on-behalf-of value.
+func ActorMiddleware(c *gin.Context) {
+	ctx := reqctx.WithActor(c.Request.Context(), anonymousActor)
+	if onBehalfOf := c.GetHeader(ActorHeader); onBehalfOf != "" {
+		ctx = reqctx.WithOnBehalfOf(ctx, onBehalfOf)
+	}
+	c.Request = c.Request.WithContext(ctx)
+	c.Next()
+}
+
+// RequestIDMiddleware takes the request ID from RequestIDHeader or generates one, echoes it back in the
+// response and stores it in the request context.
+func RequestIDMiddleware(c *gin.Context) {
+	id := c.GetHeader(RequestIDHeader)
+	if id == "" {
+		id = newRequestID()
+	}
+	c.Header(RequestIDHeader, id)
+	c.Request = c.Request.WithContext(reqctx.WithRequestID(c.Request.Context(), id))
+	c.Next()
+}
+
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
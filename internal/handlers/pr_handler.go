@@ -40,10 +40,104 @@ func (h *PRHandler) CreatePR(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"pr": pr})
 }
 
+// ReadyPR handles POST /pullRequest/ready.
+func (h *PRHandler) ReadyPR(c *gin.Context) {
+	var req struct {
+		PullRequestID string   `json:"pull_request_id" binding:"required"`
+		Repository    string   `json:"repository"`
+		ChangedFiles  []string `json:"changed_files"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("invalid ready PR request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	pr, err := h.svc.ReadyPR(c.Request.Context(), req.Repository, req.PullRequestID, req.ChangedFiles)
+	if err != nil {
+		h.log.Error("ready PR failed", slog.String("pr_id", req.PullRequestID), slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": pr})
+}
+
+// GetPR handles GET /pullRequest/get?pull_request_id=...&repository=... (repository is optional).
+func (h *PRHandler) GetPR(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		h.log.Warn("missing pull_request_id query param")
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	pr, err := h.svc.GetPR(c.Request.Context(), c.Query("repository"), prID)
+	if err != nil {
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": pr})
+}
+
+// GetPRHistory handles GET /pullRequest/history?pull_request_id=...&repository=... (repository is optional).
+func (h *PRHandler) GetPRHistory(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		h.log.Warn("missing pull_request_id query param")
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	repository := c.Query("repository")
+	history, err := h.svc.GetPRHistory(c.Request.Context(), repository, prID)
+	if err != nil {
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pull_request_id": prID,
+		"repository":      repository,
+		"history":         history,
+	})
+}
+
+// SubmitReview handles POST /pullRequest/review.
+func (h *PRHandler) SubmitReview(c *gin.Context) {
+	var req struct {
+		PullRequestID string `json:"pull_request_id" binding:"required"`
+		ReviewerID    string `json:"reviewer_id"     binding:"required"`
+		State         string `json:"state"           binding:"required"`
+		Repository    string `json:"repository"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("invalid review request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	pr, err := h.svc.SubmitReview(c.Request.Context(), req.Repository, req.PullRequestID, req.ReviewerID, req.State)
+	if err != nil {
+		h.log.Error("submit review failed",
+			slog.String("pr_id", req.PullRequestID),
+			slog.String("reviewer_id", req.ReviewerID),
+			slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": pr})
+}
+
 // MergePR handles POST /pullRequest/merge.
 func (h *PRHandler) MergePR(c *gin.Context) {
 	var req struct {
 		PullRequestID string `json:"pull_request_id" binding:"required"`
+		Repository    string `json:"repository"`
+		Force         bool   `json:"force"`
+		Reason        string `json:"reason"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.log.Warn("invalid merge PR request", slog.String("error", err.Error()))
@@ -51,7 +145,7 @@ func (h *PRHandler) MergePR(c *gin.Context) {
 		return
 	}
 
-	pr, err := h.svc.MergePR(c.Request.Context(), req.PullRequestID)
+	pr, err := h.svc.MergePR(c.Request.Context(), req.Repository, req.PullRequestID, req.Force, req.Reason)
 	if err != nil {
 		h.log.Error("merge PR failed", slog.String("pr_id", req.PullRequestID), slog.String("error", err.Error()))
 		h.mapErrorToResponse(c, err)
@@ -61,11 +155,56 @@ func (h *PRHandler) MergePR(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"pr": pr})
 }
 
+// ClosePR handles POST /pullRequest/close.
+func (h *PRHandler) ClosePR(c *gin.Context) {
+	var req struct {
+		PullRequestID string `json:"pull_request_id" binding:"required"`
+		Repository    string `json:"repository"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("invalid close PR request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	pr, err := h.svc.ClosePR(c.Request.Context(), req.Repository, req.PullRequestID)
+	if err != nil {
+		h.log.Error("close PR failed", slog.String("pr_id", req.PullRequestID), slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": pr})
+}
+
+// ReopenPR handles POST /pullRequest/reopen.
+func (h *PRHandler) ReopenPR(c *gin.Context) {
+	var req struct {
+		PullRequestID string `json:"pull_request_id" binding:"required"`
+		Repository    string `json:"repository"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("invalid reopen PR request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	pr, err := h.svc.ReopenPR(c.Request.Context(), req.Repository, req.PullRequestID)
+	if err != nil {
+		h.log.Error("reopen PR failed", slog.String("pr_id", req.PullRequestID), slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": pr})
+}
+
 // ReassignReviewer handles POST /pullRequest/reassign.
 func (h *PRHandler) ReassignReviewer(c *gin.Context) {
 	var req struct {
 		PullRequestID string `json:"pull_request_id" binding:"required"`
 		OldReviewerID string `json:"old_reviewer_id" binding:"required"`
+		Repository    string `json:"repository"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.log.Warn("invalid reassign request", slog.String("error", err.Error()))
@@ -73,7 +212,8 @@ func (h *PRHandler) ReassignReviewer(c *gin.Context) {
 		return
 	}
 
-	pr, newReviewer, err := h.svc.ReassignReviewer(c.Request.Context(), req.PullRequestID, req.OldReviewerID)
+	pr, newReviewer, err := h.svc.ReassignReviewer(
+		c.Request.Context(), req.Repository, req.PullRequestID, req.OldReviewerID)
 	if err != nil {
 		h.log.Error("reassign failed",
 			slog.String("pr_id", req.PullRequestID),
@@ -88,7 +228,7 @@ func (h *PRHandler) ReassignReviewer(c *gin.Context) {
 
 // GetTotalPRs handles GET /stats/total-prs.
 func (h *PRHandler) GetTotalPRs(c *gin.Context) {
-	total, err := h.svc.GetTotalPRs(c.Request.Context())
+	total, err := h.svc.GetTotalPRs(c.Request.Context(), c.Query("repository"))
 	if err != nil {
 		h.mapErrorToResponse(c, err)
 		return
@@ -98,17 +238,17 @@ func (h *PRHandler) GetTotalPRs(c *gin.Context) {
 
 // GetPrsByStatus handles GET /stats/prs-by-status.
 func (h *PRHandler) GetPrsByStatus(c *gin.Context) {
-	open, merged, err := h.svc.GetPrsByStatus(c.Request.Context())
+	status, err := h.svc.GetPrsByStatus(c.Request.Context(), c.Query("repository"))
 	if err != nil {
 		h.mapErrorToResponse(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, models.PrsStatus{OpenPRs: open, MergedPRs: merged})
+	c.JSON(http.StatusOK, status)
 }
 
 // GetAssignmentsPerUser handles GET /stats/assignments-per-user.
 func (h *PRHandler) GetAssignmentsPerUser(c *gin.Context) {
-	assignments, err := h.svc.GetAssignmentsPerUser(c.Request.Context())
+	assignments, err := h.svc.GetAssignmentsPerUser(c.Request.Context(), c.Query("repository"))
 	if err != nil {
 		h.mapErrorToResponse(c, err)
 		return
@@ -118,7 +258,7 @@ func (h *PRHandler) GetAssignmentsPerUser(c *gin.Context) {
 
 // GetTopReviewers handles GET /stats/top-reviewers.
 func (h *PRHandler) GetTopReviewers(c *gin.Context) {
-	top, err := h.svc.GetTopReviewers(c.Request.Context())
+	top, err := h.svc.GetTopReviewers(c.Request.Context(), c.Query("repository"))
 	if err != nil {
 		h.mapErrorToResponse(c, err)
 		return
@@ -128,7 +268,7 @@ func (h *PRHandler) GetTopReviewers(c *gin.Context) {
 
 // GetAvgCloseTime handles GET /stats/avg-close-time.
 func (h *PRHandler) GetAvgCloseTime(c *gin.Context) {
-	detail, err := h.svc.GetAvgCloseTime(c.Request.Context())
+	detail, err := h.svc.GetAvgCloseTime(c.Request.Context(), c.Query("repository"))
 	if err != nil {
 		h.mapErrorToResponse(c, err)
 		return
@@ -138,7 +278,7 @@ func (h *PRHandler) GetAvgCloseTime(c *gin.Context) {
 
 // GetIdleUsersPerTeam handles GET /stats/idle-users-per-team.
 func (h *PRHandler) GetIdleUsersPerTeam(c *gin.Context) {
-	metrics, err := h.svc.GetIdleUsersPerTeam(c.Request.Context())
+	metrics, err := h.svc.GetIdleUsersPerTeam(c.Request.Context(), c.Query("repository"))
 	if err != nil {
 		h.mapErrorToResponse(c, err)
 		return
@@ -148,7 +288,7 @@ func (h *PRHandler) GetIdleUsersPerTeam(c *gin.Context) {
 
 // GetNeedyPRsPerTeam handles GET /stats/needy-prs-per-team.
 func (h *PRHandler) GetNeedyPRsPerTeam(c *gin.Context) {
-	metrics, err := h.svc.GetNeedyPRsPerTeam(c.Request.Context())
+	metrics, err := h.svc.GetNeedyPRsPerTeam(c.Request.Context(), c.Query("repository"))
 	if err != nil {
 		h.mapErrorToResponse(c, err)
 		return
@@ -160,6 +300,7 @@ func (h *PRHandler) mapErrorToResponse(c *gin.Context, err error) {
 	status := http.StatusInternalServerError
 	code := ErrorCodeInternalError
 	msg := ErrorMessageInternalError
+	var details gin.H
 
 	switch {
 	case errors.Is(err, apperrors.ErrNotFound):
@@ -174,6 +315,23 @@ func (h *PRHandler) mapErrorToResponse(c *gin.Context, err error) {
 		status = http.StatusConflict
 		code = "PR_MERGED"
 		msg = "Cannot modify merged PR"
+	case errors.Is(err, apperrors.ErrPRClosed):
+		status = http.StatusConflict
+		code = "PR_CLOSED"
+		msg = "Cannot modify closed PR"
+	case errors.Is(err, apperrors.ErrMergeBlocked):
+		status = http.StatusConflict
+		code = "MERGE_BLOCKED"
+		msg = "Merge blocked by review policy"
+		var blocked *apperrors.MergeBlockedError
+		if errors.As(err, &blocked) {
+			details = gin.H{
+				"required_approvals":   blocked.RequiredApprovals,
+				"approvals":            blocked.Approvals,
+				"pending_reviewers":    blocked.PendingReviewers,
+				"changes_requested_by": blocked.ChangesRequestedBy,
+			}
+		}
 	case errors.Is(err, apperrors.ErrNotAssigned):
 		status = http.StatusConflict
 		code = "NOT_ASSIGNED"
@@ -190,5 +348,9 @@ func (h *PRHandler) mapErrorToResponse(c *gin.Context, err error) {
 		h.log.Error("unexpected error", slog.String("error", err.Error()))
 	}
 
-	c.JSON(status, gin.H{"error": gin.H{"code": code, "message": msg}})
+	body := gin.H{"code": code, "message": msg}
+	if details != nil {
+		body["details"] = details
+	}
+	c.JSON(status, gin.H{"error": body})
 }
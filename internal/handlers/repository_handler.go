@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCodeOwnersSize caps the request body of POST /repository/set-codeowners.
+const maxCodeOwnersSize = 3 << 20
+
+type RepositoryHandler struct {
+	svc *services.RepositoryService
+	log *slog.Logger
+}
+
+func NewRepositoryHandler(svc *services.RepositoryService, log *slog.Logger) *RepositoryHandler {
+	return &RepositoryHandler{svc: svc, log: log}
+}
+
+// SetCodeOwners handles POST /repository/set-codeowners with a multipart "file" field holding the
+// CODEOWNERS file, a "repository" field and an optional "mode" field (preferred or required).
+func (h *RepositoryHandler) SetCodeOwners(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxCodeOwnersSize)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		h.log.Warn("invalid set codeowners request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.log.Error("failed to open uploaded CODEOWNERS", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		h.log.Error("failed to read uploaded CODEOWNERS", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	name := c.PostForm("repository")
+	repo, err := h.svc.SetCodeOwners(c.Request.Context(), name, string(content), c.PostForm("mode"))
+	if err != nil {
+		h.log.Error("set codeowners failed", slog.String("repository", name), slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"repository": repo})
+}
+
+// GetRepository handles GET /repository/get?repository=...
+func (h *RepositoryHandler) GetRepository(c *gin.Context) {
+	name := c.Query("repository")
+	if name == "" {
+		h.log.Warn("missing repository query param")
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	name, _ = url.QueryUnescape(name)
+
+	repo, err := h.svc.GetRepository(c.Request.Context(), name)
+	if err != nil {
+		h.log.Error("get repository failed", slog.String("repository", name), slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"repository": repo})
+}
+
+// SetRepositorySettings handles POST /repository/set-settings.
+func (h *RepositoryHandler) SetRepositorySettings(c *gin.Context) {
+	var req struct {
+		Repository        string  `json:"repository"         binding:"required"`
+		DefaultTeamName   *string `json:"default_team_name"`
+		ReviewersCount    *int    `json:"reviewers_count"`
+		RequiredApprovals *int    `json:"required_approvals"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("invalid set repository settings request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	settings := &models.RepositorySettings{
+		DefaultTeamName:   req.DefaultTeamName,
+		ReviewersCount:    req.ReviewersCount,
+		RequiredApprovals: req.RequiredApprovals,
+	}
+	repo, err := h.svc.UpdateRepositorySettings(c.Request.Context(), req.Repository, settings)
+	if err != nil {
+		h.log.Error("set repository settings failed",
+			slog.String("repository", req.Repository),
+			slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"repository": repo})
+}
+
+func (h *RepositoryHandler) mapErrorToResponse(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	code := ErrorCodeInternalError
+	msg := ErrorMessageInternalError
+
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound):
+		status = http.StatusNotFound
+		code = ErrorCodeNotFound
+		msg = ErrorMessageNotFound
+	case errors.Is(err, apperrors.ErrInvalidInput):
+		status = http.StatusBadRequest
+		code = ErrorCodeInvalidInput
+		msg = ErrorMessageInvalidInput
+	default:
+		h.log.Error("unexpected error", slog.String("error", err.Error()))
+	}
+
+	c.JSON(status, gin.H{"error": gin.H{"code": code, "message": msg}})
+}
@@ -3,30 +3,85 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+
 	"github.com/gin-gonic/gin"
 )
 
-// SetupRoutes registers all API routes.
-func SetupRoutes(r *gin.Engine, prHandler *PRHandler, teamHandler *TeamHandler, userHandler *UserHandler) {
-	api := r.Group("/")
+// SetupRoutes registers all API routes. Everything but /health and the webhooks requires a token, and each
+// route checks the permission it needs against the caller's roles. Webhooks are checked by their signature or token.
+func SetupRoutes(
+	r *gin.Engine,
+	prHandler *PRHandler,
+	teamHandler *TeamHandler,
+	userHandler *UserHandler,
+	repoHandler *RepositoryHandler,
+	auditHandler *AuditHandler,
+	accountHandler *AccountHandler,
+	webhookHandler *WebhookHandler,
+	subscriptionHandler *SubscriptionHandler,
+	slackHandler *SlackHandler,
+	auth *AuthMiddleware,
+) {
+	api := r.Group("/", RequestIDMiddleware, ActorMiddleware, auth.Authenticate)
+
+	read := auth.Require(models.PermRead)
+	writePR := auth.Require(models.PermWritePR)
+	manageTeam := auth.Require(models.PermManageTeam)
+	manageRepository := auth.Require(models.PermManageRepository)
+	manageWebhooks := auth.Require(models.PermManageWebhooks)
 
 	// Teams
-	api.POST("/team/add", teamHandler.CreateTeam)
-	api.POST("/team/add-member", teamHandler.AddMemberToTeam) // New
-	api.GET("/team/get", teamHandler.GetTeam)
+	api.POST("/team/add", auth.Require(models.PermCreateTeam), teamHandler.CreateTeam)
+	api.POST("/team/add-member", manageTeam, teamHandler.AddMemberToTeam) // New
+	api.GET("/team/get", read, teamHandler.GetTeam)
+	api.POST("/team/set-settings", manageTeam, teamHandler.SetTeamSettings)
+	api.POST("/team/set-fallbacks", manageTeam, teamHandler.SetFallbackTeams)
+	api.POST("/team/set-slack-channel", manageTeam, slackHandler.SetTeamChannel)
 
 	// Users
-	api.POST("/users/setIsActive", userHandler.SetUserActive)
-	api.GET("/users/getReview", userHandler.GetPRsForUser)
-	api.POST("/users/deactivateByTeam", userHandler.DeactivateUsersByTeam)
+	api.POST("/users/setIsActive", manageTeam, userHandler.SetUserActive)
+	api.POST("/users/setMaxOpenReviews", manageTeam, userHandler.SetMaxOpenReviews)
+	api.POST("/users/setEmail", manageTeam, userHandler.SetEmail)
+	api.GET("/users/getReview", read, userHandler.GetPRsForUser)
+	api.POST("/users/deactivateByTeam", manageTeam, userHandler.DeactivateUsersByTeam)
+	api.POST("/users/addAbsence", manageTeam, userHandler.AddAbsence)
+	api.GET("/users/getAbsences", read, userHandler.GetAbsences)
+	api.POST("/users/deleteAbsence", manageTeam, userHandler.DeleteAbsence)
+	api.POST("/users/importAbsences", manageTeam, userHandler.ImportAbsences)
+	api.POST("/users/linkAccount", manageTeam, accountHandler.LinkAccount)
+	api.POST("/users/unlinkAccount", manageTeam, accountHandler.UnlinkAccount)
+	api.GET("/users/getAccounts", read, accountHandler.GetAccounts)
+	api.POST("/users/setSlackHandle", manageTeam, slackHandler.SetUserHandle)
+
+	// Repositories
+	api.POST("/repository/set-codeowners", manageRepository, repoHandler.SetCodeOwners)
+	api.GET("/repository/get", read, repoHandler.GetRepository)
+	api.POST("/repository/set-settings", manageRepository, repoHandler.SetRepositorySettings)
 
 	// PullRequests
-	api.POST("/pullRequest/create", prHandler.CreatePR)
-	api.POST("/pullRequest/merge", prHandler.MergePR)
-	api.POST("/pullRequest/reassign", prHandler.ReassignReviewer)
+	api.POST("/pullRequest/create", writePR, prHandler.CreatePR)
+	api.GET("/pullRequest/get", read, prHandler.GetPR)
+	api.GET("/pullRequest/history", read, prHandler.GetPRHistory)
+	api.POST("/pullRequest/review", writePR, prHandler.SubmitReview)
+	api.POST("/pullRequest/ready", writePR, prHandler.ReadyPR)
+	api.POST("/pullRequest/merge", auth.Require(models.PermMergePR), auth.RequireForced(models.PermForceMerge),
+		prHandler.MergePR)
+	api.POST("/pullRequest/close", writePR, prHandler.ClosePR)
+	api.POST("/pullRequest/reopen", writePR, prHandler.ReopenPR)
+	api.POST("/pullRequest/reassign", writePR, prHandler.ReassignReviewer)
+
+	// Outbound webhook subscriptions
+	api.POST("/subscriptions/add", manageWebhooks, subscriptionHandler.Subscribe)
+	api.POST("/subscriptions/delete", manageWebhooks, subscriptionHandler.Unsubscribe)
+	api.GET("/subscriptions/list", manageWebhooks, subscriptionHandler.ListSubscriptions)
+	api.GET("/subscriptions/getDeliveries", manageWebhooks, subscriptionHandler.GetDeliveries)
+
+	// Audit
+	api.GET("/audit", auth.Require(models.PermReadAudit), auditHandler.ListEvents)
 
 	// Stats
-	stats := api.Group("/stats")
+	stats := api.Group("/stats", read)
 	stats.GET("/prs-total", prHandler.GetTotalPRs)
 	stats.GET("/prs-status", prHandler.GetPrsByStatus)
 	stats.GET("/assignments-per-user", prHandler.GetAssignmentsPerUser)
@@ -35,6 +90,10 @@ func SetupRoutes(r *gin.Engine, prHandler *PRHandler, teamHandler *TeamHandler,
 	stats.GET("/idle-users-per-team", prHandler.GetIdleUsersPerTeam)
 	stats.GET("/needy-prs-per-team", prHandler.GetNeedyPRsPerTeam)
 
+	// Webhooks
+	r.POST("/webhooks/github", RequestIDMiddleware, webhookHandler.GitHub)
+	r.POST("/webhooks/gitlab", RequestIDMiddleware, webhookHandler.GitLab)
+
 	// Health
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SlackHandler struct {
+	svc *services.SlackService
+	log *slog.Logger
+}
+
+func NewSlackHandler(svc *services.SlackService, log *slog.Logger) *SlackHandler {
+	return &SlackHandler{svc: svc, log: log}
+}
+
+// SetTeamChannel handles POST /team/set-slack-channel. An empty webhook_path removes the channel.
+func (h *SlackHandler) SetTeamChannel(c *gin.Context) {
+	var req struct {
+		TeamName    string `json:"team_name"    binding:"required"`
+		WebhookPath string `json:"webhook_path"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("invalid set slack channel request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	if err := h.svc.SetTeamChannel(c.Request.Context(), req.TeamName, req.WebhookPath); err != nil {
+		h.log.Error("set slack channel failed",
+			slog.String("team_name", req.TeamName),
+			slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"team_name": req.TeamName, "slack_channel": req.WebhookPath != ""})
+}
+
+// SetUserHandle handles POST /users/setSlackHandle. An empty handle removes it.
+func (h *SlackHandler) SetUserHandle(c *gin.Context) {
+	var req struct {
+		UserID string `json:"user_id" binding:"required"`
+		Handle string `json:"handle"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("invalid set slack handle request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	if err := h.svc.SetUserHandle(c.Request.Context(), req.UserID, req.Handle); err != nil {
+		h.log.Error("set slack handle failed",
+			slog.String("user_id", req.UserID),
+			slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user_id": req.UserID, "handle": req.Handle})
+}
+
+func (h *SlackHandler) mapErrorToResponse(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	code := ErrorCodeInternalError
+	msg := ErrorMessageInternalError
+
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound):
+		status = http.StatusNotFound
+		code = ErrorCodeNotFound
+		msg = ErrorMessageNotFound
+	case errors.Is(err, apperrors.ErrInvalidInput):
+		status = http.StatusBadRequest
+		code = ErrorCodeInvalidInput
+		msg = ErrorMessageInvalidInput
+	default:
+		h.log.Error("unexpected error", slog.String("error", err.Error()))
+	}
+
+	c.JSON(status, gin.H{"error": gin.H{"code": code, "message": msg}})
+}
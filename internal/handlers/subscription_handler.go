@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubscriptionHandler struct {
+	svc *services.SubscriptionService
+	log *slog.Logger
+}
+
+func NewSubscriptionHandler(svc *services.SubscriptionService, log *slog.Logger) *SubscriptionHandler {
+	return &SubscriptionHandler{svc: svc, log: log}
+}
+
+// Subscribe handles POST /subscriptions/add.
+func (h *SubscriptionHandler) Subscribe(c *gin.Context) {
+	var req struct {
+		URL        string   `json:"url"         binding:"required"`
+		Secret     string   `json:"secret"`
+		EventTypes []string `json:"event_types" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("invalid subscribe request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	sub, err := h.svc.Subscribe(c.Request.Context(), &models.WebhookSubscription{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+	})
+	if err != nil {
+		h.log.Error("subscribe failed", slog.String("url", req.URL), slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"subscription": sub})
+}
+
+// Unsubscribe handles POST /subscriptions/delete.
+func (h *SubscriptionHandler) Unsubscribe(c *gin.Context) {
+	var req struct {
+		SubscriptionID int64 `json:"subscription_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("invalid unsubscribe request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	if err := h.svc.Unsubscribe(c.Request.Context(), req.SubscriptionID); err != nil {
+		h.log.Error("unsubscribe failed",
+			slog.Int64("subscription_id", req.SubscriptionID),
+			slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "subscription deleted successfully"})
+}
+
+// ListSubscriptions handles GET /subscriptions/list.
+func (h *SubscriptionHandler) ListSubscriptions(c *gin.Context) {
+	subs, err := h.svc.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"subscriptions": subs})
+}
+
+// GetDeliveries handles GET /subscriptions/getDeliveries?subscription_id=...[&limit=...]
+func (h *SubscriptionHandler) GetDeliveries(c *gin.Context) {
+	subscriptionID, err := strconv.ParseInt(c.Query("subscription_id"), 10, 64)
+	if err != nil {
+		h.log.Warn("invalid subscription_id query param", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		h.log.Warn("invalid limit query param", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	deliveries, err := h.svc.ListDeliveries(c.Request.Context(), subscriptionID, limit)
+	if err != nil {
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"subscription_id": subscriptionID, "deliveries": deliveries})
+}
+
+func (h *SubscriptionHandler) mapErrorToResponse(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	code := ErrorCodeInternalError
+	msg := ErrorMessageInternalError
+
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound):
+		status = http.StatusNotFound
+		code = ErrorCodeNotFound
+		msg = ErrorMessageNotFound
+	case errors.Is(err, apperrors.ErrInvalidInput):
+		status = http.StatusBadRequest
+		code = ErrorCodeInvalidInput
+		msg = ErrorMessageInvalidInput
+	default:
+		h.log.Error("unexpected error", slog.String("error", err.Error()))
+	}
+
+	c.JSON(status, gin.H{"error": gin.H{"code": code, "message": msg}})
+}
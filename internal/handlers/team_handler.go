@@ -87,6 +87,56 @@ func (h *TeamHandler) AddMemberToTeam(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "member added successfully"})
 }
 
+// SetTeamSettings handles POST /team/set-settings.
+func (h *TeamHandler) SetTeamSettings(c *gin.Context) {
+	var req struct {
+		TeamName          string `json:"team_name"          binding:"required"`
+		ReviewersCount    *int   `json:"reviewers_count"`
+		RequiredApprovals *int   `json:"required_approvals"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("invalid set team settings request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	settings := &models.TeamSettings{ReviewersCount: req.ReviewersCount, RequiredApprovals: req.RequiredApprovals}
+	team, err := h.svc.UpdateTeamSettings(c.Request.Context(), req.TeamName, settings)
+	if err != nil {
+		h.log.Error("set team settings failed",
+			slog.String("team_name", req.TeamName),
+			slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"team": team})
+}
+
+// SetFallbackTeams handles POST /team/set-fallbacks. An empty list removes all fallbacks.
+func (h *TeamHandler) SetFallbackTeams(c *gin.Context) {
+	var req struct {
+		TeamName      string   `json:"team_name" binding:"required"`
+		FallbackTeams []string `json:"fallback_teams"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("invalid set fallback teams request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	team, err := h.svc.SetFallbackTeams(c.Request.Context(), req.TeamName, req.FallbackTeams)
+	if err != nil {
+		h.log.Error("set fallback teams failed",
+			slog.String("team_name", req.TeamName),
+			slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"team": team})
+}
+
 func (h *TeamHandler) mapErrorToResponse(c *gin.Context, err error) {
 	status := http.StatusInternalServerError
 	code := ErrorCodeInternalError
@@ -5,13 +5,18 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
+// maxICSUploadSize caps the request body of POST /users/importAbsences.
+const maxICSUploadSize = 5 << 20
+
 type UserHandler struct {
 	svc *services.UserService
 	log *slog.Logger
@@ -43,7 +48,55 @@ func (h *UserHandler) SetUserActive(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"user": user})
 }
 
-// GetPRsForUser handles GET /users/getReview?user_id=...
+// SetMaxOpenReviews handles POST /users/setMaxOpenReviews.
+func (h *UserHandler) SetMaxOpenReviews(c *gin.Context) {
+	var req struct {
+		UserID         string `json:"user_id" binding:"required"`
+		MaxOpenReviews *int   `json:"max_open_reviews"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("invalid set max open reviews request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	user, err := h.svc.SetMaxOpenReviews(c.Request.Context(), req.UserID, req.MaxOpenReviews)
+	if err != nil {
+		h.log.Error("set max open reviews failed",
+			slog.String("user_id", req.UserID),
+			slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
+
+// SetEmail handles POST /users/setEmail.
+func (h *UserHandler) SetEmail(c *gin.Context) {
+	var req struct {
+		UserID string `json:"user_id" binding:"required"`
+		Email  string `json:"email"`
+		Digest bool   `json:"digest"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("invalid set email request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	user, err := h.svc.SetEmail(c.Request.Context(), req.UserID, req.Email, req.Digest)
+	if err != nil {
+		h.log.Error("set email failed", slog.String("user_id", req.UserID), slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
+
+// GetPRsForUser handles GET /users/getReview?user_id=...&repository=...&pending=true
+// (repository and pending are optional).
 func (h *UserHandler) GetPRsForUser(c *gin.Context) {
 	userID := c.Query("user_id")
 	if userID == "" {
@@ -54,7 +107,17 @@ func (h *UserHandler) GetPRsForUser(c *gin.Context) {
 
 	userID, _ = url.QueryUnescape(userID)
 
-	prs, err := h.svc.GetPRsForUser(c.Request.Context(), userID)
+	pendingOnly := false
+	if pending := c.Query("pending"); pending != "" {
+		var err error
+		if pendingOnly, err = strconv.ParseBool(pending); err != nil {
+			h.log.Warn("invalid pending query param", slog.String("pending", pending))
+			h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+			return
+		}
+	}
+
+	prs, err := h.svc.GetPRsForUser(c.Request.Context(), userID, c.Query("repository"), pendingOnly)
 	if err != nil {
 		h.log.Error("get PRs for user failed", slog.String("user_id", userID), slog.String("error", err.Error()))
 		h.mapErrorToResponse(c, err)
@@ -87,6 +150,100 @@ func (h *UserHandler) DeactivateUsersByTeam(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "users deactivated and PRs reassigned successfully"})
 }
 
+// AddAbsence handles POST /users/addAbsence.
+func (h *UserHandler) AddAbsence(c *gin.Context) {
+	var req models.Absence
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("invalid add absence request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	absence, err := h.svc.AddAbsence(c.Request.Context(), &req)
+	if err != nil {
+		h.log.Error("add absence failed", slog.String("user_id", req.UserID), slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"absence": absence})
+}
+
+// GetAbsences handles GET /users/getAbsences?user_id=...
+func (h *UserHandler) GetAbsences(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		h.log.Warn("missing user_id query param")
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	userID, _ = url.QueryUnescape(userID)
+
+	absences, err := h.svc.GetAbsences(c.Request.Context(), userID)
+	if err != nil {
+		h.log.Error("get absences failed", slog.String("user_id", userID), slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user_id": userID, "absences": absences})
+}
+
+// DeleteAbsence handles POST /users/deleteAbsence.
+func (h *UserHandler) DeleteAbsence(c *gin.Context) {
+	var req struct {
+		AbsenceID int64 `json:"absence_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("invalid delete absence request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	if err := h.svc.DeleteAbsence(c.Request.Context(), req.AbsenceID); err != nil {
+		h.log.Error("delete absence failed", slog.Int64("absence_id", req.AbsenceID), slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "absence deleted successfully"})
+}
+
+// ImportAbsences handles POST /users/importAbsences with a multipart "file" field holding an ICS
+// calendar and either a "user_id" or a "team_name" field.
+func (h *UserHandler) ImportAbsences(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxICSUploadSize)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		h.log.Warn("invalid import absences request", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.log.Error("failed to open uploaded calendar", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+	defer file.Close()
+
+	userID, teamName := c.PostForm("user_id"), c.PostForm("team_name")
+	result, err := h.svc.ImportAbsences(c.Request.Context(), userID, teamName, file)
+	if err != nil {
+		h.log.Error("import absences failed",
+			slog.String("user_id", userID),
+			slog.String("team_name", teamName),
+			slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 func (h *UserHandler) mapErrorToResponse(c *gin.Context, err error) {
 	status := http.StatusInternalServerError
 	code := ErrorCodeInternalError
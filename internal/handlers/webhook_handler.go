@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/github"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/gitlab"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookPayloadSize caps the request body of webhook deliveries.
+const maxWebhookPayloadSize = 5 << 20
+
+// WebhookHandler receives code host webhooks. Deliveries are authenticated by their signature or secret
+// token, not by a bearer token.
+type WebhookHandler struct {
+	svc *services.WebhookService
+	log *slog.Logger
+}
+
+func NewWebhookHandler(svc *services.WebhookService, log *slog.Logger) *WebhookHandler {
+	return &WebhookHandler{svc: svc, log: log}
+}
+
+// GitHub handles POST /webhooks/github.
+func (h *WebhookHandler) GitHub(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookPayloadSize))
+	if err != nil {
+		h.log.Warn("failed to read GitHub delivery", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	event, delivery := c.GetHeader(github.EventHeader), c.GetHeader(github.DeliveryHeader)
+	result, err := h.svc.HandleGitHub(c.Request.Context(), event, c.GetHeader(github.SignatureHeader), body)
+	if err != nil {
+		h.log.Error("GitHub delivery failed",
+			slog.String("event", event),
+			slog.String("delivery", delivery),
+			slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	h.log.Info("GitHub delivery handled",
+		slog.String("event", event),
+		slog.String("delivery", delivery),
+		slog.String("status", result.Status))
+	c.JSON(http.StatusOK, result)
+}
+
+// GitLab handles POST /webhooks/gitlab.
+func (h *WebhookHandler) GitLab(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookPayloadSize))
+	if err != nil {
+		h.log.Warn("failed to read GitLab delivery", slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, apperrors.ErrInvalidInput)
+		return
+	}
+
+	event, delivery := c.GetHeader(gitlab.EventHeader), c.GetHeader(gitlab.EventUUIDHeader)
+	result, err := h.svc.HandleGitLab(c.Request.Context(), event, c.GetHeader(gitlab.TokenHeader), body)
+	if err != nil {
+		h.log.Error("GitLab delivery failed",
+			slog.String("event", event),
+			slog.String("delivery", delivery),
+			slog.String("error", err.Error()))
+		h.mapErrorToResponse(c, err)
+		return
+	}
+
+	h.log.Info("GitLab delivery handled",
+		slog.String("event", event),
+		slog.String("delivery", delivery),
+		slog.String("status", result.Status))
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *WebhookHandler) mapErrorToResponse(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	code := ErrorCodeInternalError
+	msg := ErrorMessageInternalError
+
+	switch {
+	case errors.Is(err, apperrors.ErrUnauthorized):
+		status = http.StatusUnauthorized
+		code = ErrorCodeUnauthorized
+		msg = "Invalid webhook signature or token"
+	case errors.Is(err, apperrors.ErrInvalidInput):
+		status = http.StatusBadRequest
+		code = ErrorCodeInvalidInput
+		msg = ErrorMessageInvalidInput
+	default:
+		h.log.Error("unexpected error", slog.String("error", err.Error()))
+	}
+
+	c.JSON(status, gin.H{"error": gin.H{"code": code, "message": msg}})
+}
@@ -0,0 +1,312 @@
+// Package ical parses the subset of iCalendar (RFC 5545) needed to import absence windows:
+// VEVENT components with their UID, time range, summary, status and participants.
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCalendar is returned when the input is not an iCalendar stream.
+var ErrInvalidCalendar = errors.New("invalid iCalendar data")
+
+const (
+	dateLayout      = "20060102"
+	localTimeLayout = "20060102T150405"
+	utcTimeLayout   = "20060102T150405Z"
+	maxLineSize     = 1 << 20
+)
+
+// Contact is an ATTENDEE or ORGANIZER of an event.
+type Contact struct {
+	Name  string // CN parameter
+	Email string // mailto: address, lower-cased
+}
+
+// Event is a parsed VEVENT. End is exclusive; it is derived from DURATION or
+// the start date when DTEND is missing.
+type Event struct {
+	UID       string
+	Summary   string
+	Status    string
+	Start     time.Time
+	End       time.Time
+	AllDay    bool
+	Organizer *Contact
+	Attendees []Contact
+}
+
+// Cancelled reports whether the event has STATUS:CANCELLED.
+func (e *Event) Cancelled() bool {
+	return strings.EqualFold(e.Status, "CANCELLED")
+}
+
+// Contacts returns the organizer followed by the attendees.
+func (e *Event) Contacts() []Contact {
+	contacts := make([]Contact, 0, len(e.Attendees)+1)
+	if e.Organizer != nil {
+		contacts = append(contacts, *e.Organizer)
+	}
+	return append(contacts, e.Attendees...)
+}
+
+// property is a single content line: NAME;PARAM=VALUE:value.
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads all VEVENT components from r. Events whose dates cannot be parsed are
+// still returned with a zero Start so that callers can report them.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, ErrInvalidCalendar
+	}
+
+	events := []Event{}
+	var (
+		current *Event
+		props   []property
+		nested  int
+	)
+	for _, line := range lines {
+		prop, ok := parseProperty(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT") && current == nil:
+			current = &Event{}
+			props = props[:0]
+		case prop.name == "BEGIN" && current != nil:
+			nested++
+		case prop.name == "END" && current != nil && nested > 0:
+			nested--
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT") && current != nil:
+			buildEvent(current, props)
+			events = append(events, *current)
+			current = nil
+		case current != nil && nested == 0:
+			props = append(props, prop)
+		}
+	}
+	return events, nil
+}
+
+// unfold joins folded content lines (continuation lines start with a space or tab).
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Join(ErrInvalidCalendar, err)
+	}
+	return lines, nil
+}
+
+// parseProperty splits a content line into name, parameters and value.
+func parseProperty(line string) (property, bool) {
+	colon := indexUnquoted(line, ':')
+	if colon < 0 {
+		return property{}, false
+	}
+
+	parts := splitUnquoted(line[:colon], ';')
+	prop := property{
+		name:   strings.ToUpper(parts[0]),
+		params: make(map[string]string, len(parts)-1),
+		value:  line[colon+1:],
+	}
+	for _, p := range parts[1:] {
+		key, value, found := strings.Cut(p, "=")
+		if !found {
+			continue
+		}
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return prop, true
+}
+
+func buildEvent(e *Event, props []property) {
+	var duration *time.Duration
+	var endProp *property
+	var startProp *property
+
+	for i := range props {
+		p := &props[i]
+		switch p.name {
+		case "UID":
+			e.UID = strings.TrimSpace(p.value)
+		case "SUMMARY":
+			e.Summary = unescapeText(p.value)
+		case "STATUS":
+			e.Status = strings.ToUpper(strings.TrimSpace(p.value))
+		case "DTSTART":
+			startProp = p
+		case "DTEND":
+			endProp = p
+		case "DURATION":
+			if d, ok := parseDuration(p.value); ok {
+				duration = &d
+			}
+		case "ORGANIZER":
+			c := parseContact(*p)
+			e.Organizer = &c
+		case "ATTENDEE":
+			e.Attendees = append(e.Attendees, parseContact(*p))
+		}
+	}
+
+	if startProp == nil {
+		return
+	}
+	start, allDay, ok := parseTime(*startProp)
+	if !ok {
+		return
+	}
+	e.Start, e.AllDay = start, allDay
+
+	switch {
+	case endProp != nil:
+		if end, _, endOK := parseTime(*endProp); endOK {
+			e.End = end
+		}
+	case duration != nil:
+		e.End = start.Add(*duration)
+	case allDay:
+		e.End = start.AddDate(0, 0, 1)
+	default:
+		e.End = start
+	}
+}
+
+// parseTime parses DATE and DATE-TIME values, honouring the TZID parameter.
+// Floating times and unknown time zones are interpreted as UTC.
+func parseTime(p property) (time.Time, bool, bool) {
+	value := strings.TrimSpace(p.value)
+	loc := time.UTC
+	if tzid := p.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	if strings.EqualFold(p.params["VALUE"], "DATE") || len(value) == len(dateLayout) {
+		t, err := time.ParseInLocation(dateLayout, value, loc)
+		return t, true, err == nil
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(utcTimeLayout, value)
+		return t, false, err == nil
+	}
+	t, err := time.ParseInLocation(localTimeLayout, value, loc)
+	return t, false, err == nil
+}
+
+var durationRe = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration parses RFC 5545 DURATION values such as P1D, PT8H or P2W.
+func parseDuration(value string) (time.Duration, bool) {
+	m := durationRe.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return 0, false
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+2])
+		if err != nil {
+			return 0, false
+		}
+		d += time.Duration(n) * unit
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, true
+}
+
+func parseContact(p property) Contact {
+	c := Contact{Name: unescapeText(p.params["CN"])}
+	value := strings.TrimSpace(p.value)
+	if len(value) > len("mailto:") && strings.EqualFold(value[:len("mailto:")], "mailto:") {
+		c.Email = strings.ToLower(value[len("mailto:"):])
+	}
+	return c
+}
+
+// unescapeText reverses TEXT escaping (\n, \, \; \\).
+func unescapeText(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+func indexUnquoted(s string, sep byte) int {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case sep:
+			if !quoted {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func splitUnquoted(s string, sep byte) []string {
+	var parts []string
+	for {
+		i := indexUnquoted(s, sep)
+		if i < 0 {
+			return append(parts, s)
+		}
+		parts = append(parts, s[:i])
+		s = s[i+1:]
+	}
+}
@@ -0,0 +1,232 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidKeySet is returned when a JWKS document cannot be used.
+var ErrInvalidKeySet = errors.New("invalid JWKS")
+
+// maxKeySetSize bounds a JWKS document fetched from a URL.
+const maxKeySetSize = 1 << 20
+
+// KeySet holds the RSA and EC public keys of a JWKS document by key ID.
+type KeySet struct {
+	keys map[string]crypto.PublicKey
+}
+
+// jsonWebKey is the subset of RFC 7517 fields needed for RSA and P-256 signature keys.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseKeySet parses a JWKS document. Keys that are not for signatures or are of an unsupported type
+// are skipped; a document without usable keys is an error.
+func ParseKeySet(data []byte) (*KeySet, error) {
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKeySet, err)
+	}
+
+	set := &KeySet{keys: map[string]crypto.PublicKey{}}
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch k.Kty {
+		case "RSA":
+			key, err = rsaKey(k)
+		case "EC":
+			key, err = ecKey(k)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q: %w", ErrInvalidKeySet, k.Kid, err)
+		}
+		set.keys[k.Kid] = key
+	}
+	if len(set.keys) == 0 {
+		return nil, fmt.Errorf("%w: no RSA or EC signature keys", ErrInvalidKeySet)
+	}
+	return set, nil
+}
+
+// Key returns the key with the given ID. An empty ID matches the only key of a single-key set.
+func (s *KeySet) Key(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+func rsaKey(k jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("modulus: %w", err)
+	}
+	e, err := decodeBigInt(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("exponent: %w", err)
+	}
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("bad exponent")
+	}
+	if n.BitLen() < 2048 {
+		return nil, errors.New("RSA keys must be at least 2048 bits")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func ecKey(k jsonWebKey) (*ecdsa.PublicKey, error) {
+	if k.Crv != "P-256" {
+		return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+	}
+	x, err := decodeBigInt(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("x: %w", err)
+	}
+	y, err := decodeBigInt(k.Y)
+	if err != nil {
+		return nil, fmt.Errorf("y: %w", err)
+	}
+	//nolint:staticcheck // IsOnCurve is the simplest check for a JWK point; the key is only used to verify.
+	if !elliptic.P256().IsOnCurve(x, y) {
+		return nil, errors.New("point is not on P-256")
+	}
+	return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// KeySource loads a KeySet from a file path or an http(s) URL and reloads it when a token names a key
+// it does not know, so that rotated keys are picked up. Reloads are attempted at most once per minInterval,
+// failed ones included, and run without holding the lock, so tokens with known keys are not held up by them.
+type KeySource struct {
+	source      string
+	client      *http.Client
+	minInterval time.Duration
+
+	mu          sync.Mutex
+	keys        *KeySet
+	attemptedAt time.Time
+}
+
+func NewKeySource(source string, client *http.Client, minInterval time.Duration) *KeySource {
+	return &KeySource{source: source, client: client, minInterval: minInterval}
+}
+
+// Load reads the key set now. Call it at startup to fail fast on a bad source.
+func (s *KeySource) Load(ctx context.Context) error {
+	s.mu.Lock()
+	s.attemptedAt = time.Now()
+	s.mu.Unlock()
+
+	keys, err := s.load(ctx)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+// Key returns the key with the given ID, reloading the key set if the ID is unknown. Unknown IDs and
+// failed reloads yield ErrInvalidToken: the token cannot be verified, whatever the reason.
+func (s *KeySource) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	if s.keys != nil {
+		if key, ok := s.keys.Key(kid); ok {
+			s.mu.Unlock()
+			return key, nil
+		}
+	}
+	if time.Since(s.attemptedAt) < s.minInterval {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+	}
+	s.attemptedAt = time.Now()
+	s.mu.Unlock()
+
+	keys, err := s.load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+
+	if key, ok := keys.Key(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+}
+
+func (s *KeySource) load(ctx context.Context) (*KeySet, error) {
+	data, err := s.read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load JWKS from %s: %w", s.source, err)
+	}
+	return ParseKeySet(data)
+}
+
+func (s *KeySource) read(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(s.source, "http://") && !strings.HasPrefix(s.source, "https://") {
+		return os.ReadFile(s.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxKeySetSize))
+}
@@ -0,0 +1,193 @@
+// Package jwt verifies RS256 and ES256 signed JSON Web Tokens (RFC 7519) against keys from a JWKS
+// document (RFC 7517), as issued by an OIDC provider.
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken is returned for tokens that are malformed, badly signed, expired or not meant for us.
+var ErrInvalidToken = errors.New("invalid JWT")
+
+// Supported signature algorithms.
+const (
+	RS256 = "RS256"
+	ES256 = "ES256"
+)
+
+// leeway absorbs clock skew between the issuer and this service.
+const leeway = time.Minute
+
+// Claims is the decoded payload of a verified token.
+type Claims map[string]any
+
+// String returns the string claim name, or "" when it is missing or not a string.
+func (c Claims) String(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// Strings returns the claim name as a list: a JSON array of strings, or a single string.
+func (c Claims) Strings(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return []string{v}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// Time returns the NumericDate claim name, and false when it is missing or not a number.
+func (c Claims) Time(name string) (time.Time, bool) {
+	v, ok := c[name].(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	f, err := v.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(f), 0), true
+}
+
+// KeyProvider returns the public key a token's kid header refers to.
+type KeyProvider interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// Verifier checks token signatures and the registered claims exp, nbf, iss and aud.
+type Verifier struct {
+	keys     KeyProvider
+	issuer   string
+	audience string
+}
+
+// NewVerifier returns a Verifier accepting only tokens issued by issuer for audience. Both are required:
+// a token without them is rejected.
+func NewVerifier(keys KeyProvider, issuer, audience string) *Verifier {
+	return &Verifier{keys: keys, issuer: issuer, audience: audience}
+}
+
+// LooksLikeJWT reports whether raw has the three-part compact serialization of a JWT.
+func LooksLikeJWT(raw string) bool {
+	return strings.Count(raw, ".") == 2
+}
+
+// Verify checks raw and returns its claims. A token must carry exp.
+func (v *Verifier) Verify(ctx context.Context, raw string) (Claims, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: not a compact JWS", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: header: %w", ErrInvalidToken, err)
+	}
+	if header.Alg != RS256 && header.Alg != ES256 {
+		return nil, fmt.Errorf("%w: unsupported alg %q", ErrInvalidToken, header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature: %w", ErrInvalidToken, err)
+	}
+	key, err := v.keys.Key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err = verifySignature(header.Alg, key, digest[:], sig); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: payload: %w", ErrInvalidToken, err)
+	}
+	if err = v.checkClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (v *Verifier) checkClaims(claims Claims) error {
+	now := time.Now()
+	exp, ok := claims.Time("exp")
+	if !ok {
+		return fmt.Errorf("%w: missing exp", ErrInvalidToken)
+	}
+	if now.After(exp.Add(leeway)) {
+		return fmt.Errorf("%w: expired", ErrInvalidToken)
+	}
+	if nbf, hasNbf := claims.Time("nbf"); hasNbf && now.Add(leeway).Before(nbf) {
+		return fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	}
+	if v.issuer == "" || claims.String("iss") != v.issuer {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+	if v.audience == "" || !slices.Contains(claims.Strings("aud"), v.audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+	return nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, digest, sig []byte) error {
+	switch alg {
+	case RS256:
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%w: %s token signed with a non-RSA key", ErrInvalidToken, alg)
+		}
+		if rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest, sig) != nil {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+	case ES256:
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%w: %s token signed with a non-EC key", ErrInvalidToken, alg)
+		}
+		// JWS ECDSA signatures are the fixed-size concatenation r || s, not ASN.1.
+		if len(sig) != 64 {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+	}
+	return nil
+}
+
+func decodeSegment(seg string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type TeamMember struct {
 	UserID   string `json:"user_id"   binding:"required"`
@@ -9,15 +12,53 @@ type TeamMember struct {
 }
 
 type Team struct {
-	Name    string       `json:"team_name" binding:"required,min=1"`
-	Members []TeamMember `json:"members"   binding:"required,dive"`
+	Name          string        `json:"team_name"                binding:"required,min=1"`
+	Members       []TeamMember  `json:"members"                  binding:"required,dive"`
+	Settings      *TeamSettings `json:"settings,omitempty"`
+	FallbackTeams []string      `json:"fallback_teams,omitempty"`
+}
+
+// TeamSettings holds per-team overrides; nil fields fall back to service-wide defaults.
+type TeamSettings struct {
+	ReviewersCount *int `json:"reviewers_count,omitempty"`
+	// RequiredApprovals is how many approvals PRs need before merge; nil means no merge policy.
+	RequiredApprovals *int `json:"required_approvals,omitempty"`
 }
 
 type User struct {
-	ID       string `json:"user_id"   binding:"required"`
-	Name     string `json:"username"  binding:"required"`
-	TeamName string `json:"team_name" binding:"required"`
-	IsActive bool   `json:"is_active"`
+	ID             string `json:"user_id"                    binding:"required"`
+	Name           string `json:"username"                   binding:"required"`
+	TeamName       string `json:"team_name"                  binding:"required"`
+	IsActive       bool   `json:"is_active"`
+	MaxOpenReviews *int   `json:"max_open_reviews,omitempty"`
+	// Email receives notifications; EmailDigest opts in to the daily digest of pending reviews.
+	Email       string `json:"email,omitempty"`
+	EmailDigest bool   `json:"email_digest,omitempty"`
+}
+
+// Absence is a time window during which the user must not be assigned as a reviewer.
+type Absence struct {
+	ID          int64     `json:"absence_id"`
+	UserID      string    `json:"user_id"                binding:"required"`
+	StartsAt    time.Time `json:"starts_at"              binding:"required"`
+	EndsAt      time.Time `json:"ends_at"                binding:"required"`
+	Reason      string    `json:"reason,omitempty"`
+	ExternalUID string    `json:"external_uid,omitempty"`
+}
+
+// AbsenceImportResult summarises an ICS absence import.
+type AbsenceImportResult struct {
+	Created   int              `json:"created"`
+	Updated   int              `json:"updated"`
+	Cancelled int              `json:"cancelled"`
+	Unmatched []UnmatchedEvent `json:"unmatched"`
+}
+
+// UnmatchedEvent is a calendar event that could not be imported as an absence.
+type UnmatchedEvent struct {
+	UID     string `json:"uid,omitempty"`
+	Summary string `json:"summary,omitempty"`
+	Reason  string `json:"reason"`
 }
 
 type PullRequest struct {
@@ -29,13 +70,100 @@ type PullRequest struct {
 	NeedMoreReviewers bool       `json:"need_more_reviewers,omitempty"`
 	CreatedAt         *time.Time `json:"createdAt,omitempty"`
 	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	ClosedAt          *time.Time `json:"closedAt,omitempty"`
+	// Draft PRs get no reviewers until they are marked ready.
+	Draft bool `json:"draft,omitempty"`
+	// ReviewerTeams maps each assigned reviewer to the team they were drawn from.
+	ReviewerTeams map[string]string `json:"reviewer_teams,omitempty"`
+	// ReviewStates maps each assigned reviewer to their review state.
+	ReviewStates map[string]string `json:"review_states,omitempty"`
+	// Repository scopes the PR ID: IDs are unique per repository. Empty means no repository.
+	Repository string `json:"repository,omitempty"`
+	// ChangedFiles is only used on create to match the repository's CODEOWNERS rules.
+	ChangedFiles []string `json:"changed_files,omitempty"`
+}
+
+// MergeOverride records a merge forced past the merge policy and what was blocking it.
+type MergeOverride struct {
+	ID                 int64      `json:"id"`
+	Repository         string     `json:"repository,omitempty"`
+	PRID               string     `json:"pull_request_id"`
+	Reason             string     `json:"reason,omitempty"`
+	RequiredApprovals  int        `json:"required_approvals"`
+	Approvals          int        `json:"approvals"`
+	PendingReviewers   []string   `json:"pending_reviewers"`
+	ChangesRequestedBy []string   `json:"changes_requested_by"`
+	CreatedAt          *time.Time `json:"createdAt,omitempty"`
+}
+
+// Review states of an assigned reviewer. Reviewers start PENDING until they submit a verdict.
+const (
+	ReviewStatePending          = "PENDING"
+	ReviewStateApproved         = "APPROVED"
+	ReviewStateChangesRequested = "CHANGES_REQUESTED"
+)
+
+// AssignmentChange says who changed the reviewers of a PR and why. Reason is one of the AssignReason values.
+type AssignmentChange struct {
+	Actor  string
+	Reason string
+}
+
+// Reasons for assigning or removing reviewers.
+const (
+	AssignReasonCreated     = "PR_CREATED"
+	AssignReasonReady       = "PR_READY"
+	AssignReasonReopened    = "PR_REOPENED"
+	AssignReasonReassigned  = "REASSIGNED"
+	AssignReasonDeactivated = "USER_DEACTIVATED"
+	AssignReasonAbsent      = "USER_ABSENT"
+)
+
+// Assignment event actions.
+const (
+	AssignmentAssigned = "ASSIGNED"
+	AssignmentRemoved  = "REMOVED"
+)
+
+// AssignmentEvent is a reviewer being assigned to or removed from a PR.
+type AssignmentEvent struct {
+	ReviewerID string    `json:"reviewer_id"`
+	Action     string    `json:"action"`
+	Reason     string    `json:"reason"`
+	Actor      string    `json:"actor"`
+	At         time.Time `json:"at"`
+}
+
+// CODEOWNERS modes: preferred owners fill reviewer slots first, required owners must each be covered.
+const (
+	CodeOwnersPreferred = "preferred"
+	CodeOwnersRequired  = "required"
+)
+
+// Repository holds per-repository configuration.
+type Repository struct {
+	Name           string              `json:"repository"`
+	CodeOwners     string              `json:"codeowners"`
+	CodeOwnersMode string              `json:"codeowners_mode"`
+	Settings       *RepositorySettings `json:"settings,omitempty"`
+}
+
+// RepositorySettings holds per-repository overrides of the author's team; nil fields keep the team's behaviour.
+type RepositorySettings struct {
+	DefaultTeamName *string `json:"default_team_name,omitempty"`
+	ReviewersCount  *int    `json:"reviewers_count,omitempty"`
+	// RequiredApprovals overrides the team's merge policy for the repository's PRs.
+	RequiredApprovals *int `json:"required_approvals,omitempty"`
 }
 
 type PullRequestShort struct {
-	ID       string `json:"pull_request_id"`
-	Title    string `json:"pull_request_name"`
-	AuthorID string `json:"author_id"`
-	Status   string `json:"status"`
+	ID         string `json:"pull_request_id"`
+	Title      string `json:"pull_request_name"`
+	AuthorID   string `json:"author_id"`
+	Status     string `json:"status"`
+	Repository string `json:"repository,omitempty"`
+	// ReviewState is the user's own review state on the PR.
+	ReviewState string `json:"review_state,omitempty"`
 }
 
 type PrsTotal struct {
@@ -45,12 +173,14 @@ type PrsTotal struct {
 type PrsStatus struct {
 	OpenPRs   int `json:"open_prs"`
 	MergedPRs int `json:"merged_prs"`
+	ClosedPRs int `json:"closed_prs"`
 }
 
 type UserAssignment struct {
-	UserID string `json:"user_id"`
-	Name   string `json:"username"`
-	Count  int    `json:"assignment_count"`
+	UserID         string `json:"user_id"`
+	Name           string `json:"username"`
+	Count          int    `json:"assignment_count"`
+	MaxOpenReviews *int   `json:"max_open_reviews,omitempty"`
 }
 
 type UserAssignments struct {
@@ -80,3 +210,250 @@ type TeamMetric struct {
 type TeamMetrics struct {
 	Metrics []TeamMetric `json:"team_metrics"`
 }
+
+// Audited entity types.
+const (
+	AuditEntityTeam        = "team"
+	AuditEntityUser        = "user"
+	AuditEntityAbsence     = "absence"
+	AuditEntityPullRequest = "pull_request"
+	AuditEntityAccount     = "account"
+)
+
+// Audited actions.
+const (
+	AuditTeamCreated          = "TEAM_CREATED"
+	AuditTeamMemberAdded      = "TEAM_MEMBER_ADDED"
+	AuditTeamSettingsUpdated  = "TEAM_SETTINGS_UPDATED"
+	AuditTeamFallbacksUpdated = "TEAM_FALLBACKS_UPDATED"
+	AuditTeamDeactivated      = "TEAM_DEACTIVATED"
+	AuditUserActiveChanged    = "USER_ACTIVE_CHANGED"
+	AuditUserLimitChanged     = "USER_MAX_OPEN_REVIEWS_CHANGED"
+	AuditUserEmailChanged     = "USER_EMAIL_CHANGED"
+	AuditAbsenceAdded         = "ABSENCE_ADDED"
+	AuditAbsenceDeleted       = "ABSENCE_DELETED"
+	AuditAbsencesImported     = "ABSENCES_IMPORTED"
+	AuditPRCreated            = "PR_CREATED"
+	AuditPRReady              = "PR_READY"
+	AuditPRReviewerReassigned = "PR_REVIEWER_REASSIGNED"
+	AuditPRAbsentReassigned   = "PR_ABSENT_REVIEWER_REASSIGNED"
+	AuditPRReviewed           = "PR_REVIEWED"
+	AuditPRMerged             = "PR_MERGED"
+	AuditPRForceMerged        = "PR_FORCE_MERGED"
+	AuditPRClosed             = "PR_CLOSED"
+	AuditPRReopened           = "PR_REOPENED"
+	AuditAccountLinked        = "ACCOUNT_LINKED"
+	AuditAccountUnlinked      = "ACCOUNT_UNLINKED"
+)
+
+// AuditEvent records a change made through the API. Before and After are the affected entity as JSON,
+// null when it did not exist before or after the change.
+type AuditEvent struct {
+	ID    int64  `json:"id"`
+	Actor string `json:"actor"`
+	// OnBehalfOf is the unverified X-Actor header of the request, "" when it was not sent.
+	OnBehalfOf string          `json:"on_behalf_of"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	RequestID  string          `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditFilter selects audit events; empty fields and nil times match everything.
+type AuditFilter struct {
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   string
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
+
+// AuditPage is one page of audit events, newest first, with the number of events matching the filter.
+type AuditPage struct {
+	Events []AuditEvent `json:"events"`
+	Total  int          `json:"total"`
+	Limit  int          `json:"limit"`
+	Offset int          `json:"offset"`
+}
+
+// APIToken is a bearer token for the API. The token itself is never stored, only its hash.
+// Requests made with it act as UserID.
+type APIToken struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	UserID    string     `json:"user_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Roles that can be bound to a principal.
+const (
+	RoleAdmin    = "admin"
+	RoleTeamLead = "team_lead"
+	RoleMember   = "member"
+	RoleBot      = "bot"
+)
+
+// Permissions checked in front of the handlers. Read is never team-scoped.
+const (
+	PermRead             = "read"
+	PermWritePR          = "pr:write"
+	PermMergePR          = "pr:merge"
+	PermForceMerge       = "pr:force-merge"
+	PermManageTeam       = "team:manage"
+	PermCreateTeam       = "team:create"
+	PermManageRepository = "repository:manage"
+	PermManageWebhooks   = "webhook:manage"
+	PermReadAudit        = "audit:read"
+)
+
+// RoleBinding grants Role to UserID, within TeamName only when it is set.
+type RoleBinding struct {
+	ID        int64     `json:"id"`
+	UserID    string    `json:"user_id"`
+	Role      string    `json:"role"`
+	TeamName  string    `json:"team_name,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Principal is the authenticated caller with the roles bound to it.
+type Principal struct {
+	UserID   string
+	Bindings []RoleBinding
+}
+
+// AccessTarget names what a request acts on, so that a permission can be checked against the team
+// that owns it. Only the fields the request carries are set.
+type AccessTarget struct {
+	TeamName      string
+	UserID        string
+	Repository    string
+	PullRequestID string
+	AbsenceID     int64
+}
+
+// Code hosts whose logins can be linked to users and whose webhooks are accepted.
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// Account links a login on a code host to a user of the service. Login is lower-cased.
+type Account struct {
+	Provider  string    `json:"provider"`
+	Login     string    `json:"login"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Outcomes of a webhook delivery.
+const (
+	WebhookApplied = "applied"
+	WebhookIgnored = "ignored"
+)
+
+// WebhookResult tells the code host what a delivery did. PR is the pull request after the change.
+type WebhookResult struct {
+	Status string       `json:"status"`
+	Reason string       `json:"reason,omitempty"`
+	PR     *PullRequest `json:"pr,omitempty"`
+}
+
+// Domain event types. Webhook subscribers filter on them.
+const (
+	EventPRCreated          = "pr.created"
+	EventReviewerAssigned   = "reviewer.assigned"
+	EventReviewerReassigned = "reviewer.reassigned"
+	EventPRMerged           = "pr.merged"
+	EventUserDeactivated    = "user.deactivated"
+	EventReviewStale        = "review.stale"
+)
+
+// Event is a domain event: the body of an outbound webhook and what the outbox hands to its sinks. ID is the
+// same on every dispatch and delivery of the event, so sinks and receivers can drop duplicates.
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      EventData `json:"data"`
+}
+
+// EventData is what an event is about. ReviewerID is the assigned reviewer of reviewer.* and review.stale
+// events; OldReviewerID is the one reviewer.reassigned replaced. AssignedAt is when the reviewer of a stale
+// review was assigned.
+type EventData struct {
+	PullRequest   *PullRequest `json:"pull_request,omitempty"`
+	User          *User        `json:"user,omitempty"`
+	ReviewerID    string       `json:"reviewer_id,omitempty"`
+	OldReviewerID string       `json:"old_reviewer_id,omitempty"`
+	AssignedAt    *time.Time   `json:"assigned_at,omitempty"`
+}
+
+// StaleReview is a review still pending on an open PR long after the reviewer was assigned.
+type StaleReview struct {
+	PullRequest PullRequest
+	ReviewerID  string
+	AssignedAt  time.Time
+}
+
+// OutboxEntry is an event waiting in the outbox. Attempts counts the failed dispatches so far;
+// DeliveredSinks names the sinks that already took the event and are skipped when it is retried.
+type OutboxEntry struct {
+	ID             int64
+	Attempts       int
+	DeliveredSinks []string
+	Event          Event
+}
+
+// WebhookSubscription is an endpoint that receives the events of EventTypes. Secret signs the deliveries;
+// it is only returned when the subscription is created.
+type WebhookSubscription struct {
+	ID         int64     `json:"id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret,omitempty"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Statuses of a webhook delivery.
+const (
+	DeliveryPending   = "PENDING"
+	DeliveryDelivered = "DELIVERED"
+	DeliveryFailed    = "FAILED"
+)
+
+// WebhookDelivery is an event sent, or to be sent, to a subscription. Payload is the exact request body.
+// NextAttemptAt is set while the delivery is pending.
+type WebhookDelivery struct {
+	ID             int64             `json:"id"`
+	SubscriptionID int64             `json:"subscription_id"`
+	EventID        string            `json:"event_id"`
+	EventType      string            `json:"event_type"`
+	Payload        json.RawMessage   `json:"payload"`
+	Status         string            `json:"status"`
+	Attempts       int               `json:"attempts"`
+	NextAttemptAt  *time.Time        `json:"next_attempt_at,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	DeliveredAt    *time.Time        `json:"delivered_at,omitempty"`
+	AttemptLog     []DeliveryAttempt `json:"attempt_log"`
+	// URL and Secret are those of the subscription, loaded for sending.
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// DeliveryAttempt is one try to send a delivery. StatusCode is zero when no response came; Error says why.
+type DeliveryAttempt struct {
+	Attempt     int       `json:"attempt"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	DurationMS  int64     `json:"duration_ms"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
@@ -0,0 +1,53 @@
+// Package outbound signs the webhooks the service sends to subscribers and schedules their retries.
+// Signatures follow the GitHub scheme: "sha256=" and the hex HMAC-SHA256 of the body under the
+// subscription's secret.
+package outbound
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Headers set on every delivery.
+const (
+	EventHeader     = "X-Webhook-Event"
+	EventIDHeader   = "X-Webhook-Event-ID"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	SignatureHeader = "X-Webhook-Signature-256"
+)
+
+// Sign returns the signature header value of body under secret.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether header is the signature of body under secret. Receivers use it to check
+// deliveries.
+func Verify(secret, body []byte, header string) bool {
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// Backoff returns how long to wait before the retry that follows the given failed attempt: base after the
+// first, doubling with each further attempt and capped at limit.
+func Backoff(attempt int, base, limit time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+)
+
+type AbsenceRepo struct {
+	db *pgxpool.Pool
+}
+
+var _ AbsenceRepository = (*AbsenceRepo)(nil)
+
+func NewAbsenceRepo(db *pgxpool.Pool) *AbsenceRepo {
+	return &AbsenceRepo{db: db}
+}
+
+// CreateAbsence stores an absence window and sets its generated ID.
+func (r *AbsenceRepo) CreateAbsence(ctx context.Context, absence *models.Absence) error {
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		INSERT INTO user_absences (user_id, starts_at, ends_at, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, absence.UserID, absence.StartsAt, absence.EndsAt, absence.Reason).Scan(&absence.ID)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to create absence")
+	}
+	return nil
+}
+
+// GetAbsencesByUser returns all absences of a user ordered by start time.
+func (r *AbsenceRepo) GetAbsencesByUser(ctx context.Context, userID string) ([]models.Absence, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT id, user_id, starts_at, ends_at, reason, COALESCE(external_uid, '')
+		FROM user_absences
+		WHERE user_id = $1
+		ORDER BY starts_at
+	`, userID)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to query absences")
+	}
+	return scanAbsences(rows)
+}
+
+// GetAbsenceByID returns the absence with the given ID.
+func (r *AbsenceRepo) GetAbsenceByID(ctx context.Context, id int64) (*models.Absence, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT id, user_id, starts_at, ends_at, reason, COALESCE(external_uid, '')
+		FROM user_absences
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to query absence")
+	}
+	absences, err := scanAbsences(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(absences) == 0 {
+		return nil, apperrors.ErrNotFound
+	}
+	return &absences[0], nil
+}
+
+// DeleteAbsence deletes an absence by ID and returns it as it was before deletion.
+func (r *AbsenceRepo) DeleteAbsence(ctx context.Context, id int64) (*models.Absence, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		DELETE FROM user_absences WHERE id = $1
+		RETURNING id, user_id, starts_at, ends_at, reason, COALESCE(external_uid, '')
+	`, id)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to delete absence")
+	}
+	deleted, err := scanAbsences(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(deleted) == 0 {
+		return nil, apperrors.ErrNotFound
+	}
+	return &deleted[0], nil
+}
+
+// GetStartedUnprocessedAbsences returns absences that are in progress and whose reviews were not reassigned yet.
+func (r *AbsenceRepo) GetStartedUnprocessedAbsences(ctx context.Context) ([]models.Absence, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT id, user_id, starts_at, ends_at, reason, COALESCE(external_uid, '')
+		FROM user_absences
+		WHERE reassigned_at IS NULL AND starts_at <= now() AND ends_at > now()
+		ORDER BY starts_at
+	`)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to query started absences")
+	}
+	return scanAbsences(rows)
+}
+
+// MarkAbsenceProcessed records that the user's open reviews were reassigned for the absence.
+func (r *AbsenceRepo) MarkAbsenceProcessed(ctx context.Context, id int64) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `UPDATE user_absences SET reassigned_at = now() WHERE id = $1`, id)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to mark absence processed")
+	}
+	return nil
+}
+
+// UpsertAbsenceByExternalUID creates or updates the absence imported from the calendar event with the
+// same UID for the user and sets its ID. It reports whether a new row was created. Moving the start of
+// an already processed absence makes it eligible for reassignment again.
+func (r *AbsenceRepo) UpsertAbsenceByExternalUID(ctx context.Context, absence *models.Absence) (bool, error) {
+	var created bool
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		INSERT INTO user_absences (user_id, starts_at, ends_at, reason, external_uid)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, external_uid) DO UPDATE
+		SET starts_at = EXCLUDED.starts_at,
+			ends_at = EXCLUDED.ends_at,
+			reason = EXCLUDED.reason,
+			reassigned_at = CASE
+				WHEN user_absences.starts_at = EXCLUDED.starts_at THEN user_absences.reassigned_at
+			END
+		RETURNING id, xmax = 0
+	`, absence.UserID, absence.StartsAt, absence.EndsAt, absence.Reason, absence.ExternalUID).
+		Scan(&absence.ID, &created)
+	if err != nil {
+		return false, apperrors.Wrap(err, "failed to upsert absence")
+	}
+	return created, nil
+}
+
+// DeleteAbsenceByExternalUID deletes the absence imported from the given calendar event, if any.
+func (r *AbsenceRepo) DeleteAbsenceByExternalUID(ctx context.Context, userID, externalUID string) (bool, error) {
+	res, err := conn(ctx, r.db).Exec(ctx, `
+		DELETE FROM user_absences WHERE user_id = $1 AND external_uid = $2
+	`, userID, externalUID)
+	if err != nil {
+		return false, apperrors.Wrap(err, "failed to delete absence")
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func scanAbsences(rows pgx.Rows) ([]models.Absence, error) {
+	defer rows.Close()
+
+	absences := []models.Absence{}
+	for rows.Next() {
+		var a models.Absence
+		if scanErr := rows.Scan(&a.ID, &a.UserID, &a.StartsAt, &a.EndsAt, &a.Reason, &a.ExternalUID); scanErr != nil {
+			return nil, apperrors.Wrap(scanErr, "failed to scan absence")
+		}
+		absences = append(absences, a)
+	}
+	if scanErr := rows.Err(); scanErr != nil {
+		return nil, apperrors.Wrap(scanErr, "error iterating absences")
+	}
+	return absences, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+)
+
+type AccountRepo struct {
+	db *pgxpool.Pool
+}
+
+var _ AccountRepository = (*AccountRepo)(nil)
+
+func NewAccountRepo(db *pgxpool.Pool) *AccountRepo {
+	return &AccountRepo{db: db}
+}
+
+// UpsertAccount links the login to account.UserID, moving it away from any user it was linked to,
+// and fills in the creation time.
+func (r *AccountRepo) UpsertAccount(ctx context.Context, account *models.Account) error {
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		INSERT INTO user_accounts (provider, login, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (provider, login) DO UPDATE SET user_id = EXCLUDED.user_id, created_at = CURRENT_TIMESTAMP
+		RETURNING created_at
+	`, account.Provider, account.Login, account.UserID).Scan(&account.CreatedAt)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to upsert account")
+	}
+	return nil
+}
+
+// DeleteAccount removes the link of the login to userID and returns it.
+func (r *AccountRepo) DeleteAccount(ctx context.Context, provider, login, userID string) (*models.Account, error) {
+	account := &models.Account{}
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		DELETE FROM user_accounts
+		WHERE provider = $1 AND login = $2 AND user_id = $3
+		RETURNING provider, login, user_id, created_at
+	`, provider, login, userID).Scan(&account.Provider, &account.Login, &account.UserID, &account.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, apperrors.Wrap(err, "failed to delete account")
+	}
+	return account, nil
+}
+
+// GetAccount returns the link of the login.
+func (r *AccountRepo) GetAccount(ctx context.Context, provider, login string) (*models.Account, error) {
+	account := &models.Account{}
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		SELECT provider, login, user_id, created_at
+		FROM user_accounts
+		WHERE provider = $1 AND login = $2
+	`, provider, login).Scan(&account.Provider, &account.Login, &account.UserID, &account.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, apperrors.Wrap(err, "failed to query account")
+	}
+	return account, nil
+}
+
+// ListAccounts returns the logins linked to userID.
+func (r *AccountRepo) ListAccounts(ctx context.Context, userID string) ([]models.Account, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT provider, login, user_id, created_at
+		FROM user_accounts
+		WHERE user_id = $1
+		ORDER BY provider, login
+	`, userID)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to query accounts")
+	}
+	defer rows.Close()
+
+	accounts := []models.Account{}
+	for rows.Next() {
+		var a models.Account
+		if scanErr := rows.Scan(&a.Provider, &a.Login, &a.UserID, &a.CreatedAt); scanErr != nil {
+			return nil, apperrors.Wrap(scanErr, "failed to scan account")
+		}
+		accounts = append(accounts, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Wrap(err, "error iterating accounts")
+	}
+	return accounts, nil
+}
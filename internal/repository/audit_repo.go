@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+)
+
+type AuditRepo struct {
+	db *pgxpool.Pool
+}
+
+var _ AuditRepository = (*AuditRepo)(nil)
+
+func NewAuditRepo(db *pgxpool.Pool) *AuditRepo {
+	return &AuditRepo{db: db}
+}
+
+// CreateAuditEvent stores the event and sets its generated ID and time.
+// Called within TxManager.WithinTx it is committed together with the audited change.
+func (r *AuditRepo) CreateAuditEvent(ctx context.Context, event *models.AuditEvent) error {
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		INSERT INTO audit_events (actor, on_behalf_of, action, entity_type, entity_id, before, after, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`, event.Actor, event.OnBehalfOf, event.Action, event.EntityType, event.EntityID,
+		jsonOrNull(event.Before), jsonOrNull(event.After), event.RequestID,
+	).Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to create audit event")
+	}
+	return nil
+}
+
+// ListAuditEvents returns the page of events matching filter, newest first, and the number of all matches.
+func (r *AuditRepo) ListAuditEvents(ctx context.Context, filter models.AuditFilter) ([]models.AuditEvent, int, error) {
+	const where = `
+		WHERE ($1 = '' OR actor = $1)
+			AND ($2 = '' OR action = $2)
+			AND ($3 = '' OR entity_type = $3)
+			AND ($4 = '' OR entity_id = $4)
+			AND ($5 = '' OR request_id = $5)
+			AND ($6::timestamp IS NULL OR created_at >= $6)
+			AND ($7::timestamp IS NULL OR created_at < $7)
+	`
+	args := []any{
+		filter.Actor, filter.Action, filter.EntityType, filter.EntityID, filter.RequestID, filter.From, filter.To,
+	}
+
+	var total int
+	err := conn(ctx, r.db).QueryRow(ctx, `SELECT COUNT(*) FROM audit_events`+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, apperrors.Wrap(err, "failed to count audit events")
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT id, actor, on_behalf_of, action, entity_type, entity_id, before, after, request_id, created_at
+		FROM audit_events`+where+`
+		ORDER BY created_at DESC, id DESC
+		LIMIT $8 OFFSET $9
+	`, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, apperrors.Wrap(err, "failed to query audit events")
+	}
+	defer rows.Close()
+
+	events := []models.AuditEvent{}
+	for rows.Next() {
+		var e models.AuditEvent
+		var before, after []byte
+		if scanErr := rows.Scan(
+			&e.ID, &e.Actor, &e.OnBehalfOf, &e.Action, &e.EntityType, &e.EntityID, &before, &after, &e.RequestID, &e.CreatedAt,
+		); scanErr != nil {
+			return nil, 0, apperrors.Wrap(scanErr, "failed to scan audit event")
+		}
+		e.Before, e.After = before, after
+		events = append(events, e)
+	}
+	if scanErr := rows.Err(); scanErr != nil {
+		return nil, 0, apperrors.Wrap(scanErr, "error iterating audit events")
+	}
+	return events, total, nil
+}
+
+// jsonOrNull maps an absent JSON value to SQL NULL.
+func jsonOrNull(raw json.RawMessage) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return []byte(raw)
+}
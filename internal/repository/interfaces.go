@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
 )
@@ -9,30 +10,132 @@ import (
 type TeamRepository interface {
 	CreateTeam(ctx context.Context, team *models.Team) error
 	GetTeamByName(ctx context.Context, name string) (*models.Team, error)
+	GetTeamSettings(ctx context.Context, name string) (*models.TeamSettings, error)
+	UpdateTeamSettings(ctx context.Context, name string, settings *models.TeamSettings, defaultReviewers int) error
+	GetFallbackTeams(ctx context.Context, name string) ([]string, error)
+	SetFallbackTeams(ctx context.Context, name string, fallbackTeams []string) error
 }
 
 type UserRepository interface {
 	UpsertUser(ctx context.Context, user *models.User) error
 	GetUserByID(ctx context.Context, id string) (*models.User, error)
 	UpdateUserActive(ctx context.Context, id string, isActive bool) error
+	UpdateUserMaxOpenReviews(ctx context.Context, id string, limit *int) error
+	UpdateUserEmail(ctx context.Context, id, email string, digest bool) error
 	GetActiveUsersByTeam(ctx context.Context, teamName string) ([]models.User, error)
+	GetAvailableUsersByTeam(ctx context.Context, teamName string) ([]models.User, error)
 	GetTeamNameByUserID(ctx context.Context, userID string) (string, error)
 	DeactivateUsersByTeam(ctx context.Context, teamName string) error
+	ClaimDigestRecipients(ctx context.Context, slot time.Time, limit int) ([]models.User, error)
 }
 
 type PRRepository interface {
-	CreatePR(ctx context.Context, pr *models.PullRequest) error
-	GetPRByID(ctx context.Context, id string) (*models.PullRequest, error)
-	UpdatePR(ctx context.Context, pr *models.PullRequest) error
-	MergePR(ctx context.Context, id string) error
-	GetPRsForUser(ctx context.Context, userID string) ([]models.PullRequestShort, error)
-	ExistsPR(ctx context.Context, id string) (bool, error)
-	GetTotalPRs(ctx context.Context) (int, error)
-	GetPrsByStatus(ctx context.Context) (int, int, error)
-	GetAssignmentsPerUser(ctx context.Context) ([]models.UserAssignment, error)
-	GetTopReviewers(ctx context.Context) ([]models.UserAssignment, error)
-	GetAvgCloseTime(ctx context.Context) (float64, int, error)
-	GetIdleUsersPerTeam(ctx context.Context) ([]models.TeamMetric, error)
-	GetNeedyPRsPerTeam(ctx context.Context) ([]models.TeamMetric, error)
+	CreatePR(ctx context.Context, pr *models.PullRequest, change models.AssignmentChange) error
+	GetPRByID(ctx context.Context, repository, id string) (*models.PullRequest, error)
+	UpdatePR(ctx context.Context, pr *models.PullRequest, change models.AssignmentChange) error
+	MergePR(ctx context.Context, repository, id string) error
+	ForceMergePR(ctx context.Context, override *models.MergeOverride) error
+	ClosePR(ctx context.Context, repository, id string) error
+	MarkPRReady(ctx context.Context, pr *models.PullRequest, change models.AssignmentChange) error
+	ReopenPR(ctx context.Context, pr *models.PullRequest, change models.AssignmentChange) error
+	GetPRsForUser(
+		ctx context.Context, userID, repository string, pendingOnly bool,
+	) ([]models.PullRequestShort, error)
+	SetReviewState(ctx context.Context, repository, prID, reviewerID, state string) error
+	GetAssignmentHistory(ctx context.Context, repository, prID string) ([]models.AssignmentEvent, error)
+	ExistsPR(ctx context.Context, repository, id string) (bool, error)
+	GetTotalPRs(ctx context.Context, repository string) (int, error)
+	GetPrsByStatus(ctx context.Context, repository string) (models.PrsStatus, error)
+	GetAssignmentsPerUser(ctx context.Context, repository string) ([]models.UserAssignment, error)
+	GetOpenReviewLoadByTeam(ctx context.Context, teamName string) ([]models.UserAssignment, error)
+	GetTopReviewers(ctx context.Context, repository string) ([]models.UserAssignment, error)
+	GetAvgCloseTime(ctx context.Context, repository string) (float64, int, error)
+	GetIdleUsersPerTeam(ctx context.Context, repository string) ([]models.TeamMetric, error)
+	GetNeedyPRsPerTeam(ctx context.Context, repository string) ([]models.TeamMetric, error)
 	GetOpenPRsWithReviewersFromTeam(ctx context.Context, teamName string) ([]models.PullRequest, error)
+	ClaimStaleReviews(ctx context.Context, olderThan time.Duration, limit int) ([]models.StaleReview, error)
+}
+
+type AbsenceRepository interface {
+	CreateAbsence(ctx context.Context, absence *models.Absence) error
+	GetAbsencesByUser(ctx context.Context, userID string) ([]models.Absence, error)
+	GetAbsenceByID(ctx context.Context, id int64) (*models.Absence, error)
+	DeleteAbsence(ctx context.Context, id int64) (*models.Absence, error)
+	GetStartedUnprocessedAbsences(ctx context.Context) ([]models.Absence, error)
+	MarkAbsenceProcessed(ctx context.Context, id int64) error
+	UpsertAbsenceByExternalUID(ctx context.Context, absence *models.Absence) (bool, error)
+	DeleteAbsenceByExternalUID(ctx context.Context, userID, externalUID string) (bool, error)
+}
+
+type RepositoryRepository interface {
+	GetRepository(ctx context.Context, name string) (*models.Repository, error)
+	SetCodeOwners(ctx context.Context, name, content, mode string) error
+	UpdateRepositorySettings(
+		ctx context.Context, name string, settings *models.RepositorySettings, defaultReviewers int,
+	) error
+}
+
+type AuditRepository interface {
+	CreateAuditEvent(ctx context.Context, event *models.AuditEvent) error
+	ListAuditEvents(ctx context.Context, filter models.AuditFilter) ([]models.AuditEvent, int, error)
+}
+
+type TokenRepository interface {
+	CreateToken(ctx context.Context, token *models.APIToken, hash string) error
+	GetTokenByHash(ctx context.Context, hash string) (*models.APIToken, error)
+	ListTokens(ctx context.Context) ([]models.APIToken, error)
+	RevokeToken(ctx context.Context, name string) error
+}
+
+type RoleRepository interface {
+	CreateRoleBinding(ctx context.Context, binding *models.RoleBinding) error
+	DeleteRoleBinding(ctx context.Context, userID, role, teamName string) error
+	ListRoleBindings(ctx context.Context, userID string) ([]models.RoleBinding, error)
+}
+
+type AccountRepository interface {
+	UpsertAccount(ctx context.Context, account *models.Account) error
+	DeleteAccount(ctx context.Context, provider, login, userID string) (*models.Account, error)
+	GetAccount(ctx context.Context, provider, login string) (*models.Account, error)
+	ListAccounts(ctx context.Context, userID string) ([]models.Account, error)
+}
+
+type SubscriptionRepository interface {
+	CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id int64) error
+	GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context, eventType string) ([]models.WebhookSubscription, error)
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+	RecordAttempt(
+		ctx context.Context,
+		delivery *models.WebhookDelivery,
+		attempt *models.DeliveryAttempt,
+		retryAfter time.Duration,
+	) error
+	ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error)
+}
+
+type SlackRepository interface {
+	SetTeamChannel(ctx context.Context, teamName, webhookPath string) error
+	DeleteTeamChannel(ctx context.Context, teamName string) error
+	GetTeamChannel(ctx context.Context, teamName string) (string, error)
+	SetUserHandle(ctx context.Context, userID, handle string) error
+	DeleteUserHandle(ctx context.Context, userID string) error
+	GetUserHandles(ctx context.Context, userIDs []string) (map[string]string, error)
+}
+
+type OutboxRepository interface {
+	AddEvent(ctx context.Context, event *models.Event) error
+	ClaimNextEvent(ctx context.Context) (*models.OutboxEntry, error)
+	MarkDispatched(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, deliveredSinks []string, lastError string, retryAfter time.Duration) error
+	MarkAbandoned(ctx context.Context, id int64, deliveredSinks []string, lastError string) error
+}
+
+// Transactor runs fn in a database transaction shared by the repositories called with the context it passes.
+// WithinSavepoint lets a part of the transaction fail without failing the rest.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error
 }
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+)
+
+type OutboxRepo struct {
+	db *pgxpool.Pool
+}
+
+var _ OutboxRepository = (*OutboxRepo)(nil)
+
+func NewOutboxRepo(db *pgxpool.Pool) *OutboxRepo {
+	return &OutboxRepo{db: db}
+}
+
+// AddEvent writes event to the outbox. Called within TxManager.WithinTx it is committed together with the
+// change it reports.
+func (r *OutboxRepo) AddEvent(ctx context.Context, event *models.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to encode event")
+	}
+	_, err = conn(ctx, r.db).Exec(ctx, `
+		INSERT INTO outbox (event_id, event_type, payload) VALUES ($1, $2, $3)
+	`, event.ID, event.Type, payload)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to add outbox event")
+	}
+	return nil
+}
+
+// ClaimNextEvent locks the oldest pending event that is due and returns it, or nil when there is none.
+// Events locked by other dispatchers are skipped. Call it within TxManager.WithinTx: the lock is held, and
+// MarkDispatched takes effect, until the transaction ends.
+func (r *OutboxRepo) ClaimNextEvent(ctx context.Context) (*models.OutboxEntry, error) {
+	entry := &models.OutboxEntry{}
+	var payload []byte
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		SELECT id, attempts, delivered_sinks, payload
+		FROM outbox
+		WHERE status = 'PENDING' AND next_attempt_at <= CURRENT_TIMESTAMP
+		ORDER BY next_attempt_at, id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`).Scan(&entry.ID, &entry.Attempts, &entry.DeliveredSinks, &payload)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil //nolint:nilnil // no event is due
+		}
+		return nil, apperrors.Wrap(err, "failed to claim outbox event")
+	}
+	if err = json.Unmarshal(payload, &entry.Event); err != nil {
+		return nil, apperrors.Wrap(err, "failed to decode outbox event")
+	}
+	return entry, nil
+}
+
+// MarkDispatched records that the event reached all sinks.
+func (r *OutboxRepo) MarkDispatched(ctx context.Context, id int64) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE outbox SET status = 'DISPATCHED', dispatched_at = CURRENT_TIMESTAMP, last_error = '' WHERE id = $1
+	`, id)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to mark outbox event dispatched")
+	}
+	return nil
+}
+
+// MarkFailed records a failed dispatch of the event, with the sinks that took it before the failure, and
+// makes it due again retryAfter from now.
+func (r *OutboxRepo) MarkFailed(
+	ctx context.Context,
+	id int64,
+	deliveredSinks []string,
+	lastError string,
+	retryAfter time.Duration,
+) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE outbox
+		SET attempts = attempts + 1,
+			delivered_sinks = COALESCE($2::TEXT[], '{}'),
+			last_error = $3,
+			next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $4)
+		WHERE id = $1
+	`, id, deliveredSinks, lastError, retryAfter.Seconds())
+	if err != nil {
+		return apperrors.Wrap(err, "failed to mark outbox event failed")
+	}
+	return nil
+}
+
+// MarkAbandoned records the last failed dispatch of the event and gives it up: it is not claimed again.
+func (r *OutboxRepo) MarkAbandoned(ctx context.Context, id int64, deliveredSinks []string, lastError string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE outbox
+		SET status = 'FAILED',
+			attempts = attempts + 1,
+			delivered_sinks = COALESCE($2::TEXT[], '{}'),
+			last_error = $3
+		WHERE id = $1
+	`, id, deliveredSinks, lastError)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to mark outbox event abandoned")
+	}
+	return nil
+}
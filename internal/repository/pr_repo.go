@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -22,9 +23,9 @@ func NewPRRepo(db *pgxpool.Pool) *PRRepo {
 	return &PRRepo{db: db}
 }
 
-// CreatePR creates PR.
-func (r *PRRepo) CreatePR(ctx context.Context, pr *models.PullRequest) error {
-	exists, err := r.ExistsPR(ctx, pr.ID)
+// CreatePR creates PR and records the assignment of its reviewers.
+func (r *PRRepo) CreatePR(ctx context.Context, pr *models.PullRequest, change models.AssignmentChange) error {
+	exists, err := r.ExistsPR(ctx, pr.Repository, pr.ID)
 	if err != nil {
 		return apperrors.Wrap(err, "failed to check PR existence")
 	}
@@ -32,25 +33,50 @@ func (r *PRRepo) CreatePR(ctx context.Context, pr *models.PullRequest) error {
 		return apperrors.ErrPRExists
 	}
 
-	_, err = r.db.Exec(ctx, `
-		INSERT INTO pull_requests (id, title, author_id, status, reviewers, need_more_reviewers, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`, pr.ID, pr.Title, pr.AuthorID, pr.Status, pr.Reviewers, pr.NeedMoreReviewers, time.Now())
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO pull_requests (repository, id, title, author_id, status, need_more_reviewers, is_draft, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, pr.Repository, pr.ID, pr.Title, pr.AuthorID, pr.Status, pr.NeedMoreReviewers, pr.Draft, time.Now())
 	if err != nil {
 		return apperrors.Wrap(err, "failed to create PR")
 	}
+	if err = syncReviewers(ctx, tx, pr.Repository, pr.ID, pr.Reviewers, change); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return apperrors.Wrap(err, "failed to commit tx")
+	}
 	return nil
 }
 
-// GetPRByID gets PRs by ID.
-func (r *PRRepo) GetPRByID(ctx context.Context, id string) (*models.PullRequest, error) {
+// GetPRByID gets PR by repository and ID.
+func (r *PRRepo) GetPRByID(ctx context.Context, repository, id string) (*models.PullRequest, error) {
 	pr := &models.PullRequest{}
 	var createdAt time.Time
-	var mergedAt *time.Time
-	err := r.db.QueryRow(ctx, `
-		SELECT id, title, author_id, status, reviewers, need_more_reviewers, created_at, merged_at
-		FROM pull_requests WHERE id = $1
-	`, id).Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.Reviewers, &pr.NeedMoreReviewers, &createdAt, &mergedAt)
+	var mergedAt, closedAt *time.Time
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		SELECT pr.repository, pr.id, pr.title, pr.author_id, pr.status, COALESCE(rv.reviewers, '{}'),
+			pr.need_more_reviewers, pr.is_draft, pr.created_at, pr.merged_at, pr.closed_at, rv.teams, rv.states
+		FROM pull_requests pr
+		LEFT JOIN LATERAL (
+			SELECT array_agg(r.user_id ORDER BY r.position) AS reviewers,
+				jsonb_object_agg(r.user_id, u.team_name) FILTER (WHERE u.team_name IS NOT NULL) AS teams,
+				jsonb_object_agg(r.user_id, r.state) AS states
+			FROM pr_reviewers r
+			JOIN users u ON u.id = r.user_id
+			WHERE r.repository = pr.repository AND r.pr_id = pr.id AND r.removed_at IS NULL
+		) rv ON true
+		WHERE pr.repository = $1 AND pr.id = $2
+	`, repository, id).Scan(
+		&pr.Repository, &pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.Reviewers, &pr.NeedMoreReviewers,
+		&pr.Draft, &createdAt, &mergedAt, &closedAt, &pr.ReviewerTeams, &pr.ReviewStates,
+	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperrors.ErrNotFound
@@ -59,48 +85,263 @@ func (r *PRRepo) GetPRByID(ctx context.Context, id string) (*models.PullRequest,
 	}
 	pr.CreatedAt = &createdAt
 	pr.MergedAt = mergedAt
+	pr.ClosedAt = closedAt
 	return pr, nil
 }
 
-// UpdatePR updates PR.
-func (r *PRRepo) UpdatePR(ctx context.Context, pr *models.PullRequest) error {
-	current, err := r.GetPRByID(ctx, pr.ID)
+// UpdatePR updates PR and records the reviewer changes.
+func (r *PRRepo) UpdatePR(ctx context.Context, pr *models.PullRequest, change models.AssignmentChange) error {
+	current, err := r.GetPRByID(ctx, pr.Repository, pr.ID)
 	if err != nil {
 		return apperrors.Wrap(err, "failed to get PR for update")
 	}
-	if current.Status == "MERGED" {
+	switch current.Status {
+	case "MERGED":
 		return apperrors.ErrPRMerged
+	case "CLOSED":
+		return apperrors.ErrPRClosed
 	}
 
-	_, err = r.db.Exec(ctx, `
-		UPDATE pull_requests SET reviewers = $2, need_more_reviewers = $3
-		WHERE id = $1
-	`, pr.ID, pr.Reviewers, pr.NeedMoreReviewers)
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `
+		UPDATE pull_requests SET need_more_reviewers = $3 WHERE repository = $1 AND id = $2
+	`, pr.Repository, pr.ID, pr.NeedMoreReviewers)
 	if err != nil {
 		return apperrors.Wrap(err, "failed to update PR")
 	}
+	if err = syncReviewers(ctx, tx, pr.Repository, pr.ID, pr.Reviewers, change); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return apperrors.Wrap(err, "failed to commit tx")
+	}
 	return nil
 }
 
-// MergePR merge PR idempotently.
-func (r *PRRepo) MergePR(ctx context.Context, id string) error {
-	_, err := r.db.Exec(ctx, `
+// MergePR merge PR idempotently. Only OPEN PRs are merged.
+func (r *PRRepo) MergePR(ctx context.Context, repository, id string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
 		UPDATE pull_requests SET status = 'MERGED', merged_at = CURRENT_TIMESTAMP
-		WHERE id = $1 AND status != 'MERGED'
-	`, id)
+		WHERE repository = $1 AND id = $2 AND status = 'OPEN'
+	`, repository, id)
 	if err != nil {
 		return apperrors.Wrap(err, "failed to merge PR")
 	}
 	return nil
 }
 
-// GetPRsForUser gets PRs for user.
-func (r *PRRepo) GetPRsForUser(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
-	rows, err := r.db.Query(ctx, `
-		SELECT id, title, author_id, status
-		FROM pull_requests 
-		WHERE $1 = ANY(reviewers)
-	`, userID)
+// ForceMergePR merges an OPEN PR past the merge policy and records the override in the same transaction.
+func (r *PRRepo) ForceMergePR(ctx context.Context, override *models.MergeOverride) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	res, err := tx.Exec(ctx, `
+		UPDATE pull_requests SET status = 'MERGED', merged_at = CURRENT_TIMESTAMP
+		WHERE repository = $1 AND id = $2 AND status = 'OPEN'
+	`, override.Repository, override.PRID)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to merge PR")
+	}
+	if res.RowsAffected() == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO merge_overrides (
+			repository, pr_id, reason, required_approvals, approvals, pending_reviewers, changes_requested_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, override.Repository, override.PRID, override.Reason, override.RequiredApprovals, override.Approvals,
+		override.PendingReviewers, override.ChangesRequestedBy)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to record merge override")
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return apperrors.Wrap(err, "failed to commit tx")
+	}
+	return nil
+}
+
+// ClosePR closes PR without merging idempotently. Only OPEN PRs are closed.
+func (r *PRRepo) ClosePR(ctx context.Context, repository, id string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE pull_requests SET status = 'CLOSED', closed_at = CURRENT_TIMESTAMP
+		WHERE repository = $1 AND id = $2 AND status = 'OPEN'
+	`, repository, id)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to close PR")
+	}
+	return nil
+}
+
+// SetReviewState stores the reviewer's review state on their current assignment to the PR.
+func (r *PRRepo) SetReviewState(ctx context.Context, repository, prID, reviewerID, state string) error {
+	res, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE pr_reviewers SET state = $4, reviewed_at = CURRENT_TIMESTAMP
+		WHERE repository = $1 AND pr_id = $2 AND user_id = $3 AND removed_at IS NULL
+	`, repository, prID, reviewerID, state)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to set review state")
+	}
+	if res.RowsAffected() == 0 {
+		return apperrors.ErrNotAssigned
+	}
+	return nil
+}
+
+// MarkPRReady clears the draft flag of an OPEN PR and stores its reviewers idempotently.
+func (r *PRRepo) MarkPRReady(ctx context.Context, pr *models.PullRequest, change models.AssignmentChange) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	res, err := tx.Exec(ctx, `
+		UPDATE pull_requests SET is_draft = false, need_more_reviewers = $3
+		WHERE repository = $1 AND id = $2 AND status = 'OPEN' AND is_draft
+	`, pr.Repository, pr.ID, pr.NeedMoreReviewers)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to mark PR ready")
+	}
+	if res.RowsAffected() == 0 {
+		return nil
+	}
+	if err = syncReviewers(ctx, tx, pr.Repository, pr.ID, pr.Reviewers, change); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return apperrors.Wrap(err, "failed to commit tx")
+	}
+	return nil
+}
+
+// ReopenPR reopens a CLOSED PR with the given reviewers idempotently.
+func (r *PRRepo) ReopenPR(ctx context.Context, pr *models.PullRequest, change models.AssignmentChange) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	res, err := tx.Exec(ctx, `
+		UPDATE pull_requests SET status = 'OPEN', closed_at = NULL, need_more_reviewers = $3
+		WHERE repository = $1 AND id = $2 AND status = 'CLOSED'
+	`, pr.Repository, pr.ID, pr.NeedMoreReviewers)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to reopen PR")
+	}
+	if res.RowsAffected() == 0 {
+		return nil
+	}
+	if err = syncReviewers(ctx, tx, pr.Repository, pr.ID, pr.Reviewers, change); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return apperrors.Wrap(err, "failed to commit tx")
+	}
+	return nil
+}
+
+// syncReviewers makes reviewers the current reviewers of the PR, in the given order. Reviewers left out are marked
+// removed and newly listed ones get a PENDING assignment, both attributed to change; kept reviewers keep their
+// assignment and verdict.
+func syncReviewers(
+	ctx context.Context,
+	tx pgx.Tx,
+	repository, prID string,
+	reviewers []string,
+	change models.AssignmentChange,
+) error {
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+
+	_, err := tx.Exec(ctx, `
+		UPDATE pr_reviewers SET removed_at = CURRENT_TIMESTAMP, removed_by = $4, removed_reason = $5
+		WHERE repository = $1 AND pr_id = $2 AND removed_at IS NULL AND NOT (user_id = ANY($3))
+	`, repository, prID, reviewers, change.Actor, change.Reason)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to remove reviewers")
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO pr_reviewers (repository, pr_id, user_id, position, assigned_by, assigned_reason)
+		SELECT $1, $2, r.user_id, r.position - 1, $4, $5
+		FROM unnest($3::text[]) WITH ORDINALITY AS r(user_id, position)
+		ON CONFLICT (repository, pr_id, user_id) WHERE removed_at IS NULL
+		DO UPDATE SET position = EXCLUDED.position
+	`, repository, prID, reviewers, change.Actor, change.Reason)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to assign reviewers")
+	}
+	return nil
+}
+
+// GetAssignmentHistory returns the reviewer assignments and removals of the PR in the order they happened.
+// When a reviewer is replaced, the removal comes before the assignment that replaced them.
+func (r *PRRepo) GetAssignmentHistory(
+	ctx context.Context,
+	repository, prID string,
+) ([]models.AssignmentEvent, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT user_id, action, reason, actor, at
+		FROM (
+			SELECT id, user_id, 'ASSIGNED' AS action, assigned_reason AS reason, assigned_by AS actor,
+				assigned_at AS at
+			FROM pr_reviewers
+			WHERE repository = $1 AND pr_id = $2
+			UNION ALL
+			SELECT id, user_id, 'REMOVED', COALESCE(removed_reason, ''), COALESCE(removed_by, 'system'), removed_at
+			FROM pr_reviewers
+			WHERE repository = $1 AND pr_id = $2 AND removed_at IS NOT NULL
+		) events
+		ORDER BY at, action DESC, id
+	`, repository, prID)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to query assignment history")
+	}
+	defer rows.Close()
+
+	history := []models.AssignmentEvent{}
+	for rows.Next() {
+		var e models.AssignmentEvent
+		if scanErr := rows.Scan(&e.ReviewerID, &e.Action, &e.Reason, &e.Actor, &e.At); scanErr != nil {
+			return nil, apperrors.Wrap(scanErr, "failed to scan assignment event")
+		}
+		history = append(history, e)
+	}
+	if scanErr := rows.Err(); scanErr != nil {
+		return nil, apperrors.Wrap(scanErr, "error iterating assignment history")
+	}
+	return history, nil
+}
+
+// GetPRsForUser gets PRs for user, except CLOSED ones, with the user's review state.
+// An empty repository matches all repositories; pendingOnly keeps OPEN PRs the user has not reviewed yet.
+func (r *PRRepo) GetPRsForUser(
+	ctx context.Context,
+	userID, repository string,
+	pendingOnly bool,
+) ([]models.PullRequestShort, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT pr.id, pr.title, pr.author_id, pr.status, pr.repository, r.state
+		FROM pr_reviewers r
+		JOIN pull_requests pr ON pr.repository = r.repository AND pr.id = r.pr_id
+		WHERE r.user_id = $1
+		  AND r.removed_at IS NULL
+		  AND pr.status != 'CLOSED'
+		  AND ($2 = '' OR pr.repository = $2)
+		  AND (NOT $3 OR (pr.status = 'OPEN' AND r.state = 'PENDING'))
+	`, userID, repository, pendingOnly)
 	if err != nil {
 		return nil, apperrors.Wrap(err, "failed to query PRs for user")
 	}
@@ -109,7 +350,9 @@ func (r *PRRepo) GetPRsForUser(ctx context.Context, userID string) ([]models.Pul
 	var prs []models.PullRequestShort
 	for rows.Next() {
 		var p models.PullRequestShort
-		if scanErr := rows.Scan(&p.ID, &p.Title, &p.AuthorID, &p.Status); scanErr != nil {
+		if scanErr := rows.Scan(
+			&p.ID, &p.Title, &p.AuthorID, &p.Status, &p.Repository, &p.ReviewState,
+		); scanErr != nil {
 			return nil, apperrors.Wrap(scanErr, "failed to scan PR")
 		}
 		prs = append(prs, p)
@@ -121,50 +364,59 @@ func (r *PRRepo) GetPRsForUser(ctx context.Context, userID string) ([]models.Pul
 }
 
 // ExistsPR checks pull requests for existence.
-func (r *PRRepo) ExistsPR(ctx context.Context, id string) (bool, error) {
+func (r *PRRepo) ExistsPR(ctx context.Context, repository, id string) (bool, error) {
 	var exists bool
-	err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM pull_requests WHERE id = $1)`, id).Scan(&exists)
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM pull_requests WHERE repository = $1 AND id = $2)
+	`, repository, id).Scan(&exists)
 	if err != nil {
 		return false, apperrors.Wrap(err, "failed to check PR existence")
 	}
 	return exists, nil
 }
 
-// GetTotalPRs returns the total count of all pull requests.
-func (r *PRRepo) GetTotalPRs(ctx context.Context) (int, error) {
+// GetTotalPRs returns the total count of pull requests. An empty repository counts all repositories.
+func (r *PRRepo) GetTotalPRs(ctx context.Context, repository string) (int, error) {
 	var total int
-	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM pull_requests`).Scan(&total)
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		SELECT COUNT(*) FROM pull_requests WHERE $1 = '' OR repository = $1
+	`, repository).Scan(&total)
 	if err != nil {
 		return 0, apperrors.Wrap(err, "failed to count total PRs")
 	}
 	return total, nil
 }
 
-// GetPrsByStatus returns the count of open and merged pull requests.
-func (r *PRRepo) GetPrsByStatus(ctx context.Context) (int, int, error) {
-	var open, merged int
-	err := r.db.QueryRow(ctx, `
+// GetPrsByStatus returns the count of open, merged and closed pull requests in the repository, or in all of them.
+func (r *PRRepo) GetPrsByStatus(ctx context.Context, repository string) (models.PrsStatus, error) {
+	var status models.PrsStatus
+	err := conn(ctx, r.db).QueryRow(ctx, `
 		SELECT 
 			COUNT(*) FILTER (WHERE status = 'OPEN'),
-			COUNT(*) FILTER (WHERE status = 'MERGED')
+			COUNT(*) FILTER (WHERE status = 'MERGED'),
+			COUNT(*) FILTER (WHERE status = 'CLOSED')
 		FROM pull_requests
-	`).Scan(&open, &merged)
+		WHERE $1 = '' OR repository = $1
+	`, repository).Scan(&status.OpenPRs, &status.MergedPRs, &status.ClosedPRs)
 	if err != nil {
-		return 0, 0, apperrors.Wrap(err, "failed to count PRs by status")
+		return models.PrsStatus{}, apperrors.Wrap(err, "failed to count PRs by status")
 	}
-	return open, merged, nil
+	return status, nil
 }
 
-// GetAssignmentsPerUser returns the number of PR assignments per active user, ordered by count descending.
-func (r *PRRepo) GetAssignmentsPerUser(ctx context.Context) ([]models.UserAssignment, error) {
-	rows, err := r.db.Query(ctx, `
-		SELECT u.id, u.name, COUNT(pr.id) as count
+// GetAssignmentsPerUser returns the number of PR assignments per active user together with
+// their open review limit, ordered by count descending. An empty repository counts all repositories.
+func (r *PRRepo) GetAssignmentsPerUser(ctx context.Context, repository string) ([]models.UserAssignment, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT u.id, u.name, COUNT(pr.id) as count, u.max_open_reviews
 		FROM users u
-		JOIN pull_requests pr ON u.id = ANY(pr.reviewers)
+		JOIN pr_reviewers r ON r.user_id = u.id AND r.removed_at IS NULL
+		JOIN pull_requests pr ON pr.repository = r.repository AND pr.id = r.pr_id
 		WHERE u.is_active = true
-		GROUP BY u.id, u.name
+		  AND ($1 = '' OR pr.repository = $1)
+		GROUP BY u.id, u.name, u.max_open_reviews
 		ORDER BY count DESC
-	`)
+	`, repository)
 	if err != nil {
 		return nil, apperrors.Wrap(err, "failed to count assignments per user")
 	}
@@ -173,7 +425,7 @@ func (r *PRRepo) GetAssignmentsPerUser(ctx context.Context) ([]models.UserAssign
 	var assignments []models.UserAssignment
 	for rows.Next() {
 		var ua models.UserAssignment
-		if scanErr := rows.Scan(&ua.UserID, &ua.Name, &ua.Count); scanErr != nil {
+		if scanErr := rows.Scan(&ua.UserID, &ua.Name, &ua.Count, &ua.MaxOpenReviews); scanErr != nil {
 			return nil, apperrors.Wrap(scanErr, "failed to scan assignment")
 		}
 		assignments = append(assignments, ua)
@@ -184,17 +436,52 @@ func (r *PRRepo) GetAssignmentsPerUser(ctx context.Context) ([]models.UserAssign
 	return assignments, nil
 }
 
-// GetTopReviewers returns the top 5 reviewers by assignment count.
-func (r *PRRepo) GetTopReviewers(ctx context.Context) ([]models.UserAssignment, error) {
-	rows, err := r.db.Query(ctx, `
+// GetOpenReviewLoadByTeam returns the number of OPEN non-draft PRs each active member of the team is reviewing,
+// including members with no reviews, ordered by count ascending.
+func (r *PRRepo) GetOpenReviewLoadByTeam(ctx context.Context, teamName string) ([]models.UserAssignment, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
 		SELECT u.id, u.name, COUNT(pr.id) as count
 		FROM users u
-		JOIN pull_requests pr ON u.id = ANY(pr.reviewers)
-		WHERE u.is_active = true
+		LEFT JOIN pr_reviewers r ON r.user_id = u.id AND r.removed_at IS NULL
+		LEFT JOIN pull_requests pr
+		  ON pr.repository = r.repository AND pr.id = r.pr_id AND pr.status = 'OPEN' AND NOT pr.is_draft
+		WHERE u.team_name = $1
+		  AND u.is_active = true
 		GROUP BY u.id, u.name
+		ORDER BY count ASC
+	`, teamName)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to count open reviews per team member")
+	}
+	defer rows.Close()
+
+	var load []models.UserAssignment
+	for rows.Next() {
+		var ua models.UserAssignment
+		if scanErr := rows.Scan(&ua.UserID, &ua.Name, &ua.Count); scanErr != nil {
+			return nil, apperrors.Wrap(scanErr, "failed to scan review load")
+		}
+		load = append(load, ua)
+	}
+	if scanErr := rows.Err(); scanErr != nil {
+		return nil, apperrors.Wrap(scanErr, "error iterating review load")
+	}
+	return load, nil
+}
+
+// GetTopReviewers returns the top 5 reviewers by assignment count. An empty repository counts all repositories.
+func (r *PRRepo) GetTopReviewers(ctx context.Context, repository string) ([]models.UserAssignment, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT u.id, u.name, COUNT(pr.id) as count, u.max_open_reviews
+		FROM users u
+		JOIN pr_reviewers r ON r.user_id = u.id AND r.removed_at IS NULL
+		JOIN pull_requests pr ON pr.repository = r.repository AND pr.id = r.pr_id
+		WHERE u.is_active = true
+		  AND ($1 = '' OR pr.repository = $1)
+		GROUP BY u.id, u.name, u.max_open_reviews
 		ORDER BY count DESC
 		LIMIT 5
-	`)
+	`, repository)
 	if err != nil {
 		return nil, apperrors.Wrap(err, "failed to get top reviewers")
 	}
@@ -203,7 +490,7 @@ func (r *PRRepo) GetTopReviewers(ctx context.Context) ([]models.UserAssignment,
 	var top []models.UserAssignment
 	for rows.Next() {
 		var ua models.UserAssignment
-		if scanErr := rows.Scan(&ua.UserID, &ua.Name, &ua.Count); scanErr != nil {
+		if scanErr := rows.Scan(&ua.UserID, &ua.Name, &ua.Count, &ua.MaxOpenReviews); scanErr != nil {
 			return nil, apperrors.Wrap(scanErr, "failed to scan top reviewer")
 		}
 		top = append(top, ua)
@@ -215,16 +502,18 @@ func (r *PRRepo) GetTopReviewers(ctx context.Context) ([]models.UserAssignment,
 }
 
 // GetAvgCloseTime returns the average time in seconds to close merged PRs and the count of merged PRs.
-func (r *PRRepo) GetAvgCloseTime(ctx context.Context) (float64, int, error) {
+// An empty repository covers all repositories.
+func (r *PRRepo) GetAvgCloseTime(ctx context.Context, repository string) (float64, int, error) {
 	var avgSeconds float64
 	var count int
-	err := r.db.QueryRow(ctx, `
+	err := conn(ctx, r.db).QueryRow(ctx, `
 		SELECT 
 			AVG(EXTRACT(epoch FROM (merged_at - created_at))),
 			COUNT(*)
 		FROM pull_requests
 		WHERE status = 'MERGED'
-	`).Scan(&avgSeconds, &count)
+		  AND ($1 = '' OR repository = $1)
+	`, repository).Scan(&avgSeconds, &count)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return 0, 0, nil
@@ -234,20 +523,25 @@ func (r *PRRepo) GetAvgCloseTime(ctx context.Context) (float64, int, error) {
 	return avgSeconds, count, nil
 }
 
-// GetIdleUsersPerTeam returns active users with 0 assignments, grouped by team.
-func (r *PRRepo) GetIdleUsersPerTeam(ctx context.Context) ([]models.TeamMetric, error) {
-	rows, err := r.db.Query(ctx, `
+// GetIdleUsersPerTeam returns active users with no reviews on OPEN non-draft PRs, grouped by team.
+// With a repository, only assignments in that repository count.
+func (r *PRRepo) GetIdleUsersPerTeam(ctx context.Context, repository string) ([]models.TeamMetric, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
 		SELECT u.team_name, COUNT(u.id) as count
 		FROM users u
 		WHERE u.is_active = true
 		  AND u.id NOT IN (
-		    SELECT DISTINCT unnest(pr.reviewers)
-		    FROM pull_requests pr
-		    WHERE pr.status = 'OPEN'
+		    SELECT r.user_id
+		    FROM pr_reviewers r
+		    JOIN pull_requests pr ON pr.repository = r.repository AND pr.id = r.pr_id
+		    WHERE r.removed_at IS NULL
+		      AND pr.status = 'OPEN'
+		      AND NOT pr.is_draft
+		      AND ($1 = '' OR pr.repository = $1)
 		  )
 		GROUP BY u.team_name
 		ORDER BY count DESC
-	`)
+	`, repository)
 	if err != nil {
 		return nil, apperrors.Wrap(err, "failed to get idle users per team")
 	}
@@ -267,17 +561,20 @@ func (r *PRRepo) GetIdleUsersPerTeam(ctx context.Context) ([]models.TeamMetric,
 	return metrics, nil
 }
 
-// GetNeedyPRsPerTeam returns OPEN PRs with need_more_reviewers=true, grouped by author's team.
-func (r *PRRepo) GetNeedyPRsPerTeam(ctx context.Context) ([]models.TeamMetric, error) {
-	rows, err := r.db.Query(ctx, `
+// GetNeedyPRsPerTeam returns OPEN non-draft PRs with need_more_reviewers=true, grouped by author's team.
+// An empty repository covers all repositories.
+func (r *PRRepo) GetNeedyPRsPerTeam(ctx context.Context, repository string) ([]models.TeamMetric, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
 		SELECT u.team_name, COUNT(pr.id) as count
 		FROM pull_requests pr
 		JOIN users u ON pr.author_id = u.id
 		WHERE pr.status = 'OPEN'
+		  AND NOT pr.is_draft
 		  AND pr.need_more_reviewers = true
+		  AND ($1 = '' OR pr.repository = $1)
 		GROUP BY u.team_name
 		ORDER BY count DESC
-	`)
+	`, repository)
 	if err != nil {
 		return nil, apperrors.Wrap(err, "failed to get needy PRs per team")
 	}
@@ -299,12 +596,21 @@ func (r *PRRepo) GetNeedyPRsPerTeam(ctx context.Context) ([]models.TeamMetric, e
 
 // GetOpenPRsWithReviewersFromTeam returns all OPEN PRs that have reviewers from the specified team.
 func (r *PRRepo) GetOpenPRsWithReviewersFromTeam(ctx context.Context, teamName string) ([]models.PullRequest, error) {
-	rows, err := r.db.Query(ctx, `
-		SELECT DISTINCT pr.id, pr.title, pr.author_id, pr.status, pr.reviewers, pr.need_more_reviewers, pr.created_at, pr.merged_at
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT pr.repository, pr.id, pr.title, pr.author_id, pr.status,
+			(SELECT array_agg(r.user_id ORDER BY r.position)
+			 FROM pr_reviewers r
+			 WHERE r.repository = pr.repository AND r.pr_id = pr.id AND r.removed_at IS NULL),
+			pr.need_more_reviewers, pr.created_at, pr.merged_at
 		FROM pull_requests pr
-		JOIN users u ON u.id = ANY(pr.reviewers)
 		WHERE pr.status = 'OPEN'
-		  AND u.team_name = $1
+		  AND EXISTS (
+		    SELECT 1
+		    FROM pr_reviewers r
+		    JOIN users u ON u.id = r.user_id
+		    WHERE r.repository = pr.repository AND r.pr_id = pr.id AND r.removed_at IS NULL
+		      AND u.team_name = $1
+		  )
 	`, teamName)
 	if err != nil {
 		return nil, apperrors.Wrap(err, "failed to get open PRs with reviewers from team")
@@ -316,7 +622,10 @@ func (r *PRRepo) GetOpenPRsWithReviewersFromTeam(ctx context.Context, teamName s
 		var pr models.PullRequest
 		var createdAt time.Time
 		var mergedAt *time.Time
-		if scanErr := rows.Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.Reviewers, &pr.NeedMoreReviewers, &createdAt, &mergedAt); scanErr != nil {
+		if scanErr := rows.Scan(
+			&pr.Repository, &pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.Reviewers, &pr.NeedMoreReviewers,
+			&createdAt, &mergedAt,
+		); scanErr != nil {
 			return nil, apperrors.Wrap(scanErr, "failed to scan PR")
 		}
 		pr.CreatedAt = &createdAt
@@ -328,3 +637,54 @@ func (r *PRRepo) GetOpenPRsWithReviewersFromTeam(ctx context.Context, teamName s
 	}
 	return prs, nil
 }
+
+// ClaimStaleReviews returns up to limit pending reviews of open, ready PRs whose reviewer was assigned, or last
+// reminded, at least olderThan ago, oldest first, and marks them reminded now. Reviews claimed by a concurrent
+// call are skipped.
+func (r *PRRepo) ClaimStaleReviews(
+	ctx context.Context,
+	olderThan time.Duration,
+	limit int,
+) ([]models.StaleReview, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		WITH stale AS (
+			SELECT r.id
+			FROM pr_reviewers r
+			JOIN pull_requests pr ON pr.repository = r.repository AND pr.id = r.pr_id
+			WHERE r.removed_at IS NULL AND r.state = 'PENDING'
+			  AND pr.status = 'OPEN' AND NOT pr.is_draft
+			  AND COALESCE(r.reminded_at, r.assigned_at) <= CURRENT_TIMESTAMP - make_interval(secs => $1)
+			ORDER BY r.assigned_at, r.id
+			LIMIT $2
+			FOR UPDATE OF r SKIP LOCKED
+		)
+		UPDATE pr_reviewers r
+		SET reminded_at = CURRENT_TIMESTAMP
+		FROM stale, pull_requests pr
+		WHERE r.id = stale.id AND pr.repository = r.repository AND pr.id = r.pr_id
+		RETURNING pr.repository, pr.id, pr.title, pr.author_id, pr.status, r.user_id, r.assigned_at
+	`, olderThan.Seconds(), limit)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to claim stale reviews")
+	}
+	defer rows.Close()
+
+	reviews := []models.StaleReview{}
+	for rows.Next() {
+		var review models.StaleReview
+		pr := &review.PullRequest
+		if scanErr := rows.Scan(
+			&pr.Repository, &pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &review.ReviewerID, &review.AssignedAt,
+		); scanErr != nil {
+			return nil, apperrors.Wrap(scanErr, "failed to scan stale review")
+		}
+		reviews = append(reviews, review)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Wrap(err, "error iterating stale reviews")
+	}
+	slices.SortFunc(reviews, func(a, b models.StaleReview) int {
+		return a.AssignedAt.Compare(b.AssignedAt)
+	})
+	return reviews, nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+)
+
+type RepositoryRepo struct {
+	db *pgxpool.Pool
+}
+
+var _ RepositoryRepository = (*RepositoryRepo)(nil)
+
+func NewRepositoryRepo(db *pgxpool.Pool) *RepositoryRepo {
+	return &RepositoryRepo{db: db}
+}
+
+// GetRepository gets repository configuration by name.
+func (r *RepositoryRepo) GetRepository(ctx context.Context, name string) (*models.Repository, error) {
+	repo := &models.Repository{}
+	var defaultTeamName *string
+	var reviewersCount, requiredApprovals *int
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		SELECT name, codeowners, codeowners_mode, default_team_name, reviewers_count, required_approvals
+		FROM repositories WHERE name = $1
+	`, name).Scan(
+		&repo.Name, &repo.CodeOwners, &repo.CodeOwnersMode, &defaultTeamName, &reviewersCount, &requiredApprovals,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, apperrors.Wrap(err, "failed to query repository")
+	}
+	if defaultTeamName != nil || reviewersCount != nil || requiredApprovals != nil {
+		repo.Settings = &models.RepositorySettings{
+			DefaultTeamName:   defaultTeamName,
+			ReviewersCount:    reviewersCount,
+			RequiredApprovals: requiredApprovals,
+		}
+	}
+	return repo, nil
+}
+
+// SetCodeOwners stores the repository's CODEOWNERS file, creating the repository if needed.
+func (r *RepositoryRepo) SetCodeOwners(ctx context.Context, name, content, mode string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		INSERT INTO repositories (name, codeowners, codeowners_mode)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE
+		SET codeowners = EXCLUDED.codeowners,
+			codeowners_mode = EXCLUDED.codeowners_mode,
+			updated_at = CURRENT_TIMESTAMP
+	`, name, content, mode)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to store codeowners")
+	}
+	return nil
+}
+
+// UpdateRepositorySettings stores repository settings, creating the repository if needed, and re-evaluates
+// need_more_reviewers on the repository's open PRs against the new reviewer target. defaultReviewers applies
+// when neither the repository nor the team reviewers are drawn from has its own reviewer count.
+func (r *RepositoryRepo) UpdateRepositorySettings(
+	ctx context.Context,
+	name string,
+	settings *models.RepositorySettings,
+	defaultReviewers int,
+) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to begin tx")
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	if settings.DefaultTeamName != nil {
+		var exists bool
+		err = tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM teams WHERE name = $1)`, *settings.DefaultTeamName).
+			Scan(&exists)
+		if err != nil {
+			return apperrors.Wrap(err, "failed to check team existence")
+		}
+		if !exists {
+			err = apperrors.ErrNotFound
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO repositories (name, default_team_name, reviewers_count, required_approvals)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (name) DO UPDATE
+		SET default_team_name = EXCLUDED.default_team_name,
+			reviewers_count = EXCLUDED.reviewers_count,
+			required_approvals = EXCLUDED.required_approvals,
+			updated_at = CURRENT_TIMESTAMP
+	`, name, settings.DefaultTeamName, settings.ReviewersCount, settings.RequiredApprovals)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to store repository settings")
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE pull_requests pr
+		SET need_more_reviewers = (
+			SELECT COUNT(*) FROM pr_reviewers rv
+			WHERE rv.repository = pr.repository AND rv.pr_id = pr.id AND rv.removed_at IS NULL
+		) < COALESCE($2::int, t.reviewers_count, $4::int)
+		FROM users u
+		LEFT JOIN teams t ON t.name = COALESCE($3::text, u.team_name)
+		WHERE pr.author_id = u.id
+		  AND pr.repository = $1
+		  AND pr.status = 'OPEN'
+		  AND NOT pr.is_draft
+	`, name, settings.ReviewersCount, settings.DefaultTeamName, defaultReviewers)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to refresh need_more_reviewers")
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+)
+
+type RoleRepo struct {
+	db *pgxpool.Pool
+}
+
+var _ RoleRepository = (*RoleRepo)(nil)
+
+func NewRoleRepo(db *pgxpool.Pool) *RoleRepo {
+	return &RoleRepo{db: db}
+}
+
+// CreateRoleBinding stores a binding and fills in its ID and creation time. An empty TeamName binds the
+// role globally. Returns ErrRoleExists when the same binding is already there.
+func (r *RoleRepo) CreateRoleBinding(ctx context.Context, binding *models.RoleBinding) error {
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		INSERT INTO role_bindings (user_id, role, team_name)
+		VALUES ($1, $2, NULLIF($3, ''))
+		ON CONFLICT (user_id, role, COALESCE(team_name, '')) DO NOTHING
+		RETURNING id, created_at
+	`, binding.UserID, binding.Role, binding.TeamName).Scan(&binding.ID, &binding.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperrors.ErrRoleExists
+		}
+		return apperrors.Wrap(err, "failed to insert role binding")
+	}
+	return nil
+}
+
+// DeleteRoleBinding removes the binding of role to userID in teamName, or the global one when teamName is empty.
+func (r *RoleRepo) DeleteRoleBinding(ctx context.Context, userID, role, teamName string) error {
+	tag, err := conn(ctx, r.db).Exec(ctx, `
+		DELETE FROM role_bindings
+		WHERE user_id = $1 AND role = $2 AND COALESCE(team_name, '') = $3
+	`, userID, role, teamName)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to delete role binding")
+	}
+	if tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// ListRoleBindings returns the bindings of userID, or of everyone when userID is empty.
+func (r *RoleRepo) ListRoleBindings(ctx context.Context, userID string) ([]models.RoleBinding, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT id, user_id, role, COALESCE(team_name, ''), created_at
+		FROM role_bindings
+		WHERE $1 = '' OR user_id = $1
+		ORDER BY user_id, id
+	`, userID)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to query role bindings")
+	}
+	defer rows.Close()
+
+	bindings := []models.RoleBinding{}
+	for rows.Next() {
+		var b models.RoleBinding
+		if scanErr := rows.Scan(&b.ID, &b.UserID, &b.Role, &b.TeamName, &b.CreatedAt); scanErr != nil {
+			return nil, apperrors.Wrap(scanErr, "failed to scan role binding")
+		}
+		bindings = append(bindings, b)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Wrap(err, "error iterating role bindings")
+	}
+	return bindings, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+)
+
+type SlackRepo struct {
+	db *pgxpool.Pool
+}
+
+var _ SlackRepository = (*SlackRepo)(nil)
+
+func NewSlackRepo(db *pgxpool.Pool) *SlackRepo {
+	return &SlackRepo{db: db}
+}
+
+// SetTeamChannel sets the webhook path of the team's channel, replacing the one it had.
+func (r *SlackRepo) SetTeamChannel(ctx context.Context, teamName, webhookPath string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		INSERT INTO team_slack_channels (team_name, webhook_path)
+		VALUES ($1, $2)
+		ON CONFLICT (team_name) DO UPDATE SET webhook_path = EXCLUDED.webhook_path, updated_at = CURRENT_TIMESTAMP
+	`, teamName, webhookPath)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to set team slack channel")
+	}
+	return nil
+}
+
+// DeleteTeamChannel removes the team's channel. A team without one is left as is.
+func (r *SlackRepo) DeleteTeamChannel(ctx context.Context, teamName string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM team_slack_channels WHERE team_name = $1`, teamName)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to delete team slack channel")
+	}
+	return nil
+}
+
+// GetTeamChannel returns the webhook path of the team's channel.
+func (r *SlackRepo) GetTeamChannel(ctx context.Context, teamName string) (string, error) {
+	var path string
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		SELECT webhook_path FROM team_slack_channels WHERE team_name = $1
+	`, teamName).Scan(&path)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", apperrors.ErrNotFound
+		}
+		return "", apperrors.Wrap(err, "failed to query team slack channel")
+	}
+	return path, nil
+}
+
+// SetUserHandle sets the Slack member ID the user is mentioned by.
+func (r *SlackRepo) SetUserHandle(ctx context.Context, userID, handle string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		INSERT INTO user_slack_handles (user_id, handle)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET handle = EXCLUDED.handle
+	`, userID, handle)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to set user slack handle")
+	}
+	return nil
+}
+
+// DeleteUserHandle removes the user's Slack member ID. A user without one is left as is.
+func (r *SlackRepo) DeleteUserHandle(ctx context.Context, userID string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM user_slack_handles WHERE user_id = $1`, userID)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to delete user slack handle")
+	}
+	return nil
+}
+
+// GetUserHandles returns the Slack member IDs of those of userIDs that have one, by user ID.
+func (r *SlackRepo) GetUserHandles(ctx context.Context, userIDs []string) (map[string]string, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT user_id, handle FROM user_slack_handles WHERE user_id = ANY($1)
+	`, userIDs)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to query user slack handles")
+	}
+	defer rows.Close()
+
+	handles := make(map[string]string, len(userIDs))
+	for rows.Next() {
+		var userID, handle string
+		if scanErr := rows.Scan(&userID, &handle); scanErr != nil {
+			return nil, apperrors.Wrap(scanErr, "failed to scan user slack handle")
+		}
+		handles[userID] = handle
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Wrap(err, "error iterating user slack handles")
+	}
+	return handles, nil
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+)
+
+type SubscriptionRepo struct {
+	db *pgxpool.Pool
+}
+
+var _ SubscriptionRepository = (*SubscriptionRepo)(nil)
+
+func NewSubscriptionRepo(db *pgxpool.Pool) *SubscriptionRepo {
+	return &SubscriptionRepo{db: db}
+}
+
+// CreateSubscription stores sub and sets its generated ID and time.
+func (r *SubscriptionRepo) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		INSERT INTO webhook_subscriptions (url, secret, event_types)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`, sub.URL, sub.Secret, sub.EventTypes).Scan(&sub.ID, &sub.CreatedAt)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to create subscription")
+	}
+	return nil
+}
+
+// DeleteSubscription removes the subscription together with its deliveries.
+func (r *SubscriptionRepo) DeleteSubscription(ctx context.Context, id int64) error {
+	tag, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to delete subscription")
+	}
+	if tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// GetSubscription returns the subscription without its secret.
+func (r *SubscriptionRepo) GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	sub := &models.WebhookSubscription{}
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		SELECT id, url, event_types, created_at FROM webhook_subscriptions WHERE id = $1
+	`, id).Scan(&sub.ID, &sub.URL, &sub.EventTypes, &sub.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, apperrors.Wrap(err, "failed to query subscription")
+	}
+	return sub, nil
+}
+
+// ListSubscriptions returns the subscriptions subscribed to eventType, or all of them when it is empty,
+// without their secrets.
+func (r *SubscriptionRepo) ListSubscriptions(
+	ctx context.Context,
+	eventType string,
+) ([]models.WebhookSubscription, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT id, url, event_types, created_at
+		FROM webhook_subscriptions
+		WHERE $1 = '' OR $1 = ANY(event_types)
+		ORDER BY id
+	`, eventType)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to query subscriptions")
+	}
+	defer rows.Close()
+
+	subs := []models.WebhookSubscription{}
+	for rows.Next() {
+		var s models.WebhookSubscription
+		if scanErr := rows.Scan(&s.ID, &s.URL, &s.EventTypes, &s.CreatedAt); scanErr != nil {
+			return nil, apperrors.Wrap(scanErr, "failed to scan subscription")
+		}
+		subs = append(subs, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Wrap(err, "error iterating subscriptions")
+	}
+	return subs, nil
+}
+
+// CreateDelivery stores a pending delivery, due right away, and sets its generated ID and time. When the
+// event is already queued for the subscription the existing delivery is kept and delivery.ID stays zero.
+func (r *SubscriptionRepo) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+		RETURNING id, status, created_at, next_attempt_at
+	`, delivery.SubscriptionID, delivery.EventID, delivery.EventType, []byte(delivery.Payload),
+	).Scan(&delivery.ID, &delivery.Status, &delivery.CreatedAt, &delivery.NextAttemptAt)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return apperrors.Wrap(err, "failed to create delivery")
+	}
+	return nil
+}
+
+// ClaimDueDeliveries claims up to limit pending deliveries whose next attempt is due, those waiting longest
+// first, and returns them with the URL and secret of their subscription. A claimed delivery is leased:
+// it is not due again for lease, so concurrent workers skip it, and comes back if its attempt is never
+// recorded.
+func (r *SubscriptionRepo) ClaimDueDeliveries(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]models.WebhookDelivery, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		WITH due AS (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'PENDING' AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY next_attempt_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+		FROM due, webhook_subscriptions s
+		WHERE d.id = due.id AND s.id = d.subscription_id
+		RETURNING d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+			d.next_attempt_at, d.created_at, s.url, s.secret
+	`, limit, lease.Seconds())
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to claim due deliveries")
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var d models.WebhookDelivery
+		var payload []byte
+		if scanErr := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &payload, &d.Status,
+			&d.Attempts, &d.NextAttemptAt, &d.CreatedAt, &d.URL, &d.Secret); scanErr != nil {
+			return nil, apperrors.Wrap(scanErr, "failed to scan delivery")
+		}
+		d.Payload = payload
+		deliveries = append(deliveries, d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Wrap(err, "error iterating deliveries")
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt stores attempt and saves the delivery's new status and attempt count. A delivery still
+// pending is due again retryAfter from now.
+func (r *SubscriptionRepo) RecordAttempt(
+	ctx context.Context,
+	delivery *models.WebhookDelivery,
+	attempt *models.DeliveryAttempt,
+	retryAfter time.Duration,
+) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to begin tx")
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	var statusCode *int
+	if attempt.StatusCode != 0 {
+		statusCode = &attempt.StatusCode
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO webhook_delivery_attempts (delivery_id, attempt, status_code, error, duration_ms)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING attempted_at
+	`, delivery.ID, attempt.Attempt, statusCode, attempt.Error, attempt.DurationMS).Scan(&attempt.AttemptedAt)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to record delivery attempt")
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2,
+			attempts = $3,
+			next_attempt_at = CASE WHEN $2 = 'PENDING' THEN CURRENT_TIMESTAMP + make_interval(secs => $4) END,
+			delivered_at = CASE WHEN $2 = 'DELIVERED' THEN CURRENT_TIMESTAMP END
+		WHERE id = $1
+		RETURNING next_attempt_at, delivered_at
+	`, delivery.ID, delivery.Status, delivery.Attempts, retryAfter.Seconds(),
+	).Scan(&delivery.NextAttemptAt, &delivery.DeliveredAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperrors.ErrNotFound
+		}
+		return apperrors.Wrap(err, "failed to update delivery")
+	}
+	return nil
+}
+
+// ListDeliveries returns the latest limit deliveries of the subscription, newest first, with their attempts.
+func (r *SubscriptionRepo) ListDeliveries(
+	ctx context.Context,
+	subscriptionID int64,
+	limit int,
+) ([]models.WebhookDelivery, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT id, subscription_id, event_id, event_type, payload, status, attempts,
+			next_attempt_at, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY id DESC
+		LIMIT $2
+	`, subscriptionID, limit)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to query deliveries")
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	index := make(map[int64]int)
+	ids := []int64{}
+	for rows.Next() {
+		var d models.WebhookDelivery
+		var payload []byte
+		if scanErr := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &payload, &d.Status,
+			&d.Attempts, &d.NextAttemptAt, &d.CreatedAt, &d.DeliveredAt); scanErr != nil {
+			return nil, apperrors.Wrap(scanErr, "failed to scan delivery")
+		}
+		d.Payload = payload
+		d.AttemptLog = []models.DeliveryAttempt{}
+		index[d.ID] = len(deliveries)
+		ids = append(ids, d.ID)
+		deliveries = append(deliveries, d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Wrap(err, "error iterating deliveries")
+	}
+	rows.Close()
+
+	attemptRows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT delivery_id, attempt, COALESCE(status_code, 0), error, duration_ms, attempted_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = ANY($1)
+		ORDER BY delivery_id, attempt
+	`, ids)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to query delivery attempts")
+	}
+	defer attemptRows.Close()
+
+	for attemptRows.Next() {
+		var deliveryID int64
+		var a models.DeliveryAttempt
+		if scanErr := attemptRows.Scan(
+			&deliveryID, &a.Attempt, &a.StatusCode, &a.Error, &a.DurationMS, &a.AttemptedAt,
+		); scanErr != nil {
+			return nil, apperrors.Wrap(scanErr, "failed to scan delivery attempt")
+		}
+		d := &deliveries[index[deliveryID]]
+		d.AttemptLog = append(d.AttemptLog, a)
+	}
+	if err = attemptRows.Err(); err != nil {
+		return nil, apperrors.Wrap(err, "error iterating delivery attempts")
+	}
+	return deliveries, nil
+}
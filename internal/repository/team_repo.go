@@ -23,7 +23,7 @@ func NewTeamRepo(db *pgxpool.Pool) *TeamRepo {
 
 // CreateTeam creates team.
 func (r *TeamRepo) CreateTeam(ctx context.Context, team *models.Team) error {
-	tx, err := r.db.Begin(ctx)
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
 		return apperrors.Wrap(err, "failed to begin tx")
 	}
@@ -44,7 +44,14 @@ func (r *TeamRepo) CreateTeam(ctx context.Context, team *models.Team) error {
 		return apperrors.ErrTeamExists // ← Early return, no upsert
 	}
 
-	_, err = tx.Exec(ctx, `INSERT INTO teams (name) VALUES ($1)`, team.Name)
+	var reviewersCount, requiredApprovals *int
+	if team.Settings != nil {
+		reviewersCount = team.Settings.ReviewersCount
+		requiredApprovals = team.Settings.RequiredApprovals
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO teams (name, reviewers_count, required_approvals) VALUES ($1, $2, $3)`,
+		team.Name, reviewersCount, requiredApprovals)
 	if err != nil {
 		return apperrors.Wrap(err, "failed to insert team")
 	}
@@ -67,15 +74,21 @@ func (r *TeamRepo) CreateTeam(ctx context.Context, team *models.Team) error {
 func (r *TeamRepo) GetTeamByName(ctx context.Context, name string) (*models.Team, error) {
 	team := &models.Team{Name: name}
 
-	err := r.db.QueryRow(ctx, `SELECT name FROM teams WHERE name = $1`, name).Scan(&team.Name)
+	var reviewersCount, requiredApprovals *int
+	err := conn(ctx, r.db).
+		QueryRow(ctx, `SELECT name, reviewers_count, required_approvals FROM teams WHERE name = $1`, name).
+		Scan(&team.Name, &reviewersCount, &requiredApprovals)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperrors.ErrNotFound
 		}
 		return nil, apperrors.Wrap(err, "failed to query team")
 	}
+	if reviewersCount != nil || requiredApprovals != nil {
+		team.Settings = &models.TeamSettings{ReviewersCount: reviewersCount, RequiredApprovals: requiredApprovals}
+	}
 
-	rows, err := r.db.Query(ctx, `SELECT id, name, is_active FROM users WHERE team_name = $1`, name)
+	rows, err := conn(ctx, r.db).Query(ctx, `SELECT id, name, is_active FROM users WHERE team_name = $1`, name)
 	if err != nil {
 		return nil, apperrors.Wrap(err, "failed to query members")
 	}
@@ -93,5 +106,152 @@ func (r *TeamRepo) GetTeamByName(ctx context.Context, name string) (*models.Team
 		return nil, apperrors.Wrap(scanErr, "error iterating members")
 	}
 
+	team.FallbackTeams, err = r.GetFallbackTeams(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
 	return team, nil
 }
+
+// GetTeamSettings gets per-team settings by team name.
+func (r *TeamRepo) GetTeamSettings(ctx context.Context, name string) (*models.TeamSettings, error) {
+	settings := &models.TeamSettings{}
+	err := conn(ctx, r.db).QueryRow(ctx, `SELECT reviewers_count, required_approvals FROM teams WHERE name = $1`, name).
+		Scan(&settings.ReviewersCount, &settings.RequiredApprovals)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, apperrors.Wrap(err, "failed to query team settings")
+	}
+	return settings, nil
+}
+
+// UpdateTeamSettings stores team settings and re-evaluates need_more_reviewers on the team's open PRs
+// against the new reviewer target. defaultReviewers applies when the team has no own reviewer count.
+// PRs of a repository that draws reviewers from another team or has its own reviewer count are skipped.
+func (r *TeamRepo) UpdateTeamSettings(
+	ctx context.Context,
+	name string,
+	settings *models.TeamSettings,
+	defaultReviewers int,
+) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to begin tx")
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	res, err := tx.Exec(ctx, `UPDATE teams SET reviewers_count = $2, required_approvals = $3 WHERE name = $1`,
+		name, settings.ReviewersCount, settings.RequiredApprovals)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to update team settings")
+	}
+	if res.RowsAffected() == 0 {
+		err = apperrors.ErrNotFound
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE pull_requests pr
+		SET need_more_reviewers = (
+			SELECT COUNT(*) FROM pr_reviewers rv
+			WHERE rv.repository = pr.repository AND rv.pr_id = pr.id AND rv.removed_at IS NULL
+		) < COALESCE($2::int, $3::int)
+		FROM users u
+		WHERE pr.author_id = u.id
+		  AND pr.status = 'OPEN'
+		  AND NOT pr.is_draft
+		  AND COALESCE(
+		    (SELECT r.default_team_name FROM repositories r WHERE r.name = pr.repository),
+		    u.team_name
+		  ) = $1
+		  AND NOT EXISTS (
+		    SELECT 1 FROM repositories r
+		    WHERE r.name = pr.repository AND r.reviewers_count IS NOT NULL
+		  )
+	`, name, settings.ReviewersCount, defaultReviewers)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to refresh need_more_reviewers")
+	}
+
+	return nil
+}
+
+// GetFallbackTeams returns the team's fallback teams in the order they should be tried.
+func (r *TeamRepo) GetFallbackTeams(ctx context.Context, name string) ([]string, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT fallback_team_name
+		FROM team_fallbacks
+		WHERE team_name = $1
+		ORDER BY position
+	`, name)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to query fallback teams")
+	}
+	defer rows.Close()
+
+	fallbackTeams := []string{}
+	for rows.Next() {
+		var fallback string
+		if scanErr := rows.Scan(&fallback); scanErr != nil {
+			return nil, apperrors.Wrap(scanErr, "failed to scan fallback team")
+		}
+		fallbackTeams = append(fallbackTeams, fallback)
+	}
+	if scanErr := rows.Err(); scanErr != nil {
+		return nil, apperrors.Wrap(scanErr, "error iterating fallback teams")
+	}
+	return fallbackTeams, nil
+}
+
+// SetFallbackTeams replaces the team's ordered fallback list. It returns ErrNotFound when the team
+// or any of the fallback teams does not exist.
+func (r *TeamRepo) SetFallbackTeams(ctx context.Context, name string, fallbackTeams []string) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to begin tx")
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	var found int
+	err = tx.QueryRow(ctx, `
+		SELECT count(*) FROM teams WHERE name = $1 OR name = ANY($2::text[])
+	`, name, fallbackTeams).Scan(&found)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to check teams existence")
+	}
+	if found != len(fallbackTeams)+1 {
+		err = apperrors.ErrNotFound
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM team_fallbacks WHERE team_name = $1`, name)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to clear fallback teams")
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO team_fallbacks (team_name, fallback_team_name, position)
+		SELECT $1, f.name, f.position
+		FROM unnest($2::text[]) WITH ORDINALITY AS f(name, position)
+	`, name, fallbackTeams)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to insert fallback teams")
+	}
+
+	return nil
+}
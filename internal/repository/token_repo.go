@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+)
+
+type TokenRepo struct {
+	db *pgxpool.Pool
+}
+
+var _ TokenRepository = (*TokenRepo)(nil)
+
+func NewTokenRepo(db *pgxpool.Pool) *TokenRepo {
+	return &TokenRepo{db: db}
+}
+
+// CreateToken stores a token under its hash and fills in its ID and creation time.
+// Returns ErrTokenExists when an active token already has the name.
+func (r *TokenRepo) CreateToken(ctx context.Context, token *models.APIToken, hash string) error {
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		INSERT INTO api_tokens (name, user_id, token_hash)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (name) WHERE revoked_at IS NULL DO NOTHING
+		RETURNING id, created_at
+	`, token.Name, token.UserID, hash).Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperrors.ErrTokenExists
+		}
+		return apperrors.Wrap(err, "failed to insert token")
+	}
+	return nil
+}
+
+// GetTokenByHash finds the active token with the given hash.
+func (r *TokenRepo) GetTokenByHash(ctx context.Context, hash string) (*models.APIToken, error) {
+	token := &models.APIToken{}
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		SELECT id, name, user_id, created_at
+		FROM api_tokens
+		WHERE token_hash = $1 AND revoked_at IS NULL
+	`, hash).Scan(&token.ID, &token.Name, &token.UserID, &token.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, apperrors.Wrap(err, "failed to query token")
+	}
+	return token, nil
+}
+
+// ListTokens returns all tokens, revoked ones included, oldest first.
+func (r *TokenRepo) ListTokens(ctx context.Context) ([]models.APIToken, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT id, name, user_id, created_at, revoked_at
+		FROM api_tokens
+		ORDER BY created_at, id
+	`)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to query tokens")
+	}
+	defer rows.Close()
+
+	tokens := []models.APIToken{}
+	for rows.Next() {
+		var t models.APIToken
+		if scanErr := rows.Scan(&t.ID, &t.Name, &t.UserID, &t.CreatedAt, &t.RevokedAt); scanErr != nil {
+			return nil, apperrors.Wrap(scanErr, "failed to scan token")
+		}
+		tokens = append(tokens, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Wrap(err, "error iterating tokens")
+	}
+	return tokens, nil
+}
+
+// RevokeToken revokes the active token with the given name.
+func (r *TokenRepo) RevokeToken(ctx context.Context, name string) error {
+	tag, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE api_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE name = $1 AND revoked_at IS NULL
+	`, name)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to revoke token")
+	}
+	if tag.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
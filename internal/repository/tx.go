@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+)
+
+// DBTX is what repositories query through: the pool, or the transaction started by TxManager.
+type DBTX interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type txKey struct{}
+
+// TxManager runs several repository calls in one database transaction.
+type TxManager struct {
+	db *pgxpool.Pool
+}
+
+var _ Transactor = (*TxManager)(nil)
+
+func NewTxManager(db *pgxpool.Pool) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTx calls fn with a context carrying a transaction, committing it when fn succeeds and rolling it back
+// otherwise. Repositories called with that context run in the transaction; their own transactions become
+// savepoints. Called inside another WithinTx, fn joins the outer transaction.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to begin tx")
+	}
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return apperrors.Wrap(err, "failed to commit tx")
+	}
+	return nil
+}
+
+// WithinSavepoint calls fn in a savepoint of the transaction carried by ctx and rolls back to it when fn
+// fails, so that the caller can go on with the transaction. Outside of WithinTx it works like WithinTx.
+func (m *TxManager) WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	if !ok {
+		return m.WithinTx(ctx, fn)
+	}
+
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to create savepoint")
+	}
+	if err = fn(context.WithValue(ctx, txKey{}, savepoint)); err != nil {
+		_ = savepoint.Rollback(ctx)
+		return err
+	}
+	if err = savepoint.Commit(ctx); err != nil {
+		return apperrors.Wrap(err, "failed to release savepoint")
+	}
+	return nil
+}
+
+// conn returns the transaction carried by ctx, or db outside of WithinTx.
+func conn(ctx context.Context, db *pgxpool.Pool) DBTX {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return db
+}
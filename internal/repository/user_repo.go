@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -23,7 +24,7 @@ func NewUserRepo(db *pgxpool.Pool) *UserRepo {
 
 // UpsertUser creates or updates a user.
 func (r *UserRepo) UpsertUser(ctx context.Context, user *models.User) error {
-	_, err := r.db.Exec(ctx, `
+	_, err := conn(ctx, r.db).Exec(ctx, `
 		INSERT INTO users (id, name, team_name, is_active)
 		VALUES ($1, $2, $3, $4)
 		ON CONFLICT (id) DO UPDATE SET name = $2, team_name = $3, is_active = $4
@@ -37,9 +38,9 @@ func (r *UserRepo) UpsertUser(ctx context.Context, user *models.User) error {
 // GetUserByID gets a user by ID.
 func (r *UserRepo) GetUserByID(ctx context.Context, id string) (*models.User, error) {
 	u := &models.User{}
-	err := r.db.QueryRow(ctx,
-		`SELECT id, name, team_name, is_active FROM users WHERE id = $1`,
-		id).Scan(&u.ID, &u.Name, &u.TeamName, &u.IsActive)
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		SELECT id, name, team_name, is_active, max_open_reviews, email, email_digest FROM users WHERE id = $1
+	`, id).Scan(&u.ID, &u.Name, &u.TeamName, &u.IsActive, &u.MaxOpenReviews, &u.Email, &u.EmailDigest)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperrors.ErrNotFound
@@ -51,7 +52,7 @@ func (r *UserRepo) GetUserByID(ctx context.Context, id string) (*models.User, er
 
 // UpdateUserActive updates the active status of a user.
 func (r *UserRepo) UpdateUserActive(ctx context.Context, id string, isActive bool) error {
-	res, err := r.db.Exec(ctx, `UPDATE users SET is_active = $2 WHERE id = $1`, id, isActive)
+	res, err := conn(ctx, r.db).Exec(ctx, `UPDATE users SET is_active = $2 WHERE id = $1`, id, isActive)
 	if err != nil {
 		return apperrors.Wrap(err, "failed to update user active")
 	}
@@ -61,10 +62,35 @@ func (r *UserRepo) UpdateUserActive(ctx context.Context, id string, isActive boo
 	return nil
 }
 
+// UpdateUserMaxOpenReviews sets the limit of concurrent open reviews for a user; nil removes the limit.
+func (r *UserRepo) UpdateUserMaxOpenReviews(ctx context.Context, id string, limit *int) error {
+	res, err := conn(ctx, r.db).Exec(ctx, `UPDATE users SET max_open_reviews = $2 WHERE id = $1`, id, limit)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to update user max open reviews")
+	}
+	if res.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateUserEmail sets the email address of a user and whether they get the daily digest.
+func (r *UserRepo) UpdateUserEmail(ctx context.Context, id, email string, digest bool) error {
+	res, err := conn(ctx, r.db).Exec(ctx, `UPDATE users SET email = $2, email_digest = $3 WHERE id = $1`,
+		id, email, digest)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to update user email")
+	}
+	if res.RowsAffected() == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
 // GetActiveUsersByTeam gets all active users for a team.
 func (r *UserRepo) GetActiveUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
-	rows, err := r.db.Query(ctx, `
-		SELECT id, name, team_name, is_active FROM users 
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT id, name, team_name, is_active, max_open_reviews, email, email_digest FROM users 
 		WHERE team_name = $1 AND is_active = true
 	`, teamName)
 	if err != nil {
@@ -75,7 +101,40 @@ func (r *UserRepo) GetActiveUsersByTeam(ctx context.Context, teamName string) ([
 	var users []models.User
 	for rows.Next() {
 		var u models.User
-		if scanErr := rows.Scan(&u.ID, &u.Name, &u.TeamName, &u.IsActive); scanErr != nil {
+		if scanErr := rows.Scan(
+			&u.ID, &u.Name, &u.TeamName, &u.IsActive, &u.MaxOpenReviews, &u.Email, &u.EmailDigest,
+		); scanErr != nil {
+			return nil, apperrors.Wrap(scanErr, "failed to scan user")
+		}
+		users = append(users, u)
+	}
+	if scanErr := rows.Err(); scanErr != nil {
+		return nil, apperrors.Wrap(scanErr, "error iterating users")
+	}
+	return users, nil
+}
+
+// GetAvailableUsersByTeam gets active users of a team who are not inside an absence window right now.
+func (r *UserRepo) GetAvailableUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT u.id, u.name, u.team_name, u.is_active, u.max_open_reviews, u.email, u.email_digest FROM users u
+		WHERE u.team_name = $1 AND u.is_active = true
+		  AND NOT EXISTS (
+			SELECT 1 FROM user_absences a
+			WHERE a.user_id = u.id AND a.starts_at <= now() AND a.ends_at > now()
+		  )
+	`, teamName)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to query available users")
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var u models.User
+		if scanErr := rows.Scan(
+			&u.ID, &u.Name, &u.TeamName, &u.IsActive, &u.MaxOpenReviews, &u.Email, &u.EmailDigest,
+		); scanErr != nil {
 			return nil, apperrors.Wrap(scanErr, "failed to scan user")
 		}
 		users = append(users, u)
@@ -89,7 +148,7 @@ func (r *UserRepo) GetActiveUsersByTeam(ctx context.Context, teamName string) ([
 // GetTeamNameByUserID gets the team name for a user by user ID.
 func (r *UserRepo) GetTeamNameByUserID(ctx context.Context, userID string) (string, error) {
 	var teamName string
-	err := r.db.QueryRow(ctx, `SELECT team_name FROM users WHERE id = $1`, userID).Scan(&teamName)
+	err := conn(ctx, r.db).QueryRow(ctx, `SELECT team_name FROM users WHERE id = $1`, userID).Scan(&teamName)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return "", apperrors.ErrNotFound
@@ -101,7 +160,7 @@ func (r *UserRepo) GetTeamNameByUserID(ctx context.Context, userID string) (stri
 
 // DeactivateUsersByTeam deactivates all users in a team.
 func (r *UserRepo) DeactivateUsersByTeam(ctx context.Context, teamName string) error {
-	tx, err := r.db.Begin(ctx)
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
 		return apperrors.Wrap(err, "failed to begin tx")
 	}
@@ -120,3 +179,42 @@ func (r *UserRepo) DeactivateUsersByTeam(ctx context.Context, teamName string) e
 
 	return nil
 }
+
+// ClaimDigestRecipients returns up to limit active users who opted in to the digest and were not sent the
+// digest of slot yet, and marks them sent it. Users claimed by a concurrent call are skipped.
+func (r *UserRepo) ClaimDigestRecipients(ctx context.Context, slot time.Time, limit int) ([]models.User, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		WITH due AS (
+			SELECT id FROM users
+			WHERE email_digest AND email <> '' AND is_active
+			  AND (last_digest_at IS NULL OR last_digest_at < $1)
+			ORDER BY id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE users u
+		SET last_digest_at = $1
+		FROM due
+		WHERE u.id = due.id
+		RETURNING u.id, u.name, u.team_name, u.is_active, u.max_open_reviews, u.email, u.email_digest
+	`, slot, limit)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to claim digest recipients")
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var u models.User
+		if scanErr := rows.Scan(
+			&u.ID, &u.Name, &u.TeamName, &u.IsActive, &u.MaxOpenReviews, &u.Email, &u.EmailDigest,
+		); scanErr != nil {
+			return nil, apperrors.Wrap(scanErr, "failed to scan user")
+		}
+		users = append(users, u)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.Wrap(err, "error iterating digest recipients")
+	}
+	return users, nil
+}
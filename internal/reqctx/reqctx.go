@@ -0,0 +1,49 @@
+// Package reqctx carries request-scoped values through context.Context.
+package reqctx
+
+import "context"
+
+// SystemActor is the actor of work not started by an API caller, such as background jobs.
+const SystemActor = "system"
+
+type (
+	actorKey      struct{}
+	onBehalfOfKey struct{}
+	requestIDKey  struct{}
+)
+
+// WithActor returns a copy of ctx that records who made the request.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// Actor returns who made the request, or SystemActor when ctx carries no actor.
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
+
+// WithOnBehalfOf returns a copy of ctx that records whom the caller says it acts for. The value is not
+// verified and never replaces the actor.
+func WithOnBehalfOf(ctx context.Context, onBehalfOf string) context.Context {
+	return context.WithValue(ctx, onBehalfOfKey{}, onBehalfOf)
+}
+
+// OnBehalfOf returns whom the caller says it acts for, or "" when it did not say.
+func OnBehalfOf(ctx context.Context) string {
+	onBehalfOf, _ := ctx.Value(onBehalfOfKey{}).(string)
+	return onBehalfOf
+}
+
+// WithRequestID returns a copy of ctx that carries the ID of the request being served.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the ID of the request being served, or "" outside of a request.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
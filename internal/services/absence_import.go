@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/ical"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+)
+
+// Reasons reported for calendar events that were not imported.
+const (
+	unmatchedMissingUID = "event has no UID"
+	unmatchedBadTime    = "event has no valid time range"
+	unmatchedNoUser     = "no matching user"
+)
+
+// ImportAbsences imports the VEVENTs of an ICS stream as absences of the user, or of the team members
+// they name when teamName is set. Events are keyed by UID, so re-importing a calendar updates existing
+// absences instead of duplicating them, and cancelled events remove them.
+func (s *UserService) ImportAbsences(
+	ctx context.Context,
+	userID, teamName string,
+	data io.Reader,
+) (*models.AbsenceImportResult, error) {
+	if (userID == "") == (teamName == "") {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	members, err := s.importTargets(ctx, userID, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := ical.Parse(data)
+	if err != nil {
+		s.log.WarnContext(ctx, "invalid ICS upload", slog.String("error", err.Error()))
+		return nil, apperrors.Wrap(apperrors.ErrInvalidInput, err.Error())
+	}
+
+	result := &models.AbsenceImportResult{Unmatched: []models.UnmatchedEvent{}}
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		for i := range events {
+			event := &events[i]
+			reason, importErr := s.importEvent(ctx, event, members, userID != "", result)
+			if importErr != nil {
+				return importErr
+			}
+			if reason != "" {
+				result.Unmatched = append(result.Unmatched, models.UnmatchedEvent{
+					UID:     event.UID,
+					Summary: event.Summary,
+					Reason:  reason,
+				})
+			}
+		}
+
+		entityType, entityID := models.AuditEntityUser, userID
+		if teamName != "" {
+			entityType, entityID = models.AuditEntityTeam, teamName
+		}
+		return s.audit.Record(ctx, models.AuditAbsencesImported, entityType, entityID, nil, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.InfoContext(ctx, "absences imported",
+		slog.String("user_id", userID),
+		slog.String("team_name", teamName),
+		slog.Int("created", result.Created),
+		slog.Int("updated", result.Updated),
+		slog.Int("cancelled", result.Cancelled),
+		slog.Int("unmatched", len(result.Unmatched)))
+	return result, nil
+}
+
+// importTargets returns the users events can be matched to.
+func (s *UserService) importTargets(ctx context.Context, userID, teamName string) ([]models.TeamMember, error) {
+	if userID != "" {
+		user, err := s.userRepo.GetUserByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrNotFound) {
+				s.log.WarnContext(ctx, "user not found for absence import", slog.String("user_id", userID))
+			}
+			return nil, err
+		}
+		return []models.TeamMember{{UserID: user.ID, Username: user.Name, IsActive: user.IsActive}}, nil
+	}
+
+	team, err := s.teamRepo.GetTeamByName(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			s.log.WarnContext(ctx, "team not found for absence import", slog.String("team_name", teamName))
+		}
+		return nil, err
+	}
+	return team.Members, nil
+}
+
+// importEvent stores the event for every matched user. It returns the reason the event was skipped,
+// or an empty string once it was imported.
+func (s *UserService) importEvent(
+	ctx context.Context,
+	event *ical.Event,
+	members []models.TeamMember,
+	single bool,
+	result *models.AbsenceImportResult,
+) (string, error) {
+	if event.UID == "" {
+		return unmatchedMissingUID, nil
+	}
+
+	matched := members
+	if !single {
+		matched = matchEventMembers(event, members)
+	}
+	if len(matched) == 0 {
+		return unmatchedNoUser, nil
+	}
+
+	if event.Cancelled() {
+		for _, m := range matched {
+			deleted, err := s.absenceRepo.DeleteAbsenceByExternalUID(ctx, m.UserID, event.UID)
+			if err != nil {
+				s.log.ErrorContext(ctx, "failed to delete cancelled absence",
+					slog.String("user_id", m.UserID),
+					slog.String("uid", event.UID),
+					slog.String("error", err.Error()))
+				return "", err
+			}
+			if deleted {
+				result.Cancelled++
+			}
+		}
+		return "", nil
+	}
+
+	if event.Start.IsZero() || !event.End.After(event.Start) {
+		return unmatchedBadTime, nil
+	}
+
+	for _, m := range matched {
+		absence := &models.Absence{
+			UserID:      m.UserID,
+			StartsAt:    event.Start,
+			EndsAt:      event.End,
+			Reason:      event.Summary,
+			ExternalUID: event.UID,
+		}
+		created, err := s.absenceRepo.UpsertAbsenceByExternalUID(ctx, absence)
+		if err != nil {
+			s.log.ErrorContext(ctx, "failed to import absence",
+				slog.String("user_id", m.UserID),
+				slog.String("uid", event.UID),
+				slog.String("error", err.Error()))
+			return "", err
+		}
+		if created {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+	}
+	return "", nil
+}
+
+// matchEventMembers finds the team members an event refers to. Organizer and attendees are matched
+// by name or e-mail local part against user IDs and usernames; the summary is searched for whole-word
+// usernames or user IDs only when no participant matched.
+func matchEventMembers(event *ical.Event, members []models.TeamMember) []models.TeamMember {
+	var matched []models.TeamMember
+	for _, m := range members {
+		for _, c := range event.Contacts() {
+			local, _, _ := strings.Cut(c.Email, "@")
+			if matchesMember(c.Name, m) || matchesMember(local, m) {
+				matched = append(matched, m)
+				break
+			}
+		}
+	}
+	if len(matched) > 0 {
+		return matched
+	}
+
+	for _, m := range members {
+		if containsWord(event.Summary, m.UserID) || containsWord(event.Summary, m.Username) {
+			matched = append(matched, m)
+		}
+	}
+	return matched
+}
+
+func matchesMember(s string, m models.TeamMember) bool {
+	s = strings.TrimSpace(s)
+	return s != "" && (strings.EqualFold(s, m.UserID) || strings.EqualFold(s, m.Username))
+}
+
+// containsWord reports whether word occurs in text, case-insensitively, not surrounded by letters or digits.
+func containsWord(text, word string) bool {
+	if word == "" {
+		return false
+	}
+	text, word = strings.ToLower(text), strings.ToLower(word)
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], word)
+		if i < 0 {
+			return false
+		}
+		start := offset + i
+		end := start + len(word)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+)
+
+// rolePermissions lists what each role may do. A binding scoped to a team grants these only for that
+// team's users and PRs, except PermRead, which is never scoped.
+var rolePermissions = map[string][]string{
+	models.RoleAdmin: {
+		models.PermRead, models.PermWritePR, models.PermMergePR, models.PermForceMerge,
+		models.PermManageTeam, models.PermCreateTeam, models.PermManageRepository, models.PermManageWebhooks,
+		models.PermReadAudit,
+	},
+	models.RoleTeamLead: {models.PermRead, models.PermWritePR, models.PermMergePR, models.PermManageTeam},
+	models.RoleMember:   {models.PermRead, models.PermWritePR},
+	models.RoleBot:      {models.PermRead},
+}
+
+// AccessService manages role bindings and decides whether a principal may perform an action.
+type AccessService struct {
+	roleRepo    repository.RoleRepository
+	userRepo    repository.UserRepository
+	teamRepo    repository.TeamRepository
+	prRepo      repository.PRRepository
+	absenceRepo repository.AbsenceRepository
+	log         *slog.Logger
+}
+
+var _ AccessServiceInterface = (*AccessService)(nil)
+
+func NewAccessService(
+	roleRepo repository.RoleRepository,
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	prRepo repository.PRRepository,
+	absenceRepo repository.AbsenceRepository,
+	log *slog.Logger,
+) *AccessService {
+	return &AccessService{
+		roleRepo:    roleRepo,
+		userRepo:    userRepo,
+		teamRepo:    teamRepo,
+		prRepo:      prRepo,
+		absenceRepo: absenceRepo,
+		log:         log,
+	}
+}
+
+// GrantRole binds role to userID, within teamName only when it is not empty.
+func (s *AccessService) GrantRole(ctx context.Context, userID, role, teamName string) (*models.RoleBinding, error) {
+	userID = strings.TrimSpace(userID)
+	if _, ok := rolePermissions[role]; !ok || userID == "" {
+		return nil, apperrors.ErrInvalidInput
+	}
+	if teamName != "" {
+		if _, err := s.teamRepo.GetTeamByName(ctx, teamName); err != nil {
+			if errors.Is(err, apperrors.ErrNotFound) {
+				return nil, err
+			}
+			s.log.ErrorContext(ctx, "failed to get team for role binding",
+				slog.String("team_name", teamName),
+				slog.String("error", err.Error()))
+			return nil, apperrors.ErrInternal
+		}
+	}
+
+	binding := &models.RoleBinding{UserID: userID, Role: role, TeamName: teamName}
+	if err := s.roleRepo.CreateRoleBinding(ctx, binding); err != nil {
+		if errors.Is(err, apperrors.ErrRoleExists) {
+			return nil, err
+		}
+		s.log.ErrorContext(ctx, "failed to create role binding",
+			slog.String("user_id", userID),
+			slog.String("error", err.Error()))
+		return nil, apperrors.ErrInternal
+	}
+
+	s.log.InfoContext(ctx, "role granted",
+		slog.String("user_id", userID),
+		slog.String("role", role),
+		slog.String("team_name", teamName))
+	return binding, nil
+}
+
+// RevokeRole removes a binding created by GrantRole with the same arguments.
+func (s *AccessService) RevokeRole(ctx context.Context, userID, role, teamName string) error {
+	if err := s.roleRepo.DeleteRoleBinding(ctx, userID, role, teamName); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return err
+		}
+		s.log.ErrorContext(ctx, "failed to delete role binding",
+			slog.String("user_id", userID),
+			slog.String("error", err.Error()))
+		return apperrors.ErrInternal
+	}
+
+	s.log.InfoContext(ctx, "role revoked",
+		slog.String("user_id", userID),
+		slog.String("role", role),
+		slog.String("team_name", teamName))
+	return nil
+}
+
+// ListRoleBindings returns the bindings of userID, or of everyone when userID is empty.
+func (s *AccessService) ListRoleBindings(ctx context.Context, userID string) ([]models.RoleBinding, error) {
+	bindings, err := s.roleRepo.ListRoleBindings(ctx, userID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to list role bindings", slog.String("error", err.Error()))
+		return nil, apperrors.ErrInternal
+	}
+	return bindings, nil
+}
+
+// Principal loads userID with its role bindings. A principal without bindings may do nothing.
+func (s *AccessService) Principal(ctx context.Context, userID string) (*models.Principal, error) {
+	bindings, err := s.roleRepo.ListRoleBindings(ctx, userID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to load role bindings",
+			slog.String("user_id", userID),
+			slog.String("error", err.Error()))
+		return nil, apperrors.ErrInternal
+	}
+	return &models.Principal{UserID: userID, Bindings: bindings}, nil
+}
+
+// Authorize returns ErrForbidden unless principal holds perm through a global binding or a binding
+// scoped to the team that owns target.
+func (s *AccessService) Authorize(
+	ctx context.Context,
+	principal *models.Principal,
+	perm string,
+	target models.AccessTarget,
+) error {
+	var scoped []models.RoleBinding
+	for _, b := range principal.Bindings {
+		if !slices.Contains(rolePermissions[b.Role], perm) {
+			continue
+		}
+		if b.TeamName == "" || perm == models.PermRead {
+			return nil
+		}
+		scoped = append(scoped, b)
+	}
+
+	if len(scoped) > 0 {
+		team, err := s.targetTeam(ctx, target)
+		if err != nil {
+			s.log.ErrorContext(ctx, "failed to resolve team of access target",
+				slog.String("user_id", principal.UserID),
+				slog.String("error", err.Error()))
+			return apperrors.ErrInternal
+		}
+		for _, b := range scoped {
+			if team != "" && b.TeamName == team {
+				return nil
+			}
+		}
+	}
+
+	s.log.WarnContext(ctx, "access denied",
+		slog.String("user_id", principal.UserID),
+		slog.String("permission", perm))
+	return apperrors.ErrForbidden
+}
+
+// targetTeam returns the team that owns target, or "" when it names nothing that exists or when its
+// fields point at different teams. A field naming something that does not exist yet, such as a user
+// being added to a team, does not take part.
+func (s *AccessService) targetTeam(ctx context.Context, target models.AccessTarget) (string, error) {
+	teams := []string{target.TeamName}
+
+	if target.PullRequestID != "" {
+		pr, err := s.prRepo.GetPRByID(ctx, target.Repository, target.PullRequestID)
+		switch {
+		case err == nil:
+			team, teamErr := s.userTeam(ctx, pr.AuthorID)
+			if teamErr != nil {
+				return "", teamErr
+			}
+			teams = append(teams, team)
+		case !errors.Is(err, apperrors.ErrNotFound):
+			return "", err
+		}
+		// A PR that is being created belongs to the team of its author, which UserID names.
+	}
+	if target.AbsenceID != 0 {
+		absence, err := s.absenceRepo.GetAbsenceByID(ctx, target.AbsenceID)
+		switch {
+		case err == nil:
+			team, teamErr := s.userTeam(ctx, absence.UserID)
+			if teamErr != nil {
+				return "", teamErr
+			}
+			teams = append(teams, team)
+		case !errors.Is(err, apperrors.ErrNotFound):
+			return "", err
+		}
+	}
+	team, err := s.userTeam(ctx, target.UserID)
+	if err != nil {
+		return "", err
+	}
+	teams = append(teams, team)
+
+	owner := ""
+	for _, t := range teams {
+		if t == "" {
+			continue
+		}
+		if owner != "" && t != owner {
+			return "", nil
+		}
+		owner = t
+	}
+	return owner, nil
+}
+
+func (s *AccessService) userTeam(ctx context.Context, userID string) (string, error) {
+	if userID == "" {
+		return "", nil
+	}
+	team, err := s.userRepo.GetTeamNameByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return team, nil
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+)
+
+// AccountService links code host logins to users, so that webhook events can be attributed.
+type AccountService struct {
+	accountRepo repository.AccountRepository
+	userRepo    repository.UserRepository
+	audit       *AuditService
+	log         *slog.Logger
+}
+
+var _ AccountServiceInterface = (*AccountService)(nil)
+
+func NewAccountService(
+	accountRepo repository.AccountRepository,
+	userRepo repository.UserRepository,
+	audit *AuditService,
+	log *slog.Logger,
+) *AccountService {
+	return &AccountService{accountRepo: accountRepo, userRepo: userRepo, audit: audit, log: log}
+}
+
+// LinkAccount links login on provider to userID. A login already linked to another user moves to userID.
+func (s *AccountService) LinkAccount(ctx context.Context, provider, login, userID string) (*models.Account, error) {
+	login = normalizeLogin(login)
+	if !knownProvider(provider) || login == "" || userID == "" {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			s.log.WarnContext(ctx, "user not found for account link", slog.String("user_id", userID))
+		}
+		return nil, err
+	}
+
+	account := &models.Account{Provider: provider, Login: login, UserID: userID}
+	err := s.audit.InTx(ctx, func(ctx context.Context) error {
+		before, getErr := s.accountRepo.GetAccount(ctx, provider, login)
+		if getErr != nil && !errors.Is(getErr, apperrors.ErrNotFound) {
+			return getErr
+		}
+		if upsertErr := s.accountRepo.UpsertAccount(ctx, account); upsertErr != nil {
+			s.log.ErrorContext(ctx, "failed to link account",
+				slog.String("provider", provider),
+				slog.String("login", login),
+				slog.String("error", upsertErr.Error()))
+			return upsertErr
+		}
+		return s.audit.Record(ctx, models.AuditAccountLinked, models.AuditEntityAccount, accountID(account),
+			before, account)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.InfoContext(ctx, "account linked",
+		slog.String("provider", provider),
+		slog.String("login", login),
+		slog.String("user_id", userID))
+	return account, nil
+}
+
+// UnlinkAccount removes the link of login on provider to userID.
+func (s *AccountService) UnlinkAccount(ctx context.Context, provider, login, userID string) error {
+	login = normalizeLogin(login)
+	if !knownProvider(provider) || login == "" || userID == "" {
+		return apperrors.ErrInvalidInput
+	}
+
+	err := s.audit.InTx(ctx, func(ctx context.Context) error {
+		account, deleteErr := s.accountRepo.DeleteAccount(ctx, provider, login, userID)
+		if deleteErr != nil {
+			if !errors.Is(deleteErr, apperrors.ErrNotFound) {
+				s.log.ErrorContext(ctx, "failed to unlink account",
+					slog.String("provider", provider),
+					slog.String("login", login),
+					slog.String("error", deleteErr.Error()))
+			}
+			return deleteErr
+		}
+		return s.audit.Record(ctx, models.AuditAccountUnlinked, models.AuditEntityAccount, accountID(account),
+			account, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.log.InfoContext(ctx, "account unlinked", slog.String("provider", provider), slog.String("login", login))
+	return nil
+}
+
+// ListAccounts returns the logins linked to userID.
+func (s *AccountService) ListAccounts(ctx context.Context, userID string) ([]models.Account, error) {
+	if userID == "" {
+		return nil, apperrors.ErrInvalidInput
+	}
+	accounts, err := s.accountRepo.ListAccounts(ctx, userID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to list accounts",
+			slog.String("user_id", userID),
+			slog.String("error", err.Error()))
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// ResolveUser returns the user that login on provider is linked to, or ErrNoAccount.
+func (s *AccountService) ResolveUser(ctx context.Context, provider, login string) (string, error) {
+	account, err := s.accountRepo.GetAccount(ctx, provider, normalizeLogin(login))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return "", apperrors.Wrap(apperrors.ErrNoAccount, provider+" login "+login)
+		}
+		s.log.ErrorContext(ctx, "failed to resolve account",
+			slog.String("provider", provider),
+			slog.String("login", login),
+			slog.String("error", err.Error()))
+		return "", err
+	}
+	return account.UserID, nil
+}
+
+func knownProvider(provider string) bool {
+	return provider == models.ProviderGitHub || provider == models.ProviderGitLab
+}
+
+// normalizeLogin lower-cases login: code hosts compare logins case-insensitively.
+func normalizeLogin(login string) string {
+	return strings.ToLower(strings.TrimSpace(login))
+}
+
+// accountID is the audit entity ID of account.
+func accountID(account *models.Account) string {
+	return account.Provider + ":" + account.Login
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/reqctx"
+)
+
+// Page sizes of ListEvents.
+const (
+	DefaultAuditPageSize = 50
+	MaxAuditPageSize     = 500
+)
+
+// ChangeListener is told of every change recorded in the audit log, within the transaction of the change.
+// before and after are the values passed to Record; an error rolls the change back.
+type ChangeListener interface {
+	OnChange(ctx context.Context, action, entityID string, before, after any) error
+}
+
+// AuditService records changes made through the API and serves the audit log.
+type AuditService struct {
+	auditRepo repository.AuditRepository
+	tx        repository.Transactor
+	listeners []ChangeListener
+	log       *slog.Logger
+}
+
+var _ AuditServiceInterface = (*AuditService)(nil)
+
+func NewAuditService(
+	auditRepo repository.AuditRepository,
+	tx repository.Transactor,
+	log *slog.Logger,
+	listeners ...ChangeListener,
+) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+		tx:        tx,
+		listeners: listeners,
+		log:       log,
+	}
+}
+
+// InTx runs fn in a transaction, so that a change and the audit event recorded for it in fn
+// are committed or rolled back together.
+func (s *AuditService) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return s.tx.WithinTx(ctx, fn)
+}
+
+// InSavepoint runs fn in a savepoint of the transaction in ctx: when fn fails, its change and audit
+// events are rolled back and the rest of the transaction can still commit.
+func (s *AuditService) InSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	return s.tx.WithinSavepoint(ctx, fn)
+}
+
+// Record writes an audit event attributed to the actor and request in ctx and passes the change on to the
+// listeners. before and after are stored as JSON; pass nil when the entity did not exist before or after
+// the change.
+func (s *AuditService) Record(ctx context.Context, action, entityType, entityID string, before, after any) error {
+	beforeJSON, err := auditJSON(before)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to encode audit state")
+	}
+	afterJSON, err := auditJSON(after)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to encode audit state")
+	}
+
+	event := &models.AuditEvent{
+		Actor:      reqctx.Actor(ctx),
+		OnBehalfOf: reqctx.OnBehalfOf(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		RequestID:  reqctx.RequestID(ctx),
+	}
+	if err = s.auditRepo.CreateAuditEvent(ctx, event); err != nil {
+		s.log.ErrorContext(ctx, "failed to record audit event",
+			slog.String("action", action),
+			slog.String("entity_id", entityID),
+			slog.String("error", err.Error()))
+		return err
+	}
+	for _, l := range s.listeners {
+		if err = l.OnChange(ctx, action, entityID, before, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListEvents returns a page of audit events matching filter, newest first.
+// A zero limit selects DefaultAuditPageSize.
+func (s *AuditService) ListEvents(ctx context.Context, filter models.AuditFilter) (*models.AuditPage, error) {
+	if filter.Limit == 0 {
+		filter.Limit = DefaultAuditPageSize
+	}
+	if filter.Limit < 0 || filter.Limit > MaxAuditPageSize || filter.Offset < 0 {
+		return nil, apperrors.ErrInvalidInput
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	events, total, err := s.auditRepo.ListAuditEvents(ctx, filter)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to list audit events", slog.String("error", err.Error()))
+		return nil, err
+	}
+	return &models.AuditPage{Events: events, Total: total, Limit: filter.Limit, Offset: filter.Offset}, nil
+}
+
+// auditJSON encodes v for an audit event; nil values, including typed nil pointers, become no JSON at all.
+func auditJSON(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return nil, err
+	}
+	return data, nil
+}
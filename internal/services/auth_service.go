@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+)
+
+// tokenBytes is the amount of randomness in an issued token.
+const tokenBytes = 32
+
+// AuthService issues API tokens and resolves bearer tokens to them.
+type AuthService struct {
+	tokenRepo repository.TokenRepository
+	log       *slog.Logger
+}
+
+var _ AuthServiceInterface = (*AuthService)(nil)
+
+func NewAuthService(tokenRepo repository.TokenRepository, log *slog.Logger) *AuthService {
+	return &AuthService{tokenRepo: tokenRepo, log: log}
+}
+
+// CreateToken issues a token with the given name that acts as userID. The returned raw token is shown once;
+// only its hash is stored.
+func (s *AuthService) CreateToken(ctx context.Context, name, userID string) (string, *models.APIToken, error) {
+	name, userID = strings.TrimSpace(name), strings.TrimSpace(userID)
+	if name == "" || userID == "" {
+		return "", nil, apperrors.ErrInvalidInput
+	}
+
+	var b [tokenBytes]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", nil, apperrors.Wrap(err, "failed to generate token")
+	}
+	raw := hex.EncodeToString(b[:])
+
+	token := &models.APIToken{Name: name, UserID: userID}
+	if err := s.tokenRepo.CreateToken(ctx, token, hashToken(raw)); err != nil {
+		if errors.Is(err, apperrors.ErrTokenExists) {
+			return "", nil, err
+		}
+		s.log.ErrorContext(ctx, "failed to create token",
+			slog.String("name", name),
+			slog.String("error", err.Error()))
+		return "", nil, apperrors.ErrInternal
+	}
+
+	s.log.InfoContext(ctx, "token created", slog.String("name", name), slog.String("user_id", userID))
+	return raw, token, nil
+}
+
+// Authenticate returns the active token matching raw, or ErrUnauthorized.
+func (s *AuthService) Authenticate(ctx context.Context, raw string) (*models.APIToken, error) {
+	if raw == "" {
+		return nil, apperrors.ErrUnauthorized
+	}
+
+	token, err := s.tokenRepo.GetTokenByHash(ctx, hashToken(raw))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, apperrors.ErrUnauthorized
+		}
+		s.log.ErrorContext(ctx, "failed to look up token", slog.String("error", err.Error()))
+		return nil, apperrors.ErrInternal
+	}
+	return token, nil
+}
+
+// ListTokens returns all issued tokens, revoked ones included.
+func (s *AuthService) ListTokens(ctx context.Context) ([]models.APIToken, error) {
+	tokens, err := s.tokenRepo.ListTokens(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to list tokens", slog.String("error", err.Error()))
+		return nil, apperrors.ErrInternal
+	}
+	return tokens, nil
+}
+
+// RevokeToken revokes the active token with the given name.
+func (s *AuthService) RevokeToken(ctx context.Context, name string) error {
+	if err := s.tokenRepo.RevokeToken(ctx, name); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return err
+		}
+		s.log.ErrorContext(ctx, "failed to revoke token",
+			slog.String("name", name),
+			slog.String("error", err.Error()))
+		return apperrors.ErrInternal
+	}
+
+	s.log.InfoContext(ctx, "token revoked", slog.String("name", name))
+	return nil
+}
+
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
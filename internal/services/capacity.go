@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"slices"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+)
+
+// filterByCapacity drops team members who already review as many OPEN PRs as their
+// max_open_reviews allows. The team load is fetched only when some of the users have a limit.
+func filterByCapacity(
+	ctx context.Context,
+	prRepo repository.PRRepository,
+	teamName string,
+	users []models.User,
+) ([]models.User, error) {
+	hasLimit := slices.ContainsFunc(users, func(u models.User) bool {
+		return u.MaxOpenReviews != nil
+	})
+	if !hasLimit {
+		return users, nil
+	}
+
+	teamLoad, err := prRepo.GetOpenReviewLoadByTeam(ctx, teamName)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to get reviewer load")
+	}
+	load := make(map[string]int, len(teamLoad))
+	for _, a := range teamLoad {
+		load[a.UserID] = a.Count
+	}
+
+	available := make([]models.User, 0, len(users))
+	for _, u := range users {
+		if u.MaxOpenReviews == nil || load[u.ID] < *u.MaxOpenReviews {
+			available = append(available, u)
+		}
+	}
+	return available, nil
+}
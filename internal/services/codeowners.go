@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/codeowners"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+)
+
+// ownerSelection is the outcome of matching a PR's changed files against its repository's CODEOWNERS.
+type ownerSelection struct {
+	reviewers     []string
+	reviewerTeams map[string]string
+	// uncovered counts owner sets that are required but had no available reviewer.
+	uncovered int
+}
+
+// covers reports whether one of the selected reviewers is, or belongs to a team listed in, owners.
+func (o *ownerSelection) covers(owners []string) bool {
+	for _, owner := range owners {
+		name := ownerName(owner)
+		for _, r := range o.reviewers {
+			if name == r || name == o.reviewerTeams[r] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// selectCodeOwners picks one reviewer for each owner set of repo's CODEOWNERS matching the PR's changed files.
+// In preferred mode owners only fill the first count reviewer slots; in required mode every owner set gets
+// a reviewer when one is available. Selected users are added to exclude. A nil repo selects nobody.
+func (s *PRService) selectCodeOwners(
+	ctx context.Context,
+	pr *models.PullRequest,
+	repo *models.Repository,
+	exclude map[string]bool,
+	count int,
+) (*ownerSelection, error) {
+	sel := &ownerSelection{reviewers: []string{}, reviewerTeams: map[string]string{}}
+	if repo == nil || repo.CodeOwners == "" || len(pr.ChangedFiles) == 0 {
+		return sel, nil
+	}
+
+	file, err := codeowners.Parse(repo.CodeOwners)
+	if err != nil {
+		s.log.ErrorContext(ctx, "stored CODEOWNERS is invalid",
+			slog.String("repository", pr.Repository),
+			slog.String("error", err.Error()))
+		return nil, apperrors.Wrap(apperrors.ErrInternal, err.Error())
+	}
+	required := repo.CodeOwnersMode == models.CodeOwnersRequired
+
+	for _, owners := range file.OwnerSets(pr.ChangedFiles) {
+		if !required && len(sel.reviewers) >= count {
+			break
+		}
+		if sel.covers(owners) {
+			continue
+		}
+
+		candidates, teamOf, candidatesErr := s.ownerCandidates(ctx, owners, exclude)
+		if candidatesErr != nil {
+			return nil, candidatesErr
+		}
+		if len(candidates) == 0 {
+			if required {
+				sel.uncovered++
+			}
+			continue
+		}
+
+		teamName := teamOf[candidates[0]]
+		selected, selectErr := s.selector.Select(ctx, teamName, candidates, 1)
+		if selectErr != nil {
+			s.log.ErrorContext(ctx, "failed to select code owner",
+				slog.String("team_name", teamName),
+				slog.String("error", selectErr.Error()))
+			return nil, apperrors.Wrap(selectErr, "reviewer selection failed")
+		}
+		for _, id := range selected {
+			exclude[id] = true
+			sel.reviewers = append(sel.reviewers, id)
+			sel.reviewerTeams[id] = teamOf[id]
+		}
+	}
+
+	s.log.InfoContext(ctx, "code owners matched",
+		slog.String("repository", pr.Repository),
+		slog.Int("selected", len(sel.reviewers)),
+		slog.Int("uncovered", sel.uncovered))
+	return sel, nil
+}
+
+// ownerCandidates resolves CODEOWNERS owners to available reviewer candidates and the team each one comes
+// from. An owner names a user ID or, when no such user exists, a team.
+func (s *PRService) ownerCandidates(
+	ctx context.Context,
+	owners []string,
+	exclude map[string]bool,
+) ([]string, map[string]string, error) {
+	candidates := []string{}
+	teamOf := map[string]string{}
+	add := func(id, teamName string) {
+		if _, ok := teamOf[id]; !ok {
+			teamOf[id] = teamName
+			candidates = append(candidates, id)
+		}
+	}
+
+	for _, owner := range owners {
+		name := ownerName(owner)
+		if name == "" || exclude[name] {
+			continue
+		}
+
+		user, err := s.userRepo.GetUserByID(ctx, name)
+		switch {
+		case err == nil:
+			teamCandidates, candidatesErr := s.teamCandidates(ctx, user.TeamName, exclude)
+			if candidatesErr != nil {
+				return nil, nil, candidatesErr
+			}
+			if slices.Contains(teamCandidates, user.ID) {
+				add(user.ID, user.TeamName)
+			}
+		case errors.Is(err, apperrors.ErrNotFound):
+			teamCandidates, candidatesErr := s.teamCandidates(ctx, name, exclude)
+			if candidatesErr != nil {
+				return nil, nil, candidatesErr
+			}
+			for _, id := range teamCandidates {
+				add(id, name)
+			}
+		default:
+			s.log.ErrorContext(ctx, "failed to resolve code owner",
+				slog.String("owner", owner),
+				slog.String("error", err.Error()))
+			return nil, nil, apperrors.Wrap(err, "code owner fetch failed")
+		}
+	}
+	return candidates, teamOf, nil
+}
+
+// ownerName strips the "@" and organisation prefix from an owner ("@org/backend" -> "backend").
+// E-mail owners cannot be mapped to users and yield an empty name.
+func ownerName(owner string) string {
+	name, isHandle := strings.CutPrefix(owner, "@")
+	if !isHandle && strings.Contains(owner, "@") {
+		return ""
+	}
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
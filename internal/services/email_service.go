@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/email"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+)
+
+// digestBatchSize caps the recipients claimed at once by SendDigests.
+const digestBatchSize = 100
+
+// EmailService emails reviewers when they are assigned or reminded of a review, and sends the daily digest
+// of pending reviews to the users who opted in. Users without an email get nothing.
+type EmailService struct {
+	userRepo   repository.UserRepository
+	prRepo     repository.PRRepository
+	mailer     *email.Mailer
+	templates  *email.Templates
+	digestHour int
+	log        *slog.Logger
+}
+
+var (
+	_ EmailServiceInterface = (*EmailService)(nil)
+	_ EventSink             = (*EmailService)(nil)
+)
+
+func NewEmailService(
+	userRepo repository.UserRepository,
+	prRepo repository.PRRepository,
+	mailer *email.Mailer,
+	templates *email.Templates,
+	digestHour int,
+	log *slog.Logger,
+) *EmailService {
+	return &EmailService{
+		userRepo:   userRepo,
+		prRepo:     prRepo,
+		mailer:     mailer,
+		templates:  templates,
+		digestHour: digestHour,
+		log:        log,
+	}
+}
+
+// Name marks mailed events in outbox.delivered_sinks; keep it stable, or events awaiting a retry of another
+// sink would be mailed again.
+func (s *EmailService) Name() string {
+	return "email"
+}
+
+// Publish emails the reviewer of an assignment, reassignment or stale review. Other events are ignored.
+// Emails the SMTP server refuses for good, such as to an unknown mailbox, are dropped rather than retried.
+func (s *EmailService) Publish(ctx context.Context, event *models.Event) error {
+	name := emailTemplate(event.Type)
+	pr := event.Data.PullRequest
+	if name == "" || pr == nil {
+		return nil
+	}
+
+	reviewer, err := s.userRepo.GetUserByID(ctx, event.Data.ReviewerID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if reviewer.Email == "" || !reviewer.IsActive {
+		return nil
+	}
+
+	subject, body, err := s.templates.Render(name, email.EventData{
+		Recipient: *reviewer, Event: *event, PullRequest: *pr,
+	})
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to render email",
+			slog.String("template", name),
+			slog.String("error", err.Error()))
+		return nil
+	}
+	msg := email.Message{To: []string{reviewer.Email}, Subject: subject, Body: body}
+	if err = s.send(ctx, reviewer, event.Type, msg); err != nil && !email.Permanent(err) {
+		return err
+	}
+	return nil
+}
+
+// SendDigests emails every user who opted in the list of PRs waiting for their review, once a day after
+// the digest hour (UTC), and returns how many digests were sent. Users are claimed before their digest is
+// sent, so a digest that fails to send is not retried until the next day. Users with nothing to review
+// get no digest.
+func (s *EmailService) SendDigests(ctx context.Context) (int, error) {
+	slot := s.digestSlot()
+	sent := 0
+	for ctx.Err() == nil {
+		users, err := s.userRepo.ClaimDigestRecipients(ctx, slot, digestBatchSize)
+		if err != nil {
+			s.log.ErrorContext(ctx, "failed to claim digest recipients", slog.String("error", err.Error()))
+			return sent, err
+		}
+		for i := range users {
+			if s.sendDigest(ctx, &users[i], slot) {
+				sent++
+			}
+		}
+		if len(users) < digestBatchSize {
+			break
+		}
+	}
+
+	if sent > 0 {
+		s.log.InfoContext(ctx, "email digests sent", slog.Int("count", sent))
+	}
+	return sent, nil
+}
+
+// digestSlot returns the latest digest hour that has passed.
+func (s *EmailService) digestSlot() time.Time {
+	now := time.Now().UTC()
+	slot := time.Date(now.Year(), now.Month(), now.Day(), s.digestHour, 0, 0, 0, time.UTC)
+	if now.Before(slot) {
+		slot = slot.AddDate(0, 0, -1)
+	}
+	return slot
+}
+
+// sendDigest emails user their pending reviews and reports whether a digest was sent.
+func (s *EmailService) sendDigest(ctx context.Context, user *models.User, slot time.Time) bool {
+	prs, err := s.prRepo.GetPRsForUser(ctx, user.ID, "", true)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to get PRs for digest",
+			slog.String("user_id", user.ID),
+			slog.String("error", err.Error()))
+		return false
+	}
+	if len(prs) == 0 {
+		return false
+	}
+
+	subject, body, err := s.templates.Render(email.TemplateDigest, email.DigestData{
+		Recipient: *user, Date: slot, PullRequests: prs,
+	})
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to render email",
+			slog.String("template", email.TemplateDigest),
+			slog.String("error", err.Error()))
+		return false
+	}
+	msg := email.Message{To: []string{user.Email}, Subject: subject, Body: body}
+	return s.send(ctx, user, "digest", msg) == nil
+}
+
+// send emails msg to user about kind.
+func (s *EmailService) send(ctx context.Context, user *models.User, kind string, msg email.Message) error {
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		logMsg := "failed to send email"
+		if email.Permanent(err) {
+			logMsg = "email rejected"
+		}
+		s.log.WarnContext(ctx, logMsg,
+			slog.String("user_id", user.ID),
+			slog.String("kind", kind),
+			slog.String("error", err.Error()))
+		return err
+	}
+	s.log.DebugContext(ctx, "email sent", slog.String("user_id", user.ID), slog.String("kind", kind))
+	return nil
+}
+
+// emailTemplate returns the template of the email about an event type, or "" when there is none.
+func emailTemplate(eventType string) string {
+	switch eventType {
+	case models.EventReviewerAssigned:
+		return email.TemplateReviewerAssigned
+	case models.EventReviewerReassigned:
+		return email.TemplateReviewerReassigned
+	case models.EventReviewStale:
+		return email.TemplateReviewStale
+	}
+	return ""
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"io"
 
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
 )
@@ -9,23 +10,106 @@ import (
 type TeamServiceInterface interface {
 	CreateTeam(ctx context.Context, team *models.Team) (*models.Team, error)
 	GetTeam(ctx context.Context, name string) (*models.Team, error)
+	UpdateTeamSettings(ctx context.Context, name string, settings *models.TeamSettings) (*models.Team, error)
+	SetFallbackTeams(ctx context.Context, name string, fallbackTeams []string) (*models.Team, error)
 }
 
 type UserServiceInterface interface {
 	SetUserActive(ctx context.Context, id string, isActive bool) (*models.User, error)
-	GetPRsForUser(ctx context.Context, userID string) ([]models.PullRequestShort, error)
+	SetMaxOpenReviews(ctx context.Context, id string, limit *int) (*models.User, error)
+	SetEmail(ctx context.Context, id, email string, digest bool) (*models.User, error)
+	GetPRsForUser(ctx context.Context, userID, repository string, pendingOnly bool) ([]models.PullRequestShort, error)
 	DeactivateUsersByTeam(ctx context.Context, teamName string) error
+	AddAbsence(ctx context.Context, absence *models.Absence) (*models.Absence, error)
+	GetAbsences(ctx context.Context, userID string) ([]models.Absence, error)
+	DeleteAbsence(ctx context.Context, id int64) error
+	ImportAbsences(ctx context.Context, userID, teamName string, data io.Reader) (*models.AbsenceImportResult, error)
+	ReassignAbsentReviewers(ctx context.Context) (int, error)
 }
 
 type PRServiceInterface interface {
 	CreatePR(ctx context.Context, pr *models.PullRequest) (*models.PullRequest, error)
-	ReassignReviewer(ctx context.Context, prID, oldReviewerID string) (*models.PullRequest, string, error)
-	MergePR(ctx context.Context, prID string) (*models.PullRequest, error)
-	GetTotalPRs(ctx context.Context) (int, error)
-	GetPrsByStatus(ctx context.Context) (int, int, error)
-	GetAssignmentsPerUser(ctx context.Context) ([]models.UserAssignment, error)
-	GetTopReviewers(ctx context.Context) ([]models.UserAssignment, error)
-	GetAvgCloseTime(ctx context.Context) (models.AvgCloseTimeDetail, error)
-	GetIdleUsersPerTeam(ctx context.Context) ([]models.TeamMetric, error)
-	GetNeedyPRsPerTeam(ctx context.Context) ([]models.TeamMetric, error)
+	ReassignReviewer(ctx context.Context, repository, prID, oldReviewerID string) (*models.PullRequest, string, error)
+	ReadyPR(ctx context.Context, repository, prID string, changedFiles []string) (*models.PullRequest, error)
+	GetPR(ctx context.Context, repository, prID string) (*models.PullRequest, error)
+	GetPRHistory(ctx context.Context, repository, prID string) ([]models.AssignmentEvent, error)
+	SubmitReview(ctx context.Context, repository, prID, reviewerID, state string) (*models.PullRequest, error)
+	MergePR(ctx context.Context, repository, prID string, force bool, reason string) (*models.PullRequest, error)
+	ClosePR(ctx context.Context, repository, prID string) (*models.PullRequest, error)
+	ReopenPR(ctx context.Context, repository, prID string) (*models.PullRequest, error)
+	GetTotalPRs(ctx context.Context, repository string) (int, error)
+	GetPrsByStatus(ctx context.Context, repository string) (models.PrsStatus, error)
+	GetAssignmentsPerUser(ctx context.Context, repository string) ([]models.UserAssignment, error)
+	GetTopReviewers(ctx context.Context, repository string) ([]models.UserAssignment, error)
+	GetAvgCloseTime(ctx context.Context, repository string) (models.AvgCloseTimeDetail, error)
+	GetIdleUsersPerTeam(ctx context.Context, repository string) ([]models.TeamMetric, error)
+	GetNeedyPRsPerTeam(ctx context.Context, repository string) ([]models.TeamMetric, error)
+}
+
+type RepositoryServiceInterface interface {
+	SetCodeOwners(ctx context.Context, name, content, mode string) (*models.Repository, error)
+	GetRepository(ctx context.Context, name string) (*models.Repository, error)
+	UpdateRepositorySettings(
+		ctx context.Context, name string, settings *models.RepositorySettings,
+	) (*models.Repository, error)
+}
+
+type AuditServiceInterface interface {
+	ListEvents(ctx context.Context, filter models.AuditFilter) (*models.AuditPage, error)
+}
+
+type SubscriptionServiceInterface interface {
+	Subscribe(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error)
+	Unsubscribe(ctx context.Context, id int64) error
+	ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error)
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+type OutboxServiceInterface interface {
+	Dispatch(ctx context.Context) (int, error)
+}
+
+type ReminderServiceInterface interface {
+	RemindStale(ctx context.Context) (int, error)
+}
+
+type SlackServiceInterface interface {
+	SetTeamChannel(ctx context.Context, teamName, webhookPath string) error
+	SetUserHandle(ctx context.Context, userID, handle string) error
+}
+
+type EmailServiceInterface interface {
+	SendDigests(ctx context.Context) (int, error)
+}
+
+type AuthServiceInterface interface {
+	CreateToken(ctx context.Context, name, userID string) (string, *models.APIToken, error)
+	Authenticate(ctx context.Context, raw string) (*models.APIToken, error)
+	ListTokens(ctx context.Context) ([]models.APIToken, error)
+	RevokeToken(ctx context.Context, name string) error
+}
+
+type AccessServiceInterface interface {
+	GrantRole(ctx context.Context, userID, role, teamName string) (*models.RoleBinding, error)
+	RevokeRole(ctx context.Context, userID, role, teamName string) error
+	ListRoleBindings(ctx context.Context, userID string) ([]models.RoleBinding, error)
+	Principal(ctx context.Context, userID string) (*models.Principal, error)
+	Authorize(ctx context.Context, principal *models.Principal, perm string, target models.AccessTarget) error
+}
+
+type OIDCServiceInterface interface {
+	Principal(ctx context.Context, raw string) (*models.Principal, error)
+}
+
+type AccountServiceInterface interface {
+	LinkAccount(ctx context.Context, provider, login, userID string) (*models.Account, error)
+	UnlinkAccount(ctx context.Context, provider, login, userID string) error
+	ListAccounts(ctx context.Context, userID string) ([]models.Account, error)
+	ResolveUser(ctx context.Context, provider, login string) (string, error)
+}
+
+type WebhookServiceInterface interface {
+	HandleGitHub(ctx context.Context, event, signature string, body []byte) (*models.WebhookResult, error)
+	HandleGitLab(ctx context.Context, event, token string, body []byte) (*models.WebhookResult, error)
 }
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/jwt"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+)
+
+// OIDCService authenticates JWTs issued by the company SSO and maps their claims onto principals: the
+// user claim names the service user, and each group in the groups claim may grant roles.
+type OIDCService struct {
+	verifier    *jwt.Verifier
+	access      *AccessService
+	userClaim   string
+	groupsClaim string
+	groupRoles  map[string][]models.RoleBinding
+	log         *slog.Logger
+}
+
+var _ OIDCServiceInterface = (*OIDCService)(nil)
+
+func NewOIDCService(
+	verifier *jwt.Verifier,
+	access *AccessService,
+	userClaim, groupsClaim string,
+	groupRoles map[string][]models.RoleBinding,
+	log *slog.Logger,
+) *OIDCService {
+	return &OIDCService{
+		verifier:    verifier,
+		access:      access,
+		userClaim:   userClaim,
+		groupsClaim: groupsClaim,
+		groupRoles:  groupRoles,
+		log:         log,
+	}
+}
+
+// ParseGroupRoles parses comma-separated "group=role" or "group=role@team" mappings. A group may be
+// listed several times to grant several roles.
+func ParseGroupRoles(spec string) (map[string][]models.RoleBinding, error) {
+	groupRoles := map[string][]models.RoleBinding{}
+	for entry := range strings.SplitSeq(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		group, role, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(group) == "" {
+			return nil, apperrors.Wrap(apperrors.ErrInvalidInput, fmt.Sprintf("group role mapping %q", entry))
+		}
+		role, team, _ := strings.Cut(strings.TrimSpace(role), "@")
+		if _, known := rolePermissions[role]; !known {
+			return nil, apperrors.Wrap(apperrors.ErrInvalidInput, fmt.Sprintf("unknown role %q", role))
+		}
+		group = strings.TrimSpace(group)
+		groupRoles[group] = append(groupRoles[group], models.RoleBinding{Role: role, TeamName: team})
+	}
+	return groupRoles, nil
+}
+
+// Principal verifies raw and returns the user it names, with the roles bound to that user in the service
+// plus the roles its groups map to. Tokens that fail verification or name no user yield ErrUnauthorized.
+func (s *OIDCService) Principal(ctx context.Context, raw string) (*models.Principal, error) {
+	claims, err := s.verifier.Verify(ctx, raw)
+	if err != nil {
+		if errors.Is(err, jwt.ErrInvalidToken) {
+			s.log.WarnContext(ctx, "rejected JWT", slog.String("error", err.Error()))
+			return nil, apperrors.ErrUnauthorized
+		}
+		s.log.ErrorContext(ctx, "failed to verify JWT", slog.String("error", err.Error()))
+		return nil, apperrors.ErrInternal
+	}
+
+	userID := claims.String(s.userClaim)
+	if userID == "" {
+		s.log.WarnContext(ctx, "JWT without user claim", slog.String("claim", s.userClaim))
+		return nil, apperrors.ErrUnauthorized
+	}
+
+	principal, err := s.access.Principal(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range claims.Strings(s.groupsClaim) {
+		for _, b := range s.groupRoles[group] {
+			b.UserID = userID
+			principal.Bindings = append(principal.Bindings, b)
+		}
+	}
+	return principal, nil
+}
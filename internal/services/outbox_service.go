@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/outbound"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/reqctx"
+)
+
+const (
+	// outboxBatchSize caps the events taken from the outbox by one Dispatch call.
+	outboxBatchSize = 100
+	// eventIDBytes is the amount of randomness in an event ID.
+	eventIDBytes = 16
+)
+
+// EventSink receives the events dispatched from the outbox. Delivery is at least once: an event is
+// published again to a sink that failed it, and to every sink when the dispatch itself fails to commit, so
+// sinks drop the events whose ID they have seen. Publish runs in a savepoint of the dispatch transaction;
+// database writes made with its ctx commit with the dispatch unless Publish fails. Name must be stable: it
+// records which sinks took an event.
+type EventSink interface {
+	Name() string
+	Publish(ctx context.Context, event *models.Event) error
+}
+
+// OutboxService turns the changes recorded in the audit log into domain events, written to the outbox in
+// the transaction of the change, and dispatches them to the sinks. An event that a sink failed is retried
+// for that sink with exponential backoff, without holding back the other sinks or the events after it,
+// until it has failed maxAttempts times; then it is given up.
+type OutboxService struct {
+	repo        repository.OutboxRepository
+	tx          repository.Transactor
+	maxAttempts int
+	backoff     time.Duration
+	sinks       []EventSink
+	log         *slog.Logger
+}
+
+var (
+	_ OutboxServiceInterface = (*OutboxService)(nil)
+	_ ChangeListener         = (*OutboxService)(nil)
+)
+
+func NewOutboxService(
+	repo repository.OutboxRepository,
+	tx repository.Transactor,
+	maxAttempts int,
+	backoff time.Duration,
+	log *slog.Logger,
+	sinks ...EventSink,
+) *OutboxService {
+	return &OutboxService{
+		repo:        repo,
+		tx:          tx,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		sinks:       sinks,
+		log:         log,
+	}
+}
+
+// OnChange writes the events the change makes to the outbox, attributed to the actor in ctx.
+func (s *OutboxService) OnChange(ctx context.Context, action, _ string, before, after any) error {
+	events := changeEvents(action, before, after)
+	for i := range events {
+		if err := s.Publish(ctx, &events[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Publish writes event to the outbox, attributed to the actor in ctx, and sets its ID and time. Events
+// that do not come from a recorded change, such as reminders, are published with it in the transaction of
+// the state they depend on.
+func (s *OutboxService) Publish(ctx context.Context, event *models.Event) error {
+	var err error
+	if event.ID, err = randomHex(eventIDBytes); err != nil {
+		return apperrors.Wrap(err, "failed to generate event ID")
+	}
+	event.Actor = reqctx.Actor(ctx)
+	event.CreatedAt = time.Now().UTC()
+	if err = s.repo.AddEvent(ctx, event); err != nil {
+		s.log.ErrorContext(ctx, "failed to add outbox event",
+			slog.String("event_type", event.Type),
+			slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+// Dispatch publishes the due outbox events to the sinks and returns how many of them were dispatched.
+// Several dispatchers can run at once: each event is claimed by one of them.
+func (s *OutboxService) Dispatch(ctx context.Context) (int, error) {
+	dispatched := 0
+	for range outboxBatchSize {
+		if ctx.Err() != nil {
+			break
+		}
+		claimed, ok, err := s.dispatchNext(ctx)
+		if err != nil {
+			s.log.ErrorContext(ctx, "failed to dispatch outbox event", slog.String("error", err.Error()))
+			return dispatched, err
+		}
+		if !claimed {
+			break
+		}
+		if ok {
+			dispatched++
+		}
+	}
+	return dispatched, nil
+}
+
+// dispatchNext claims the next due event and publishes it, in one transaction, to every sink that has not
+// taken it yet. It reports whether there was an event and whether it was dispatched. Each sink runs in a
+// savepoint: a failed sink is rolled back alone, and the event is rescheduled with the sinks that took it
+// recorded, so that a retry does not publish to them again. An event that fails its last attempt is given up.
+func (s *OutboxService) dispatchNext(ctx context.Context) (bool, bool, error) {
+	var entry *models.OutboxEntry
+	var failures []string
+	var retryAfter time.Duration
+	var abandoned bool
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var claimErr error
+		if entry, claimErr = s.repo.ClaimNextEvent(ctx); claimErr != nil || entry == nil {
+			return claimErr
+		}
+		for _, sink := range s.sinks {
+			if slices.Contains(entry.DeliveredSinks, sink.Name()) {
+				continue
+			}
+			sinkErr := s.tx.WithinSavepoint(ctx, func(ctx context.Context) error {
+				return sink.Publish(ctx, &entry.Event)
+			})
+			if sinkErr != nil {
+				failures = append(failures, sink.Name()+": "+sinkErr.Error())
+				continue
+			}
+			entry.DeliveredSinks = append(entry.DeliveredSinks, sink.Name())
+		}
+		if len(failures) == 0 {
+			return s.repo.MarkDispatched(ctx, entry.ID)
+		}
+		if abandoned = entry.Attempts+1 >= s.maxAttempts; abandoned {
+			return s.repo.MarkAbandoned(ctx, entry.ID, entry.DeliveredSinks, strings.Join(failures, "; "))
+		}
+		retryAfter = outbound.Backoff(entry.Attempts+1, s.backoff, maxRetryBackoff)
+		return s.repo.MarkFailed(ctx, entry.ID, entry.DeliveredSinks, strings.Join(failures, "; "), retryAfter)
+	})
+	if entry == nil {
+		return false, false, err
+	}
+	if err != nil {
+		return true, false, err
+	}
+
+	log := s.log.With(
+		slog.Int64("outbox_id", entry.ID),
+		slog.String("event_id", entry.Event.ID),
+		slog.String("event_type", entry.Event.Type))
+	if len(failures) == 0 {
+		log.DebugContext(ctx, "outbox event dispatched")
+		return true, true, nil
+	}
+	log = log.With(
+		slog.Any("delivered_sinks", entry.DeliveredSinks),
+		slog.Int("attempt", entry.Attempts+1),
+		slog.String("error", strings.Join(failures, "; ")))
+	if abandoned {
+		log.ErrorContext(ctx, "outbox event dispatch failed, giving up")
+	} else {
+		log.WarnContext(ctx, "outbox event dispatch failed, will retry", slog.Duration("retry_after", retryAfter))
+	}
+	return true, false, nil
+}
+
+// changeEvents maps a change recorded in the audit log to the events it makes. before and after are the
+// values the services record for action.
+func changeEvents(action string, before, after any) []models.Event {
+	switch action {
+	case models.AuditPRCreated, models.AuditPRReady, models.AuditPRReopened:
+		pr, ok := after.(*models.PullRequest)
+		if !ok {
+			return nil
+		}
+		var events []models.Event
+		var assigned []string
+		if action == models.AuditPRCreated {
+			events = append(events, prEvent(models.EventPRCreated, pr))
+		}
+		if prBefore, hasBefore := before.(*models.PullRequest); hasBefore {
+			assigned = prBefore.Reviewers
+		}
+		for _, id := range pr.Reviewers {
+			if !slices.Contains(assigned, id) {
+				event := prEvent(models.EventReviewerAssigned, pr)
+				event.Data.ReviewerID = id
+				events = append(events, event)
+			}
+		}
+		return events
+	case models.AuditPRReviewerReassigned, models.AuditPRAbsentReassigned:
+		prBefore, okBefore := before.(*models.PullRequest)
+		pr, okAfter := after.(*models.PullRequest)
+		if !okBefore || !okAfter {
+			return nil
+		}
+		removed := slices.DeleteFunc(slices.Clone(prBefore.Reviewers), func(id string) bool {
+			return slices.Contains(pr.Reviewers, id)
+		})
+		var events []models.Event
+		for _, id := range pr.Reviewers {
+			if slices.Contains(prBefore.Reviewers, id) {
+				continue
+			}
+			event := prEvent(models.EventReviewerReassigned, pr)
+			event.Data.ReviewerID = id
+			if len(removed) > 0 {
+				event.Data.OldReviewerID, removed = removed[0], removed[1:]
+			}
+			events = append(events, event)
+		}
+		return events
+	case models.AuditPRMerged, models.AuditPRForceMerged:
+		if pr, ok := after.(*models.PullRequest); ok {
+			return []models.Event{prEvent(models.EventPRMerged, pr)}
+		}
+	case models.AuditUserActiveChanged:
+		userBefore, okBefore := before.(*models.User)
+		user, okAfter := after.(*models.User)
+		if okBefore && okAfter && userBefore.IsActive && !user.IsActive {
+			return []models.Event{{Type: models.EventUserDeactivated, Data: models.EventData{User: user}}}
+		}
+	case models.AuditTeamDeactivated:
+		users, _ := after.([]models.User)
+		events := make([]models.Event, 0, len(users))
+		for i := range users {
+			events = append(events, models.Event{
+				Type: models.EventUserDeactivated,
+				Data: models.EventData{User: &users[i]},
+			})
+		}
+		return events
+	}
+	return nil
+}
+
+func prEvent(eventType string, pr *models.PullRequest) models.Event {
+	return models.Event{Type: eventType, Data: models.EventData{PullRequest: pr}}
+}
@@ -4,32 +4,57 @@ import (
 	"context"
 	"errors"
 	"log/slog"
-	"math/rand/v2"
+	"maps"
 	"slices"
 	"time"
 
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/reqctx"
 )
 
 type PRService struct {
-	prRepo   repository.PRRepository
-	userRepo repository.UserRepository
-	log      *slog.Logger
+	prRepo           repository.PRRepository
+	userRepo         repository.UserRepository
+	teamRepo         repository.TeamRepository
+	repoRepo         repository.RepositoryRepository
+	audit            *AuditService
+	selector         ReviewerSelector
+	defaultReviewers int
+	log              *slog.Logger
 }
 
 var _ PRServiceInterface = (*PRService)(nil)
 
-func NewPRService(prRepo repository.PRRepository, userRepo repository.UserRepository, log *slog.Logger) *PRService {
+func NewPRService(
+	prRepo repository.PRRepository,
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	repoRepo repository.RepositoryRepository,
+	audit *AuditService,
+	selector ReviewerSelector,
+	defaultReviewers int,
+	log *slog.Logger,
+) *PRService {
 	return &PRService{
-		prRepo:   prRepo,
-		userRepo: userRepo,
-		log:      log,
+		prRepo:           prRepo,
+		userRepo:         userRepo,
+		teamRepo:         teamRepo,
+		repoRepo:         repoRepo,
+		audit:            audit,
+		selector:         selector,
+		defaultReviewers: defaultReviewers,
+		log:              log,
 	}
 }
 
-// CreatePR creates PR and auto-assigns up to 2 active reviewers from author's team (exclude author).
+// CreatePR creates PR and auto-assigns available reviewers from author's team (exclude author, absent members
+// and members at their open review limit) using the configured ReviewerSelector.
+// The number of reviewers follows the team's settings; when the team runs short, the rest are drawn from
+// the team's fallback teams. A repository's default team and reviewer count override the author's team.
+// When changed files are given, owners from the repository's CODEOWNERS are assigned first.
+// Draft PRs are stored without reviewers; assignment runs when they are marked ready.
 func (s *PRService) CreatePR(ctx context.Context, pr *models.PullRequest) (*models.PullRequest, error) {
 	if pr.ID == "" || pr.Title == "" || pr.AuthorID == "" {
 		return nil, apperrors.ErrInvalidInput
@@ -49,114 +74,240 @@ func (s *PRService) CreatePR(ctx context.Context, pr *models.PullRequest) (*mode
 		return nil, apperrors.Wrap(err, "author validation failed")
 	}
 
-	activeUsers, err := s.userRepo.GetActiveUsersByTeam(ctx, author.TeamName)
-	if err != nil {
-		s.log.ErrorContext(ctx, "failed to get active team users",
-			slog.String("team_name", author.TeamName),
-			slog.String("error", err.Error()))
-		return nil, apperrors.Wrap(err, "team users fetch failed")
+	if pr.Draft {
+		pr.Reviewers = []string{}
+		pr.NeedMoreReviewers = false
+	} else if assignErr := s.assignReviewers(ctx, pr, author.TeamName); assignErr != nil {
+		return nil, assignErr
 	}
+	now := time.Now()
+	pr.CreatedAt = &now
 
-	candidates := make([]string, 0, len(activeUsers))
-	for _, u := range activeUsers {
-		if u.ID != pr.AuthorID {
-			candidates = append(candidates, u.ID)
+	var reloaded *models.PullRequest
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		createErr := s.prRepo.CreatePR(ctx, pr, assignmentChange(ctx, models.AssignReasonCreated))
+		if createErr != nil {
+			s.log.ErrorContext(ctx, "failed to create PR",
+				slog.String("pr_id", pr.ID),
+				slog.String("error", createErr.Error()))
+			return createErr
 		}
-	}
 
-	// Shuffle candidates
-	rand.Shuffle(len(candidates), func(i, j int) {
-		candidates[i], candidates[j] = candidates[j], candidates[i]
+		var getErr error
+		reloaded, getErr = s.prRepo.GetPRByID(ctx, pr.Repository, pr.ID)
+		if getErr != nil {
+			s.log.ErrorContext(ctx, "failed to reload PR after create",
+				slog.String("pr_id", pr.ID),
+				slog.String("error", getErr.Error()))
+			return apperrors.ErrInternal
+		}
+		return s.audit.Record(ctx, models.AuditPRCreated, models.AuditEntityPullRequest, pr.ID, nil, reloaded)
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	const maxReviewers = 2
+	s.log.InfoContext(ctx, "PR created with auto-assign",
+		slog.String("pr_id", pr.ID),
+		slog.String("repository", pr.Repository),
+		slog.Bool("draft", reloaded.Draft),
+		slog.Int("reviewers_count", len(reloaded.Reviewers)),
+		slog.Bool("need_more", reloaded.NeedMoreReviewers))
+	return reloaded, nil
+}
 
-	numReviewers := min(len(candidates), maxReviewers)
-	pr.Reviewers = candidates[:numReviewers]
-	pr.NeedMoreReviewers = numReviewers < maxReviewers
-	now := time.Now()
-	pr.CreatedAt = &now
+// ReadyPR marks a draft PR as ready for review and assigns its reviewers the same way CreatePR does.
+// changedFiles are matched against the repository's CODEOWNERS. Marking a non-draft PR ready is a no-op.
+func (s *PRService) ReadyPR(
+	ctx context.Context,
+	repository, prID string,
+	changedFiles []string,
+) (*models.PullRequest, error) {
+	if prID == "" {
+		return nil, apperrors.ErrInvalidInput
+	}
 
-	if createErr := s.prRepo.CreatePR(ctx, pr); createErr != nil {
-		s.log.ErrorContext(ctx, "failed to create PR",
-			slog.String("pr_id", pr.ID),
-			slog.String("error", createErr.Error()))
-		return nil, createErr
+	pr, err := s.prRepo.GetPRByID(ctx, repository, prID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			s.log.WarnContext(ctx, "PR not found for ready",
+				slog.String("repository", repository),
+				slog.String("pr_id", prID))
+		}
+		return nil, err
+	}
+	switch pr.Status {
+	case "MERGED":
+		return nil, apperrors.ErrPRMerged
+	case "CLOSED":
+		return nil, apperrors.ErrPRClosed
 	}
+	if !pr.Draft {
+		return pr, nil
+	}
+	before := snapshotPR(pr)
 
-	reloaded, err := s.prRepo.GetPRByID(ctx, pr.ID)
+	author, err := s.userRepo.GetUserByID(ctx, pr.AuthorID)
 	if err != nil {
-		s.log.ErrorContext(ctx, "failed to reload PR after create",
-			slog.String("pr_id", pr.ID),
+		s.log.ErrorContext(ctx, "failed to get author",
+			slog.String("author_id", pr.AuthorID),
 			slog.String("error", err.Error()))
-		return nil, apperrors.ErrInternal
+		return nil, apperrors.Wrap(err, "author fetch failed")
 	}
 
-	s.log.InfoContext(ctx, "PR created with auto-assign",
-		slog.String("pr_id", pr.ID),
+	pr.ChangedFiles = changedFiles
+	if assignErr := s.assignReviewers(ctx, pr, author.TeamName); assignErr != nil {
+		return nil, assignErr
+	}
+
+	var reloaded *models.PullRequest
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		readyErr := s.prRepo.MarkPRReady(ctx, pr, assignmentChange(ctx, models.AssignReasonReady))
+		if readyErr != nil {
+			s.log.ErrorContext(ctx, "failed to mark PR ready",
+				slog.String("pr_id", prID),
+				slog.String("error", readyErr.Error()))
+			return readyErr
+		}
+
+		var getErr error
+		reloaded, getErr = s.prRepo.GetPRByID(ctx, repository, prID)
+		if getErr != nil {
+			s.log.ErrorContext(ctx, "failed to reload PR after ready",
+				slog.String("pr_id", prID),
+				slog.String("error", getErr.Error()))
+			return apperrors.ErrInternal
+		}
+		return s.audit.Record(ctx, models.AuditPRReady, models.AuditEntityPullRequest, prID, before, reloaded)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.InfoContext(ctx, "PR ready for review",
+		slog.String("pr_id", prID),
+		slog.String("repository", repository),
 		slog.Int("reviewers_count", len(reloaded.Reviewers)),
 		slog.Bool("need_more", reloaded.NeedMoreReviewers))
 	return reloaded, nil
 }
 
-// ReassignReviewer replaces old_reviewer_id with random active from old's team (exclude current/author).
+// assignReviewers picks the PR's reviewers and sets Reviewers and NeedMoreReviewers.
+// authorTeam is the home team unless the PR's repository sets a default team.
+func (s *PRService) assignReviewers(ctx context.Context, pr *models.PullRequest, authorTeam string) error {
+	repo, err := repositoryConfig(ctx, s.repoRepo, pr.Repository)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to get repository",
+			slog.String("repository", pr.Repository),
+			slog.String("error", err.Error()))
+		return apperrors.Wrap(err, "repository fetch failed")
+	}
+
+	homeTeam, reviewersCount, err := reviewerTarget(ctx, s.teamRepo, repo, authorTeam, s.defaultReviewers)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to get team reviewers count",
+			slog.String("team_name", homeTeam),
+			slog.String("error", err.Error()))
+		return apperrors.Wrap(err, "team settings fetch failed")
+	}
+
+	exclude := map[string]bool{pr.AuthorID: true}
+	candidates, err := s.teamCandidates(ctx, homeTeam, exclude)
+	if err != nil {
+		return err
+	}
+
+	owners, err := s.selectCodeOwners(ctx, pr, repo, exclude, reviewersCount)
+	if err != nil {
+		return err
+	}
+	candidates = slices.DeleteFunc(candidates, func(id string) bool { return exclude[id] })
+
+	reviewers, _, err := s.selectReviewers(
+		ctx, homeTeam, candidates, exclude, reviewersCount-len(owners.reviewers))
+	if err != nil {
+		return err
+	}
+
+	pr.Reviewers = append(owners.reviewers, reviewers...)
+	pr.NeedMoreReviewers = len(pr.Reviewers) < reviewersCount || owners.uncovered > 0
+	return nil
+}
+
+// ReassignReviewer replaces old_reviewer_id with an active member of old's team, or of its fallback teams
+// when the team has no free reviewers (exclude current/author).
 func (s *PRService) ReassignReviewer(
 	ctx context.Context,
-	prID, oldReviewerID string,
+	repository, prID, oldReviewerID string,
 ) (*models.PullRequest, string, error) {
 	if prID == "" || oldReviewerID == "" {
 		return nil, "", apperrors.ErrInvalidInput
 	}
 
-	pr, err := s.getPRForReassign(ctx, prID)
+	pr, err := s.getPRForReassign(ctx, repository, prID)
 	if err != nil {
 		return nil, "", err
 	}
 
-	newReviewer, err := s.selectNewReviewer(ctx, pr, oldReviewerID)
+	newReviewer, newReviewerTeam, err := s.selectNewReviewer(ctx, pr, oldReviewerID)
 	if err != nil {
 		return nil, "", err
 	}
 
-	s.replaceReviewerInPR(pr, oldReviewerID, newReviewer)
+	before := snapshotPR(pr)
+	s.replaceReviewerInPR(pr, oldReviewerID, newReviewer, newReviewerTeam)
 
-	if updateErr := s.prRepo.UpdatePR(ctx, pr); updateErr != nil {
-		s.log.ErrorContext(ctx, "failed to update PR for reassign",
-			slog.String("pr_id", prID),
-			slog.String("error", updateErr.Error()))
-		return nil, "", updateErr
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		updateErr := s.prRepo.UpdatePR(ctx, pr, assignmentChange(ctx, models.AssignReasonReassigned))
+		if updateErr != nil {
+			s.log.ErrorContext(ctx, "failed to update PR for reassign",
+				slog.String("pr_id", prID),
+				slog.String("error", updateErr.Error()))
+			return updateErr
+		}
+		return s.audit.Record(ctx, models.AuditPRReviewerReassigned, models.AuditEntityPullRequest, prID, before, pr)
+	})
+	if err != nil {
+		return nil, "", err
 	}
 
 	s.log.InfoContext(ctx, "reviewer reassigned",
 		slog.String("pr_id", prID),
 		slog.String("old", oldReviewerID),
-		slog.String("new", newReviewer))
+		slog.String("new", newReviewer),
+		slog.String("new_team", newReviewerTeam))
 	return pr, newReviewer, nil
 }
 
 // getPRForReassign проверяет существование PR, статус и наличие старого ревьюера.
-func (s *PRService) getPRForReassign(ctx context.Context, prID string) (*models.PullRequest, error) {
-	pr, err := s.prRepo.GetPRByID(ctx, prID)
+func (s *PRService) getPRForReassign(ctx context.Context, repository, prID string) (*models.PullRequest, error) {
+	pr, err := s.prRepo.GetPRByID(ctx, repository, prID)
 	if err != nil {
 		if errors.Is(err, apperrors.ErrNotFound) {
-			s.log.WarnContext(ctx, "PR not found for reassign", slog.String("pr_id", prID))
+			s.log.WarnContext(ctx, "PR not found for reassign",
+				slog.String("repository", repository),
+				slog.String("pr_id", prID))
 		}
 		return nil, err
 	}
-	if pr.Status == "MERGED" {
+	switch pr.Status {
+	case "MERGED":
 		return nil, apperrors.ErrPRMerged
+	case "CLOSED":
+		return nil, apperrors.ErrPRClosed
 	}
 	return pr, nil
 }
 
-// selectNewReviewer selects a new reviewer from the active team members.
+// selectNewReviewer selects a new reviewer from the available members of the old reviewer's team, or of its
+// fallback teams, below their open review limit. It returns the reviewer and the team they were drawn from.
 func (s *PRService) selectNewReviewer(
 	ctx context.Context,
 	pr *models.PullRequest,
 	oldReviewerID string,
-) (string, error) {
+) (string, string, error) {
 	if !slices.Contains(pr.Reviewers, oldReviewerID) {
-		return "", apperrors.ErrNotAssigned
+		return "", "", apperrors.ErrNotAssigned
 	}
 
 	oldReviewer, err := s.userRepo.GetUserByID(ctx, oldReviewerID)
@@ -164,80 +315,629 @@ func (s *PRService) selectNewReviewer(
 		s.log.ErrorContext(ctx, "failed to get old reviewer",
 			slog.String("reviewer_id", oldReviewerID),
 			slog.String("error", err.Error()))
-		return "", apperrors.Wrap(err, "old reviewer fetch failed")
+		return "", "", apperrors.Wrap(err, "old reviewer fetch failed")
+	}
+
+	exclude := map[string]bool{pr.AuthorID: true}
+	for _, r := range pr.Reviewers {
+		exclude[r] = true
+	}
+
+	candidates, err := s.teamCandidates(ctx, oldReviewer.TeamName, exclude)
+	if err != nil {
+		return "", "", err
+	}
+
+	selected, reviewerTeams, err := s.selectReviewers(ctx, oldReviewer.TeamName, candidates, exclude, 1)
+	if err != nil {
+		return "", "", err
 	}
+	if len(selected) == 0 {
+		return "", "", apperrors.ErrNoCandidate
+	}
+	return selected[0], reviewerTeams[selected[0]], nil
+}
 
-	activeInTeam, err := s.userRepo.GetActiveUsersByTeam(ctx, oldReviewer.TeamName)
+// teamCandidates returns the available members of the team below their open review limit, except excluded users.
+func (s *PRService) teamCandidates(ctx context.Context, teamName string, exclude map[string]bool) ([]string, error) {
+	users, err := s.userRepo.GetAvailableUsersByTeam(ctx, teamName)
 	if err != nil {
-		s.log.ErrorContext(ctx, "failed to get active team users for reassign",
-			slog.String("team_name", oldReviewer.TeamName),
+		s.log.ErrorContext(ctx, "failed to get available team users",
+			slog.String("team_name", teamName),
 			slog.String("error", err.Error()))
-		return "", apperrors.Wrap(err, "team users fetch failed")
+		return nil, apperrors.Wrap(err, "team users fetch failed")
 	}
 
-	exclude := map[string]bool{pr.AuthorID: true}
-	for _, r := range pr.Reviewers {
-		exclude[r] = true
+	users, err = filterByCapacity(ctx, s.prRepo, teamName, users)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to check reviewer capacity",
+			slog.String("team_name", teamName),
+			slog.String("error", err.Error()))
+		return nil, err
 	}
 
-	candidates := []string{}
-	for _, u := range activeInTeam {
+	candidates := make([]string, 0, len(users))
+	for _, u := range users {
 		if !exclude[u.ID] {
 			candidates = append(candidates, u.ID)
 		}
 	}
+	return candidates, nil
+}
 
-	if len(candidates) == 0 {
-		return "", apperrors.ErrNoCandidate
+// selectReviewers picks up to count reviewers from the home team candidates and, while that falls short,
+// from the home team's fallback teams in their configured order. It returns the reviewers and the team
+// each of them was drawn from. Selected users are added to exclude.
+func (s *PRService) selectReviewers(
+	ctx context.Context,
+	homeTeam string,
+	candidates []string,
+	exclude map[string]bool,
+	count int,
+) ([]string, map[string]string, error) {
+	reviewers := []string{}
+	reviewerTeams := map[string]string{}
+	if count <= 0 {
+		return reviewers, reviewerTeams, nil
 	}
 
-	//nolint:gosec // for this app is allowed to use rand/v2
-	newReviewer := candidates[rand.IntN(len(candidates))]
-	return newReviewer, nil
-}
+	pick := func(teamName string, candidates []string) error {
+		if len(candidates) == 0 {
+			return nil
+		}
+		selected, err := s.selector.Select(ctx, teamName, candidates, count-len(reviewers))
+		if err != nil {
+			s.log.ErrorContext(ctx, "failed to select reviewers",
+				slog.String("team_name", teamName),
+				slog.String("error", err.Error()))
+			return apperrors.Wrap(err, "reviewer selection failed")
+		}
+		for _, id := range selected {
+			exclude[id] = true
+			reviewerTeams[id] = teamName
+		}
+		reviewers = append(reviewers, selected...)
+		return nil
+	}
+
+	if err := pick(homeTeam, candidates); err != nil {
+		return nil, nil, err
+	}
+	if len(reviewers) >= count {
+		return reviewers, reviewerTeams, nil
+	}
 
-// replaceReviewerInPR replaces the old reviewer with the new one and updates the NeedMoreReviewers flag.
-func (s *PRService) replaceReviewerInPR(pr *models.PullRequest, oldReviewerID, newReviewer string) {
-	const minReviewers = 2
+	fallbackTeams, err := s.teamRepo.GetFallbackTeams(ctx, homeTeam)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to get fallback teams",
+			slog.String("team_name", homeTeam),
+			slog.String("error", err.Error()))
+		return nil, nil, apperrors.Wrap(err, "fallback teams fetch failed")
+	}
 
+	for _, teamName := range fallbackTeams {
+		if len(reviewers) >= count {
+			break
+		}
+		fallbackCandidates, candidatesErr := s.teamCandidates(ctx, teamName, exclude)
+		if candidatesErr != nil {
+			return nil, nil, candidatesErr
+		}
+		before := len(reviewers)
+		if pickErr := pick(teamName, fallbackCandidates); pickErr != nil {
+			return nil, nil, pickErr
+		}
+		if len(reviewers) > before {
+			s.log.InfoContext(ctx, "reviewers drawn from fallback team",
+				slog.String("team_name", homeTeam),
+				slog.String("fallback_team", teamName),
+				slog.Int("count", len(reviewers)-before))
+		}
+	}
+	return reviewers, reviewerTeams, nil
+}
+
+// replaceReviewerInPR replaces the old reviewer with the new one.
+// The reviewer count does not change, so NeedMoreReviewers stays as it was.
+func (s *PRService) replaceReviewerInPR(pr *models.PullRequest, oldReviewerID, newReviewer, newReviewerTeam string) {
 	for i, r := range pr.Reviewers {
 		if r == oldReviewerID {
 			pr.Reviewers[i] = newReviewer
 			break
 		}
 	}
-	pr.NeedMoreReviewers = len(pr.Reviewers) < minReviewers
+
+	if pr.ReviewerTeams == nil {
+		pr.ReviewerTeams = map[string]string{}
+	}
+	delete(pr.ReviewerTeams, oldReviewerID)
+	pr.ReviewerTeams[newReviewer] = newReviewerTeam
 }
 
-// MergePR sets status to MERGED.
-func (s *PRService) MergePR(ctx context.Context, prID string) (*models.PullRequest, error) {
+// GetPR returns the PR with its reviewers' review states.
+func (s *PRService) GetPR(ctx context.Context, repository, prID string) (*models.PullRequest, error) {
 	if prID == "" {
 		return nil, apperrors.ErrInvalidInput
 	}
 
-	err := s.prRepo.MergePR(ctx, prID)
+	pr, err := s.prRepo.GetPRByID(ctx, repository, prID)
 	if err != nil {
-		s.log.ErrorContext(ctx, "failed to merge PR",
+		if errors.Is(err, apperrors.ErrNotFound) {
+			s.log.WarnContext(ctx, "PR not found",
+				slog.String("repository", repository),
+				slog.String("pr_id", prID))
+		} else {
+			s.log.ErrorContext(ctx, "failed to get PR",
+				slog.String("pr_id", prID),
+				slog.String("error", err.Error()))
+		}
+		return nil, err
+	}
+	return pr, nil
+}
+
+// GetPRHistory returns the timeline of reviewer assignments and removals of the PR.
+func (s *PRService) GetPRHistory(
+	ctx context.Context,
+	repository, prID string,
+) ([]models.AssignmentEvent, error) {
+	if prID == "" {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	exists, err := s.prRepo.ExistsPR(ctx, repository, prID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to check PR existence",
+			slog.String("pr_id", prID),
+			slog.String("error", err.Error()))
+		return nil, err
+	}
+	if !exists {
+		s.log.WarnContext(ctx, "PR not found for history",
+			slog.String("repository", repository),
+			slog.String("pr_id", prID))
+		return nil, apperrors.ErrNotFound
+	}
+
+	history, err := s.prRepo.GetAssignmentHistory(ctx, repository, prID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to get assignment history",
 			slog.String("pr_id", prID),
 			slog.String("error", err.Error()))
 		return nil, err
 	}
+	return history, nil
+}
+
+// SubmitReview records the reviewer's verdict (APPROVED or CHANGES_REQUESTED) on an OPEN PR.
+// A later verdict replaces the earlier one.
+func (s *PRService) SubmitReview(
+	ctx context.Context,
+	repository, prID, reviewerID, state string,
+) (*models.PullRequest, error) {
+	if prID == "" || reviewerID == "" {
+		return nil, apperrors.ErrInvalidInput
+	}
+	if state != models.ReviewStateApproved && state != models.ReviewStateChangesRequested {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidInput, "unknown review state: "+state)
+	}
 
-	pr, err := s.prRepo.GetPRByID(ctx, prID)
+	pr, err := s.prRepo.GetPRByID(ctx, repository, prID)
 	if err != nil {
 		if errors.Is(err, apperrors.ErrNotFound) {
-			s.log.WarnContext(ctx, "PR not found after merge", slog.String("pr_id", prID))
+			s.log.WarnContext(ctx, "PR not found for review",
+				slog.String("repository", repository),
+				slog.String("pr_id", prID))
 		}
 		return nil, err
 	}
+	switch pr.Status {
+	case "MERGED":
+		return nil, apperrors.ErrPRMerged
+	case "CLOSED":
+		return nil, apperrors.ErrPRClosed
+	}
+	if !slices.Contains(pr.Reviewers, reviewerID) {
+		return nil, apperrors.ErrNotAssigned
+	}
+
+	var reloaded *models.PullRequest
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if setErr := s.prRepo.SetReviewState(ctx, repository, prID, reviewerID, state); setErr != nil {
+			s.log.ErrorContext(ctx, "failed to set review state",
+				slog.String("pr_id", prID),
+				slog.String("reviewer_id", reviewerID),
+				slog.String("error", setErr.Error()))
+			return setErr
+		}
+
+		var getErr error
+		reloaded, getErr = s.prRepo.GetPRByID(ctx, repository, prID)
+		if getErr != nil {
+			s.log.ErrorContext(ctx, "failed to reload PR after review",
+				slog.String("pr_id", prID),
+				slog.String("error", getErr.Error()))
+			return apperrors.ErrInternal
+		}
+		return s.audit.Record(ctx, models.AuditPRReviewed, models.AuditEntityPullRequest, prID, pr, reloaded)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	s.log.InfoContext(ctx, "review submitted",
+		slog.String("pr_id", prID),
+		slog.String("reviewer_id", reviewerID),
+		slog.String("state", state))
+	return reloaded, nil
+}
+
+// MergePR sets status to MERGED. CLOSED PRs have to be reopened first.
+// When the PR's repository or team sets a merge policy, the PR needs the required approvals and no outstanding
+// CHANGES_REQUESTED verdicts. force merges past the policy; each such override is recorded with reason.
+func (s *PRService) MergePR(
+	ctx context.Context,
+	repository, prID string,
+	force bool,
+	reason string,
+) (*models.PullRequest, error) {
+	if prID == "" {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	pr, err := s.prRepo.GetPRByID(ctx, repository, prID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			s.log.WarnContext(ctx, "PR not found for merge",
+				slog.String("repository", repository),
+				slog.String("pr_id", prID))
+		}
+		return nil, err
+	}
+	switch pr.Status {
+	case "CLOSED":
+		return nil, apperrors.ErrPRClosed
+	case "MERGED":
+		return pr, nil
+	}
+
+	var blocked *apperrors.MergeBlockedError
+	err = s.checkMergePolicy(ctx, pr)
+	if errors.As(err, &blocked) && !force {
+		s.log.InfoContext(ctx, "merge blocked by policy",
+			slog.String("pr_id", prID),
+			slog.Int("approvals", blocked.Approvals),
+			slog.Int("required", blocked.RequiredApprovals),
+			slog.Any("pending", blocked.PendingReviewers),
+			slog.Any("changes_requested_by", blocked.ChangesRequestedBy))
+		return nil, err
+	}
+	if err != nil && blocked == nil {
+		return nil, err
+	}
+
+	before := pr
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		action := models.AuditPRMerged
+		var mergeErr error
+		if blocked == nil {
+			mergeErr = s.prRepo.MergePR(ctx, repository, prID)
+		} else {
+			action = models.AuditPRForceMerged
+			mergeErr = s.prRepo.ForceMergePR(ctx, &models.MergeOverride{
+				Repository:         repository,
+				PRID:               prID,
+				Reason:             reason,
+				RequiredApprovals:  blocked.RequiredApprovals,
+				Approvals:          blocked.Approvals,
+				PendingReviewers:   blocked.PendingReviewers,
+				ChangesRequestedBy: blocked.ChangesRequestedBy,
+			})
+		}
+		if mergeErr != nil {
+			s.log.ErrorContext(ctx, "failed to merge PR",
+				slog.String("pr_id", prID),
+				slog.String("error", mergeErr.Error()))
+			return mergeErr
+		}
+
+		var getErr error
+		pr, getErr = s.prRepo.GetPRByID(ctx, repository, prID)
+		if getErr != nil {
+			if errors.Is(getErr, apperrors.ErrNotFound) {
+				s.log.WarnContext(ctx, "PR not found after merge",
+					slog.String("repository", repository),
+					slog.String("pr_id", prID))
+			}
+			return getErr
+		}
+		if pr.Status == "CLOSED" {
+			return apperrors.ErrPRClosed
+		}
+		return s.audit.Record(ctx, action, models.AuditEntityPullRequest, prID, before, pr)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if blocked != nil {
+		s.log.WarnContext(ctx, "PR force-merged past merge policy",
+			slog.String("pr_id", prID),
+			slog.String("reason", reason),
+			slog.Any("pending", blocked.PendingReviewers),
+			slog.Any("changes_requested_by", blocked.ChangesRequestedBy))
+	}
 	s.log.InfoContext(ctx, "PR merged", slog.String("pr_id", prID), slog.String("title", pr.Title))
 	return pr, nil
 }
 
+// checkMergePolicy returns a *apperrors.MergeBlockedError when the PR lacks the approvals its merge policy
+// requires or has outstanding CHANGES_REQUESTED verdicts. PRs without a merge policy always pass.
+func (s *PRService) checkMergePolicy(ctx context.Context, pr *models.PullRequest) error {
+	required, ok, err := s.requiredApprovals(ctx, pr)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to get merge policy",
+			slog.String("pr_id", pr.ID),
+			slog.String("error", err.Error()))
+		return apperrors.Wrap(err, "merge policy fetch failed")
+	}
+	if !ok {
+		return nil
+	}
+
+	blocked := &apperrors.MergeBlockedError{
+		RequiredApprovals:  required,
+		PendingReviewers:   []string{},
+		ChangesRequestedBy: []string{},
+	}
+	for _, id := range pr.Reviewers {
+		switch pr.ReviewStates[id] {
+		case models.ReviewStateApproved:
+			blocked.Approvals++
+		case models.ReviewStateChangesRequested:
+			blocked.ChangesRequestedBy = append(blocked.ChangesRequestedBy, id)
+		default:
+			blocked.PendingReviewers = append(blocked.PendingReviewers, id)
+		}
+	}
+	if blocked.Approvals >= required && len(blocked.ChangesRequestedBy) == 0 {
+		return nil
+	}
+	return blocked
+}
+
+// requiredApprovals returns how many approvals the PR needs before merge and whether a merge policy applies.
+// The repository's policy takes precedence over the policy of the team its reviewers are drawn from.
+func (s *PRService) requiredApprovals(ctx context.Context, pr *models.PullRequest) (int, bool, error) {
+	repo, err := repositoryConfig(ctx, s.repoRepo, pr.Repository)
+	if err != nil {
+		return 0, false, err
+	}
+	if repo != nil && repo.Settings != nil && repo.Settings.RequiredApprovals != nil {
+		return *repo.Settings.RequiredApprovals, true, nil
+	}
+
+	var teamName string
+	if repo != nil && repo.Settings != nil && repo.Settings.DefaultTeamName != nil {
+		teamName = *repo.Settings.DefaultTeamName
+	} else {
+		author, authorErr := s.userRepo.GetUserByID(ctx, pr.AuthorID)
+		if errors.Is(authorErr, apperrors.ErrNotFound) {
+			return 0, false, nil
+		}
+		if authorErr != nil {
+			return 0, false, authorErr
+		}
+		teamName = author.TeamName
+	}
+
+	settings, err := s.teamRepo.GetTeamSettings(ctx, teamName)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	if settings.RequiredApprovals == nil {
+		return 0, false, nil
+	}
+	return *settings.RequiredApprovals, true, nil
+}
+
+// ClosePR sets status to CLOSED, declining the PR without merging. Closing a CLOSED PR is a no-op.
+func (s *PRService) ClosePR(ctx context.Context, repository, prID string) (*models.PullRequest, error) {
+	if prID == "" {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	before, err := s.prRepo.GetPRByID(ctx, repository, prID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			s.log.WarnContext(ctx, "PR not found for close",
+				slog.String("repository", repository),
+				slog.String("pr_id", prID))
+		}
+		return nil, err
+	}
+	switch before.Status {
+	case "MERGED":
+		return nil, apperrors.ErrPRMerged
+	case "CLOSED":
+		return before, nil
+	}
+
+	var pr *models.PullRequest
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if closeErr := s.prRepo.ClosePR(ctx, repository, prID); closeErr != nil {
+			s.log.ErrorContext(ctx, "failed to close PR",
+				slog.String("pr_id", prID),
+				slog.String("error", closeErr.Error()))
+			return closeErr
+		}
+
+		var getErr error
+		pr, getErr = s.prRepo.GetPRByID(ctx, repository, prID)
+		if getErr != nil {
+			if errors.Is(getErr, apperrors.ErrNotFound) {
+				s.log.WarnContext(ctx, "PR not found after close",
+					slog.String("repository", repository),
+					slog.String("pr_id", prID))
+			}
+			return getErr
+		}
+		if pr.Status == "MERGED" {
+			return apperrors.ErrPRMerged
+		}
+		return s.audit.Record(ctx, models.AuditPRClosed, models.AuditEntityPullRequest, prID, before, pr)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.InfoContext(ctx, "PR closed", slog.String("pr_id", prID), slog.String("title", pr.Title))
+	return pr, nil
+}
+
+// ReopenPR sets a CLOSED PR back to OPEN. Reviewers that are no longer available — inactive, absent or at
+// their open review limit — are dropped and their slots refilled the same way CreatePR assigns reviewers.
+// Reopening an OPEN PR is a no-op.
+func (s *PRService) ReopenPR(ctx context.Context, repository, prID string) (*models.PullRequest, error) {
+	if prID == "" {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	pr, err := s.prRepo.GetPRByID(ctx, repository, prID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			s.log.WarnContext(ctx, "PR not found for reopen",
+				slog.String("repository", repository),
+				slog.String("pr_id", prID))
+		}
+		return nil, err
+	}
+	switch pr.Status {
+	case "MERGED":
+		return nil, apperrors.ErrPRMerged
+	case "OPEN":
+		return pr, nil
+	}
+	before := snapshotPR(pr)
+
+	author, err := s.userRepo.GetUserByID(ctx, pr.AuthorID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to get author",
+			slog.String("author_id", pr.AuthorID),
+			slog.String("error", err.Error()))
+		return nil, apperrors.Wrap(err, "author fetch failed")
+	}
+
+	repo, err := repositoryConfig(ctx, s.repoRepo, pr.Repository)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to get repository",
+			slog.String("repository", pr.Repository),
+			slog.String("error", err.Error()))
+		return nil, apperrors.Wrap(err, "repository fetch failed")
+	}
+
+	homeTeam, reviewersCount, err := reviewerTarget(ctx, s.teamRepo, repo, author.TeamName, s.defaultReviewers)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to get team reviewers count",
+			slog.String("team_name", homeTeam),
+			slog.String("error", err.Error()))
+		return nil, apperrors.Wrap(err, "team settings fetch failed")
+	}
+
+	kept, err := s.availableReviewers(ctx, pr.Reviewers)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := map[string]bool{pr.AuthorID: true}
+	for _, r := range pr.Reviewers {
+		exclude[r] = true
+	}
+	candidates, err := s.teamCandidates(ctx, homeTeam, exclude)
+	if err != nil {
+		return nil, err
+	}
+	added, _, err := s.selectReviewers(ctx, homeTeam, candidates, exclude, reviewersCount-len(kept))
+	if err != nil {
+		return nil, err
+	}
+
+	pr.Reviewers = append(kept, added...)
+	pr.NeedMoreReviewers = len(pr.Reviewers) < reviewersCount
+	var reloaded *models.PullRequest
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		reopenErr := s.prRepo.ReopenPR(ctx, pr, assignmentChange(ctx, models.AssignReasonReopened))
+		if reopenErr != nil {
+			s.log.ErrorContext(ctx, "failed to reopen PR",
+				slog.String("pr_id", prID),
+				slog.String("error", reopenErr.Error()))
+			return reopenErr
+		}
+
+		var getErr error
+		reloaded, getErr = s.prRepo.GetPRByID(ctx, repository, prID)
+		if getErr != nil {
+			s.log.ErrorContext(ctx, "failed to reload PR after reopen",
+				slog.String("pr_id", prID),
+				slog.String("error", getErr.Error()))
+			return apperrors.ErrInternal
+		}
+		return s.audit.Record(ctx, models.AuditPRReopened, models.AuditEntityPullRequest, prID, before, reloaded)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.InfoContext(ctx, "PR reopened",
+		slog.String("pr_id", prID),
+		slog.String("repository", repository),
+		slog.Int("kept", len(kept)),
+		slog.Int("added", len(added)),
+		slog.Bool("need_more", reloaded.NeedMoreReviewers))
+	return reloaded, nil
+}
+
+// availableReviewers returns the reviewers that could still be assigned to a new PR, in their original
+// order: they exist and are candidates of their team by the rules of teamCandidates.
+func (s *PRService) availableReviewers(ctx context.Context, reviewers []string) ([]string, error) {
+	teamCandidates := map[string][]string{}
+	available := make([]string, 0, len(reviewers))
+	for _, id := range reviewers {
+		u, err := s.userRepo.GetUserByID(ctx, id)
+		if errors.Is(err, apperrors.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			s.log.ErrorContext(ctx, "failed to get reviewer",
+				slog.String("reviewer_id", id),
+				slog.String("error", err.Error()))
+			return nil, apperrors.Wrap(err, "reviewer fetch failed")
+		}
+		if !u.IsActive {
+			continue
+		}
+
+		candidates, ok := teamCandidates[u.TeamName]
+		if !ok {
+			candidates, err = s.teamCandidates(ctx, u.TeamName, nil)
+			if err != nil {
+				return nil, err
+			}
+			teamCandidates[u.TeamName] = candidates
+		}
+		if slices.Contains(candidates, id) {
+			available = append(available, id)
+		}
+	}
+	return available, nil
+}
+
 // GetTotalPRs returns the total count of all pull requests.
-func (s *PRService) GetTotalPRs(ctx context.Context) (int, error) {
-	total, err := s.prRepo.GetTotalPRs(ctx)
+func (s *PRService) GetTotalPRs(ctx context.Context, repository string) (int, error) {
+	total, err := s.prRepo.GetTotalPRs(ctx, repository)
 	if err != nil {
 		s.log.ErrorContext(ctx, "failed to get total PRs", slog.String("error", err.Error()))
 		return 0, err
@@ -246,20 +946,23 @@ func (s *PRService) GetTotalPRs(ctx context.Context) (int, error) {
 	return total, nil
 }
 
-// GetPrsByStatus returns the count of open and merged pull requests.
-func (s *PRService) GetPrsByStatus(ctx context.Context) (int, int, error) {
-	open, merged, err := s.prRepo.GetPrsByStatus(ctx)
+// GetPrsByStatus returns the count of open, merged and closed pull requests.
+func (s *PRService) GetPrsByStatus(ctx context.Context, repository string) (models.PrsStatus, error) {
+	status, err := s.prRepo.GetPrsByStatus(ctx, repository)
 	if err != nil {
 		s.log.ErrorContext(ctx, "failed to get PRs by status", slog.String("error", err.Error()))
-		return 0, 0, err
+		return models.PrsStatus{}, err
 	}
-	s.log.InfoContext(ctx, "PRs by status fetched", slog.Int("open", open), slog.Int("merged", merged))
-	return open, merged, nil
+	s.log.InfoContext(ctx, "PRs by status fetched",
+		slog.Int("open", status.OpenPRs),
+		slog.Int("merged", status.MergedPRs),
+		slog.Int("closed", status.ClosedPRs))
+	return status, nil
 }
 
 // GetAssignmentsPerUser returns the number of PR assignments per active user, ordered by count descending.
-func (s *PRService) GetAssignmentsPerUser(ctx context.Context) ([]models.UserAssignment, error) {
-	assignments, err := s.prRepo.GetAssignmentsPerUser(ctx)
+func (s *PRService) GetAssignmentsPerUser(ctx context.Context, repository string) ([]models.UserAssignment, error) {
+	assignments, err := s.prRepo.GetAssignmentsPerUser(ctx, repository)
 	if err != nil {
 		s.log.ErrorContext(ctx, "failed to get assignments per user", slog.String("error", err.Error()))
 		return nil, err
@@ -269,8 +972,8 @@ func (s *PRService) GetAssignmentsPerUser(ctx context.Context) ([]models.UserAss
 }
 
 // GetTopReviewers returns the top 5 reviewers by assignment count.
-func (s *PRService) GetTopReviewers(ctx context.Context) ([]models.UserAssignment, error) {
-	top, err := s.prRepo.GetTopReviewers(ctx)
+func (s *PRService) GetTopReviewers(ctx context.Context, repository string) ([]models.UserAssignment, error) {
+	top, err := s.prRepo.GetTopReviewers(ctx, repository)
 	if err != nil {
 		s.log.ErrorContext(ctx, "failed to get top reviewers", slog.String("error", err.Error()))
 		return nil, err
@@ -280,14 +983,14 @@ func (s *PRService) GetTopReviewers(ctx context.Context) ([]models.UserAssignmen
 }
 
 // GetAvgCloseTime returns the average time to close merged PRs with breakdown by days, hours, minutes, and seconds.
-func (s *PRService) GetAvgCloseTime(ctx context.Context) (models.AvgCloseTimeDetail, error) {
+func (s *PRService) GetAvgCloseTime(ctx context.Context, repository string) (models.AvgCloseTimeDetail, error) {
 	const (
 		secondsPerDay    = 86400
 		secondsPerHour   = 3600
 		secondsPerMinute = 60
 	)
 
-	avgSeconds, count, err := s.prRepo.GetAvgCloseTime(ctx)
+	avgSeconds, count, err := s.prRepo.GetAvgCloseTime(ctx, repository)
 	if err != nil {
 		s.log.ErrorContext(ctx, "failed to get avg close time", slog.String("error", err.Error()))
 		return models.AvgCloseTimeDetail{}, err
@@ -313,8 +1016,8 @@ func (s *PRService) GetAvgCloseTime(ctx context.Context) (models.AvgCloseTimeDet
 }
 
 // GetIdleUsersPerTeam returns the count of active users with zero PR assignments, grouped by team.
-func (s *PRService) GetIdleUsersPerTeam(ctx context.Context) ([]models.TeamMetric, error) {
-	metrics, err := s.prRepo.GetIdleUsersPerTeam(ctx)
+func (s *PRService) GetIdleUsersPerTeam(ctx context.Context, repository string) ([]models.TeamMetric, error) {
+	metrics, err := s.prRepo.GetIdleUsersPerTeam(ctx, repository)
 	if err != nil {
 		s.log.ErrorContext(ctx, "failed to get idle users per team", slog.String("error", err.Error()))
 		return nil, err
@@ -324,8 +1027,8 @@ func (s *PRService) GetIdleUsersPerTeam(ctx context.Context) ([]models.TeamMetri
 }
 
 // GetNeedyPRsPerTeam returns the count of open PRs that need more reviewers, grouped by author's team.
-func (s *PRService) GetNeedyPRsPerTeam(ctx context.Context) ([]models.TeamMetric, error) {
-	metrics, err := s.prRepo.GetNeedyPRsPerTeam(ctx)
+func (s *PRService) GetNeedyPRsPerTeam(ctx context.Context, repository string) ([]models.TeamMetric, error) {
+	metrics, err := s.prRepo.GetNeedyPRsPerTeam(ctx, repository)
 	if err != nil {
 		s.log.ErrorContext(ctx, "failed to get needy PRs per team", slog.String("error", err.Error()))
 		return nil, err
@@ -333,3 +1036,17 @@ func (s *PRService) GetNeedyPRsPerTeam(ctx context.Context) ([]models.TeamMetric
 	s.log.InfoContext(ctx, "needy PRs per team fetched", slog.Int("teams_count", len(metrics)))
 	return metrics, nil
 }
+
+// assignmentChange attributes reviewer changes made for reason to the caller recorded in ctx.
+func assignmentChange(ctx context.Context, reason string) models.AssignmentChange {
+	return models.AssignmentChange{Actor: reqctx.Actor(ctx), Reason: reason}
+}
+
+// snapshotPR copies the PR for its audit event before it is changed in place.
+func snapshotPR(pr *models.PullRequest) *models.PullRequest {
+	snapshot := *pr
+	snapshot.Reviewers = slices.Clone(pr.Reviewers)
+	snapshot.ReviewerTeams = maps.Clone(pr.ReviewerTeams)
+	snapshot.ReviewStates = maps.Clone(pr.ReviewStates)
+	return &snapshot
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+)
+
+// reminderBatchSize caps the reviews claimed by one transaction of RemindStale.
+const reminderBatchSize = 100
+
+// ReminderService reminds reviewers of the reviews they have left pending for too long. Each reminder is
+// a review.stale event; a review still pending is reminded of again every staleAfter.
+type ReminderService struct {
+	prRepo     repository.PRRepository
+	tx         repository.Transactor
+	outbox     *OutboxService
+	staleAfter time.Duration
+	log        *slog.Logger
+}
+
+var _ ReminderServiceInterface = (*ReminderService)(nil)
+
+func NewReminderService(
+	prRepo repository.PRRepository,
+	tx repository.Transactor,
+	outbox *OutboxService,
+	staleAfter time.Duration,
+	log *slog.Logger,
+) *ReminderService {
+	return &ReminderService{
+		prRepo:     prRepo,
+		tx:         tx,
+		outbox:     outbox,
+		staleAfter: staleAfter,
+		log:        log,
+	}
+}
+
+// RemindStale publishes a review.stale event for every review pending for staleAfter since its reviewer
+// was assigned or last reminded, and returns how many were published. A review is marked reminded in the
+// transaction that publishes its event.
+func (s *ReminderService) RemindStale(ctx context.Context) (int, error) {
+	reminded := 0
+	for ctx.Err() == nil {
+		var claimed int
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			reviews, err := s.prRepo.ClaimStaleReviews(ctx, s.staleAfter, reminderBatchSize)
+			if err != nil {
+				return err
+			}
+			for i := range reviews {
+				review := &reviews[i]
+				event := prEvent(models.EventReviewStale, &review.PullRequest)
+				event.Data.ReviewerID = review.ReviewerID
+				event.Data.AssignedAt = &review.AssignedAt
+				if err = s.outbox.Publish(ctx, &event); err != nil {
+					return err
+				}
+			}
+			claimed = len(reviews)
+			return nil
+		})
+		if err != nil {
+			s.log.ErrorContext(ctx, "failed to remind of stale reviews", slog.String("error", err.Error()))
+			return reminded, err
+		}
+		reminded += claimed
+		if claimed < reminderBatchSize {
+			break
+		}
+	}
+
+	if reminded > 0 {
+		s.log.InfoContext(ctx, "reviewers reminded of stale reviews", slog.Int("count", reminded))
+	}
+	return reminded, nil
+}
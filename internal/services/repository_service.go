@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/codeowners"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+)
+
+type RepositoryService struct {
+	repoRepo         repository.RepositoryRepository
+	defaultReviewers int
+	log              *slog.Logger
+}
+
+var _ RepositoryServiceInterface = (*RepositoryService)(nil)
+
+func NewRepositoryService(
+	repoRepo repository.RepositoryRepository,
+	defaultReviewers int,
+	log *slog.Logger,
+) *RepositoryService {
+	return &RepositoryService{repoRepo: repoRepo, defaultReviewers: defaultReviewers, log: log}
+}
+
+// SetCodeOwners validates and stores a CODEOWNERS file for the repository. An empty mode means preferred.
+func (s *RepositoryService) SetCodeOwners(
+	ctx context.Context,
+	name, content, mode string,
+) (*models.Repository, error) {
+	if mode == "" {
+		mode = models.CodeOwnersPreferred
+	}
+	if name == "" || (mode != models.CodeOwnersPreferred && mode != models.CodeOwnersRequired) {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	if _, err := codeowners.Parse(content); err != nil {
+		s.log.WarnContext(ctx, "invalid CODEOWNERS upload",
+			slog.String("repository", name),
+			slog.String("error", err.Error()))
+		return nil, apperrors.Wrap(apperrors.ErrInvalidInput, err.Error())
+	}
+
+	if err := s.repoRepo.SetCodeOwners(ctx, name, content, mode); err != nil {
+		s.log.ErrorContext(ctx, "failed to store CODEOWNERS",
+			slog.String("repository", name),
+			slog.String("error", err.Error()))
+		return nil, apperrors.ErrInternal
+	}
+
+	s.log.InfoContext(ctx, "CODEOWNERS updated",
+		slog.String("repository", name),
+		slog.String("mode", mode))
+	return &models.Repository{Name: name, CodeOwners: content, CodeOwnersMode: mode}, nil
+}
+
+// GetRepository retrieves repository configuration by name.
+func (s *RepositoryService) GetRepository(ctx context.Context, name string) (*models.Repository, error) {
+	if name == "" {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	repo, err := s.repoRepo.GetRepository(ctx, name)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			s.log.WarnContext(ctx, "repository not found", slog.String("repository", name))
+		} else {
+			s.log.ErrorContext(ctx, "failed to get repository",
+				slog.String("repository", name),
+				slog.String("error", err.Error()))
+		}
+		return nil, err
+	}
+	return repo, nil
+}
+
+// UpdateRepositorySettings replaces the repository's settings, creating the repository if needed.
+// Nil fields reset the repository to the author's team and its reviewer count.
+func (s *RepositoryService) UpdateRepositorySettings(
+	ctx context.Context,
+	name string,
+	settings *models.RepositorySettings,
+) (*models.Repository, error) {
+	if name == "" || settings == nil || !validRepositorySettings(settings) {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	if err := s.repoRepo.UpdateRepositorySettings(ctx, name, settings, s.defaultReviewers); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			s.log.WarnContext(ctx, "default team not found for repository settings",
+				slog.String("repository", name),
+				slog.String("default_team_name", *settings.DefaultTeamName))
+			return nil, apperrors.ErrNotFound
+		}
+		s.log.ErrorContext(ctx, "failed to update repository settings",
+			slog.String("repository", name),
+			slog.String("error", err.Error()))
+		return nil, apperrors.ErrInternal
+	}
+
+	repo, err := s.repoRepo.GetRepository(ctx, name)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to reload repository after settings update",
+			slog.String("repository", name),
+			slog.String("error", err.Error()))
+		return nil, apperrors.ErrInternal
+	}
+
+	s.log.InfoContext(ctx, "repository settings updated", slog.String("repository", name))
+	return repo, nil
+}
+
+// validRepositorySettings checks that the provided overrides are in range.
+func validRepositorySettings(settings *models.RepositorySettings) bool {
+	if settings.DefaultTeamName != nil && *settings.DefaultTeamName == "" {
+		return false
+	}
+	if settings.RequiredApprovals != nil && *settings.RequiredApprovals < 0 {
+		return false
+	}
+	return settings.ReviewersCount == nil || *settings.ReviewersCount > 0
+}
+
+// repositoryConfig returns the configuration of the named repository, or nil when the name is empty
+// or the repository has not been configured.
+func repositoryConfig(
+	ctx context.Context,
+	repoRepo repository.RepositoryRepository,
+	name string,
+) (*models.Repository, error) {
+	if name == "" {
+		return nil, nil //nolint:nilnil // no repository is not an error
+	}
+	repo, err := repoRepo.GetRepository(ctx, name)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, nil //nolint:nilnil // unconfigured repositories use team defaults
+		}
+		return nil, err
+	}
+	return repo, nil
+}
+
+// reviewerTarget returns the team reviewers of a PR are drawn from and how many reviewers it needs.
+// The repository's default team and reviewer count, when set, take precedence over the author's team
+// and its settings.
+func reviewerTarget(
+	ctx context.Context,
+	teamRepo repository.TeamRepository,
+	repo *models.Repository,
+	authorTeam string,
+	defaultCount int,
+) (string, int, error) {
+	teamName := authorTeam
+	var settings *models.RepositorySettings
+	if repo != nil && repo.Settings != nil {
+		settings = repo.Settings
+	}
+	if settings != nil && settings.DefaultTeamName != nil {
+		teamName = *settings.DefaultTeamName
+	}
+	if settings != nil && settings.ReviewersCount != nil {
+		return teamName, *settings.ReviewersCount, nil
+	}
+
+	count, err := reviewersCountForTeam(ctx, teamRepo, teamName, defaultCount)
+	if err != nil {
+		return "", 0, err
+	}
+	return teamName, count, nil
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"math/rand/v2"
+	"slices"
+	"sync"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+)
+
+// Reviewer selection strategies supported by NewReviewerSelector.
+const (
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round-robin"
+	StrategyLeastLoaded = "least-loaded"
+)
+
+// ReviewerSelector decides which of the eligible candidates get assigned to a PR.
+type ReviewerSelector interface {
+	// Select returns up to count user IDs from candidates, in assignment order.
+	// teamName is the team the candidates were drawn from.
+	Select(ctx context.Context, teamName string, candidates []string, count int) ([]string, error)
+}
+
+// NewReviewerSelector builds a selector for the given strategy name.
+func NewReviewerSelector(strategy string, prRepo repository.PRRepository) (ReviewerSelector, error) {
+	switch strategy {
+	case "", StrategyRandom:
+		return NewRandomSelector(), nil
+	case StrategyRoundRobin:
+		return NewRoundRobinSelector(), nil
+	case StrategyLeastLoaded:
+		return NewLeastLoadedSelector(prRepo), nil
+	default:
+		return nil, apperrors.Wrap(apperrors.ErrInvalidInput, "unknown reviewer strategy: "+strategy)
+	}
+}
+
+// RandomSelector picks candidates uniformly at random.
+type RandomSelector struct{}
+
+var _ ReviewerSelector = (*RandomSelector)(nil)
+
+func NewRandomSelector() *RandomSelector {
+	return &RandomSelector{}
+}
+
+// Select shuffles candidates and takes the first count of them.
+func (s *RandomSelector) Select(_ context.Context, _ string, candidates []string, count int) ([]string, error) {
+	shuffled := slices.Clone(candidates)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return takeFirst(shuffled, count), nil
+}
+
+// RoundRobinSelector rotates through team members so that assignments are spread evenly over time.
+// The rotation state is kept in memory per team and resets on restart.
+type RoundRobinSelector struct {
+	mu      sync.Mutex
+	cursors map[string]int
+}
+
+var _ ReviewerSelector = (*RoundRobinSelector)(nil)
+
+func NewRoundRobinSelector() *RoundRobinSelector {
+	return &RoundRobinSelector{cursors: make(map[string]int)}
+}
+
+// Select takes count candidates starting from the team's cursor and advances it.
+func (s *RoundRobinSelector) Select(
+	_ context.Context,
+	teamName string,
+	candidates []string,
+	count int,
+) ([]string, error) {
+	if len(candidates) == 0 || count <= 0 {
+		return []string{}, nil
+	}
+
+	ordered := slices.Clone(candidates)
+	slices.Sort(ordered)
+	n := min(count, len(ordered))
+
+	s.mu.Lock()
+	start := s.cursors[teamName] % len(ordered)
+	s.cursors[teamName] = start + n
+	s.mu.Unlock()
+
+	selected := make([]string, 0, n)
+	for i := range n {
+		selected = append(selected, ordered[(start+i)%len(ordered)])
+	}
+	return selected, nil
+}
+
+// LeastLoadedSelector prefers candidates reviewing the fewest OPEN PRs in their team.
+// Candidates with equal load are picked in random order.
+type LeastLoadedSelector struct {
+	prRepo repository.PRRepository
+}
+
+var _ ReviewerSelector = (*LeastLoadedSelector)(nil)
+
+func NewLeastLoadedSelector(prRepo repository.PRRepository) *LeastLoadedSelector {
+	return &LeastLoadedSelector{prRepo: prRepo}
+}
+
+// Select orders candidates by open review count ascending and takes the first count.
+func (s *LeastLoadedSelector) Select(
+	ctx context.Context,
+	teamName string,
+	candidates []string,
+	count int,
+) ([]string, error) {
+	if len(candidates) == 0 || count <= 0 {
+		return []string{}, nil
+	}
+
+	teamLoad, err := s.prRepo.GetOpenReviewLoadByTeam(ctx, teamName)
+	if err != nil {
+		return nil, apperrors.Wrap(err, "failed to get reviewer load")
+	}
+	load := make(map[string]int, len(teamLoad))
+	for _, a := range teamLoad {
+		load[a.UserID] = a.Count
+	}
+
+	// Shuffle first so that the stable sort breaks ties randomly.
+	ordered := slices.Clone(candidates)
+	rand.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+	slices.SortStableFunc(ordered, func(a, b string) int {
+		return cmp.Compare(load[a], load[b])
+	})
+	return takeFirst(ordered, count), nil
+}
+
+// takeFirst returns at most count leading elements of ids, never nil.
+func takeFirst(ids []string, count int) []string {
+	n := max(min(count, len(ids)), 0)
+	selected := make([]string, 0, n)
+	return append(selected, ids[:n]...)
+}
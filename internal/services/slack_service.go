@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/slack"
+)
+
+// staleDateLayout formats when the reviewer of a stale review was assigned.
+const staleDateLayout = "2006-01-02 15:04 MST"
+
+// SlackService posts the events about a PR to the Slack channel of its author's team, mentioning the
+// users involved by their Slack handle. Teams without a channel get no messages. Slack cannot drop
+// duplicates, so an event redispatched after another sink failed is posted again.
+type SlackService struct {
+	repo     repository.SlackRepository
+	userRepo repository.UserRepository
+	teamRepo repository.TeamRepository
+	client   *http.Client
+	baseURL  string
+	log      *slog.Logger
+}
+
+var (
+	_ SlackServiceInterface = (*SlackService)(nil)
+	_ EventSink             = (*SlackService)(nil)
+)
+
+func NewSlackService(
+	repo repository.SlackRepository,
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	client *http.Client,
+	baseURL string,
+	log *slog.Logger,
+) *SlackService {
+	return &SlackService{
+		repo:     repo,
+		userRepo: userRepo,
+		teamRepo: teamRepo,
+		client:   client,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		log:      log,
+	}
+}
+
+// SetTeamChannel sets the incoming webhook of the team's channel to webhookPath, the path of its URL
+// under the base URL. An empty path removes the channel.
+func (s *SlackService) SetTeamChannel(ctx context.Context, teamName, webhookPath string) error {
+	if teamName == "" || (webhookPath != "" && !strings.HasPrefix(webhookPath, "/")) {
+		return apperrors.ErrInvalidInput
+	}
+	if _, err := s.teamRepo.GetTeamByName(ctx, teamName); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			s.log.WarnContext(ctx, "team not found for slack channel", slog.String("team_name", teamName))
+		}
+		return err
+	}
+
+	if webhookPath == "" {
+		if err := s.repo.DeleteTeamChannel(ctx, teamName); err != nil {
+			return err
+		}
+		s.log.InfoContext(ctx, "slack channel removed", slog.String("team_name", teamName))
+		return nil
+	}
+	if err := s.repo.SetTeamChannel(ctx, teamName, webhookPath); err != nil {
+		s.log.ErrorContext(ctx, "failed to set slack channel",
+			slog.String("team_name", teamName),
+			slog.String("error", err.Error()))
+		return err
+	}
+	s.log.InfoContext(ctx, "slack channel set", slog.String("team_name", teamName))
+	return nil
+}
+
+// SetUserHandle sets the Slack member ID the user is mentioned by. An empty handle removes it; the user is
+// then named by their ID.
+func (s *SlackService) SetUserHandle(ctx context.Context, userID, handle string) error {
+	if userID == "" || (handle != "" && !slack.ValidHandle(handle)) {
+		return apperrors.ErrInvalidInput
+	}
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			s.log.WarnContext(ctx, "user not found for slack handle", slog.String("user_id", userID))
+		}
+		return err
+	}
+
+	if handle == "" {
+		if err := s.repo.DeleteUserHandle(ctx, userID); err != nil {
+			return err
+		}
+		s.log.InfoContext(ctx, "slack handle removed", slog.String("user_id", userID))
+		return nil
+	}
+	if err := s.repo.SetUserHandle(ctx, userID, handle); err != nil {
+		s.log.ErrorContext(ctx, "failed to set slack handle",
+			slog.String("user_id", userID),
+			slog.String("error", err.Error()))
+		return err
+	}
+	s.log.InfoContext(ctx, "slack handle set", slog.String("user_id", userID), slog.String("handle", handle))
+	return nil
+}
+
+// Name records in outbox.delivered_sinks that an event was posted to Slack. Changing it would post events
+// still pending for other sinks a second time.
+func (s *SlackService) Name() string {
+	return "slack"
+}
+
+// Publish posts event to the channel of the PR author's team. Events other than assignments, reassignments,
+// stale reviews and merges are ignored. Messages the webhook refuses for good, such as when it was deleted,
+// are dropped rather than retried; server and network errors are retried.
+func (s *SlackService) Publish(ctx context.Context, event *models.Event) error {
+	pr := event.Data.PullRequest
+	if pr == nil || !slackEvent(event.Type) {
+		return nil
+	}
+
+	teamName, err := s.userRepo.GetTeamNameByUserID(ctx, pr.AuthorID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	path, err := s.repo.GetTeamChannel(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	handles, err := s.repo.GetUserHandles(ctx, eventUsers(event))
+	if err != nil {
+		return err
+	}
+	msg := slack.Message{Text: slackText(event, func(userID string) string {
+		if handle, ok := handles[userID]; ok {
+			return slack.Mention(handle)
+		}
+		return slack.Escape(userID)
+	})}
+
+	if err = slack.Post(ctx, s.client, s.baseURL+path, msg); err != nil {
+		if slack.Permanent(err) {
+			s.log.WarnContext(ctx, "slack rejected message, dropping it",
+				slog.String("team_name", teamName),
+				slog.String("event_type", event.Type),
+				slog.String("error", err.Error()))
+			return nil
+		}
+		s.log.WarnContext(ctx, "failed to post slack message",
+			slog.String("team_name", teamName),
+			slog.String("event_type", event.Type),
+			slog.String("error", err.Error()))
+		return err
+	}
+	s.log.DebugContext(ctx, "slack message posted",
+		slog.String("team_name", teamName),
+		slog.String("event_type", event.Type))
+	return nil
+}
+
+func slackEvent(eventType string) bool {
+	switch eventType {
+	case models.EventReviewerAssigned, models.EventReviewerReassigned, models.EventReviewStale,
+		models.EventPRMerged:
+		return true
+	}
+	return false
+}
+
+// eventUsers returns the IDs of the users a message about event names.
+func eventUsers(event *models.Event) []string {
+	pr := event.Data.PullRequest
+	ids := []string{pr.AuthorID}
+	if event.Type == models.EventPRMerged {
+		return append(ids, pr.Reviewers...)
+	}
+	ids = append(ids, event.Data.ReviewerID)
+	if event.Data.OldReviewerID != "" {
+		ids = append(ids, event.Data.OldReviewerID)
+	}
+	return ids
+}
+
+// slackText returns the message about event, naming users with mention.
+func slackText(event *models.Event, mention func(userID string) string) string {
+	pr := event.Data.PullRequest
+	name := pr.ID
+	if pr.Repository != "" {
+		name = pr.Repository + "#" + pr.ID
+	}
+	ref := "*" + slack.Escape(pr.Title) + "* (`" + slack.Escape(name) + "`)"
+	switch event.Type {
+	case models.EventReviewerAssigned:
+		return mention(event.Data.ReviewerID) + " was assigned to review " + ref + " by " + mention(pr.AuthorID)
+	case models.EventReviewerReassigned:
+		text := mention(event.Data.ReviewerID) + " was assigned to review " + ref
+		if event.Data.OldReviewerID != "" {
+			text += " instead of " + mention(event.Data.OldReviewerID)
+		}
+		return text
+	case models.EventReviewStale:
+		text := mention(event.Data.ReviewerID) + ", " + ref + " by " + mention(pr.AuthorID) +
+			" is still waiting for your review"
+		if event.Data.AssignedAt != nil {
+			text += " since " + event.Data.AssignedAt.UTC().Format(staleDateLayout)
+		}
+		return text
+	default:
+		text := ref + " by " + mention(pr.AuthorID) + " was merged"
+		if len(pr.Reviewers) > 0 {
+			names := make([]string, 0, len(pr.Reviewers))
+			for _, id := range pr.Reviewers {
+				names = append(names, mention(id))
+			}
+			text += ", reviewed by " + strings.Join(names, ", ")
+		}
+		return text
+	}
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/outbound"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+)
+
+// Page sizes of ListDeliveries.
+const (
+	DefaultDeliveryPageSize = 50
+	MaxDeliveryPageSize     = 500
+)
+
+const (
+	// deliveryBatchSize caps the deliveries sent by one DeliverDue call.
+	deliveryBatchSize = 100
+	// minDeliveryLease is the shortest time a claimed delivery is kept from other DeliverDue calls.
+	minDeliveryLease = time.Minute
+	// maxRetryBackoff caps the wait between two attempts of a delivery.
+	maxRetryBackoff = time.Hour
+	// secretBytes is the amount of randomness in a generated subscription secret.
+	secretBytes = 32
+	// maxResponseDrain is how much of a receiver's response body is read before the connection is reused.
+	maxResponseDrain = 64 << 10
+)
+
+// eventTypes are the event types subscriptions can filter on.
+var eventTypes = []string{
+	models.EventPRCreated,
+	models.EventReviewerAssigned,
+	models.EventReviewerReassigned,
+	models.EventPRMerged,
+	models.EventUserDeactivated,
+	models.EventReviewStale,
+}
+
+// SubscriptionService manages outbound webhook subscriptions and sends them events. As the outbox sink it
+// queues a delivery of each event for every subscription to its type; DeliverDue sends them, retrying
+// failed ones with exponential backoff up to maxAttempts attempts.
+type SubscriptionService struct {
+	repo        repository.SubscriptionRepository
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration
+	log         *slog.Logger
+}
+
+var (
+	_ SubscriptionServiceInterface = (*SubscriptionService)(nil)
+	_ EventSink                    = (*SubscriptionService)(nil)
+)
+
+func NewSubscriptionService(
+	repo repository.SubscriptionRepository,
+	client *http.Client,
+	maxAttempts int,
+	backoff time.Duration,
+	log *slog.Logger,
+) *SubscriptionService {
+	return &SubscriptionService{
+		repo:        repo,
+		client:      client,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		log:         log,
+	}
+}
+
+// Subscribe registers sub.URL for sub.EventTypes. Without a secret one is generated; the returned
+// subscription carries it, later reads do not.
+func (s *SubscriptionService) Subscribe(
+	ctx context.Context,
+	sub *models.WebhookSubscription,
+) (*models.WebhookSubscription, error) {
+	target, err := url.Parse(sub.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidInput, "url must be an absolute http(s) URL")
+	}
+	if len(sub.EventTypes) == 0 {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidInput, "no event types")
+	}
+	for _, t := range sub.EventTypes {
+		if !slices.Contains(eventTypes, t) {
+			return nil, apperrors.Wrap(apperrors.ErrInvalidInput, "unknown event type "+t)
+		}
+	}
+	slices.Sort(sub.EventTypes)
+	sub.EventTypes = slices.Compact(sub.EventTypes)
+
+	if sub.Secret == "" {
+		if sub.Secret, err = randomHex(secretBytes); err != nil {
+			return nil, apperrors.Wrap(err, "failed to generate secret")
+		}
+	}
+	if err = s.repo.CreateSubscription(ctx, sub); err != nil {
+		s.log.ErrorContext(ctx, "failed to create subscription",
+			slog.String("url", sub.URL),
+			slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	s.log.InfoContext(ctx, "webhook subscription created",
+		slog.Int64("subscription_id", sub.ID),
+		slog.String("url", sub.URL),
+		slog.Any("event_types", sub.EventTypes))
+	return sub, nil
+}
+
+// Unsubscribe removes the subscription and its pending deliveries.
+func (s *SubscriptionService) Unsubscribe(ctx context.Context, id int64) error {
+	if err := s.repo.DeleteSubscription(ctx, id); err != nil {
+		return err
+	}
+	s.log.InfoContext(ctx, "webhook subscription deleted", slog.Int64("subscription_id", id))
+	return nil
+}
+
+// ListSubscriptions returns all subscriptions without their secrets.
+func (s *SubscriptionService) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return s.repo.ListSubscriptions(ctx, "")
+}
+
+// ListDeliveries returns the latest deliveries of the subscription with their attempts, newest first.
+// A zero limit selects DefaultDeliveryPageSize.
+func (s *SubscriptionService) ListDeliveries(
+	ctx context.Context,
+	subscriptionID int64,
+	limit int,
+) ([]models.WebhookDelivery, error) {
+	if limit == 0 {
+		limit = DefaultDeliveryPageSize
+	}
+	if limit < 0 || limit > MaxDeliveryPageSize {
+		return nil, apperrors.ErrInvalidInput
+	}
+	if _, err := s.repo.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, subscriptionID, limit)
+}
+
+// Name is stored in outbox.delivered_sinks once an event is queued for the subscriptions. It must stay
+// stable: after a rename, events waiting for another sink would be queued to the subscribers again.
+func (s *SubscriptionService) Name() string {
+	return "webhooks"
+}
+
+// Publish queues a delivery of event for every subscription to its type. A delivery already queued for the
+// event is kept, so publishing the event again queues nothing new.
+func (s *SubscriptionService) Publish(ctx context.Context, event *models.Event) error {
+	subs, err := s.repo.ListSubscriptions(ctx, event.Type)
+	if err != nil || len(subs) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return apperrors.Wrap(err, "failed to encode event")
+	}
+
+	for _, sub := range subs {
+		delivery := &models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        payload,
+		}
+		if err = s.repo.CreateDelivery(ctx, delivery); err != nil {
+			s.log.ErrorContext(ctx, "failed to queue webhook delivery",
+				slog.Int64("subscription_id", sub.ID),
+				slog.String("event_type", event.Type),
+				slog.String("error", err.Error()))
+			return err
+		}
+	}
+	return nil
+}
+
+// DeliverDue claims the deliveries whose next attempt is due, sends them and returns how many of them were
+// delivered. The claim lasts as long as sending the whole batch may take, so concurrent calls, from this
+// or another instance, never send the same delivery twice.
+func (s *SubscriptionService) DeliverDue(ctx context.Context) (int, error) {
+	lease := max(time.Duration(deliveryBatchSize)*s.client.Timeout, minDeliveryLease)
+	deliveries, err := s.repo.ClaimDueDeliveries(ctx, deliveryBatchSize, lease)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to claim due deliveries", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	delivered := 0
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+		ok, deliverErr := s.deliver(ctx, &deliveries[i])
+		if deliverErr != nil {
+			return delivered, deliverErr
+		}
+		if ok {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// deliver makes one attempt to send d and records it. It reports whether the receiver accepted d.
+func (s *SubscriptionService) deliver(ctx context.Context, d *models.WebhookDelivery) (bool, error) {
+	attempt := &models.DeliveryAttempt{Attempt: d.Attempts + 1}
+	start := time.Now()
+	attempt.StatusCode, attempt.Error = s.send(ctx, d)
+	attempt.DurationMS = time.Since(start).Milliseconds()
+
+	d.Attempts = attempt.Attempt
+	ok := attempt.Error == "" && attempt.StatusCode >= 200 && attempt.StatusCode < 300
+	var retryAfter time.Duration
+	switch {
+	case ok:
+		d.Status = models.DeliveryDelivered
+	case d.Attempts >= s.maxAttempts:
+		d.Status = models.DeliveryFailed
+	default:
+		d.Status = models.DeliveryPending
+		retryAfter = outbound.Backoff(d.Attempts, s.backoff, maxRetryBackoff)
+	}
+
+	if err := s.repo.RecordAttempt(ctx, d, attempt, retryAfter); err != nil {
+		s.log.ErrorContext(ctx, "failed to record delivery attempt",
+			slog.Int64("delivery_id", d.ID),
+			slog.String("error", err.Error()))
+		return false, err
+	}
+
+	log := s.log.With(
+		slog.Int64("delivery_id", d.ID),
+		slog.Int64("subscription_id", d.SubscriptionID),
+		slog.String("event_type", d.EventType),
+		slog.Int("attempt", d.Attempts),
+		slog.Int("status_code", attempt.StatusCode),
+		slog.String("error", attempt.Error))
+	switch d.Status {
+	case models.DeliveryDelivered:
+		log.InfoContext(ctx, "webhook delivered")
+	case models.DeliveryFailed:
+		log.ErrorContext(ctx, "webhook delivery failed, giving up")
+	default:
+		log.WarnContext(ctx, "webhook delivery failed, will retry", slog.Duration("retry_after", retryAfter))
+	}
+	return ok, nil
+}
+
+// send posts d to its subscription and returns the response status, or the error when no response came.
+func (s *SubscriptionService) send(ctx context.Context, d *models.WebhookDelivery) (int, string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err.Error()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(outbound.EventHeader, d.EventType)
+	req.Header.Set(outbound.EventIDHeader, d.EventID)
+	req.Header.Set(outbound.DeliveryHeader, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(outbound.SignatureHeader, outbound.Sign([]byte(d.Secret), d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err.Error()
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseDrain))
+	return resp.StatusCode, ""
+}
+
+// randomHex returns n random bytes, hex-encoded.
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -11,9 +11,11 @@ import (
 )
 
 type TeamService struct {
-	teamRepo repository.TeamRepository
-	userRepo repository.UserRepository
-	log      *slog.Logger
+	teamRepo         repository.TeamRepository
+	userRepo         repository.UserRepository
+	audit            *AuditService
+	defaultReviewers int
+	log              *slog.Logger
 }
 
 var _ TeamServiceInterface = (*TeamService)(nil)
@@ -21,38 +23,50 @@ var _ TeamServiceInterface = (*TeamService)(nil)
 func NewTeamService(
 	teamRepo repository.TeamRepository,
 	userRepo repository.UserRepository,
+	audit *AuditService,
+	defaultReviewers int,
 	log *slog.Logger,
 ) *TeamService {
 	return &TeamService{
-		teamRepo: teamRepo,
-		userRepo: userRepo,
-		log:      log,
+		teamRepo:         teamRepo,
+		userRepo:         userRepo,
+		audit:            audit,
+		defaultReviewers: defaultReviewers,
+		log:              log,
 	}
 }
 
 // CreateTeam creates a new team.
 func (s *TeamService) CreateTeam(ctx context.Context, team *models.Team) (*models.Team, error) {
-	if team.Name == "" || len(team.Members) == 0 {
+	if team.Name == "" || len(team.Members) == 0 || !validTeamSettings(team.Settings) {
 		return nil, apperrors.ErrInvalidInput
 	}
 
-	if err := s.teamRepo.CreateTeam(ctx, team); err != nil {
-		if errors.Is(err, apperrors.ErrTeamExists) {
-			s.log.InfoContext(ctx, "team already exists, returning error", slog.String("team_name", team.Name))
-			return nil, apperrors.ErrTeamExists
+	var reloaded *models.Team
+	err := s.audit.InTx(ctx, func(ctx context.Context) error {
+		if createErr := s.teamRepo.CreateTeam(ctx, team); createErr != nil {
+			if errors.Is(createErr, apperrors.ErrTeamExists) {
+				s.log.InfoContext(ctx, "team already exists, returning error", slog.String("team_name", team.Name))
+				return apperrors.ErrTeamExists
+			}
+			s.log.ErrorContext(ctx, "failed to create team",
+				slog.String("team_name", team.Name),
+				slog.String("error", createErr.Error()))
+			return apperrors.ErrInternal
 		}
-		s.log.ErrorContext(ctx, "failed to create team",
-			slog.String("team_name", team.Name),
-			slog.String("error", err.Error()))
-		return nil, apperrors.ErrInternal
-	}
 
-	reloaded, err := s.teamRepo.GetTeamByName(ctx, team.Name)
+		var getErr error
+		reloaded, getErr = s.teamRepo.GetTeamByName(ctx, team.Name)
+		if getErr != nil {
+			s.log.ErrorContext(ctx, "failed to reload team after create",
+				slog.String("team_name", team.Name),
+				slog.String("error", getErr.Error()))
+			return apperrors.ErrInternal
+		}
+		return s.audit.Record(ctx, models.AuditTeamCreated, models.AuditEntityTeam, team.Name, nil, reloaded)
+	})
 	if err != nil {
-		s.log.ErrorContext(ctx, "failed to reload team after create",
-			slog.String("team_name", team.Name),
-			slog.String("error", err.Error()))
-		return nil, apperrors.ErrInternal
+		return nil, err
 	}
 
 	s.log.InfoContext(ctx, "team created successfully",
@@ -85,12 +99,26 @@ func (s *TeamService) AddMemberToTeam(ctx context.Context, teamName string, memb
 		TeamName: teamName,
 		IsActive: member.IsActive,
 	}
-	if upsertErr := s.userRepo.UpsertUser(ctx, user); upsertErr != nil {
-		s.log.ErrorContext(ctx, "failed to add member to team",
-			slog.String("team_name", teamName),
-			slog.String("user_id", member.UserID),
-			slog.String("error", upsertErr.Error()))
-		return apperrors.ErrInternal
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		before, getErr := s.userRepo.GetUserByID(ctx, member.UserID)
+		if getErr != nil && !errors.Is(getErr, apperrors.ErrNotFound) {
+			s.log.ErrorContext(ctx, "failed to get member before upsert",
+				slog.String("user_id", member.UserID),
+				slog.String("error", getErr.Error()))
+			return apperrors.ErrInternal
+		}
+
+		if upsertErr := s.userRepo.UpsertUser(ctx, user); upsertErr != nil {
+			s.log.ErrorContext(ctx, "failed to add member to team",
+				slog.String("team_name", teamName),
+				slog.String("user_id", member.UserID),
+				slog.String("error", upsertErr.Error()))
+			return apperrors.ErrInternal
+		}
+		return s.audit.Record(ctx, models.AuditTeamMemberAdded, models.AuditEntityUser, member.UserID, before, user)
+	})
+	if err != nil {
+		return err
 	}
 
 	s.log.InfoContext(ctx, "member added to team",
@@ -122,3 +150,149 @@ func (s *TeamService) GetTeam(ctx context.Context, name string) (*models.Team, e
 		slog.Int("members_count", len(team.Members)))
 	return team, nil
 }
+
+// UpdateTeamSettings replaces the team's settings. A nil reviewers_count resets the team to the default.
+func (s *TeamService) UpdateTeamSettings(
+	ctx context.Context,
+	name string,
+	settings *models.TeamSettings,
+) (*models.Team, error) {
+	if name == "" || settings == nil || !validTeamSettings(settings) {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	var team *models.Team
+	err := s.audit.InTx(ctx, func(ctx context.Context) error {
+		before, getErr := s.teamForUpdate(ctx, name, "settings update")
+		if getErr != nil {
+			return getErr
+		}
+
+		if updateErr := s.teamRepo.UpdateTeamSettings(ctx, name, settings, s.defaultReviewers); updateErr != nil {
+			if errors.Is(updateErr, apperrors.ErrNotFound) {
+				s.log.WarnContext(ctx, "team not found for settings update", slog.String("team_name", name))
+				return apperrors.ErrNotFound
+			}
+			s.log.ErrorContext(ctx, "failed to update team settings",
+				slog.String("team_name", name),
+				slog.String("error", updateErr.Error()))
+			return apperrors.ErrInternal
+		}
+
+		team, getErr = s.teamRepo.GetTeamByName(ctx, name)
+		if getErr != nil {
+			s.log.ErrorContext(ctx, "failed to reload team after settings update",
+				slog.String("team_name", name),
+				slog.String("error", getErr.Error()))
+			return apperrors.ErrInternal
+		}
+		return s.audit.Record(ctx, models.AuditTeamSettingsUpdated, models.AuditEntityTeam, name, before, team)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.InfoContext(ctx, "team settings updated", slog.String("team_name", name))
+	return team, nil
+}
+
+// SetFallbackTeams replaces the ordered list of teams that reviewers are drawn from when the team has no
+// free reviewers. The list must not contain the team itself or duplicates.
+func (s *TeamService) SetFallbackTeams(ctx context.Context, name string, fallbackTeams []string) (*models.Team, error) {
+	if name == "" {
+		return nil, apperrors.ErrInvalidInput
+	}
+	seen := make(map[string]bool, len(fallbackTeams))
+	for _, fallback := range fallbackTeams {
+		if fallback == "" || fallback == name || seen[fallback] {
+			return nil, apperrors.ErrInvalidInput
+		}
+		seen[fallback] = true
+	}
+
+	var team *models.Team
+	err := s.audit.InTx(ctx, func(ctx context.Context) error {
+		before, getErr := s.teamForUpdate(ctx, name, "fallback update")
+		if getErr != nil {
+			return getErr
+		}
+
+		if setErr := s.teamRepo.SetFallbackTeams(ctx, name, fallbackTeams); setErr != nil {
+			if errors.Is(setErr, apperrors.ErrNotFound) {
+				s.log.WarnContext(ctx, "team not found for fallback update",
+					slog.String("team_name", name),
+					slog.Any("fallback_teams", fallbackTeams))
+				return apperrors.ErrNotFound
+			}
+			s.log.ErrorContext(ctx, "failed to set fallback teams",
+				slog.String("team_name", name),
+				slog.String("error", setErr.Error()))
+			return apperrors.ErrInternal
+		}
+
+		team, getErr = s.teamRepo.GetTeamByName(ctx, name)
+		if getErr != nil {
+			s.log.ErrorContext(ctx, "failed to reload team after fallback update",
+				slog.String("team_name", name),
+				slog.String("error", getErr.Error()))
+			return apperrors.ErrInternal
+		}
+		return s.audit.Record(ctx, models.AuditTeamFallbacksUpdated, models.AuditEntityTeam, name, before, team)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.InfoContext(ctx, "team fallbacks updated",
+		slog.String("team_name", name),
+		slog.Int("fallback_count", len(fallbackTeams)))
+	return team, nil
+}
+
+// teamForUpdate loads the team about to be changed by op, for its audit event.
+func (s *TeamService) teamForUpdate(ctx context.Context, name, op string) (*models.Team, error) {
+	team, err := s.teamRepo.GetTeamByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			s.log.WarnContext(ctx, "team not found for "+op, slog.String("team_name", name))
+			return nil, apperrors.ErrNotFound
+		}
+		s.log.ErrorContext(ctx, "failed to get team before "+op,
+			slog.String("team_name", name),
+			slog.String("error", err.Error()))
+		return nil, apperrors.ErrInternal
+	}
+	return team, nil
+}
+
+// validTeamSettings checks that the provided overrides are in range; nil settings are valid.
+func validTeamSettings(settings *models.TeamSettings) bool {
+	if settings == nil {
+		return true
+	}
+	if settings.RequiredApprovals != nil && *settings.RequiredApprovals < 0 {
+		return false
+	}
+	return settings.ReviewersCount == nil || *settings.ReviewersCount > 0
+}
+
+// reviewersCountForTeam returns how many reviewers PRs of the team should get,
+// falling back to defaultCount when the team has no own setting or does not exist.
+func reviewersCountForTeam(
+	ctx context.Context,
+	teamRepo repository.TeamRepository,
+	teamName string,
+	defaultCount int,
+) (int, error) {
+	settings, err := teamRepo.GetTeamSettings(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return defaultCount, nil
+		}
+		return 0, err
+	}
+	if settings.ReviewersCount == nil {
+		return defaultCount, nil
+	}
+	return *settings.ReviewersCount, nil
+}
@@ -4,7 +4,8 @@ import (
 	"context"
 	"errors"
 	"log/slog"
-	"math/rand/v2"
+	"net/mail"
+	"strconv"
 
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
@@ -12,18 +13,40 @@ import (
 )
 
 type UserService struct {
-	userRepo repository.UserRepository
-	prRepo   repository.PRRepository
-	log      *slog.Logger
+	userRepo         repository.UserRepository
+	prRepo           repository.PRRepository
+	teamRepo         repository.TeamRepository
+	absenceRepo      repository.AbsenceRepository
+	repoRepo         repository.RepositoryRepository
+	audit            *AuditService
+	selector         ReviewerSelector
+	defaultReviewers int
+	log              *slog.Logger
 }
 
 var _ UserServiceInterface = (*UserService)(nil)
 
-func NewUserService(userRepo repository.UserRepository, prRepo repository.PRRepository, log *slog.Logger) *UserService {
+func NewUserService(
+	userRepo repository.UserRepository,
+	prRepo repository.PRRepository,
+	teamRepo repository.TeamRepository,
+	absenceRepo repository.AbsenceRepository,
+	repoRepo repository.RepositoryRepository,
+	audit *AuditService,
+	selector ReviewerSelector,
+	defaultReviewers int,
+	log *slog.Logger,
+) *UserService {
 	return &UserService{
-		userRepo: userRepo,
-		prRepo:   prRepo,
-		log:      log,
+		userRepo:         userRepo,
+		prRepo:           prRepo,
+		teamRepo:         teamRepo,
+		absenceRepo:      absenceRepo,
+		repoRepo:         repoRepo,
+		audit:            audit,
+		selector:         selector,
+		defaultReviewers: defaultReviewers,
+		log:              log,
 	}
 }
 
@@ -33,19 +56,142 @@ func (s *UserService) SetUserActive(ctx context.Context, id string, isActive boo
 		return nil, apperrors.ErrInvalidInput
 	}
 
-	err := s.userRepo.UpdateUserActive(ctx, id, isActive)
+	var user *models.User
+	err := s.audit.InTx(ctx, func(ctx context.Context) error {
+		before, getErr := s.userForUpdate(ctx, id, "active update")
+		if getErr != nil {
+			return getErr
+		}
+
+		if updateErr := s.userRepo.UpdateUserActive(ctx, id, isActive); updateErr != nil {
+			if errors.Is(updateErr, apperrors.ErrNotFound) {
+				s.log.WarnContext(ctx, "user not found for active update", slog.String("user_id", id))
+			} else {
+				s.log.ErrorContext(ctx, "failed to update user active",
+					slog.String("user_id", id),
+					slog.Bool("is_active", isActive),
+					slog.String("error", updateErr.Error()))
+			}
+			return updateErr
+		}
+
+		user, getErr = s.reloadUser(ctx, id)
+		if getErr != nil {
+			return getErr
+		}
+		return s.audit.Record(ctx, models.AuditUserActiveChanged, models.AuditEntityUser, id, before, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.InfoContext(ctx, "user active updated",
+		slog.String("user_id", id),
+		slog.Bool("is_active", isActive))
+	return user, nil
+}
+
+// SetMaxOpenReviews updates the limit of concurrent open reviews; nil removes the limit.
+func (s *UserService) SetMaxOpenReviews(ctx context.Context, id string, limit *int) (*models.User, error) {
+	if id == "" || (limit != nil && *limit < 1) {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	var user *models.User
+	err := s.audit.InTx(ctx, func(ctx context.Context) error {
+		before, getErr := s.userForUpdate(ctx, id, "max open reviews update")
+		if getErr != nil {
+			return getErr
+		}
+
+		if updateErr := s.userRepo.UpdateUserMaxOpenReviews(ctx, id, limit); updateErr != nil {
+			if errors.Is(updateErr, apperrors.ErrNotFound) {
+				s.log.WarnContext(ctx, "user not found for max open reviews update", slog.String("user_id", id))
+			} else {
+				s.log.ErrorContext(ctx, "failed to update user max open reviews",
+					slog.String("user_id", id),
+					slog.String("error", updateErr.Error()))
+			}
+			return updateErr
+		}
+
+		user, getErr = s.reloadUser(ctx, id)
+		if getErr != nil {
+			return getErr
+		}
+		return s.audit.Record(ctx, models.AuditUserLimitChanged, models.AuditEntityUser, id, before, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.InfoContext(ctx, "user max open reviews updated", slog.String("user_id", id))
+	return user, nil
+}
+
+// SetEmail updates the address notifications are emailed to and whether the user gets the daily digest.
+// An empty email stops the emails; the digest needs an email.
+func (s *UserService) SetEmail(ctx context.Context, id, email string, digest bool) (*models.User, error) {
+	if id == "" || (email == "" && digest) {
+		return nil, apperrors.ErrInvalidInput
+	}
+	if email != "" {
+		addr, err := mail.ParseAddress(email)
+		if err != nil || addr.Address != email {
+			return nil, apperrors.ErrInvalidInput
+		}
+	}
+
+	var user *models.User
+	err := s.audit.InTx(ctx, func(ctx context.Context) error {
+		before, getErr := s.userForUpdate(ctx, id, "email update")
+		if getErr != nil {
+			return getErr
+		}
+
+		if updateErr := s.userRepo.UpdateUserEmail(ctx, id, email, digest); updateErr != nil {
+			if errors.Is(updateErr, apperrors.ErrNotFound) {
+				s.log.WarnContext(ctx, "user not found for email update", slog.String("user_id", id))
+			} else {
+				s.log.ErrorContext(ctx, "failed to update user email",
+					slog.String("user_id", id),
+					slog.String("error", updateErr.Error()))
+			}
+			return updateErr
+		}
+
+		user, getErr = s.reloadUser(ctx, id)
+		if getErr != nil {
+			return getErr
+		}
+		return s.audit.Record(ctx, models.AuditUserEmailChanged, models.AuditEntityUser, id, before, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.InfoContext(ctx, "user email updated", slog.String("user_id", id), slog.Bool("digest", digest))
+	return user, nil
+}
+
+// userForUpdate loads the user about to be changed by op, for its audit event.
+func (s *UserService) userForUpdate(ctx context.Context, id, op string) (*models.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, apperrors.ErrNotFound) {
-			s.log.WarnContext(ctx, "user not found for active update", slog.String("user_id", id))
+			s.log.WarnContext(ctx, "user not found for "+op, slog.String("user_id", id))
 		} else {
-			s.log.ErrorContext(ctx, "failed to update user active",
+			s.log.ErrorContext(ctx, "failed to get user before "+op,
 				slog.String("user_id", id),
-				slog.Bool("is_active", isActive),
 				slog.String("error", err.Error()))
 		}
 		return nil, err
 	}
+	return user, nil
+}
 
+// reloadUser returns the user as stored after an update.
+func (s *UserService) reloadUser(ctx context.Context, id string) (*models.User, error) {
 	user, err := s.userRepo.GetUserByID(ctx, id)
 	if err != nil {
 		s.log.ErrorContext(ctx, "failed to reload user after update",
@@ -53,20 +199,21 @@ func (s *UserService) SetUserActive(ctx context.Context, id string, isActive boo
 			slog.String("error", err.Error()))
 		return nil, apperrors.ErrInternal
 	}
-
-	s.log.InfoContext(ctx, "user active updated",
-		slog.String("user_id", id),
-		slog.Bool("is_active", isActive))
 	return user, nil
 }
 
-// GetPRsForUser returns PRs assigned to user as reviewer.
-func (s *UserService) GetPRsForUser(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
+// GetPRsForUser returns PRs assigned to user as reviewer. A non-empty repository limits them to that repository;
+// pendingOnly limits them to OPEN PRs still waiting for the user's verdict.
+func (s *UserService) GetPRsForUser(
+	ctx context.Context,
+	userID, repository string,
+	pendingOnly bool,
+) ([]models.PullRequestShort, error) {
 	if userID == "" {
 		return nil, apperrors.ErrInvalidInput
 	}
 
-	prs, err := s.prRepo.GetPRsForUser(ctx, userID)
+	prs, err := s.prRepo.GetPRsForUser(ctx, userID, repository, pendingOnly)
 	if err != nil {
 		s.log.ErrorContext(ctx, "failed to get PRs for user",
 			slog.String("user_id", userID),
@@ -86,6 +233,15 @@ func (s *UserService) DeactivateUsersByTeam(ctx context.Context, teamName string
 		return apperrors.ErrInvalidInput
 	}
 
+	return s.audit.InTx(ctx, func(ctx context.Context) error {
+		return s.deactivateTeam(ctx, teamName)
+	})
+}
+
+// deactivateTeam deactivates the active members of the team, replaces them as reviewers of open PRs
+// and records the change in the audit log. Each PR is reassigned in its own savepoint, so a PR that
+// fails is left as it was without failing the deactivation.
+func (s *UserService) deactivateTeam(ctx context.Context, teamName string) error {
 	activeUsersBefore, err := s.userRepo.GetActiveUsersByTeam(ctx, teamName)
 	if err != nil {
 		s.log.ErrorContext(ctx, "failed to get active users before deactivation",
@@ -108,17 +264,34 @@ func (s *UserService) DeactivateUsersByTeam(ctx context.Context, teamName string
 		return deactivateErr
 	}
 
+	deactivated := make([]models.User, 0, len(activeUsersBefore))
+	for _, u := range activeUsersBefore {
+		u.IsActive = false
+		deactivated = append(deactivated, u)
+	}
+	auditErr := s.audit.Record(ctx, models.AuditTeamDeactivated, models.AuditEntityTeam, teamName,
+		activeUsersBefore, deactivated)
+	if auditErr != nil {
+		return auditErr
+	}
+
 	openPRs, err := s.prRepo.GetOpenPRsWithReviewersFromTeam(ctx, teamName)
 	if err != nil {
 		s.log.ErrorContext(ctx, "failed to get open PRs for reassignment",
 			slog.String("team_name", teamName),
 			slog.String("error", err.Error()))
-		return nil
+		return apperrors.Wrap(err, "failed to get open PRs")
 	}
 
 	reassignedCount := 0
 	for _, pr := range openPRs {
-		reassigned, reassignErr := s.reassignDeactivatedReviewers(ctx, &pr, deactivatedUserIDs, teamName)
+		var reassigned bool
+		reassignErr := s.audit.InSavepoint(ctx, func(ctx context.Context) error {
+			var spErr error
+			reassigned, spErr = s.reassignDeactivatedReviewers(
+				ctx, &pr, deactivatedUserIDs, teamName, models.AssignReasonDeactivated)
+			return spErr
+		})
 		if reassignErr != nil {
 			s.log.WarnContext(ctx, "failed to reassign reviewers for PR",
 				slog.String("pr_id", pr.ID),
@@ -139,43 +312,246 @@ func (s *UserService) DeactivateUsersByTeam(ctx context.Context, teamName string
 	return nil
 }
 
-// reassignDeactivatedReviewers reassigns deactivated reviewers in a PR to active team members.
+// AddAbsence registers a window during which the user is not assigned as a reviewer.
+// Open reviews are moved to other members by the absence worker once the window starts.
+func (s *UserService) AddAbsence(ctx context.Context, absence *models.Absence) (*models.Absence, error) {
+	if absence.UserID == "" || !absence.EndsAt.After(absence.StartsAt) {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	if _, err := s.userRepo.GetUserByID(ctx, absence.UserID); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			s.log.WarnContext(ctx, "user not found for absence", slog.String("user_id", absence.UserID))
+		}
+		return nil, err
+	}
+
+	absence.ID = 0
+	err := s.audit.InTx(ctx, func(ctx context.Context) error {
+		if createErr := s.absenceRepo.CreateAbsence(ctx, absence); createErr != nil {
+			s.log.ErrorContext(ctx, "failed to create absence",
+				slog.String("user_id", absence.UserID),
+				slog.String("error", createErr.Error()))
+			return createErr
+		}
+		return s.audit.Record(ctx, models.AuditAbsenceAdded, models.AuditEntityAbsence,
+			strconv.FormatInt(absence.ID, 10), nil, absence)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.InfoContext(ctx, "absence added",
+		slog.String("user_id", absence.UserID),
+		slog.Int64("absence_id", absence.ID))
+	return absence, nil
+}
+
+// GetAbsences returns all absences of the user.
+func (s *UserService) GetAbsences(ctx context.Context, userID string) ([]models.Absence, error) {
+	if userID == "" {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	absences, err := s.absenceRepo.GetAbsencesByUser(ctx, userID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to get absences",
+			slog.String("user_id", userID),
+			slog.String("error", err.Error()))
+		return nil, err
+	}
+	return absences, nil
+}
+
+// DeleteAbsence removes an absence window.
+func (s *UserService) DeleteAbsence(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return apperrors.ErrInvalidInput
+	}
+
+	err := s.audit.InTx(ctx, func(ctx context.Context) error {
+		deleted, deleteErr := s.absenceRepo.DeleteAbsence(ctx, id)
+		if deleteErr != nil {
+			if errors.Is(deleteErr, apperrors.ErrNotFound) {
+				s.log.WarnContext(ctx, "absence not found for delete", slog.Int64("absence_id", id))
+			} else {
+				s.log.ErrorContext(ctx, "failed to delete absence",
+					slog.Int64("absence_id", id),
+					slog.String("error", deleteErr.Error()))
+			}
+			return deleteErr
+		}
+		return s.audit.Record(ctx, models.AuditAbsenceDeleted, models.AuditEntityAbsence,
+			strconv.FormatInt(id, 10), deleted, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.log.InfoContext(ctx, "absence deleted", slog.Int64("absence_id", id))
+	return nil
+}
+
+// ReassignAbsentReviewers moves open reviews of users whose absence has started to available members
+// of their team. Each PR is changed in its own transaction and recorded in the audit log, attributed to the
+// actor in ctx. An absence is marked processed once all PRs its user reviews were reassigned; when one
+// fails, the absence is retried on the next run. It returns the number of processed absences.
+func (s *UserService) ReassignAbsentReviewers(ctx context.Context) (int, error) {
+	absences, err := s.absenceRepo.GetStartedUnprocessedAbsences(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to get started absences", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	absentByTeam := make(map[string]map[string]bool)
+	absencesByTeam := make(map[string][]models.Absence)
+	for _, a := range absences {
+		teamName, teamErr := s.userRepo.GetTeamNameByUserID(ctx, a.UserID)
+		if teamErr != nil {
+			s.log.WarnContext(ctx, "failed to get team of absent user",
+				slog.String("user_id", a.UserID),
+				slog.String("error", teamErr.Error()))
+			continue
+		}
+		if absentByTeam[teamName] == nil {
+			absentByTeam[teamName] = make(map[string]bool)
+		}
+		absentByTeam[teamName][a.UserID] = true
+		absencesByTeam[teamName] = append(absencesByTeam[teamName], a)
+	}
+
+	processed := 0
+	// failed holds absent users with a PR that could not be reassigned; their absences stay unprocessed.
+	failed := make(map[string]bool)
+	for teamName, absentUserIDs := range absentByTeam {
+		openPRs, prsErr := s.prRepo.GetOpenPRsWithReviewersFromTeam(ctx, teamName)
+		if prsErr != nil {
+			s.log.WarnContext(ctx, "failed to get open PRs for absent reviewers",
+				slog.String("team_name", teamName),
+				slog.String("error", prsErr.Error()))
+			continue
+		}
+
+		for _, pr := range openPRs {
+			reassignErr := s.audit.InTx(ctx, func(ctx context.Context) error {
+				_, txErr := s.reassignDeactivatedReviewers(ctx, &pr, absentUserIDs, teamName, models.AssignReasonAbsent)
+				return txErr
+			})
+			if reassignErr != nil {
+				s.log.WarnContext(ctx, "failed to reassign absent reviewers for PR",
+					slog.String("pr_id", pr.ID),
+					slog.String("error", reassignErr.Error()))
+				for _, reviewerID := range pr.Reviewers {
+					if absentUserIDs[reviewerID] {
+						failed[reviewerID] = true
+					}
+				}
+			}
+		}
+
+		for _, a := range absencesByTeam[teamName] {
+			if failed[a.UserID] {
+				continue
+			}
+			if markErr := s.absenceRepo.MarkAbsenceProcessed(ctx, a.ID); markErr != nil {
+				s.log.WarnContext(ctx, "failed to mark absence processed",
+					slog.Int64("absence_id", a.ID),
+					slog.String("error", markErr.Error()))
+				continue
+			}
+			processed++
+		}
+	}
+
+	if processed > 0 {
+		s.log.InfoContext(ctx, "absent reviewers reassigned", slog.Int("absences_processed", processed))
+	}
+	return processed, nil
+}
+
+// reassignDeactivatedReviewers reassigns deactivated reviewers in a PR to available team members
+// that are below their open review limit. reason is recorded in the PR's assignment history, and the
+// change in the audit log.
 func (s *UserService) reassignDeactivatedReviewers(
 	ctx context.Context,
 	pr *models.PullRequest,
 	deactivatedUserIDs map[string]bool,
 	teamName string,
+	reason string,
 ) (bool, error) {
 	deactivatedReviewers := s.findDeactivatedReviewers(pr.Reviewers, deactivatedUserIDs)
 	if len(deactivatedReviewers) == 0 {
 		return false, nil
 	}
+	before := snapshotPR(pr)
+
+	activeUsers, err := s.userRepo.GetAvailableUsersByTeam(ctx, teamName)
+	if err != nil {
+		return false, apperrors.Wrap(err, "failed to get available users for reassignment")
+	}
 
-	activeUsers, err := s.userRepo.GetActiveUsersByTeam(ctx, teamName)
+	activeUsers, err = filterByCapacity(ctx, s.prRepo, teamName, activeUsers)
 	if err != nil {
-		return false, apperrors.Wrap(err, "failed to get active users for reassignment")
+		return false, err
 	}
 
 	candidates := s.buildCandidateList(pr, activeUsers)
 	if len(candidates) == 0 {
-		return s.removeDeactivatedReviewers(ctx, pr, deactivatedUserIDs)
+		return s.removeDeactivatedReviewers(ctx, pr, before, deactivatedUserIDs, reason)
 	}
 
-	updated := s.replaceReviewers(pr, deactivatedReviewers, candidates)
+	updated, err := s.replaceReviewers(ctx, pr, deactivatedReviewers, candidates, teamName)
+	if err != nil {
+		return false, err
+	}
 	if !updated {
 		return false, nil
 	}
 
-	const minReviewers = 2
-	pr.NeedMoreReviewers = len(pr.Reviewers) < minReviewers
+	pr.NeedMoreReviewers, err = s.needsMoreReviewers(ctx, pr)
+	if err != nil {
+		return false, err
+	}
 
-	if updateErr := s.prRepo.UpdatePR(ctx, pr); updateErr != nil {
+	if updateErr := s.prRepo.UpdatePR(ctx, pr, assignmentChange(ctx, reason)); updateErr != nil {
 		return false, apperrors.Wrap(updateErr, "failed to update PR after reassignment")
 	}
+	if auditErr := s.recordReassignment(ctx, reason, before, pr); auditErr != nil {
+		return false, auditErr
+	}
 
 	return true, nil
 }
 
+// recordReassignment records the reviewers of pr replaced or removed by the service in the audit log,
+// which also emits the events of the replacements. Reviewers moved off for an absence get their own action.
+func (s *UserService) recordReassignment(ctx context.Context, reason string, before, pr *models.PullRequest) error {
+	action := models.AuditPRReviewerReassigned
+	if reason == models.AssignReasonAbsent {
+		action = models.AuditPRAbsentReassigned
+	}
+	return s.audit.Record(ctx, action, models.AuditEntityPullRequest, pr.ID, before, pr)
+}
+
+// needsMoreReviewers reports whether the PR has fewer reviewers than its repository or author's team requires.
+func (s *UserService) needsMoreReviewers(ctx context.Context, pr *models.PullRequest) (bool, error) {
+	teamName, err := s.userRepo.GetTeamNameByUserID(ctx, pr.AuthorID)
+	if err != nil {
+		return false, apperrors.Wrap(err, "failed to get author team")
+	}
+
+	repo, err := repositoryConfig(ctx, s.repoRepo, pr.Repository)
+	if err != nil {
+		return false, apperrors.Wrap(err, "failed to get repository settings")
+	}
+
+	_, reviewersCount, err := reviewerTarget(ctx, s.teamRepo, repo, teamName, s.defaultReviewers)
+	if err != nil {
+		return false, apperrors.Wrap(err, "failed to get team reviewers count")
+	}
+	return len(pr.Reviewers) < reviewersCount, nil
+}
+
 // findDeactivatedReviewers finds reviewers that are in the deactivated list.
 func (s *UserService) findDeactivatedReviewers(reviewers []string, deactivatedUserIDs map[string]bool) []string {
 	deactivatedReviewers := []string{}
@@ -207,7 +583,9 @@ func (s *UserService) buildCandidateList(pr *models.PullRequest, activeUsers []m
 func (s *UserService) removeDeactivatedReviewers(
 	ctx context.Context,
 	pr *models.PullRequest,
+	before *models.PullRequest,
 	deactivatedUserIDs map[string]bool,
+	reason string,
 ) (bool, error) {
 	newReviewers := []string{}
 	for _, r := range pr.Reviewers {
@@ -216,21 +594,30 @@ func (s *UserService) removeDeactivatedReviewers(
 		}
 	}
 	pr.Reviewers = newReviewers
-	const minReviewers = 2
-	pr.NeedMoreReviewers = len(pr.Reviewers) < minReviewers
 
-	if updateErr := s.prRepo.UpdatePR(ctx, pr); updateErr != nil {
+	needMore, err := s.needsMoreReviewers(ctx, pr)
+	if err != nil {
+		return false, err
+	}
+	pr.NeedMoreReviewers = needMore
+
+	if updateErr := s.prRepo.UpdatePR(ctx, pr, assignmentChange(ctx, reason)); updateErr != nil {
 		return false, apperrors.Wrap(updateErr, "failed to update PR after removing deactivated reviewers")
 	}
+	if auditErr := s.recordReassignment(ctx, reason, before, pr); auditErr != nil {
+		return false, auditErr
+	}
 	return true, nil
 }
 
-// replaceReviewers replaces deactivated reviewers with candidates.
+// replaceReviewers replaces deactivated reviewers with candidates picked by the reviewer selector.
 func (s *UserService) replaceReviewers(
+	ctx context.Context,
 	pr *models.PullRequest,
 	deactivatedReviewers []string,
 	candidates []string,
-) bool {
+	teamName string,
+) (bool, error) {
 	exclude := map[string]bool{pr.AuthorID: true}
 	for _, r := range pr.Reviewers {
 		exclude[r] = true
@@ -238,31 +625,32 @@ func (s *UserService) replaceReviewers(
 
 	updated := false
 	for _, oldReviewerID := range deactivatedReviewers {
-		rand.Shuffle(len(candidates), func(i, j int) {
-			candidates[i], candidates[j] = candidates[j], candidates[i]
-		})
+		selected, err := s.selector.Select(ctx, teamName, s.findAvailableCandidates(candidates, exclude), 1)
+		if err != nil {
+			return false, apperrors.Wrap(err, "failed to select replacement reviewer")
+		}
 
-		newReviewerID := s.findAvailableCandidate(candidates, exclude)
-		if newReviewerID == "" {
+		if len(selected) == 0 {
 			s.removeReviewer(pr, oldReviewerID)
 		} else {
-			s.replaceReviewer(pr, oldReviewerID, newReviewerID)
-			exclude[newReviewerID] = true
+			s.replaceReviewer(pr, oldReviewerID, selected[0])
+			exclude[selected[0]] = true
 		}
 		updated = true
 	}
 
-	return updated
+	return updated, nil
 }
 
-// findAvailableCandidate finds the first available candidate that's not excluded.
-func (s *UserService) findAvailableCandidate(candidates []string, exclude map[string]bool) string {
+// findAvailableCandidates returns the candidates that are not excluded.
+func (s *UserService) findAvailableCandidates(candidates []string, exclude map[string]bool) []string {
+	available := []string{}
 	for _, candidateID := range candidates {
 		if !exclude[candidateID] {
-			return candidateID
+			available = append(available, candidateID)
 		}
 	}
-	return ""
+	return available
 }
 
 // removeReviewer removes a reviewer from PR.
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/github"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/gitlab"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/reqctx"
+)
+
+// WebhookService mirrors pull requests from code host webhooks onto the service's PRs: it creates, merges,
+// closes and reopens them as they change on the host. PR authors are resolved through linked accounts.
+type WebhookService struct {
+	prs          PRServiceInterface
+	accounts     AccountServiceInterface
+	githubSecret []byte
+	gitlabToken  []byte
+	log          *slog.Logger
+}
+
+var _ WebhookServiceInterface = (*WebhookService)(nil)
+
+func NewWebhookService(
+	prs PRServiceInterface,
+	accounts AccountServiceInterface,
+	githubSecret, gitlabToken string,
+	log *slog.Logger,
+) *WebhookService {
+	return &WebhookService{
+		prs:          prs,
+		accounts:     accounts,
+		githubSecret: []byte(githubSecret),
+		gitlabToken:  []byte(gitlabToken),
+		log:          log,
+	}
+}
+
+// HandleGitHub verifies the signature of a GitHub delivery and applies it. Events other than pull_request
+// and actions that do not change the PR's state are ignored. Returns ErrUnauthorized for a bad signature.
+func (s *WebhookService) HandleGitHub(
+	ctx context.Context,
+	event, signature string,
+	body []byte,
+) (*models.WebhookResult, error) {
+	if err := github.VerifySignature(s.githubSecret, body, signature); err != nil {
+		s.log.WarnContext(ctx, "rejected GitHub delivery", slog.String("error", err.Error()))
+		return nil, apperrors.ErrUnauthorized
+	}
+	if event != github.EventPullRequest {
+		s.log.WarnContext(ctx, "ignored GitHub event", slog.String("event", event))
+		return ignored("unsupported event " + event), nil
+	}
+
+	payload, err := github.ParsePullRequestEvent(body)
+	if err != nil {
+		s.log.WarnContext(ctx, "invalid GitHub payload", slog.String("error", err.Error()))
+		return nil, apperrors.Wrap(apperrors.ErrInvalidInput, err.Error())
+	}
+	ctx = reqctx.WithActor(ctx, models.ProviderGitHub+":"+payload.Sender.Login)
+	result, err := s.applyGitHub(ctx, payload)
+	return s.settle(ctx, result, err)
+}
+
+// applyGitHub mirrors the action of a pull_request event onto the PR.
+func (s *WebhookService) applyGitHub(
+	ctx context.Context,
+	payload *github.PullRequestEvent,
+) (*models.WebhookResult, error) {
+	repository, prID := payload.Repository.FullName, payload.ID()
+
+	switch payload.Action {
+	case github.ActionOpened:
+		return s.openPR(ctx, models.ProviderGitHub, payload.PullRequest.User.Login, &models.PullRequest{
+			ID:         prID,
+			Title:      payload.PullRequest.Title,
+			Repository: repository,
+			Draft:      payload.PullRequest.Draft,
+		})
+	case github.ActionReadyForReview:
+		return applied(s.prs.ReadyPR(ctx, repository, prID, nil))
+	case github.ActionClosed:
+		if payload.PullRequest.Merged {
+			return applied(s.prs.MergePR(ctx, repository, prID, true, "merged on "+models.ProviderGitHub))
+		}
+		return applied(s.prs.ClosePR(ctx, repository, prID))
+	case github.ActionReopened:
+		return applied(s.prs.ReopenPR(ctx, repository, prID))
+	default:
+		s.log.WarnContext(ctx, "ignored GitHub action",
+			slog.String("action", payload.Action),
+			slog.String("pr_id", prID))
+		return ignored("unsupported action " + payload.Action), nil
+	}
+}
+
+// HandleGitLab checks the secret token of a GitLab delivery and applies it. Events other than Merge Request
+// Hook and actions that do not change the PR's state are ignored. Returns ErrUnauthorized for a bad token.
+func (s *WebhookService) HandleGitLab(
+	ctx context.Context,
+	event, token string,
+	body []byte,
+) (*models.WebhookResult, error) {
+	if err := gitlab.VerifyToken(s.gitlabToken, token); err != nil {
+		s.log.WarnContext(ctx, "rejected GitLab delivery", slog.String("error", err.Error()))
+		return nil, apperrors.ErrUnauthorized
+	}
+	if event != gitlab.EventMergeRequest {
+		s.log.WarnContext(ctx, "ignored GitLab event", slog.String("event", event))
+		return ignored("unsupported event " + event), nil
+	}
+
+	payload, err := gitlab.ParseMergeRequestEvent(body)
+	if err != nil {
+		s.log.WarnContext(ctx, "invalid GitLab payload", slog.String("error", err.Error()))
+		return nil, apperrors.Wrap(apperrors.ErrInvalidInput, err.Error())
+	}
+	ctx = reqctx.WithActor(ctx, models.ProviderGitLab+":"+payload.User.Username)
+	result, err := s.applyGitLab(ctx, payload)
+	return s.settle(ctx, result, err)
+}
+
+// applyGitLab mirrors the action of a Merge Request Hook onto the PR.
+func (s *WebhookService) applyGitLab(
+	ctx context.Context,
+	payload *gitlab.MergeRequestEvent,
+) (*models.WebhookResult, error) {
+	repository, prID := payload.Project.PathWithNamespace, payload.ID()
+
+	switch action := payload.ObjectAttributes.Action; action {
+	case gitlab.ActionOpen:
+		author, ok := payload.Author()
+		if !ok {
+			s.log.WarnContext(ctx, "GitLab merge request author not in payload",
+				slog.Int("author_id", payload.ObjectAttributes.AuthorID),
+				slog.String("pr_id", prID))
+			return ignored("PR author has no linked account"), nil
+		}
+		return s.openPR(ctx, models.ProviderGitLab, author, &models.PullRequest{
+			ID:         prID,
+			Title:      payload.Title(),
+			Repository: repository,
+			Draft:      payload.Draft(),
+		})
+	case gitlab.ActionUpdate:
+		// Updates fire on every push and edit; only leaving draft changes the PR here.
+		if !payload.MarkedReady() {
+			return ignored("no changes to mirror"), nil
+		}
+		return applied(s.prs.ReadyPR(ctx, repository, prID, nil))
+	case gitlab.ActionMerge:
+		return applied(s.prs.MergePR(ctx, repository, prID, true, "merged on "+models.ProviderGitLab))
+	case gitlab.ActionClose:
+		return applied(s.prs.ClosePR(ctx, repository, prID))
+	case gitlab.ActionReopen:
+		return applied(s.prs.ReopenPR(ctx, repository, prID))
+	default:
+		s.log.WarnContext(ctx, "ignored GitLab action", slog.String("action", action), slog.String("pr_id", prID))
+		return ignored("unsupported action " + action), nil
+	}
+}
+
+// openPR creates pr authored by the user linked to authorLogin. A PR that already exists is left as it
+// is, so redelivered events do nothing.
+func (s *WebhookService) openPR(
+	ctx context.Context,
+	provider, authorLogin string,
+	pr *models.PullRequest,
+) (*models.WebhookResult, error) {
+	authorID, err := s.accounts.ResolveUser(ctx, provider, authorLogin)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNoAccount) {
+			s.log.WarnContext(ctx, "PR author has no linked account",
+				slog.String("provider", provider),
+				slog.String("login", authorLogin),
+				slog.String("pr_id", pr.ID))
+			return ignored("PR author has no linked account"), nil
+		}
+		return nil, err
+	}
+	pr.AuthorID = authorID
+
+	// Look first so that a redelivery does not run reviewer selection; ErrPRExists covers concurrent ones.
+	existing, err := s.prs.GetPR(ctx, pr.Repository, pr.ID)
+	if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+		return nil, err
+	}
+	if existing == nil {
+		var created *models.PullRequest
+		created, err = s.prs.CreatePR(ctx, pr)
+		if !errors.Is(err, apperrors.ErrPRExists) {
+			return applied(created, err)
+		}
+		if existing, err = s.prs.GetPR(ctx, pr.Repository, pr.ID); err != nil {
+			return nil, err
+		}
+	}
+	return &models.WebhookResult{Status: models.WebhookIgnored, Reason: "PR already exists", PR: existing}, nil
+}
+
+// settle turns errors a redelivery cannot fix — the PR is unknown, or already merged or closed — into an
+// ignored result with a warning. Code hosts count failed deliveries against the webhook and may disable it.
+func (s *WebhookService) settle(
+	ctx context.Context,
+	result *models.WebhookResult,
+	err error,
+) (*models.WebhookResult, error) {
+	var reason string
+	switch {
+	case err == nil:
+		return result, nil
+	case errors.Is(err, apperrors.ErrNotFound):
+		reason = "unknown PR"
+	case errors.Is(err, apperrors.ErrPRMerged):
+		reason = "PR already merged"
+	case errors.Is(err, apperrors.ErrPRClosed):
+		reason = "PR already closed"
+	default:
+		return nil, err
+	}
+	s.log.WarnContext(ctx, "webhook change not applied", slog.String("reason", reason))
+	return ignored(reason), nil
+}
+
+func applied(pr *models.PullRequest, err error) (*models.WebhookResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &models.WebhookResult{Status: models.WebhookApplied, PR: pr}, nil
+}
+
+func ignored(reason string) *models.WebhookResult {
+	return &models.WebhookResult{Status: models.WebhookIgnored, Reason: reason}
+}
@@ -0,0 +1,93 @@
+// Package slack formats messages for Slack incoming webhooks and posts them. Messages use Slack's mrkdwn;
+// users are mentioned by their member ID.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// ErrRejected is matched by the errors returned when the webhook does not accept a message.
+var ErrRejected = errors.New("slack rejected the message")
+
+// RejectError is returned when the webhook answers with a status other than 200. Body is the start of
+// the response, which names the reason, such as no_service.
+type RejectError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *RejectError) Error() string {
+	return fmt.Sprintf("%s: %d %s", ErrRejected, e.StatusCode, e.Body)
+}
+
+func (e *RejectError) Is(target error) bool {
+	return target == ErrRejected
+}
+
+// Permanent reports whether err is a client error (4xx) of the webhook, such as a deleted (404, 410) or
+// disabled (403) webhook, so posting the message again would fail the same way. Rate limiting (429) is
+// not permanent.
+func Permanent(err error) bool {
+	var reject *RejectError
+	return errors.As(err, &reject) && reject.StatusCode >= 400 && reject.StatusCode < 500 &&
+		reject.StatusCode != http.StatusTooManyRequests
+}
+
+// maxErrorBody is how much of a rejection's response body is kept in the error.
+const maxErrorBody = 256
+
+var handlePattern = regexp.MustCompile(`^[UW][A-Z0-9]{2,}$`)
+
+// Message is the body of an incoming webhook request.
+type Message struct {
+	Text string `json:"text"`
+}
+
+// ValidHandle reports whether handle is a Slack member ID such as U024BE7LH.
+func ValidHandle(handle string) bool {
+	return handlePattern.MatchString(handle)
+}
+
+// Mention returns the mrkdwn that mentions the member with handle.
+func Mention(handle string) string {
+	return "<@" + handle + ">"
+}
+
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Escape escapes the characters mrkdwn gives a meaning to in text.
+func Escape(text string) string {
+	return escaper.Replace(text)
+}
+
+// Post sends msg to the incoming webhook at url.
+func Post(ctx context.Context, client *http.Client, url string, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	reply, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if resp.StatusCode != http.StatusOK {
+		return &RejectError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(reply))}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+)
+
+// AbsenceWorker periodically reassigns open reviews of users whose absence has started.
+type AbsenceWorker struct {
+	userSvc  services.UserServiceInterface
+	interval time.Duration
+	log      *slog.Logger
+}
+
+func NewAbsenceWorker(userSvc services.UserServiceInterface, interval time.Duration, log *slog.Logger) *AbsenceWorker {
+	return &AbsenceWorker{userSvc: userSvc, interval: interval, log: log}
+}
+
+// Run processes started absences right away and then every interval until ctx is cancelled.
+func (w *AbsenceWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.log.InfoContext(ctx, "absence worker started", slog.Duration("interval", w.interval))
+	for {
+		if _, err := w.userSvc.ReassignAbsentReviewers(ctx); err != nil {
+			w.log.ErrorContext(ctx, "absence worker run failed", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			w.log.InfoContext(ctx, "absence worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+)
+
+// DeliveryWorker periodically sends the outbound webhook deliveries that are due.
+type DeliveryWorker struct {
+	subscriptionSvc services.SubscriptionServiceInterface
+	interval        time.Duration
+	log             *slog.Logger
+}
+
+func NewDeliveryWorker(
+	subscriptionSvc services.SubscriptionServiceInterface,
+	interval time.Duration,
+	log *slog.Logger,
+) *DeliveryWorker {
+	return &DeliveryWorker{subscriptionSvc: subscriptionSvc, interval: interval, log: log}
+}
+
+// Run sends due deliveries right away and then every interval until ctx is cancelled.
+func (w *DeliveryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.log.InfoContext(ctx, "delivery worker started", slog.Duration("interval", w.interval))
+	for {
+		if _, err := w.subscriptionSvc.DeliverDue(ctx); err != nil {
+			w.log.ErrorContext(ctx, "delivery worker run failed", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			w.log.InfoContext(ctx, "delivery worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
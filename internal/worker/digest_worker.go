@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+)
+
+// DigestWorker periodically sends the daily email digests that are due.
+type DigestWorker struct {
+	emailSvc services.EmailServiceInterface
+	interval time.Duration
+	log      *slog.Logger
+}
+
+func NewDigestWorker(
+	emailSvc services.EmailServiceInterface,
+	interval time.Duration,
+	log *slog.Logger,
+) *DigestWorker {
+	return &DigestWorker{emailSvc: emailSvc, interval: interval, log: log}
+}
+
+// Run sends the due digests right away and then every interval until ctx is cancelled.
+func (w *DigestWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.log.InfoContext(ctx, "digest worker started", slog.Duration("interval", w.interval))
+	for {
+		if _, err := w.emailSvc.SendDigests(ctx); err != nil {
+			w.log.ErrorContext(ctx, "digest worker run failed", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			w.log.InfoContext(ctx, "digest worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+)
+
+// OutboxWorker periodically dispatches the due outbox events to the sinks.
+type OutboxWorker struct {
+	outboxSvc services.OutboxServiceInterface
+	interval  time.Duration
+	log       *slog.Logger
+}
+
+func NewOutboxWorker(
+	outboxSvc services.OutboxServiceInterface,
+	interval time.Duration,
+	log *slog.Logger,
+) *OutboxWorker {
+	return &OutboxWorker{outboxSvc: outboxSvc, interval: interval, log: log}
+}
+
+// Run dispatches due events right away and then every interval until ctx is cancelled.
+func (w *OutboxWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.log.InfoContext(ctx, "outbox worker started", slog.Duration("interval", w.interval))
+	for {
+		if _, err := w.outboxSvc.Dispatch(ctx); err != nil {
+			w.log.ErrorContext(ctx, "outbox worker run failed", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			w.log.InfoContext(ctx, "outbox worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+)
+
+// ReminderWorker periodically reminds reviewers of their stale reviews.
+type ReminderWorker struct {
+	reminderSvc services.ReminderServiceInterface
+	interval    time.Duration
+	log         *slog.Logger
+}
+
+func NewReminderWorker(
+	reminderSvc services.ReminderServiceInterface,
+	interval time.Duration,
+	log *slog.Logger,
+) *ReminderWorker {
+	return &ReminderWorker{reminderSvc: reminderSvc, interval: interval, log: log}
+}
+
+// Run sends the due reminders right away and then every interval until ctx is cancelled.
+func (w *ReminderWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.log.InfoContext(ctx, "reminder worker started", slog.Duration("interval", w.interval))
+	for {
+		if _, err := w.reminderSvc.RemindStale(ctx); err != nil {
+			w.log.ErrorContext(ctx, "reminder worker run failed", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			w.log.InfoContext(ctx, "reminder worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- NULL means the team uses the service-wide default (DEFAULT_REVIEWERS_COUNT).
+ALTER TABLE teams ADD COLUMN reviewers_count INT CHECK (reviewers_count > 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE teams DROP COLUMN IF EXISTS reviewers_count;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- NULL means the user has no limit on concurrent open reviews.
+ALTER TABLE users ADD COLUMN max_open_reviews INT CHECK (max_open_reviews > 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS max_open_reviews;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE user_absences (
+                               id BIGSERIAL PRIMARY KEY,
+                               user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                               starts_at TIMESTAMPTZ NOT NULL,
+                               ends_at TIMESTAMPTZ NOT NULL,
+                               reason TEXT NOT NULL DEFAULT '',
+                               -- Set once the absence worker has moved the user's open reviews to other members.
+                               reassigned_at TIMESTAMPTZ,
+                               created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                               CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_user_absences_user_id ON user_absences(user_id);
+CREATE INDEX idx_user_absences_window ON user_absences(starts_at, ends_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_user_absences_window;
+DROP INDEX IF EXISTS idx_user_absences_user_id;
+
+DROP TABLE IF EXISTS user_absences;
+-- +goose StatementEnd
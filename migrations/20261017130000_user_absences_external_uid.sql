@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- UID of the calendar event an absence was imported from; NULL for absences added via the API.
+ALTER TABLE user_absences ADD COLUMN external_uid TEXT;
+
+ALTER TABLE user_absences
+    ADD CONSTRAINT user_absences_user_id_external_uid_key UNIQUE (user_id, external_uid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_absences DROP CONSTRAINT IF EXISTS user_absences_user_id_external_uid_key;
+
+ALTER TABLE user_absences DROP COLUMN IF EXISTS external_uid;
+-- +goose StatementEnd
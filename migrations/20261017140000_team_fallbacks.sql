@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Teams to draw reviewers from, in position order, when the home team has no free reviewers.
+CREATE TABLE team_fallbacks (
+                                team_name TEXT NOT NULL REFERENCES teams(name) ON DELETE CASCADE,
+                                fallback_team_name TEXT NOT NULL REFERENCES teams(name) ON DELETE CASCADE,
+                                position INT NOT NULL,
+                                PRIMARY KEY (team_name, fallback_team_name),
+                                UNIQUE (team_name, position),
+                                CHECK (team_name <> fallback_team_name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS team_fallbacks;
+-- +goose StatementEnd
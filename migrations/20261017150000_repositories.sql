@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Per-repository configuration. codeowners holds the raw CODEOWNERS file used to pick reviewers.
+CREATE TABLE repositories (
+                              name TEXT PRIMARY KEY,
+                              codeowners TEXT NOT NULL DEFAULT '',
+                              codeowners_mode TEXT NOT NULL DEFAULT 'preferred'
+                                  CHECK (codeowners_mode IN ('preferred', 'required')),
+                              updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS repositories;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- PR IDs are only unique within a repository. '' is the repository of PRs created without one.
+ALTER TABLE pull_requests ADD COLUMN repository TEXT NOT NULL DEFAULT '';
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_pkey;
+ALTER TABLE pull_requests ADD PRIMARY KEY (repository, id);
+CREATE INDEX idx_pr_id ON pull_requests(id);
+
+-- NULL means PRs of the repository use the author's team and its reviewer count.
+ALTER TABLE repositories
+    ADD COLUMN default_team_name TEXT REFERENCES teams(name) ON DELETE SET NULL,
+    ADD COLUMN reviewers_count INT CHECK (reviewers_count > 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE repositories
+    DROP COLUMN IF EXISTS reviewers_count,
+    DROP COLUMN IF EXISTS default_team_name;
+
+DROP INDEX IF EXISTS idx_pr_id;
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_pkey;
+ALTER TABLE pull_requests ADD PRIMARY KEY (id);
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS repository;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- CLOSED marks PRs declined without merging; they can be reopened.
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_status_check;
+ALTER TABLE pull_requests
+    ADD CONSTRAINT pull_requests_status_check CHECK (status IN ('OPEN', 'MERGED', 'CLOSED'));
+ALTER TABLE pull_requests ADD COLUMN closed_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE pull_requests SET status = 'OPEN' WHERE status = 'CLOSED';
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS closed_at;
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_status_check;
+ALTER TABLE pull_requests
+    ADD CONSTRAINT pull_requests_status_check CHECK (status IN ('OPEN', 'MERGED'));
+-- +goose StatementEnd
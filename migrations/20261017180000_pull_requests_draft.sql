@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Draft PRs are stored without reviewers until they are marked ready.
+ALTER TABLE pull_requests ADD COLUMN is_draft BOOLEAN NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS is_draft;
+-- +goose StatementEnd
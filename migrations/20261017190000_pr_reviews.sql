@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Verdicts submitted by assigned reviewers. Reviewers without a row are PENDING.
+CREATE TABLE pr_reviews (
+    repository TEXT NOT NULL,
+    pr_id TEXT NOT NULL,
+    reviewer_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    state TEXT NOT NULL CHECK (state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED')),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (repository, pr_id, reviewer_id),
+    FOREIGN KEY (repository, pr_id) REFERENCES pull_requests(repository, id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS pr_reviews;
+-- +goose StatementEnd
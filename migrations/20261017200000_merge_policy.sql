@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Approvals needed before merge. NULL means no merge policy; the repository's policy wins over the team's.
+ALTER TABLE teams ADD COLUMN required_approvals INT CHECK (required_approvals >= 0);
+ALTER TABLE repositories ADD COLUMN required_approvals INT CHECK (required_approvals >= 0);
+
+-- Merges forced past the merge policy, with what was blocking them at the time.
+CREATE TABLE merge_overrides (
+    id BIGSERIAL PRIMARY KEY,
+    repository TEXT NOT NULL,
+    pr_id TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    required_approvals INT NOT NULL,
+    approvals INT NOT NULL,
+    pending_reviewers TEXT[] NOT NULL DEFAULT '{}',
+    changes_requested_by TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repository, pr_id) REFERENCES pull_requests(repository, id) ON DELETE CASCADE
+);
+CREATE INDEX idx_merge_overrides_pr ON merge_overrides(repository, pr_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS merge_overrides;
+ALTER TABLE repositories DROP COLUMN IF EXISTS required_approvals;
+ALTER TABLE teams DROP COLUMN IF EXISTS required_approvals;
+-- +goose StatementEnd
@@ -0,0 +1,66 @@
+-- +goose Up
+-- +goose StatementBegin
+-- One row per reviewer assignment. removed_at is set when the reviewer is taken off the PR, so the current
+-- reviewers of a PR are its rows with removed_at IS NULL, ordered by position. The verdict lives on the
+-- assignment: a reviewer assigned again gets a new PENDING row.
+CREATE TABLE pr_reviewers (
+    id BIGSERIAL PRIMARY KEY,
+    repository TEXT NOT NULL,
+    pr_id TEXT NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    state TEXT NOT NULL DEFAULT 'PENDING' CHECK (state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED')),
+    assigned_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP,
+    removed_at TIMESTAMP,
+    FOREIGN KEY (repository, pr_id) REFERENCES pull_requests(repository, id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_pr_reviewers_active ON pr_reviewers(repository, pr_id, user_id) WHERE removed_at IS NULL;
+CREATE INDEX idx_pr_reviewers_user ON pr_reviewers(user_id) WHERE removed_at IS NULL;
+
+-- Reviewer IDs that never matched a user cannot be kept under the foreign key and are dropped.
+INSERT INTO pr_reviewers (repository, pr_id, user_id, position, state, assigned_at, reviewed_at)
+SELECT DISTINCT ON (pr.repository, pr.id, r.user_id)
+    pr.repository, pr.id, r.user_id, r.position - 1, COALESCE(v.state, 'PENDING'),
+    COALESCE(pr.created_at, CURRENT_TIMESTAMP), v.updated_at
+FROM pull_requests pr
+CROSS JOIN LATERAL unnest(pr.reviewers) WITH ORDINALITY AS r(user_id, position)
+JOIN users u ON u.id = r.user_id
+LEFT JOIN pr_reviews v ON v.repository = pr.repository AND v.pr_id = pr.id AND v.reviewer_id = r.user_id
+ORDER BY pr.repository, pr.id, r.user_id, r.position;
+
+DROP TABLE pr_reviews;
+DROP INDEX IF EXISTS idx_pr_reviewers;
+ALTER TABLE pull_requests DROP COLUMN reviewers;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Only current assignments survive the rollback; removed ones have no place in the array.
+ALTER TABLE pull_requests ADD COLUMN reviewers TEXT[];
+UPDATE pull_requests pr SET reviewers = rv.reviewers
+FROM (
+    SELECT repository, pr_id, array_agg(user_id ORDER BY position) AS reviewers
+    FROM pr_reviewers
+    WHERE removed_at IS NULL
+    GROUP BY repository, pr_id
+) rv
+WHERE pr.repository = rv.repository AND pr.id = rv.pr_id;
+CREATE INDEX idx_pr_reviewers ON pull_requests USING GIN(reviewers);
+
+CREATE TABLE pr_reviews (
+    repository TEXT NOT NULL,
+    pr_id TEXT NOT NULL,
+    reviewer_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    state TEXT NOT NULL CHECK (state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED')),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (repository, pr_id, reviewer_id),
+    FOREIGN KEY (repository, pr_id) REFERENCES pull_requests(repository, id) ON DELETE CASCADE
+);
+INSERT INTO pr_reviews (repository, pr_id, reviewer_id, state, updated_at)
+SELECT repository, pr_id, user_id, state, COALESCE(reviewed_at, assigned_at)
+FROM pr_reviewers
+WHERE removed_at IS NULL AND state <> 'PENDING';
+
+DROP TABLE IF EXISTS pr_reviewers;
+-- +goose StatementEnd
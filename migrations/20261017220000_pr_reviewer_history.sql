@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Who assigned or removed a reviewer and why. Assignments made before this migration have an empty reason.
+ALTER TABLE pr_reviewers
+    ADD COLUMN assigned_by TEXT NOT NULL DEFAULT 'system',
+    ADD COLUMN assigned_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN removed_by TEXT,
+    ADD COLUMN removed_reason TEXT;
+UPDATE pr_reviewers SET removed_by = 'system', removed_reason = '' WHERE removed_at IS NOT NULL;
+CREATE INDEX idx_pr_reviewers_pr ON pr_reviewers(repository, pr_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_pr_reviewers_pr;
+ALTER TABLE pr_reviewers
+    DROP COLUMN IF EXISTS removed_reason,
+    DROP COLUMN IF EXISTS removed_by,
+    DROP COLUMN IF EXISTS assigned_reason,
+    DROP COLUMN IF EXISTS assigned_by;
+-- +goose StatementEnd
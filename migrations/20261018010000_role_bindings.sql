@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Roles granted to principals: users of the service, but also admins and bots that belong to no team, so
+-- user_id is not a foreign key. A binding with team_name NULL applies to every team.
+CREATE TABLE role_bindings (
+    id BIGSERIAL PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('admin', 'team_lead', 'member', 'bot')),
+    team_name TEXT REFERENCES teams(name) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_role_bindings_unique ON role_bindings(user_id, role, COALESCE(team_name, ''));
+
+-- Tokens now authenticate a principal and its permissions come from role_bindings. Existing tokens become
+-- principals named after the token: admin tokens keep global admin, user tokens become global members.
+ALTER TABLE api_tokens ADD COLUMN user_id TEXT;
+UPDATE api_tokens SET user_id = name;
+ALTER TABLE api_tokens ALTER COLUMN user_id SET NOT NULL;
+
+INSERT INTO role_bindings (user_id, role)
+SELECT DISTINCT name, CASE role WHEN 'admin' THEN 'admin' ELSE 'member' END
+FROM api_tokens
+WHERE revoked_at IS NULL
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE api_tokens DROP COLUMN role;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Principals with a global admin binding get admin tokens back; everyone else gets user tokens.
+ALTER TABLE api_tokens ADD COLUMN role TEXT NOT NULL DEFAULT 'user' CHECK (role IN ('admin', 'user'));
+UPDATE api_tokens t SET role = 'admin'
+WHERE EXISTS (
+    SELECT 1 FROM role_bindings b
+    WHERE b.user_id = t.user_id AND b.role = 'admin' AND b.team_name IS NULL
+);
+ALTER TABLE api_tokens ALTER COLUMN role DROP DEFAULT;
+ALTER TABLE api_tokens DROP COLUMN user_id;
+
+DROP TABLE IF EXISTS role_bindings;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Logins on code hosts (GitHub, GitLab) linked to users, so that webhook events can name PR authors.
+-- Logins are stored lower-cased: both hosts treat them case-insensitively.
+CREATE TABLE user_accounts (
+    provider TEXT NOT NULL CHECK (provider IN ('github', 'gitlab')),
+    login TEXT NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, login)
+);
+CREATE INDEX idx_user_accounts_user ON user_accounts(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_accounts;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- An event published to the webhook sink again is queued once per subscription.
+CREATE UNIQUE INDEX idx_webhook_deliveries_event ON webhook_deliveries(subscription_id, event_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_webhook_deliveries_event;
+-- +goose StatementEnd
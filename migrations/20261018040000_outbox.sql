@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Domain events written in the transaction of the change they report. The outbox dispatcher claims pending
+-- rows with FOR UPDATE SKIP LOCKED, publishes them to the sinks and marks them dispatched; a failed event is
+-- retried at next_attempt_at. event_id is the dedup ID the sinks see on every attempt.
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_id TEXT NOT NULL UNIQUE,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dispatched_at TIMESTAMP
+);
+CREATE INDEX idx_outbox_pending ON outbox(next_attempt_at, id) WHERE dispatched_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Slack incoming webhook of each team's channel, as a path under SLACK_WEBHOOK_BASE_URL. The path carries
+-- the webhook's secret, so the API never returns it.
+CREATE TABLE team_slack_channels (
+    team_name TEXT PRIMARY KEY REFERENCES teams(name) ON DELETE CASCADE,
+    webhook_path TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Slack member ID each user is mentioned by.
+CREATE TABLE user_slack_handles (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    handle TEXT NOT NULL
+);
+
+-- When the reviewer was last reminded of a pending review; the next reminder is due a reminder period later.
+ALTER TABLE pr_reviewers ADD COLUMN reminded_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS reminded_at;
+DROP TABLE IF EXISTS user_slack_handles;
+DROP TABLE IF EXISTS team_slack_channels;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Empty email means the user gets no email notifications. last_digest_at is the daily digest slot the user
+-- was last sent a digest for.
+ALTER TABLE users
+    ADD COLUMN email TEXT NOT NULL DEFAULT '',
+    ADD COLUMN email_digest BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN last_digest_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN IF EXISTS last_digest_at,
+    DROP COLUMN IF EXISTS email_digest,
+    DROP COLUMN IF EXISTS email;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- actor is always the authenticated caller; on_behalf_of keeps the unverified X-Actor header next to it.
+ALTER TABLE audit_events ADD COLUMN on_behalf_of TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE audit_events DROP COLUMN IF EXISTS on_behalf_of;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Sinks that already took a pending event. A retry after a failed sink skips them, so that an email or a
+-- chat message is not sent again.
+ALTER TABLE outbox ADD COLUMN delivered_sinks TEXT[] NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE outbox DROP COLUMN IF EXISTS delivered_sinks;
+-- +goose StatementEnd
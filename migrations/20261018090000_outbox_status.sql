@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- FAILED is terminal: the event ran out of attempts and is no longer dispatched. It stays in the outbox with
+-- its last_error for inspection.
+ALTER TABLE outbox
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'DISPATCHED', 'FAILED'));
+UPDATE outbox SET status = 'DISPATCHED' WHERE dispatched_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_outbox_pending;
+CREATE INDEX idx_outbox_pending ON outbox(next_attempt_at, id) WHERE status = 'PENDING';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_outbox_pending;
+ALTER TABLE outbox DROP COLUMN IF EXISTS status;
+CREATE INDEX idx_outbox_pending ON outbox(next_attempt_at, id) WHERE dispatched_at IS NULL;
+-- +goose StatementEnd
@@ -10,6 +10,7 @@ import (
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -26,11 +27,45 @@ func (m *mockUserRepoBench) GetActiveUsersByTeam(ctx context.Context, teamName s
 	return args.Get(0).([]models.User), args.Error(1)
 }
 
+func (m *mockUserRepoBench) GetAvailableUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	args := m.Called(ctx, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
 func (m *mockUserRepoBench) DeactivateUsersByTeam(ctx context.Context, teamName string) error {
 	args := m.Called(ctx, teamName)
 	return args.Error(0)
 }
 
+func (m *mockUserRepoBench) GetTeamNameByUserID(ctx context.Context, userID string) (string, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.Error(1)
+}
+
+type mockTeamRepoBench struct {
+	mock.Mock
+	repository.TeamRepository
+}
+
+func (m *mockTeamRepoBench) GetTeamSettings(ctx context.Context, name string) (*models.TeamSettings, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TeamSettings), args.Error(1)
+}
+
+func (m *mockTeamRepoBench) GetFallbackTeams(ctx context.Context, name string) ([]string, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 type mockPRRepoBench struct {
 	mock.Mock
 	repository.PRRepository
@@ -46,16 +81,22 @@ func (m *mockPRRepoBench) GetOpenPRsWithReviewersFromTeam(
 	return args.Get(0).([]models.PullRequest), args.Error(1)
 }
 
-func (m *mockPRRepoBench) UpdatePR(ctx context.Context, pr *models.PullRequest) error {
-	args := m.Called(ctx, pr)
+func (m *mockPRRepoBench) UpdatePR(ctx context.Context, pr *models.PullRequest, change models.AssignmentChange) error {
+	args := m.Called(ctx, pr, change)
 	return args.Error(0)
 }
 
+type mockAbsenceRepoBench struct {
+	mock.Mock
+	repository.AbsenceRepository
+}
+
 // BenchmarkDeactivateUsersByTeam_NoPRs benchmarks deactivation with no PRs to reassign.
 func BenchmarkDeactivateUsersByTeam_NoPRs(b *testing.B) {
 	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 	mUserRepo := &mockUserRepoBench{}
 	mPRRepo := &mockPRRepoBench{}
+	mTeamRepo := &mockTeamRepoBench{}
 
 	// Setup: 10 active users in team
 	activeUsers := make([]models.User, 10)
@@ -72,7 +113,9 @@ func BenchmarkDeactivateUsersByTeam_NoPRs(b *testing.B) {
 	mUserRepo.On("DeactivateUsersByTeam", mock.Anything, "team1").Return(nil)
 	mPRRepo.On("GetOpenPRsWithReviewersFromTeam", mock.Anything, "team1").Return([]models.PullRequest{}, nil)
 
-	svc := services.NewUserService(mUserRepo, mPRRepo, log)
+	svc := services.NewUserService(
+		mUserRepo, mPRRepo, mTeamRepo, &mockAbsenceRepoBench{}, &mockRepositoryRepoBench{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 
 	b.ResetTimer()
 	for b.Loop() {
@@ -85,6 +128,7 @@ func BenchmarkDeactivateUsersByTeam_WithPRs(b *testing.B) {
 	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 	mUserRepo := &mockUserRepoBench{}
 	mPRRepo := &mockPRRepoBench{}
+	mTeamRepo := &mockTeamRepoBench{}
 
 	// Setup: 20 active users in team
 	activeUsers := make([]models.User, 20)
@@ -123,10 +167,14 @@ func BenchmarkDeactivateUsersByTeam_WithPRs(b *testing.B) {
 	mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").Return(activeUsers, nil)
 	mUserRepo.On("DeactivateUsersByTeam", mock.Anything, "team1").Return(nil)
 	mPRRepo.On("GetOpenPRsWithReviewersFromTeam", mock.Anything, "team1").Return(prs, nil)
-	mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").Return(activeUsersAfter, nil)
-	mPRRepo.On("UpdatePR", mock.Anything, mock.Anything).Return(nil)
+	mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return(activeUsersAfter, nil)
+	mPRRepo.On("UpdatePR", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mUserRepo.On("GetTeamNameByUserID", mock.Anything, "author1").Return("team1", nil)
+	mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
 
-	svc := services.NewUserService(mUserRepo, mPRRepo, log)
+	svc := services.NewUserService(
+		mUserRepo, mPRRepo, mTeamRepo, &mockAbsenceRepoBench{}, &mockRepositoryRepoBench{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 
 	b.ResetTimer()
 	for b.Loop() {
@@ -139,6 +187,7 @@ func BenchmarkDeactivateUsersByTeam_LargeScale(b *testing.B) {
 	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 	mUserRepo := &mockUserRepoBench{}
 	mPRRepo := &mockPRRepoBench{}
+	mTeamRepo := &mockTeamRepoBench{}
 
 	// Setup: 100 active users in team
 	activeUsers := make([]models.User, 100)
@@ -177,10 +226,14 @@ func BenchmarkDeactivateUsersByTeam_LargeScale(b *testing.B) {
 	mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").Return(activeUsers, nil)
 	mUserRepo.On("DeactivateUsersByTeam", mock.Anything, "team1").Return(nil)
 	mPRRepo.On("GetOpenPRsWithReviewersFromTeam", mock.Anything, "team1").Return(prs, nil)
-	mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").Return(activeUsersAfter, nil)
-	mPRRepo.On("UpdatePR", mock.Anything, mock.Anything).Return(nil)
-
-	svc := services.NewUserService(mUserRepo, mPRRepo, log)
+	mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return(activeUsersAfter, nil)
+	mPRRepo.On("UpdatePR", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mUserRepo.On("GetTeamNameByUserID", mock.Anything, "author1").Return("team1", nil)
+	mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+
+	svc := services.NewUserService(
+		mUserRepo, mPRRepo, mTeamRepo, &mockAbsenceRepoBench{}, &mockRepositoryRepoBench{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 
 	b.ResetTimer()
 	for b.Loop() {
@@ -11,6 +11,7 @@ import (
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -35,78 +36,102 @@ func (m *mockUserRepoForPRBench) GetActiveUsersByTeam(ctx context.Context, teamN
 	return args.Get(0).([]models.User), args.Error(1)
 }
 
+func (m *mockUserRepoForPRBench) GetAvailableUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	args := m.Called(ctx, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
 type mockPRRepoForOpsBench struct {
 	mock.Mock
 	repository.PRRepository
 }
 
-func (m *mockPRRepoForOpsBench) CreatePR(ctx context.Context, pr *models.PullRequest) error {
-	args := m.Called(ctx, pr)
+func (m *mockPRRepoForOpsBench) CreatePR(
+	ctx context.Context,
+	pr *models.PullRequest,
+	change models.AssignmentChange,
+) error {
+	args := m.Called(ctx, pr, change)
 	return args.Error(0)
 }
 
-func (m *mockPRRepoForOpsBench) GetPRByID(ctx context.Context, id string) (*models.PullRequest, error) {
-	args := m.Called(ctx, id)
+func (m *mockPRRepoForOpsBench) GetPRByID(ctx context.Context, repository, id string) (*models.PullRequest, error) {
+	args := m.Called(ctx, repository, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.PullRequest), args.Error(1)
 }
 
-func (m *mockPRRepoForOpsBench) UpdatePR(ctx context.Context, pr *models.PullRequest) error {
-	args := m.Called(ctx, pr)
+func (m *mockPRRepoForOpsBench) UpdatePR(
+	ctx context.Context,
+	pr *models.PullRequest,
+	change models.AssignmentChange,
+) error {
+	args := m.Called(ctx, pr, change)
 	return args.Error(0)
 }
 
-func (m *mockPRRepoForOpsBench) MergePR(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *mockPRRepoForOpsBench) MergePR(ctx context.Context, repository, id string) error {
+	args := m.Called(ctx, repository, id)
 	return args.Error(0)
 }
 
-func (m *mockPRRepoForOpsBench) GetTopReviewers(ctx context.Context) ([]models.UserAssignment, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForOpsBench) GetTopReviewers(
+	ctx context.Context, repository string,
+) ([]models.UserAssignment, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.UserAssignment), args.Error(1)
 }
 
-func (m *mockPRRepoForOpsBench) GetAssignmentsPerUser(ctx context.Context) ([]models.UserAssignment, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForOpsBench) GetAssignmentsPerUser(
+	ctx context.Context, repository string,
+) ([]models.UserAssignment, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.UserAssignment), args.Error(1)
 }
 
-func (m *mockPRRepoForOpsBench) GetPrsByStatus(ctx context.Context) (int, int, error) {
-	args := m.Called(ctx)
-	return args.Int(0), args.Int(1), args.Error(2)
+func (m *mockPRRepoForOpsBench) GetPrsByStatus(ctx context.Context, repository string) (models.PrsStatus, error) {
+	args := m.Called(ctx, repository)
+	return args.Get(0).(models.PrsStatus), args.Error(1)
 }
 
-func (m *mockPRRepoForOpsBench) GetTotalPRs(ctx context.Context) (int, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForOpsBench) GetTotalPRs(ctx context.Context, repository string) (int, error) {
+	args := m.Called(ctx, repository)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *mockPRRepoForOpsBench) GetIdleUsersPerTeam(ctx context.Context) ([]models.TeamMetric, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForOpsBench) GetIdleUsersPerTeam(
+	ctx context.Context, repository string,
+) ([]models.TeamMetric, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.TeamMetric), args.Error(1)
 }
 
-func (m *mockPRRepoForOpsBench) GetNeedyPRsPerTeam(ctx context.Context) ([]models.TeamMetric, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForOpsBench) GetNeedyPRsPerTeam(
+	ctx context.Context, repository string,
+) ([]models.TeamMetric, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.TeamMetric), args.Error(1)
 }
 
-func (m *mockPRRepoForOpsBench) GetAvgCloseTime(ctx context.Context) (float64, int, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForOpsBench) GetAvgCloseTime(ctx context.Context, repository string) (float64, int, error) {
+	args := m.Called(ctx, repository)
 	return args.Get(0).(float64), args.Int(1), args.Error(2)
 }
 
@@ -120,13 +145,15 @@ func (m *mockPRRepoForOpsBench) GetOpenPRsWithReviewersFromTeam(
 	return args.Get(0).([]models.PullRequest), args.Error(1)
 }
 
-func (m *mockPRRepoForOpsBench) ExistsPR(ctx context.Context, id string) (bool, error) {
-	args := m.Called(ctx, id)
+func (m *mockPRRepoForOpsBench) ExistsPR(ctx context.Context, repository, id string) (bool, error) {
+	args := m.Called(ctx, repository, id)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *mockPRRepoForOpsBench) GetPRsForUser(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
-	args := m.Called(ctx, userID)
+func (m *mockPRRepoForOpsBench) GetPRsForUser(
+	ctx context.Context, userID, repository string, pendingOnly bool,
+) ([]models.PullRequestShort, error) {
+	args := m.Called(ctx, userID, repository, pendingOnly)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -134,10 +161,24 @@ func (m *mockPRRepoForOpsBench) GetPRsForUser(ctx context.Context, userID string
 }
 
 // BenchmarkCreatePR benchmarks PR creation with auto-assignment.
+type mockRepositoryRepoBench struct {
+	mock.Mock
+	repository.RepositoryRepository
+}
+
+func (m *mockRepositoryRepoBench) GetRepository(ctx context.Context, name string) (*models.Repository, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Repository), args.Error(1)
+}
+
 func BenchmarkCreatePR(b *testing.B) {
 	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 	mUserRepo := &mockUserRepoForPRBench{}
 	mPRRepo := &mockPRRepoForOpsBench{}
+	mTeamRepo := &mockTeamRepoBench{}
 
 	author := &models.User{
 		ID:       "author1",
@@ -163,12 +204,14 @@ func BenchmarkCreatePR(b *testing.B) {
 	createdPR.CreatedAt = &now
 
 	mUserRepo.On("GetUserByID", mock.Anything, "author1").Return(author, nil)
-	mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").Return(activeUsers, nil)
-	mPRRepo.On("ExistsPR", mock.Anything, mock.AnythingOfType("string")).Return(false, nil)
-	mPRRepo.On("CreatePR", mock.Anything, mock.Anything).Return(nil)
-	mPRRepo.On("GetPRByID", mock.Anything, mock.AnythingOfType("string")).Return(createdPR, nil)
+	mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return(activeUsers, nil)
+	mPRRepo.On("ExistsPR", mock.Anything, "", mock.AnythingOfType("string")).Return(false, nil)
+	mPRRepo.On("CreatePR", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mPRRepo.On("GetPRByID", mock.Anything, "", mock.AnythingOfType("string")).Return(createdPR, nil)
+	mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
 
-	svc := services.NewPRService(mPRRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPRRepo, mUserRepo, mTeamRepo, &mockRepositoryRepoBench{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
 
 	b.ResetTimer()
 	for i := range b.N {
@@ -186,6 +229,7 @@ func BenchmarkReassignReviewer(b *testing.B) {
 	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 	mUserRepo := &mockUserRepoForPRBench{}
 	mPRRepo := &mockPRRepoForOpsBench{}
+	mTeamRepo := &mockTeamRepoBench{}
 
 	pr := &models.PullRequest{
 		ID:        "pr-1",
@@ -207,16 +251,17 @@ func BenchmarkReassignReviewer(b *testing.B) {
 		{ID: "u4", Name: "User4", TeamName: "team1", IsActive: true},
 	}
 
-	mPRRepo.On("GetPRByID", mock.Anything, "pr-1").Return(pr, nil)
+	mPRRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(pr, nil)
 	mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(oldReviewer, nil)
-	mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").Return(activeUsers, nil)
-	mPRRepo.On("UpdatePR", mock.Anything, mock.Anything).Return(nil)
+	mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return(activeUsers, nil)
+	mPRRepo.On("UpdatePR", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	svc := services.NewPRService(mPRRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPRRepo, mUserRepo, mTeamRepo, &mockRepositoryRepoBench{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
 
 	b.ResetTimer()
 	for b.Loop() {
-		_, _, _ = svc.ReassignReviewer(context.Background(), "pr-1", "u1")
+		_, _, _ = svc.ReassignReviewer(context.Background(), "", "pr-1", "u1")
 	}
 }
 
@@ -225,6 +270,7 @@ func BenchmarkGetTopReviewers(b *testing.B) {
 	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 	mUserRepo := &mockUserRepoForPRBench{}
 	mPRRepo := &mockPRRepoForOpsBench{}
+	mTeamRepo := &mockTeamRepoBench{}
 
 	topReviewers := []models.UserAssignment{
 		{UserID: "u1", Name: "User1", Count: 10},
@@ -234,13 +280,14 @@ func BenchmarkGetTopReviewers(b *testing.B) {
 		{UserID: "u5", Name: "User5", Count: 2},
 	}
 
-	mPRRepo.On("GetTopReviewers", mock.Anything).Return(topReviewers, nil)
+	mPRRepo.On("GetTopReviewers", mock.Anything, "").Return(topReviewers, nil)
 
-	svc := services.NewPRService(mPRRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPRRepo, mUserRepo, mTeamRepo, &mockRepositoryRepoBench{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
 
 	b.ResetTimer()
 	for b.Loop() {
-		_, _ = svc.GetTopReviewers(context.Background())
+		_, _ = svc.GetTopReviewers(context.Background(), "")
 	}
 }
 
@@ -249,6 +296,7 @@ func BenchmarkGetAssignmentsPerUser(b *testing.B) {
 	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 	mUserRepo := &mockUserRepoForPRBench{}
 	mPRRepo := &mockPRRepoForOpsBench{}
+	mTeamRepo := &mockTeamRepoBench{}
 
 	assignments := make([]models.UserAssignment, 50)
 	for i := range 50 {
@@ -259,13 +307,14 @@ func BenchmarkGetAssignmentsPerUser(b *testing.B) {
 		}
 	}
 
-	mPRRepo.On("GetAssignmentsPerUser", mock.Anything).Return(assignments, nil)
+	mPRRepo.On("GetAssignmentsPerUser", mock.Anything, "").Return(assignments, nil)
 
-	svc := services.NewPRService(mPRRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPRRepo, mUserRepo, mTeamRepo, &mockRepositoryRepoBench{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
 
 	b.ResetTimer()
 	for b.Loop() {
-		_, _ = svc.GetAssignmentsPerUser(context.Background())
+		_, _ = svc.GetAssignmentsPerUser(context.Background(), "")
 	}
 }
 
@@ -274,6 +323,7 @@ func BenchmarkMergePR(b *testing.B) {
 	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 	mUserRepo := &mockUserRepoForPRBench{}
 	mPRRepo := &mockPRRepoForOpsBench{}
+	mTeamRepo := &mockTeamRepoBench{}
 
 	pr := &models.PullRequest{
 		ID:        "pr-1",
@@ -283,19 +333,18 @@ func BenchmarkMergePR(b *testing.B) {
 		Reviewers: []string{"u1", "u2"},
 	}
 
-	mergedPR := *pr
-	mergedPR.Status = "MERGED"
-	now := time.Now()
-	mergedPR.MergedAt = &now
-
-	mPRRepo.On("MergePR", mock.Anything, mock.AnythingOfType("string")).Return(nil)
-	mPRRepo.On("GetPRByID", mock.Anything, mock.AnythingOfType("string")).Return(&mergedPR, nil)
+	// Every lookup returns the OPEN PR so each iteration runs the full policy check and merge.
+	mUserRepo.On("GetUserByID", mock.Anything, "author1").Return(&models.User{ID: "author1", TeamName: "team1"}, nil)
+	mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+	mPRRepo.On("GetPRByID", mock.Anything, "", mock.AnythingOfType("string")).Return(pr, nil)
+	mPRRepo.On("MergePR", mock.Anything, "", mock.AnythingOfType("string")).Return(nil)
 
-	svc := services.NewPRService(mPRRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPRRepo, mUserRepo, mTeamRepo, &mockRepositoryRepoBench{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
 
 	b.ResetTimer()
 	for i := range b.N {
-		_, _ = svc.MergePR(context.Background(), fmt.Sprintf("pr-%d", i))
+		_, _ = svc.MergePR(context.Background(), "", fmt.Sprintf("pr-%d", i), false, "")
 	}
 }
 
@@ -304,13 +353,16 @@ func BenchmarkGetPrsByStatus(b *testing.B) {
 	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 	mUserRepo := &mockUserRepoForPRBench{}
 	mPRRepo := &mockPRRepoForOpsBench{}
+	mTeamRepo := &mockTeamRepoBench{}
 
-	mPRRepo.On("GetPrsByStatus", mock.Anything).Return(100, 50, nil)
+	mPRRepo.On("GetPrsByStatus", mock.Anything, "").
+		Return(models.PrsStatus{OpenPRs: 100, MergedPRs: 50, ClosedPRs: 10}, nil)
 
-	svc := services.NewPRService(mPRRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPRRepo, mUserRepo, mTeamRepo, &mockRepositoryRepoBench{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
 
 	b.ResetTimer()
 	for b.Loop() {
-		_, _, _ = svc.GetPrsByStatus(context.Background())
+		_, _ = svc.GetPrsByStatus(context.Background(), "")
 	}
 }
@@ -0,0 +1,107 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbsenceRepo(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewAbsenceRepo(pool)
+	userRepo := repository.NewUserRepo(pool)
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ($1) ON CONFLICT DO NOTHING`, "team1")
+	require.NoError(t, err)
+	for _, id := range []string{"u1", "u2"} {
+		_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES ($1, $2, $3, $4)`,
+			id, id, "team1", true)
+		require.NoError(t, err)
+	}
+
+	now := time.Now()
+	current := &models.Absence{UserID: "u1", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}
+	future := &models.Absence{UserID: "u1", StartsAt: now.Add(24 * time.Hour), EndsAt: now.Add(48 * time.Hour)}
+
+	t.Run("CreateAndList", func(t *testing.T) {
+		require.NoError(t, repo.CreateAbsence(ctx, future))
+		require.NoError(t, repo.CreateAbsence(ctx, current))
+		assert.NotZero(t, current.ID)
+
+		absences, listErr := repo.GetAbsencesByUser(ctx, "u1")
+		require.NoError(t, listErr)
+		require.Len(t, absences, 2)
+		assert.Equal(t, current.ID, absences[0].ID)
+	})
+
+	t.Run("AvailableUsersExcludeAbsent", func(t *testing.T) {
+		active, activeErr := userRepo.GetActiveUsersByTeam(ctx, "team1")
+		require.NoError(t, activeErr)
+		assert.Len(t, active, 2)
+
+		available, availableErr := userRepo.GetAvailableUsersByTeam(ctx, "team1")
+		require.NoError(t, availableErr)
+		require.Len(t, available, 1)
+		assert.Equal(t, "u2", available[0].ID)
+	})
+
+	t.Run("StartedUnprocessed", func(t *testing.T) {
+		started, startedErr := repo.GetStartedUnprocessedAbsences(ctx)
+		require.NoError(t, startedErr)
+		require.Len(t, started, 1)
+		assert.Equal(t, current.ID, started[0].ID)
+
+		require.NoError(t, repo.MarkAbsenceProcessed(ctx, current.ID))
+
+		started, startedErr = repo.GetStartedUnprocessedAbsences(ctx)
+		require.NoError(t, startedErr)
+		assert.Empty(t, started)
+	})
+
+	t.Run("UpsertByExternalUID", func(t *testing.T) {
+		imported := &models.Absence{
+			UserID: "u2", StartsAt: now.Add(72 * time.Hour), EndsAt: now.Add(96 * time.Hour), ExternalUID: "vac-1",
+		}
+		created, upsertErr := repo.UpsertAbsenceByExternalUID(ctx, imported)
+		require.NoError(t, upsertErr)
+		assert.True(t, created)
+
+		moved := &models.Absence{
+			UserID: "u2", StartsAt: now.Add(96 * time.Hour), EndsAt: now.Add(120 * time.Hour), ExternalUID: "vac-1",
+		}
+		created, upsertErr = repo.UpsertAbsenceByExternalUID(ctx, moved)
+		require.NoError(t, upsertErr)
+		assert.False(t, created)
+		assert.Equal(t, imported.ID, moved.ID)
+
+		absences, listErr := repo.GetAbsencesByUser(ctx, "u2")
+		require.NoError(t, listErr)
+		require.Len(t, absences, 1)
+		assert.Equal(t, "vac-1", absences[0].ExternalUID)
+		assert.WithinDuration(t, moved.StartsAt, absences[0].StartsAt, time.Millisecond)
+
+		deleted, deleteErr := repo.DeleteAbsenceByExternalUID(ctx, "u2", "vac-1")
+		require.NoError(t, deleteErr)
+		assert.True(t, deleted)
+		deleted, deleteErr = repo.DeleteAbsenceByExternalUID(ctx, "u2", "vac-1")
+		require.NoError(t, deleteErr)
+		assert.False(t, deleted)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		deleted, deleteErr := repo.DeleteAbsence(ctx, future.ID)
+		require.NoError(t, deleteErr)
+		assert.Equal(t, future.UserID, deleted.UserID)
+		_, deleteErr = repo.DeleteAbsence(ctx, future.ID)
+		assert.ErrorIs(t, deleteErr, apperrors.ErrNotFound)
+	})
+}
@@ -0,0 +1,77 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountRepo(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewAccountRepo(pool)
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ('team1')`)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES
+		('u1', 'u1', 'team1', true),
+		('u2', 'u2', 'team1', true)`)
+	require.NoError(t, err)
+
+	t.Run("Upsert", func(t *testing.T) {
+		account := &models.Account{Provider: models.ProviderGitHub, Login: "octocat", UserID: "u1"}
+		require.NoError(t, repo.UpsertAccount(ctx, account))
+		assert.False(t, account.CreatedAt.IsZero())
+		require.NoError(t, repo.UpsertAccount(ctx, &models.Account{
+			Provider: models.ProviderGitLab, Login: "octocat", UserID: "u1",
+		}))
+
+		// Linking the login again moves it to the new user.
+		require.NoError(t, repo.UpsertAccount(ctx, &models.Account{
+			Provider: models.ProviderGitHub, Login: "octocat", UserID: "u2",
+		}))
+		got, getErr := repo.GetAccount(ctx, models.ProviderGitHub, "octocat")
+		require.NoError(t, getErr)
+		assert.Equal(t, "u2", got.UserID)
+	})
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		err := repo.UpsertAccount(ctx, &models.Account{
+			Provider: models.ProviderGitHub, Login: "ghost", UserID: "ghost",
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		accounts, listErr := repo.ListAccounts(ctx, "u1")
+		require.NoError(t, listErr)
+		require.Len(t, accounts, 1)
+		assert.Equal(t, models.ProviderGitLab, accounts[0].Provider)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		_, delErr := repo.DeleteAccount(ctx, models.ProviderGitHub, "octocat", "u1")
+		assert.ErrorIs(t, delErr, apperrors.ErrNotFound)
+
+		deleted, delErr := repo.DeleteAccount(ctx, models.ProviderGitHub, "octocat", "u2")
+		require.NoError(t, delErr)
+		assert.Equal(t, "u2", deleted.UserID)
+
+		_, getErr := repo.GetAccount(ctx, models.ProviderGitHub, "octocat")
+		assert.ErrorIs(t, getErr, apperrors.ErrNotFound)
+	})
+
+	t.Run("UserDeletionCascades", func(t *testing.T) {
+		_, err := pool.Exec(ctx, `DELETE FROM users WHERE id = 'u1'`)
+		require.NoError(t, err)
+		_, getErr := repo.GetAccount(ctx, models.ProviderGitLab, "octocat")
+		assert.ErrorIs(t, getErr, apperrors.ErrNotFound)
+	})
+}
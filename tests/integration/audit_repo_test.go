@@ -0,0 +1,144 @@
+package integration_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRepo(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewAuditRepo(pool)
+	ctx := context.Background()
+
+	events := []*models.AuditEvent{
+		{Actor: "alice", Action: models.AuditTeamCreated, EntityType: models.AuditEntityTeam, EntityID: "team1",
+			After: []byte(`{"team_name": "team1"}`), RequestID: "req-1"},
+		{Actor: "bob", OnBehalfOf: "carol", Action: models.AuditUserActiveChanged,
+			EntityType: models.AuditEntityUser, EntityID: "u1", Before: []byte(`{"is_active": true}`), After: []byte(`{"is_active": false}`), RequestID: "req-2"},
+		{Actor: "alice", Action: models.AuditUserActiveChanged, EntityType: models.AuditEntityUser, EntityID: "u2",
+			Before: []byte(`{"is_active": false}`), After: []byte(`{"is_active": true}`)},
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		for _, e := range events {
+			require.NoError(t, repo.CreateAuditEvent(ctx, e))
+			assert.NotZero(t, e.ID)
+			assert.False(t, e.CreatedAt.IsZero())
+		}
+	})
+
+	t.Run("ListNewestFirst", func(t *testing.T) {
+		list, total, err := repo.ListAuditEvents(ctx, models.AuditFilter{Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+		require.Len(t, list, 3)
+		assert.Equal(t, events[2].ID, list[0].ID)
+		assert.Equal(t, events[0].ID, list[2].ID)
+		assert.Nil(t, list[2].Before)
+		assert.JSONEq(t, `{"team_name": "team1"}`, string(list[2].After))
+		assert.Equal(t, "carol", list[1].OnBehalfOf)
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		list, total, err := repo.ListAuditEvents(ctx, models.AuditFilter{Actor: "alice", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Len(t, list, 2)
+
+		list, total, err = repo.ListAuditEvents(ctx, models.AuditFilter{
+			EntityType: models.AuditEntityUser, EntityID: "u1", Limit: 10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, list, 1)
+		assert.Equal(t, "req-2", list[0].RequestID)
+
+		future := time.Now().Add(time.Hour)
+		_, total, err = repo.ListAuditEvents(ctx, models.AuditFilter{From: &future, Limit: 10})
+		require.NoError(t, err)
+		assert.Zero(t, total)
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		list, total, err := repo.ListAuditEvents(ctx, models.AuditFilter{Limit: 2, Offset: 2})
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+		require.Len(t, list, 1)
+		assert.Equal(t, events[0].ID, list[0].ID)
+	})
+}
+
+func TestTxManager_WithinTx(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	tx := repository.NewTxManager(pool)
+	teamRepo := repository.NewTeamRepo(pool)
+	auditRepo := repository.NewAuditRepo(pool)
+	ctx := context.Background()
+
+	t.Run("RollbackOnError", func(t *testing.T) {
+		errAbort := errors.New("abort")
+		err := tx.WithinTx(ctx, func(ctx context.Context) error {
+			require.NoError(t, teamRepo.CreateTeam(ctx, &models.Team{Name: "team-rollback"}))
+			require.NoError(t, auditRepo.CreateAuditEvent(ctx, &models.AuditEvent{
+				Actor: "alice", Action: models.AuditTeamCreated, EntityType: models.AuditEntityTeam,
+				EntityID: "team-rollback",
+			}))
+			return errAbort
+		})
+		require.ErrorIs(t, err, errAbort)
+
+		_, total, err := auditRepo.ListAuditEvents(ctx, models.AuditFilter{EntityID: "team-rollback", Limit: 10})
+		require.NoError(t, err)
+		assert.Zero(t, total)
+		_, err = teamRepo.GetTeamByName(ctx, "team-rollback")
+		assert.Error(t, err)
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		err := tx.WithinTx(ctx, func(ctx context.Context) error {
+			if createErr := teamRepo.CreateTeam(ctx, &models.Team{Name: "team-commit"}); createErr != nil {
+				return createErr
+			}
+			return auditRepo.CreateAuditEvent(ctx, &models.AuditEvent{
+				Actor: "alice", Action: models.AuditTeamCreated, EntityType: models.AuditEntityTeam,
+				EntityID: "team-commit",
+			})
+		})
+		require.NoError(t, err)
+
+		_, total, err := auditRepo.ListAuditEvents(ctx, models.AuditFilter{EntityID: "team-commit", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		_, err = teamRepo.GetTeamByName(ctx, "team-commit")
+		assert.NoError(t, err)
+	})
+	t.Run("SavepointRollsBackAlone", func(t *testing.T) {
+		errAbort := errors.New("abort")
+		err := tx.WithinTx(ctx, func(ctx context.Context) error {
+			require.NoError(t, teamRepo.CreateTeam(ctx, &models.Team{Name: "team-outer"}))
+			spErr := tx.WithinSavepoint(ctx, func(ctx context.Context) error {
+				require.NoError(t, teamRepo.CreateTeam(ctx, &models.Team{Name: "team-savepoint"}))
+				return errAbort
+			})
+			require.ErrorIs(t, spErr, errAbort)
+			return nil
+		})
+		require.NoError(t, err)
+
+		_, err = teamRepo.GetTeamByName(ctx, "team-outer")
+		assert.NoError(t, err)
+		_, err = teamRepo.GetTeamByName(ctx, "team-savepoint")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,127 @@
+package integration_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxRepo(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewOutboxRepo(pool)
+	tx := repository.NewTxManager(pool)
+	ctx := context.Background()
+
+	first := &models.Event{
+		ID:        "ev-1",
+		Type:      models.EventPRCreated,
+		Actor:     "alice",
+		CreatedAt: time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC),
+		Data:      models.EventData{PullRequest: &models.PullRequest{ID: "pr-1", Reviewers: []string{"u2"}}},
+	}
+	second := &models.Event{ID: "ev-2", Type: models.EventPRMerged}
+
+	t.Run("AddInTx", func(t *testing.T) {
+		rollback := errors.New("rollback")
+		err := tx.WithinTx(ctx, func(ctx context.Context) error {
+			require.NoError(t, repo.AddEvent(ctx, &models.Event{ID: "ev-0", Type: models.EventPRCreated}))
+			return rollback
+		})
+		require.ErrorIs(t, err, rollback)
+
+		require.NoError(t, repo.AddEvent(ctx, first))
+		require.NoError(t, repo.AddEvent(ctx, second))
+		assert.Error(t, repo.AddEvent(ctx, second), "event IDs are unique")
+	})
+
+	t.Run("ClaimSkipsLocked", func(t *testing.T) {
+		claimed := make(chan struct{})
+		release := make(chan struct{})
+		done := make(chan error)
+		go func() {
+			done <- tx.WithinTx(ctx, func(ctx context.Context) error {
+				entry, err := repo.ClaimNextEvent(ctx)
+				if err != nil {
+					return err
+				}
+				assert.Equal(t, first.ID, entry.Event.ID)
+				close(claimed)
+				<-release
+				return nil
+			})
+		}()
+		<-claimed
+
+		err := tx.WithinTx(ctx, func(ctx context.Context) error {
+			entry, err := repo.ClaimNextEvent(ctx)
+			require.NoError(t, err)
+			require.NotNil(t, entry)
+			assert.Equal(t, second.ID, entry.Event.ID, "the event locked by the other dispatcher is skipped")
+			return nil
+		})
+		require.NoError(t, err)
+		close(release)
+		require.NoError(t, <-done)
+	})
+
+	t.Run("ClaimDecodesEvent", func(t *testing.T) {
+		entry, err := repo.ClaimNextEvent(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, entry)
+		assert.Equal(t, first.ID, entry.Event.ID)
+		assert.Equal(t, "alice", entry.Event.Actor)
+		assert.True(t, first.CreatedAt.Equal(entry.Event.CreatedAt))
+		require.NotNil(t, entry.Event.Data.PullRequest)
+		assert.Equal(t, []string{"u2"}, entry.Event.Data.PullRequest.Reviewers)
+		assert.Zero(t, entry.Attempts)
+	})
+
+	t.Run("FailAndDispatch", func(t *testing.T) {
+		entry, err := repo.ClaimNextEvent(ctx)
+		require.NoError(t, err)
+		require.NoError(t, repo.MarkFailed(ctx, entry.ID, []string{"webhooks"}, "slack: timeout", time.Hour))
+
+		entry, err = repo.ClaimNextEvent(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, entry)
+		assert.Equal(t, second.ID, entry.Event.ID, "a failed event waits for its retry")
+		require.NoError(t, repo.MarkDispatched(ctx, entry.ID))
+
+		entry, err = repo.ClaimNextEvent(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, entry)
+
+		var attempts int
+		var lastError string
+		var delivered []string
+		require.NoError(t, pool.QueryRow(ctx, `
+			SELECT attempts, last_error, delivered_sinks FROM outbox WHERE event_id = $1
+		`, first.ID).Scan(&attempts, &lastError, &delivered))
+		assert.Equal(t, 1, attempts)
+		assert.Equal(t, "slack: timeout", lastError)
+		assert.Equal(t, []string{"webhooks"}, delivered)
+	})
+	t.Run("Abandon", func(t *testing.T) {
+		require.NoError(t, repo.AddEvent(ctx, &models.Event{ID: "ev-3", Type: models.EventPRMerged}))
+		entry, err := repo.ClaimNextEvent(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, entry)
+		require.NoError(t, repo.MarkAbandoned(ctx, entry.ID, nil, "slack: gone"))
+
+		entry, err = repo.ClaimNextEvent(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, entry, "an abandoned event is not claimed again")
+
+		var status string
+		require.NoError(t, pool.QueryRow(ctx, `SELECT status FROM outbox WHERE event_id = 'ev-3'`).Scan(&status))
+		assert.Equal(t, "FAILED", status)
+	})
+}
@@ -21,12 +21,25 @@ func setupTestDB(t *testing.T) *pgxpool.Pool {
 	pool, err := pgxpool.NewWithConfig(ctx, cfg)
 	require.NoError(t, err)
 
-	_, err = pool.Exec(ctx, `TRUNCATE TABLE pull_requests, users, teams CASCADE`)
+	_, err = pool.Exec(ctx,
+		`TRUNCATE TABLE pull_requests, users, teams, repositories, audit_events, api_tokens, role_bindings,
+			webhook_subscriptions, outbox CASCADE`)
 	require.NoError(t, err)
 
 	return pool
 }
 
+// assignReviewers stores the reviewers of a PR inserted directly into pull_requests.
+func assignReviewers(t *testing.T, pool *pgxpool.Pool, prID string, reviewers ...string) {
+	t.Helper()
+	for i, id := range reviewers {
+		_, err := pool.Exec(context.Background(), `
+			INSERT INTO pr_reviewers (repository, pr_id, user_id, position) VALUES ('', $1, $2, $3)
+		`, prID, id, i)
+		require.NoError(t, err)
+	}
+}
+
 func TestPRRepo_CreatePR(t *testing.T) {
 	pool := setupTestDB(t)
 	defer pool.Close()
@@ -37,9 +50,11 @@ func TestPRRepo_CreatePR(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ($1) ON CONFLICT DO NOTHING`, "team1")
 		require.NoError(t, err)
-		_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES ($1, $2, $3, $4)`,
-			"u1", "User1", "team1", true)
-		require.NoError(t, err)
+		for _, id := range []string{"u1", "u2"} {
+			_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES ($1, $2, $3, $4)`,
+				id, id, "team1", true)
+			require.NoError(t, err)
+		}
 
 		pr := &models.PullRequest{
 			ID:        "pr-1",
@@ -48,10 +63,10 @@ func TestPRRepo_CreatePR(t *testing.T) {
 			Status:    "OPEN",
 			Reviewers: []string{"u2"},
 		}
-		err = repo.CreatePR(ctx, pr)
+		err = repo.CreatePR(ctx, pr, models.AssignmentChange{})
 		require.NoError(t, err)
 
-		exists, err := repo.ExistsPR(ctx, "pr-1")
+		exists, err := repo.ExistsPR(ctx, "", "pr-1")
 		require.NoError(t, err)
 		assert.True(t, exists)
 	})
@@ -63,7 +78,7 @@ func TestPRRepo_CreatePR(t *testing.T) {
 			AuthorID: "u1",
 			Status:   "OPEN",
 		}
-		err := repo.CreatePR(ctx, pr)
+		err := repo.CreatePR(ctx, pr, models.AssignmentChange{})
 		assert.ErrorIs(t, err, apperrors.ErrPRExists)
 	})
 }
@@ -81,22 +96,27 @@ func TestPRRepo_GetPRByID(t *testing.T) {
 		_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES ($1, $2, $3, $4)`,
 			"u1", "User1", "team1", true)
 		require.NoError(t, err)
+		_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES ($1, $2, $3, $4)`,
+			"u2", "User2", "team1", true)
+		require.NoError(t, err)
 
 		now := time.Now()
-		_, err = pool.Exec(ctx, `INSERT INTO pull_requests (id, title, author_id, status, reviewers, created_at) 
-			VALUES ($1, $2, $3, $4, $5, $6)`,
-			"pr-2", "Test PR", "u1", "OPEN", []string{"u2"}, now)
+		_, err = pool.Exec(ctx, `INSERT INTO pull_requests (id, title, author_id, status, created_at) 
+			VALUES ($1, $2, $3, $4, $5)`,
+			"pr-2", "Test PR", "u1", "OPEN", now)
 		require.NoError(t, err)
+		assignReviewers(t, pool, "pr-2", "u2")
 
-		pr, err := repo.GetPRByID(ctx, "pr-2")
+		pr, err := repo.GetPRByID(ctx, "", "pr-2")
 		require.NoError(t, err)
 		assert.Equal(t, "pr-2", pr.ID)
 		assert.Equal(t, "Test PR", pr.Title)
 		assert.Equal(t, "OPEN", pr.Status)
+		assert.Equal(t, map[string]string{"u2": "team1"}, pr.ReviewerTeams)
 	})
 
 	t.Run("NotFound", func(t *testing.T) {
-		_, err := repo.GetPRByID(ctx, "pr-nonexist")
+		_, err := repo.GetPRByID(ctx, "", "pr-nonexist")
 		assert.ErrorIs(t, err, apperrors.ErrNotFound)
 	})
 }
@@ -111,25 +131,28 @@ func TestPRRepo_UpdatePR(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ($1) ON CONFLICT DO NOTHING`, "team1")
 		require.NoError(t, err)
-		_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES ($1, $2, $3, $4)`,
-			"u1", "User1", "team1", true)
-		require.NoError(t, err)
+		for _, id := range []string{"u1", "u2", "u3"} {
+			_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES ($1, $2, $3, $4)`,
+				id, id, "team1", true)
+			require.NoError(t, err)
+		}
 
 		now := time.Now()
-		_, err = pool.Exec(ctx, `INSERT INTO pull_requests (id, title, author_id, status, reviewers, created_at) 
-			VALUES ($1, $2, $3, $4, $5, $6)`,
-			"pr-3", "Test PR", "u1", "OPEN", []string{"u2"}, now)
+		_, err = pool.Exec(ctx, `INSERT INTO pull_requests (id, title, author_id, status, created_at) 
+			VALUES ($1, $2, $3, $4, $5)`,
+			"pr-3", "Test PR", "u1", "OPEN", now)
 		require.NoError(t, err)
+		assignReviewers(t, pool, "pr-3", "u2")
 
 		pr := &models.PullRequest{
 			ID:                "pr-3",
 			Reviewers:         []string{"u3"},
 			NeedMoreReviewers: false,
 		}
-		err = repo.UpdatePR(ctx, pr)
+		err = repo.UpdatePR(ctx, pr, models.AssignmentChange{})
 		require.NoError(t, err)
 
-		updated, err := repo.GetPRByID(ctx, "pr-3")
+		updated, err := repo.GetPRByID(ctx, "", "pr-3")
 		require.NoError(t, err)
 		assert.Equal(t, []string{"u3"}, updated.Reviewers)
 	})
@@ -143,16 +166,16 @@ func TestPRRepo_UpdatePR(t *testing.T) {
 		require.NoError(t, err)
 
 		now := time.Now()
-		_, err = pool.Exec(ctx, `INSERT INTO pull_requests (id, title, author_id, status, reviewers, created_at) 
-			VALUES ($1, $2, $3, $4, $5, $6)`,
-			"pr-4", "Test PR", "u1-merged", "MERGED", []string{"u2"}, now)
+		_, err = pool.Exec(ctx, `INSERT INTO pull_requests (id, title, author_id, status, created_at) 
+			VALUES ($1, $2, $3, $4, $5)`,
+			"pr-4", "Test PR", "u1-merged", "MERGED", now)
 		require.NoError(t, err)
 
 		pr := &models.PullRequest{
 			ID:        "pr-4",
 			Reviewers: []string{"u3"},
 		}
-		err = repo.UpdatePR(ctx, pr)
+		err = repo.UpdatePR(ctx, pr, models.AssignmentChange{})
 		assert.ErrorIs(t, err, apperrors.ErrPRMerged)
 	})
 }
@@ -173,15 +196,15 @@ func TestPRRepo_MergePR(t *testing.T) {
 		require.NoError(t, err)
 
 		now := time.Now()
-		_, err = pool.Exec(ctx, `INSERT INTO pull_requests (id, title, author_id, status, reviewers, created_at) 
-			VALUES ($1, $2, $3, $4, $5, $6)`,
-			"pr-5", "Test PR", "u1-merge-test", "OPEN", []string{"u2"}, now)
+		_, err = pool.Exec(ctx, `INSERT INTO pull_requests (id, title, author_id, status, created_at) 
+			VALUES ($1, $2, $3, $4, $5)`,
+			"pr-5", "Test PR", "u1-merge-test", "OPEN", now)
 		require.NoError(t, err)
 
-		err = repo.MergePR(ctx, "pr-5")
+		err = repo.MergePR(ctx, "", "pr-5")
 		require.NoError(t, err)
 
-		pr, err := repo.GetPRByID(ctx, "pr-5")
+		pr, err := repo.GetPRByID(ctx, "", "pr-5")
 		require.NoError(t, err)
 		assert.Equal(t, "MERGED", pr.Status)
 		assert.NotNil(t, pr.MergedAt)
@@ -213,7 +236,7 @@ func TestPRRepo_GetTotalPRs(t *testing.T) {
 			"pr-7", "PR2", "u1", "OPEN", now)
 		require.NoError(t, err)
 
-		total, err := repo.GetTotalPRs(ctx)
+		total, err := repo.GetTotalPRs(ctx, "")
 		require.NoError(t, err)
 		assert.GreaterOrEqual(t, total, 2)
 	})
@@ -244,10 +267,10 @@ func TestPRRepo_GetPrsByStatus(t *testing.T) {
 			"pr-9", "PR2", "u1", "MERGED", now, now)
 		require.NoError(t, err)
 
-		open, merged, err := repo.GetPrsByStatus(ctx)
+		status, err := repo.GetPrsByStatus(ctx, "")
 		require.NoError(t, err)
-		assert.GreaterOrEqual(t, open, 1)
-		assert.GreaterOrEqual(t, merged, 1)
+		assert.GreaterOrEqual(t, status.OpenPRs, 1)
+		assert.GreaterOrEqual(t, status.MergedPRs, 1)
 	})
 }
 
@@ -270,29 +293,32 @@ func TestPRRepo_Metrics(t *testing.T) {
 
 	now := time.Now()
 	_, err = pool.Exec(ctx,
-		`INSERT INTO pull_requests (id, title, author_id, status, reviewers, created_at, merged_at, need_more_reviewers)
+		`INSERT INTO pull_requests (id, title, author_id, status, created_at, merged_at, need_more_reviewers)
 		VALUES 
-			('pr-10', 'PR1', 'u1', 'MERGED', ARRAY['u2'], $1, $2, false),
-			('pr-11', 'PR2', 'u2', 'OPEN', ARRAY['u3'], $1, NULL, true),
-			('pr-12', 'PR3', 'u3', 'OPEN', ARRAY['u1'], $1, NULL, false)`,
+			('pr-10', 'PR1', 'u1', 'MERGED', $1, $2, false),
+			('pr-11', 'PR2', 'u2', 'OPEN', $1, NULL, true),
+			('pr-12', 'PR3', 'u3', 'OPEN', $1, NULL, false)`,
 		now.Add(-2*time.Hour), now.Add(-time.Hour))
 	require.NoError(t, err)
+	assignReviewers(t, pool, "pr-10", "u2")
+	assignReviewers(t, pool, "pr-11", "u3")
+	assignReviewers(t, pool, "pr-12", "u1")
 
 	t.Run("GetAvgCloseTime", func(t *testing.T) {
-		avg, count, avgErr := repo.GetAvgCloseTime(ctx)
+		avg, count, avgErr := repo.GetAvgCloseTime(ctx, "")
 		require.NoError(t, avgErr)
 		assert.Greater(t, avg, 0.0)
 		assert.Equal(t, 1, count)
 	})
 
 	t.Run("GetIdleUsersPerTeam", func(t *testing.T) {
-		metrics, idleErr := repo.GetIdleUsersPerTeam(ctx)
+		metrics, idleErr := repo.GetIdleUsersPerTeam(ctx, "")
 		require.NoError(t, idleErr)
 		assert.Len(t, metrics, 1)
 	})
 
 	t.Run("GetNeedyPRsPerTeam", func(t *testing.T) {
-		metrics, needyErr := repo.GetNeedyPRsPerTeam(ctx)
+		metrics, needyErr := repo.GetNeedyPRsPerTeam(ctx, "")
 		require.NoError(t, needyErr)
 		require.Len(t, metrics, 1)
 		assert.Equal(t, "team1", metrics[0].TeamName)
@@ -300,7 +326,7 @@ func TestPRRepo_Metrics(t *testing.T) {
 	})
 
 	t.Run("GetAssignmentsPerUser", func(t *testing.T) {
-		assignments, assignErr := repo.GetAssignmentsPerUser(ctx)
+		assignments, assignErr := repo.GetAssignmentsPerUser(ctx, "")
 		require.NoError(t, assignErr)
 		require.Len(t, assignments, 3)
 		for _, a := range assignments {
@@ -316,4 +342,455 @@ func TestPRRepo_Metrics(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("GetOpenReviewLoadByTeam", func(t *testing.T) {
+		load, loadErr := repo.GetOpenReviewLoadByTeam(ctx, "team1")
+		require.NoError(t, loadErr)
+		require.Len(t, load, 2)
+		assert.Equal(t, "u2", load[0].UserID)
+		assert.Equal(t, 0, load[0].Count)
+		assert.Equal(t, "u1", load[1].UserID)
+		assert.Equal(t, 1, load[1].Count)
+	})
+}
+
+func TestPRRepo_Repositories(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewPRRepo(pool)
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ($1) ON CONFLICT DO NOTHING`, "team1")
+	require.NoError(t, err)
+	for _, id := range []string{"u1", "u2"} {
+		_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES ($1, $2, $3, $4)`,
+			id, id, "team1", true)
+		require.NoError(t, err)
+	}
+
+	t.Run("SameIDInTwoRepositories", func(t *testing.T) {
+		for _, name := range []string{"mono", "svc-a"} {
+			require.NoError(t, repo.CreatePR(ctx, &models.PullRequest{
+				ID: "pr-1001", Title: name, AuthorID: "u1", Status: "OPEN", Reviewers: []string{"u2"}, Repository: name,
+			}, models.AssignmentChange{}))
+		}
+
+		err := repo.CreatePR(ctx, &models.PullRequest{
+			ID: "pr-1001", Title: "dup", AuthorID: "u1", Status: "OPEN", Repository: "mono",
+		}, models.AssignmentChange{})
+		assert.ErrorIs(t, err, apperrors.ErrPRExists)
+
+		pr, getErr := repo.GetPRByID(ctx, "svc-a", "pr-1001")
+		require.NoError(t, getErr)
+		assert.Equal(t, "svc-a", pr.Title)
+		assert.Equal(t, "svc-a", pr.Repository)
+	})
+
+	t.Run("MergeOnlyTouchesOneRepository", func(t *testing.T) {
+		require.NoError(t, repo.MergePR(ctx, "mono", "pr-1001"))
+
+		status, statusErr := repo.GetPrsByStatus(ctx, "")
+		require.NoError(t, statusErr)
+		assert.Equal(t, 1, status.OpenPRs)
+		assert.Equal(t, 1, status.MergedPRs)
+
+		status, statusErr = repo.GetPrsByStatus(ctx, "svc-a")
+		require.NoError(t, statusErr)
+		assert.Equal(t, 1, status.OpenPRs)
+		assert.Equal(t, 0, status.MergedPRs)
+	})
+
+	t.Run("FilterByRepository", func(t *testing.T) {
+		total, totalErr := repo.GetTotalPRs(ctx, "mono")
+		require.NoError(t, totalErr)
+		assert.Equal(t, 1, total)
+
+		prs, prsErr := repo.GetPRsForUser(ctx, "u2", "svc-a", false)
+		require.NoError(t, prsErr)
+		require.Len(t, prs, 1)
+		assert.Equal(t, "svc-a", prs[0].Repository)
+
+		prs, prsErr = repo.GetPRsForUser(ctx, "u2", "", false)
+		require.NoError(t, prsErr)
+		assert.Len(t, prs, 2)
+	})
+}
+
+func TestPRRepo_CloseAndReopen(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewPRRepo(pool)
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ($1) ON CONFLICT DO NOTHING`, "team1")
+	require.NoError(t, err)
+	for _, id := range []string{"u1", "u2", "u3"} {
+		_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES ($1, $2, $3, $4)`,
+			id, id, "team1", true)
+		require.NoError(t, err)
+	}
+	require.NoError(t, repo.CreatePR(ctx, &models.PullRequest{
+		ID: "pr-close", Title: "Close", AuthorID: "u1", Status: "OPEN", Reviewers: []string{"u2"},
+	}, models.AssignmentChange{}))
+
+	t.Run("Close", func(t *testing.T) {
+		require.NoError(t, repo.ClosePR(ctx, "", "pr-close"))
+
+		pr, getErr := repo.GetPRByID(ctx, "", "pr-close")
+		require.NoError(t, getErr)
+		assert.Equal(t, "CLOSED", pr.Status)
+		assert.NotNil(t, pr.ClosedAt)
+
+		prs, listErr := repo.GetPRsForUser(ctx, "u2", "", false)
+		require.NoError(t, listErr)
+		assert.Empty(t, prs)
+
+		status, statusErr := repo.GetPrsByStatus(ctx, "")
+		require.NoError(t, statusErr)
+		assert.Equal(t, 1, status.ClosedPRs)
+	})
+
+	t.Run("ClosedIsNotMergedOrUpdated", func(t *testing.T) {
+		require.NoError(t, repo.MergePR(ctx, "", "pr-close"))
+		pr, getErr := repo.GetPRByID(ctx, "", "pr-close")
+		require.NoError(t, getErr)
+		assert.Equal(t, "CLOSED", pr.Status)
+
+		updateErr := repo.UpdatePR(ctx,
+			&models.PullRequest{ID: "pr-close", Reviewers: []string{"u3"}}, models.AssignmentChange{})
+		assert.ErrorIs(t, updateErr, apperrors.ErrPRClosed)
+	})
+
+	t.Run("Reopen", func(t *testing.T) {
+		require.NoError(t, repo.ReopenPR(ctx,
+			&models.PullRequest{ID: "pr-close", Reviewers: []string{"u3"}}, models.AssignmentChange{}))
+
+		pr, getErr := repo.GetPRByID(ctx, "", "pr-close")
+		require.NoError(t, getErr)
+		assert.Equal(t, "OPEN", pr.Status)
+		assert.Nil(t, pr.ClosedAt)
+		assert.Equal(t, []string{"u3"}, pr.Reviewers)
+	})
+}
+
+func TestPRRepo_Drafts(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewPRRepo(pool)
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ($1) ON CONFLICT DO NOTHING`, "team1")
+	require.NoError(t, err)
+	for _, id := range []string{"u1", "u2"} {
+		_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES ($1, $2, $3, $4)`,
+			id, id, "team1", true)
+		require.NoError(t, err)
+	}
+	require.NoError(t, repo.CreatePR(ctx, &models.PullRequest{
+		ID: "pr-draft", Title: "WIP", AuthorID: "u1", Status: "OPEN", Reviewers: []string{"u2"},
+		NeedMoreReviewers: true, Draft: true,
+	}, models.AssignmentChange{}))
+
+	t.Run("ExcludedFromWorkload", func(t *testing.T) {
+		pr, getErr := repo.GetPRByID(ctx, "", "pr-draft")
+		require.NoError(t, getErr)
+		assert.True(t, pr.Draft)
+
+		load, loadErr := repo.GetOpenReviewLoadByTeam(ctx, "team1")
+		require.NoError(t, loadErr)
+		for _, ua := range load {
+			assert.Equal(t, 0, ua.Count, ua.UserID)
+		}
+
+		needy, needyErr := repo.GetNeedyPRsPerTeam(ctx, "")
+		require.NoError(t, needyErr)
+		assert.Empty(t, needy)
+	})
+
+	t.Run("MarkReady", func(t *testing.T) {
+		require.NoError(t, repo.MarkPRReady(ctx,
+			&models.PullRequest{ID: "pr-draft", Reviewers: []string{"u2"}}, models.AssignmentChange{}))
+
+		pr, getErr := repo.GetPRByID(ctx, "", "pr-draft")
+		require.NoError(t, getErr)
+		assert.False(t, pr.Draft)
+		assert.False(t, pr.NeedMoreReviewers)
+		assert.Equal(t, []string{"u2"}, pr.Reviewers)
+	})
+}
+
+func TestPRRepo_ReviewStates(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewPRRepo(pool)
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ($1) ON CONFLICT DO NOTHING`, "team1")
+	require.NoError(t, err)
+	for _, id := range []string{"u1", "u2", "u3", "u4"} {
+		_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES ($1, $2, $3, $4)`,
+			id, id, "team1", true)
+		require.NoError(t, err)
+	}
+	require.NoError(t, repo.CreatePR(ctx, &models.PullRequest{
+		ID: "pr-review", Title: "Review", AuthorID: "u1", Status: "OPEN", Reviewers: []string{"u2", "u3"},
+	}, models.AssignmentChange{}))
+
+	t.Run("DefaultsToPending", func(t *testing.T) {
+		pr, getErr := repo.GetPRByID(ctx, "", "pr-review")
+		require.NoError(t, getErr)
+		assert.Equal(t, map[string]string{"u2": "PENDING", "u3": "PENDING"}, pr.ReviewStates)
+	})
+
+	t.Run("SetAndFilterPending", func(t *testing.T) {
+		require.NoError(t, repo.SetReviewState(ctx, "", "pr-review", "u2", models.ReviewStateApproved))
+		require.NoError(t, repo.SetReviewState(ctx, "", "pr-review", "u3", models.ReviewStateChangesRequested))
+		require.NoError(t, repo.SetReviewState(ctx, "", "pr-review", "u3", models.ReviewStateApproved))
+
+		pr, getErr := repo.GetPRByID(ctx, "", "pr-review")
+		require.NoError(t, getErr)
+		assert.Equal(t, map[string]string{"u2": "APPROVED", "u3": "APPROVED"}, pr.ReviewStates)
+
+		all, listErr := repo.GetPRsForUser(ctx, "u2", "", false)
+		require.NoError(t, listErr)
+		require.Len(t, all, 1)
+		assert.Equal(t, "APPROVED", all[0].ReviewState)
+
+		pending, listErr := repo.GetPRsForUser(ctx, "u2", "", true)
+		require.NoError(t, listErr)
+		assert.Empty(t, pending)
+	})
+
+	t.Run("ReplacedReviewerStartsPending", func(t *testing.T) {
+		require.NoError(t, repo.UpdatePR(ctx,
+			&models.PullRequest{ID: "pr-review", Reviewers: []string{"u2", "u4"}}, models.AssignmentChange{}))
+		require.NoError(t, repo.UpdatePR(ctx,
+			&models.PullRequest{ID: "pr-review", Reviewers: []string{"u2", "u3"}}, models.AssignmentChange{}))
+
+		pr, getErr := repo.GetPRByID(ctx, "", "pr-review")
+		require.NoError(t, getErr)
+		assert.Equal(t, map[string]string{"u2": "APPROVED", "u3": "PENDING"}, pr.ReviewStates)
+	})
+
+	t.Run("UnassignedReviewer", func(t *testing.T) {
+		err := repo.SetReviewState(ctx, "", "pr-review", "u4", models.ReviewStateApproved)
+		assert.ErrorIs(t, err, apperrors.ErrNotAssigned)
+	})
+}
+
+func TestPRRepo_ReviewerAssignments(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewPRRepo(pool)
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ($1) ON CONFLICT DO NOTHING`, "team1")
+	require.NoError(t, err)
+	for _, id := range []string{"u1", "u2", "u3", "u4"} {
+		_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES ($1, $2, $3, $4)`,
+			id, id, "team1", true)
+		require.NoError(t, err)
+	}
+	require.NoError(t, repo.CreatePR(ctx, &models.PullRequest{
+		ID: "pr-assign", Title: "Assign", AuthorID: "u1", Status: "OPEN", Reviewers: []string{"u2", "u3"},
+	}, models.AssignmentChange{}))
+
+	countAssignments := func(t *testing.T, userID string) (int, int) {
+		t.Helper()
+		var active, removed int
+		err := pool.QueryRow(ctx, `
+			SELECT COUNT(*) FILTER (WHERE removed_at IS NULL), COUNT(*) FILTER (WHERE removed_at IS NOT NULL)
+			FROM pr_reviewers WHERE pr_id = $1 AND user_id = $2
+		`, "pr-assign", userID).Scan(&active, &removed)
+		require.NoError(t, err)
+		return active, removed
+	}
+
+	t.Run("ReplacementKeepsOrder", func(t *testing.T) {
+		require.NoError(t, repo.UpdatePR(ctx,
+			&models.PullRequest{ID: "pr-assign", Reviewers: []string{"u4", "u3"}}, models.AssignmentChange{}))
+
+		pr, getErr := repo.GetPRByID(ctx, "", "pr-assign")
+		require.NoError(t, getErr)
+		assert.Equal(t, []string{"u4", "u3"}, pr.Reviewers)
+
+		active, removed := countAssignments(t, "u2")
+		assert.Equal(t, 0, active)
+		assert.Equal(t, 1, removed)
+	})
+
+	t.Run("ReassignedReviewerGetsNewAssignment", func(t *testing.T) {
+		require.NoError(t, repo.UpdatePR(ctx,
+			&models.PullRequest{ID: "pr-assign", Reviewers: []string{"u2", "u3"}}, models.AssignmentChange{}))
+
+		active, removed := countAssignments(t, "u2")
+		assert.Equal(t, 1, active)
+		assert.Equal(t, 1, removed)
+
+		active, removed = countAssignments(t, "u3")
+		assert.Equal(t, 1, active)
+		assert.Equal(t, 0, removed)
+	})
+
+	t.Run("NoReviewers", func(t *testing.T) {
+		require.NoError(t, repo.UpdatePR(ctx,
+			&models.PullRequest{ID: "pr-assign", NeedMoreReviewers: true}, models.AssignmentChange{}))
+
+		pr, getErr := repo.GetPRByID(ctx, "", "pr-assign")
+		require.NoError(t, getErr)
+		assert.Empty(t, pr.Reviewers)
+		assert.True(t, pr.NeedMoreReviewers)
+	})
+}
+
+func TestPRRepo_AssignmentHistory(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewPRRepo(pool)
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ($1) ON CONFLICT DO NOTHING`, "team1")
+	require.NoError(t, err)
+	for _, id := range []string{"u1", "u2", "u3", "u4"} {
+		_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES ($1, $2, $3, $4)`,
+			id, id, "team1", true)
+		require.NoError(t, err)
+	}
+	require.NoError(t, repo.CreatePR(ctx, &models.PullRequest{
+		ID: "pr-hist", Title: "History", AuthorID: "u1", Status: "OPEN", Reviewers: []string{"u2", "u3"},
+	}, models.AssignmentChange{Actor: "alice", Reason: models.AssignReasonCreated}))
+	require.NoError(t, repo.UpdatePR(ctx,
+		&models.PullRequest{ID: "pr-hist", Reviewers: []string{"u4", "u3"}},
+		models.AssignmentChange{Actor: "bob", Reason: models.AssignReasonReassigned}))
+
+	t.Run("Timeline", func(t *testing.T) {
+		history, histErr := repo.GetAssignmentHistory(ctx, "", "pr-hist")
+		require.NoError(t, histErr)
+
+		type event struct{ reviewer, action, reason, actor string }
+		got := make([]event, 0, len(history))
+		for _, e := range history {
+			got = append(got, event{e.ReviewerID, e.Action, e.Reason, e.Actor})
+			assert.False(t, e.At.IsZero())
+		}
+		assert.Equal(t, []event{
+			{"u2", models.AssignmentAssigned, models.AssignReasonCreated, "alice"},
+			{"u3", models.AssignmentAssigned, models.AssignReasonCreated, "alice"},
+			{"u2", models.AssignmentRemoved, models.AssignReasonReassigned, "bob"},
+			{"u4", models.AssignmentAssigned, models.AssignReasonReassigned, "bob"},
+		}, got)
+	})
+
+	t.Run("UnknownPR", func(t *testing.T) {
+		history, histErr := repo.GetAssignmentHistory(ctx, "", "pr-none")
+		require.NoError(t, histErr)
+		assert.NotNil(t, history)
+		assert.Empty(t, history)
+	})
+}
+
+func TestPRRepo_ForceMergePR(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewPRRepo(pool)
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ($1) ON CONFLICT DO NOTHING`, "team1")
+	require.NoError(t, err)
+	for _, id := range []string{"u1", "u2", "u3"} {
+		_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES ($1, $2, $3, $4)`,
+			id, id, "team1", true)
+		require.NoError(t, err)
+	}
+	require.NoError(t, repo.CreatePR(ctx, &models.PullRequest{
+		ID: "pr-force", Title: "Force", AuthorID: "u1", Status: "OPEN", Reviewers: []string{"u2", "u3"},
+	}, models.AssignmentChange{}))
+
+	override := &models.MergeOverride{
+		PRID:               "pr-force",
+		Reason:             "hotfix",
+		RequiredApprovals:  2,
+		Approvals:          1,
+		PendingReviewers:   []string{"u3"},
+		ChangesRequestedBy: []string{},
+	}
+	require.NoError(t, repo.ForceMergePR(ctx, override))
+	// A repeated override of a merged PR is a no-op and is not recorded again.
+	require.NoError(t, repo.ForceMergePR(ctx, override))
+
+	pr, err := repo.GetPRByID(ctx, "", "pr-force")
+	require.NoError(t, err)
+	assert.Equal(t, "MERGED", pr.Status)
+	assert.NotNil(t, pr.MergedAt)
+
+	var (
+		count   int
+		reason  string
+		pending []string
+	)
+	err = pool.QueryRow(ctx, `
+		SELECT COUNT(*) OVER (), reason, pending_reviewers FROM merge_overrides WHERE pr_id = $1
+	`, "pr-force").Scan(&count, &reason, &pending)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "hotfix", reason)
+	assert.Equal(t, []string{"u3"}, pending)
+}
+
+func TestPRRepo_ClaimStaleReviews(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewPRRepo(pool)
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ($1) ON CONFLICT DO NOTHING`, "team1")
+	require.NoError(t, err)
+	for _, id := range []string{"u1", "u2", "u3"} {
+		_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES ($1, $2, $3, $4)`,
+			id, id, "team1", true)
+		require.NoError(t, err)
+	}
+	for _, pr := range []*models.PullRequest{
+		{ID: "pr-stale", Title: "Stale", AuthorID: "u1", Status: "OPEN", Reviewers: []string{"u2", "u3"}},
+		{ID: "pr-draft", Title: "Draft", AuthorID: "u1", Status: "OPEN", Reviewers: []string{"u2"}, Draft: true},
+		{ID: "pr-merged", Title: "Merged", AuthorID: "u1", Status: "OPEN", Reviewers: []string{"u2"}},
+	} {
+		require.NoError(t, repo.CreatePR(ctx, pr, models.AssignmentChange{}))
+	}
+	require.NoError(t, repo.MergePR(ctx, "", "pr-merged"))
+	require.NoError(t, repo.SetReviewState(ctx, "", "pr-stale", "u3", models.ReviewStateApproved))
+	_, err = pool.Exec(ctx, `UPDATE pr_reviewers SET assigned_at = CURRENT_TIMESTAMP - INTERVAL '2 days'`)
+	require.NoError(t, err)
+
+	t.Run("Claim", func(t *testing.T) {
+		reviews, claimErr := repo.ClaimStaleReviews(ctx, 24*time.Hour, 10)
+		require.NoError(t, claimErr)
+		require.Len(t, reviews, 1, "only pending reviews of open, ready PRs are stale")
+		assert.Equal(t, "pr-stale", reviews[0].PullRequest.ID)
+		assert.Equal(t, "Stale", reviews[0].PullRequest.Title)
+		assert.Equal(t, "u1", reviews[0].PullRequest.AuthorID)
+		assert.Equal(t, "u2", reviews[0].ReviewerID)
+		assert.WithinDuration(t, time.Now().Add(-48*time.Hour), reviews[0].AssignedAt, time.Hour)
+	})
+
+	t.Run("RemindedOncePerPeriod", func(t *testing.T) {
+		reviews, claimErr := repo.ClaimStaleReviews(ctx, 24*time.Hour, 10)
+		require.NoError(t, claimErr)
+		assert.Empty(t, reviews)
+
+		_, err = pool.Exec(ctx, `UPDATE pr_reviewers SET reminded_at = CURRENT_TIMESTAMP - INTERVAL '25 hours'
+			WHERE reminded_at IS NOT NULL`)
+		require.NoError(t, err)
+		reviews, claimErr = repo.ClaimStaleReviews(ctx, 24*time.Hour, 10)
+		require.NoError(t, claimErr)
+		assert.Len(t, reviews, 1)
+	})
 }
@@ -0,0 +1,78 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoryRepo(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewRepositoryRepo(pool)
+	ctx := context.Background()
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := repo.GetRepository(ctx, "svc")
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	t.Run("SetAndReplace", func(t *testing.T) {
+		require.NoError(t, repo.SetCodeOwners(ctx, "svc", "* @dev\n", models.CodeOwnersPreferred))
+		require.NoError(t, repo.SetCodeOwners(ctx, "svc", "*.go @org/backend\n", models.CodeOwnersRequired))
+
+		stored, err := repo.GetRepository(ctx, "svc")
+		require.NoError(t, err)
+		assert.Equal(t, "*.go @org/backend\n", stored.CodeOwners)
+		assert.Equal(t, models.CodeOwnersRequired, stored.CodeOwnersMode)
+	})
+}
+
+func TestRepositoryRepo_Settings(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewRepositoryRepo(pool)
+	prRepo := repository.NewPRRepo(pool)
+	ctx := context.Background()
+
+	for _, name := range []string{"team1", "platform"} {
+		_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ($1) ON CONFLICT DO NOTHING`, name)
+		require.NoError(t, err)
+	}
+	_, err := pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES
+		('u1', 'u1', 'team1', true),
+		('u2', 'u2', 'team1', true)`)
+	require.NoError(t, err)
+	require.NoError(t, prRepo.CreatePR(ctx, &models.PullRequest{
+		ID: "pr-1", Title: "PR", AuthorID: "u1", Status: "OPEN", Reviewers: []string{"u2"}, Repository: "mono",
+	}, models.AssignmentChange{}))
+
+	t.Run("UnknownTeam", func(t *testing.T) {
+		ghost := "ghost"
+		err := repo.UpdateRepositorySettings(ctx, "mono", &models.RepositorySettings{DefaultTeamName: &ghost}, 1)
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	t.Run("StoreAndRefreshNeedMore", func(t *testing.T) {
+		teamName, count, approvals := "platform", 2, 1
+		settings := &models.RepositorySettings{
+			DefaultTeamName: &teamName, ReviewersCount: &count, RequiredApprovals: &approvals,
+		}
+		require.NoError(t, repo.UpdateRepositorySettings(ctx, "mono", settings, 1))
+
+		stored, getErr := repo.GetRepository(ctx, "mono")
+		require.NoError(t, getErr)
+		assert.Equal(t, settings, stored.Settings)
+
+		pr, prErr := prRepo.GetPRByID(ctx, "mono", "pr-1")
+		require.NoError(t, prErr)
+		assert.True(t, pr.NeedMoreReviewers)
+	})
+}
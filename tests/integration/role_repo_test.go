@@ -0,0 +1,76 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleRepo(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewRoleRepo(pool)
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ('team1')`)
+	require.NoError(t, err)
+
+	t.Run("Create", func(t *testing.T) {
+		global := &models.RoleBinding{UserID: "alice", Role: models.RoleAdmin}
+		require.NoError(t, repo.CreateRoleBinding(ctx, global))
+		assert.NotZero(t, global.ID)
+
+		scoped := &models.RoleBinding{UserID: "bob", Role: models.RoleTeamLead, TeamName: "team1"}
+		require.NoError(t, repo.CreateRoleBinding(ctx, scoped))
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		err := repo.CreateRoleBinding(ctx, &models.RoleBinding{UserID: "alice", Role: models.RoleAdmin})
+		assert.ErrorIs(t, err, apperrors.ErrRoleExists)
+
+		err = repo.CreateRoleBinding(ctx, &models.RoleBinding{
+			UserID: "bob", Role: models.RoleTeamLead, TeamName: "team1",
+		})
+		assert.ErrorIs(t, err, apperrors.ErrRoleExists)
+
+		// The same role in another scope is a separate binding.
+		require.NoError(t, repo.CreateRoleBinding(ctx, &models.RoleBinding{UserID: "bob", Role: models.RoleTeamLead}))
+	})
+
+	t.Run("List", func(t *testing.T) {
+		all, listErr := repo.ListRoleBindings(ctx, "")
+		require.NoError(t, listErr)
+		assert.Len(t, all, 3)
+
+		bob, listErr := repo.ListRoleBindings(ctx, "bob")
+		require.NoError(t, listErr)
+		require.Len(t, bob, 2)
+		assert.Equal(t, "team1", bob[0].TeamName)
+		assert.Empty(t, bob[1].TeamName)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, repo.DeleteRoleBinding(ctx, "bob", models.RoleTeamLead, ""))
+		assert.ErrorIs(t, repo.DeleteRoleBinding(ctx, "bob", models.RoleTeamLead, ""), apperrors.ErrNotFound)
+
+		bob, listErr := repo.ListRoleBindings(ctx, "bob")
+		require.NoError(t, listErr)
+		require.Len(t, bob, 1)
+		assert.Equal(t, "team1", bob[0].TeamName)
+	})
+
+	t.Run("TeamDeletionDropsScopedBindings", func(t *testing.T) {
+		_, execErr := pool.Exec(ctx, `DELETE FROM teams WHERE name = 'team1'`)
+		require.NoError(t, execErr)
+
+		bob, listErr := repo.ListRoleBindings(ctx, "bob")
+		require.NoError(t, listErr)
+		assert.Empty(t, bob)
+	})
+}
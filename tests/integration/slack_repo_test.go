@@ -0,0 +1,61 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlackRepo(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewSlackRepo(pool)
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ('team1')`)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES
+		('u1', 'u1', 'team1', true),
+		('u2', 'u2', 'team1', true)`)
+	require.NoError(t, err)
+
+	t.Run("TeamChannel", func(t *testing.T) {
+		_, getErr := repo.GetTeamChannel(ctx, "team1")
+		require.ErrorIs(t, getErr, apperrors.ErrNotFound)
+
+		require.NoError(t, repo.SetTeamChannel(ctx, "team1", "/services/T1/B1/a"))
+		require.NoError(t, repo.SetTeamChannel(ctx, "team1", "/services/T1/B1/b"))
+		path, getErr := repo.GetTeamChannel(ctx, "team1")
+		require.NoError(t, getErr)
+		assert.Equal(t, "/services/T1/B1/b", path)
+
+		require.NoError(t, repo.DeleteTeamChannel(ctx, "team1"))
+		require.NoError(t, repo.DeleteTeamChannel(ctx, "team1"))
+		_, getErr = repo.GetTeamChannel(ctx, "team1")
+		assert.ErrorIs(t, getErr, apperrors.ErrNotFound)
+
+		assert.Error(t, repo.SetTeamChannel(ctx, "nope", "/services/x"), "the team must exist")
+	})
+
+	t.Run("UserHandles", func(t *testing.T) {
+		require.NoError(t, repo.SetUserHandle(ctx, "u1", "U0USER1"))
+		require.NoError(t, repo.SetUserHandle(ctx, "u2", "U0OLD"))
+		require.NoError(t, repo.SetUserHandle(ctx, "u2", "U0USER2"))
+
+		handles, getErr := repo.GetUserHandles(ctx, []string{"u1", "u2", "u3"})
+		require.NoError(t, getErr)
+		assert.Equal(t, map[string]string{"u1": "U0USER1", "u2": "U0USER2"}, handles)
+
+		require.NoError(t, repo.DeleteUserHandle(ctx, "u1"))
+		handles, getErr = repo.GetUserHandles(ctx, []string{"u1", "u2"})
+		require.NoError(t, getErr)
+		assert.Equal(t, map[string]string{"u2": "U0USER2"}, handles)
+
+		assert.Error(t, repo.SetUserHandle(ctx, "ghost", "U0GHOST"), "the user must exist")
+	})
+}
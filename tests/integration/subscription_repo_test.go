@@ -0,0 +1,149 @@
+package integration_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionRepo(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewSubscriptionRepo(pool)
+	ctx := context.Background()
+
+	merged := &models.WebhookSubscription{
+		URL:        "https://hooks.example.com/merged",
+		Secret:     "s3cret",
+		EventTypes: []string{models.EventPRMerged},
+	}
+	all := &models.WebhookSubscription{
+		URL:        "https://hooks.example.com/all",
+		Secret:     "other",
+		EventTypes: []string{models.EventPRCreated, models.EventPRMerged},
+	}
+
+	t.Run("CreateAndList", func(t *testing.T) {
+		require.NoError(t, repo.CreateSubscription(ctx, merged))
+		require.NoError(t, repo.CreateSubscription(ctx, all))
+		assert.NotZero(t, merged.ID)
+		assert.False(t, merged.CreatedAt.IsZero())
+
+		subs, err := repo.ListSubscriptions(ctx, "")
+		require.NoError(t, err)
+		require.Len(t, subs, 2)
+		assert.Empty(t, subs[0].Secret, "secrets are not listed")
+
+		subs, err = repo.ListSubscriptions(ctx, models.EventPRCreated)
+		require.NoError(t, err)
+		require.Len(t, subs, 1)
+		assert.Equal(t, all.ID, subs[0].ID)
+
+		got, err := repo.GetSubscription(ctx, merged.ID)
+		require.NoError(t, err)
+		assert.Equal(t, merged.URL, got.URL)
+		assert.Equal(t, []string{models.EventPRMerged}, got.EventTypes)
+
+		_, err = repo.GetSubscription(ctx, all.ID+1)
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	delivery := &models.WebhookDelivery{
+		EventID:   "ev-1",
+		EventType: models.EventPRMerged,
+		Payload:   json.RawMessage(`{"type": "pr.merged"}`),
+	}
+
+	t.Run("DeliveryLifecycle", func(t *testing.T) {
+		delivery.SubscriptionID = merged.ID
+		require.NoError(t, repo.CreateDelivery(ctx, delivery))
+		assert.Equal(t, models.DeliveryPending, delivery.Status)
+
+		due, err := repo.ClaimDueDeliveries(ctx, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, due, 1, "the delivery is due right away")
+		due, err = repo.ClaimDueDeliveries(ctx, 10, time.Hour)
+		require.NoError(t, err)
+		require.Len(t, due, 1, "an expired lease makes the delivery due again")
+		again, err := repo.ClaimDueDeliveries(ctx, 10, time.Hour)
+		require.NoError(t, err)
+		assert.Empty(t, again, "a leased delivery is not claimed twice")
+		assert.Equal(t, merged.URL, due[0].URL)
+		assert.Equal(t, "s3cret", due[0].Secret)
+		assert.JSONEq(t, `{"type": "pr.merged"}`, string(due[0].Payload))
+
+		failed := due[0]
+		failed.Attempts = 1
+		require.NoError(t, repo.RecordAttempt(ctx, &failed, &models.DeliveryAttempt{
+			Attempt: 1, StatusCode: 500, DurationMS: 3,
+		}, time.Minute))
+		require.NotNil(t, failed.NextAttemptAt)
+
+		due, err = repo.ClaimDueDeliveries(ctx, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, due, "the retry is not due yet")
+
+		failed.Attempts = 2
+		failed.Status = models.DeliveryDelivered
+		require.NoError(t, repo.RecordAttempt(ctx, &failed, &models.DeliveryAttempt{
+			Attempt: 2, StatusCode: 204, DurationMS: 5,
+		}, 0))
+		assert.Nil(t, failed.NextAttemptAt)
+		assert.NotNil(t, failed.DeliveredAt)
+	})
+
+	t.Run("DuplicateDelivery", func(t *testing.T) {
+		again := &models.WebhookDelivery{
+			SubscriptionID: merged.ID,
+			EventID:        delivery.EventID,
+			EventType:      models.EventPRMerged,
+			Payload:        json.RawMessage(`{}`),
+		}
+		require.NoError(t, repo.CreateDelivery(ctx, again))
+		assert.Zero(t, again.ID, "the event is already queued for the subscription")
+	})
+
+	t.Run("ListDeliveries", func(t *testing.T) {
+		second := &models.WebhookDelivery{
+			SubscriptionID: merged.ID,
+			EventID:        "ev-2",
+			EventType:      models.EventPRMerged,
+			Payload:        json.RawMessage(`{}`),
+		}
+		require.NoError(t, repo.CreateDelivery(ctx, second))
+
+		deliveries, err := repo.ListDeliveries(ctx, merged.ID, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+		assert.Equal(t, second.ID, deliveries[0].ID, "newest first")
+		assert.Empty(t, deliveries[0].AttemptLog)
+
+		first := deliveries[1]
+		assert.Equal(t, models.DeliveryDelivered, first.Status)
+		assert.Equal(t, 2, first.Attempts)
+		require.Len(t, first.AttemptLog, 2)
+		assert.Equal(t, 500, first.AttemptLog[0].StatusCode)
+		assert.Equal(t, 204, first.AttemptLog[1].StatusCode)
+
+		deliveries, err = repo.ListDeliveries(ctx, merged.ID, 1)
+		require.NoError(t, err)
+		assert.Len(t, deliveries, 1)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, repo.DeleteSubscription(ctx, merged.ID))
+		assert.ErrorIs(t, repo.DeleteSubscription(ctx, merged.ID), apperrors.ErrNotFound)
+
+		deliveries, err := repo.ListDeliveries(ctx, merged.ID, 10)
+		require.NoError(t, err)
+		assert.Empty(t, deliveries, "deliveries are deleted with their subscription")
+	})
+}
@@ -2,6 +2,7 @@ package integration_test
 
 import (
 	"context"
+	"strconv"
 	"testing"
 
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
@@ -73,3 +74,117 @@ func TestTeamRepo_GetTeamByName(t *testing.T) {
 		assert.ErrorIs(t, err, apperrors.ErrNotFound)
 	})
 }
+
+func TestTeamRepo_UpdateTeamSettings(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewTeamRepo(pool)
+	prRepo := repository.NewPRRepo(pool)
+	ctx := context.Background()
+
+	team := &models.Team{
+		Name: "team3",
+		Members: []models.TeamMember{
+			{UserID: "u5", Username: "User5", IsActive: true},
+			{UserID: "u6", Username: "User6", IsActive: true},
+		},
+	}
+	require.NoError(t, repo.CreateTeam(ctx, team))
+	require.NoError(t, prRepo.CreatePR(ctx, &models.PullRequest{
+		ID: "pr-settings", Title: "Settings", AuthorID: "u5", Status: "OPEN", Reviewers: []string{"u6"},
+	}, models.AssignmentChange{}))
+
+	t.Run("Success", func(t *testing.T) {
+		three, one := 3, 1
+		err := repo.UpdateTeamSettings(
+			ctx, "team3", &models.TeamSettings{ReviewersCount: &three, RequiredApprovals: &one}, 2)
+		require.NoError(t, err)
+
+		settings, err := repo.GetTeamSettings(ctx, "team3")
+		require.NoError(t, err)
+		require.NotNil(t, settings.ReviewersCount)
+		assert.Equal(t, 3, *settings.ReviewersCount)
+		require.NotNil(t, settings.RequiredApprovals)
+		assert.Equal(t, 1, *settings.RequiredApprovals)
+
+		retrieved, err := repo.GetTeamByName(ctx, "team3")
+		require.NoError(t, err)
+		require.NotNil(t, retrieved.Settings)
+		assert.Equal(t, 3, *retrieved.Settings.ReviewersCount)
+
+		pr, err := prRepo.GetPRByID(ctx, "", "pr-settings")
+		require.NoError(t, err)
+		assert.True(t, pr.NeedMoreReviewers)
+	})
+
+	t.Run("ResetToDefault", func(t *testing.T) {
+		err := repo.UpdateTeamSettings(ctx, "team3", &models.TeamSettings{}, 1)
+		require.NoError(t, err)
+
+		settings, err := repo.GetTeamSettings(ctx, "team3")
+		require.NoError(t, err)
+		assert.Nil(t, settings.ReviewersCount)
+		assert.Nil(t, settings.RequiredApprovals)
+
+		pr, err := prRepo.GetPRByID(ctx, "", "pr-settings")
+		require.NoError(t, err)
+		assert.False(t, pr.NeedMoreReviewers)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		err := repo.UpdateTeamSettings(ctx, "team-nonexist", &models.TeamSettings{}, 2)
+		require.ErrorIs(t, err, apperrors.ErrNotFound)
+
+		_, err = repo.GetTeamSettings(ctx, "team-nonexist")
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+}
+
+func TestTeamRepo_FallbackTeams(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewTeamRepo(pool)
+	ctx := context.Background()
+
+	for i, name := range []string{"backend", "platform", "frontend"} {
+		require.NoError(t, repo.CreateTeam(ctx, &models.Team{
+			Name:    name,
+			Members: []models.TeamMember{{UserID: "fb" + strconv.Itoa(i), Username: name, IsActive: true}},
+		}))
+	}
+
+	t.Run("SetAndGet", func(t *testing.T) {
+		require.NoError(t, repo.SetFallbackTeams(ctx, "backend", []string{"platform", "frontend"}))
+
+		fallbacks, err := repo.GetFallbackTeams(ctx, "backend")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"platform", "frontend"}, fallbacks)
+
+		team, err := repo.GetTeamByName(ctx, "backend")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"platform", "frontend"}, team.FallbackTeams)
+	})
+
+	t.Run("ReplaceOrder", func(t *testing.T) {
+		require.NoError(t, repo.SetFallbackTeams(ctx, "backend", []string{"frontend"}))
+
+		fallbacks, err := repo.GetFallbackTeams(ctx, "backend")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"frontend"}, fallbacks)
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		require.NoError(t, repo.SetFallbackTeams(ctx, "backend", nil))
+
+		fallbacks, err := repo.GetFallbackTeams(ctx, "backend")
+		require.NoError(t, err)
+		assert.Empty(t, fallbacks)
+	})
+
+	t.Run("UnknownTeam", func(t *testing.T) {
+		assert.ErrorIs(t, repo.SetFallbackTeams(ctx, "backend", []string{"ghost"}), apperrors.ErrNotFound)
+		assert.ErrorIs(t, repo.SetFallbackTeams(ctx, "ghost", []string{"backend"}), apperrors.ErrNotFound)
+	})
+}
@@ -0,0 +1,60 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenRepo(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewTokenRepo(pool)
+	ctx := context.Background()
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		token := &models.APIToken{Name: "ci", UserID: "ci-bot"}
+		require.NoError(t, repo.CreateToken(ctx, token, "hash-1"))
+		assert.NotZero(t, token.ID)
+		assert.False(t, token.CreatedAt.IsZero())
+
+		got, err := repo.GetTokenByHash(ctx, "hash-1")
+		require.NoError(t, err)
+		assert.Equal(t, "ci", got.Name)
+		assert.Equal(t, "ci-bot", got.UserID)
+
+		_, err = repo.GetTokenByHash(ctx, "hash-unknown")
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	t.Run("DuplicateName", func(t *testing.T) {
+		err := repo.CreateToken(ctx, &models.APIToken{Name: "ci", UserID: "alice"}, "hash-2")
+		assert.ErrorIs(t, err, apperrors.ErrTokenExists)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		require.NoError(t, repo.RevokeToken(ctx, "ci"))
+		assert.ErrorIs(t, repo.RevokeToken(ctx, "ci"), apperrors.ErrNotFound)
+
+		_, err := repo.GetTokenByHash(ctx, "hash-1")
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+
+		// The name is free again once revoked.
+		require.NoError(t, repo.CreateToken(ctx, &models.APIToken{Name: "ci", UserID: "alice"}, "hash-3"))
+	})
+
+	t.Run("List", func(t *testing.T) {
+		tokens, err := repo.ListTokens(ctx)
+		require.NoError(t, err)
+		require.Len(t, tokens, 2)
+		assert.NotNil(t, tokens[0].RevokedAt)
+		assert.Nil(t, tokens[1].RevokedAt)
+		assert.Equal(t, "alice", tokens[1].UserID)
+	})
+}
@@ -3,6 +3,7 @@ package integration_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
@@ -117,6 +118,127 @@ func TestUserRepo_UpdateUserActive(t *testing.T) {
 	})
 }
 
+func TestUserRepo_UpdateUserMaxOpenReviews(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewUserRepo(pool)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ($1) ON CONFLICT DO NOTHING`, "team1")
+		require.NoError(t, err)
+		_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES ($1, $2, $3, $4)`,
+			"u3", "User3", "team1", true)
+		require.NoError(t, err)
+
+		limit := 3
+		err = repo.UpdateUserMaxOpenReviews(ctx, "u3", &limit)
+		require.NoError(t, err)
+
+		user, err := repo.GetUserByID(ctx, "u3")
+		require.NoError(t, err)
+		require.NotNil(t, user.MaxOpenReviews)
+		assert.Equal(t, 3, *user.MaxOpenReviews)
+
+		active, err := repo.GetActiveUsersByTeam(ctx, "team1")
+		require.NoError(t, err)
+		require.Len(t, active, 1)
+		require.NotNil(t, active[0].MaxOpenReviews)
+		assert.Equal(t, 3, *active[0].MaxOpenReviews)
+
+		err = repo.UpdateUserMaxOpenReviews(ctx, "u3", nil)
+		require.NoError(t, err)
+
+		user, err = repo.GetUserByID(ctx, "u3")
+		require.NoError(t, err)
+		assert.Nil(t, user.MaxOpenReviews)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		err := repo.UpdateUserMaxOpenReviews(ctx, "u-nonexist", nil)
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+}
+
+func TestUserRepo_UpdateUserEmail(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewUserRepo(pool)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ($1) ON CONFLICT DO NOTHING`, "team1")
+		require.NoError(t, err)
+		_, err = pool.Exec(ctx, `INSERT INTO users (id, name, team_name, is_active) VALUES ($1, $2, $3, $4)`,
+			"u20", "User20", "team1", true)
+		require.NoError(t, err)
+
+		err = repo.UpdateUserEmail(ctx, "u20", "u20@example.com", true)
+		require.NoError(t, err)
+
+		user, err := repo.GetUserByID(ctx, "u20")
+		require.NoError(t, err)
+		assert.Equal(t, "u20@example.com", user.Email)
+		assert.True(t, user.EmailDigest)
+
+		err = repo.UpdateUserEmail(ctx, "u20", "", false)
+		require.NoError(t, err)
+
+		user, err = repo.GetUserByID(ctx, "u20")
+		require.NoError(t, err)
+		assert.Empty(t, user.Email)
+		assert.False(t, user.EmailDigest)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		err := repo.UpdateUserEmail(ctx, "u-nonexist", "x@example.com", false)
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+}
+
+func TestUserRepo_ClaimDigestRecipients(t *testing.T) {
+	pool := setupTestDB(t)
+	defer pool.Close()
+
+	repo := repository.NewUserRepo(pool)
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, `INSERT INTO teams (name) VALUES ($1) ON CONFLICT DO NOTHING`, "team-digest")
+	require.NoError(t, err)
+	for _, u := range []struct {
+		id, email string
+		digest    bool
+		active    bool
+	}{
+		{"d1", "d1@example.com", true, true},
+		{"d2", "d2@example.com", false, true},
+		{"d3", "", false, true},
+		{"d4", "d4@example.com", true, false},
+	} {
+		_, err = pool.Exec(ctx, `
+			INSERT INTO users (id, name, team_name, is_active, email, email_digest) VALUES ($1, $1, $2, $3, $4, $5)
+		`, u.id, "team-digest", u.active, u.email, u.digest)
+		require.NoError(t, err)
+	}
+
+	slot := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	users, err := repo.ClaimDigestRecipients(ctx, slot, 100)
+	require.NoError(t, err)
+	require.Len(t, users, 1, "only active users who opted in with an email")
+	assert.Equal(t, "d1", users[0].ID)
+	assert.Equal(t, "d1@example.com", users[0].Email)
+
+	users, err = repo.ClaimDigestRecipients(ctx, slot, 100)
+	require.NoError(t, err)
+	assert.Empty(t, users, "a user is claimed once per slot")
+
+	users, err = repo.ClaimDigestRecipients(ctx, slot.AddDate(0, 0, 1), 100)
+	require.NoError(t, err)
+	assert.Len(t, users, 1, "and again on the next day")
+}
+
 func TestUserRepo_GetActiveUsersByTeam(t *testing.T) {
 	pool := setupTestDB(t)
 	defer pool.Close()
@@ -0,0 +1,97 @@
+// Package testutil holds the in-memory fakes and helpers shared by the unit tests and benchmarks.
+package testutil
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+)
+
+// InlineTx runs fn without a transaction.
+type InlineTx struct{}
+
+func (InlineTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (InlineTx) WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// SavepointTx runs fn without a transaction and, when a savepoint fails, drops the audit events recorded
+// in it, as rolling back to the savepoint would.
+type SavepointTx struct {
+	InlineTx
+	Recorder   *AuditRecorder
+	RolledBack int
+}
+
+func (tx *SavepointTx) WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	n := len(tx.Recorder.Events)
+	if err := fn(ctx); err != nil {
+		tx.Recorder.Events = tx.Recorder.Events[:n]
+		tx.RolledBack++
+		return err
+	}
+	return nil
+}
+
+// AuditRecorder keeps created audit events in memory and serves them back. Err fails every call.
+type AuditRecorder struct {
+	Events []models.AuditEvent
+	Err    error
+	Filter models.AuditFilter
+}
+
+func (r *AuditRecorder) CreateAuditEvent(_ context.Context, event *models.AuditEvent) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	r.Events = append(r.Events, *event)
+	return nil
+}
+
+func (r *AuditRecorder) ListAuditEvents(
+	_ context.Context,
+	filter models.AuditFilter,
+) ([]models.AuditEvent, int, error) {
+	r.Filter = filter
+	if r.Err != nil {
+		return nil, 0, r.Err
+	}
+	return r.Events, len(r.Events), nil
+}
+
+// Actions lists the actions of the recorded events in order.
+func (r *AuditRecorder) Actions() []string {
+	actions := make([]string, 0, len(r.Events))
+	for _, e := range r.Events {
+		actions = append(actions, e.Action)
+	}
+	return actions
+}
+
+// discardAudit drops audit events.
+type discardAudit struct{}
+
+func (discardAudit) CreateAuditEvent(context.Context, *models.AuditEvent) error {
+	return nil
+}
+
+func (discardAudit) ListAuditEvents(context.Context, models.AuditFilter) ([]models.AuditEvent, int, error) {
+	return nil, 0, nil
+}
+
+// NewTestAudit returns an audit service that runs changes without a transaction and drops their events.
+func NewTestAudit() *services.AuditService {
+	return services.NewAuditService(discardAudit{}, InlineTx{}, slog.New(slog.DiscardHandler))
+}
+
+// NewRecordingAudit returns an audit service that runs changes without a transaction and keeps their events
+// in the returned recorder.
+func NewRecordingAudit() (*services.AuditService, *AuditRecorder) {
+	recorder := &AuditRecorder{}
+	return services.NewAuditService(recorder, InlineTx{}, slog.New(slog.DiscardHandler)), recorder
+}
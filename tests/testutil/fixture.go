@@ -0,0 +1,19 @@
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Fixture returns the content of tests/testdata/<host>/<name>.
+func Fixture(t *testing.T, host, name string) []byte {
+	t.Helper()
+	_, file, _, _ := runtime.Caller(0)
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(file), "..", "testdata", host, name))
+	require.NoError(t, err)
+	return data
+}
@@ -0,0 +1,288 @@
+package testutil
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+// TokenStore keeps active API tokens in memory by hash. Err fails every call but RevokeToken.
+type TokenStore struct {
+	ByHash map[string]models.APIToken
+	Err    error
+}
+
+func NewTokenStore() *TokenStore {
+	return &TokenStore{ByHash: map[string]models.APIToken{}}
+}
+
+func (s *TokenStore) CreateToken(_ context.Context, token *models.APIToken, hash string) error {
+	if s.Err != nil {
+		return s.Err
+	}
+	for _, t := range s.ByHash {
+		if t.Name == token.Name {
+			return apperrors.ErrTokenExists
+		}
+	}
+	token.ID = int64(len(s.ByHash) + 1)
+	s.ByHash[hash] = *token
+	return nil
+}
+
+func (s *TokenStore) GetTokenByHash(_ context.Context, hash string) (*models.APIToken, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	t, ok := s.ByHash[hash]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	return &t, nil
+}
+
+func (s *TokenStore) ListTokens(_ context.Context) ([]models.APIToken, error) {
+	tokens := []models.APIToken{}
+	for _, t := range s.ByHash {
+		tokens = append(tokens, t)
+	}
+	return tokens, s.Err
+}
+
+func (s *TokenStore) RevokeToken(_ context.Context, name string) error {
+	for hash, t := range s.ByHash {
+		if t.Name == name {
+			delete(s.ByHash, hash)
+			return nil
+		}
+	}
+	return apperrors.ErrNotFound
+}
+
+// RoleStore keeps role bindings in memory. Err fails ListRoleBindings.
+type RoleStore struct {
+	Bindings []models.RoleBinding
+	Err      error
+}
+
+func (s *RoleStore) CreateRoleBinding(_ context.Context, binding *models.RoleBinding) error {
+	for _, b := range s.Bindings {
+		if b.UserID == binding.UserID && b.Role == binding.Role && b.TeamName == binding.TeamName {
+			return apperrors.ErrRoleExists
+		}
+	}
+	binding.ID = int64(len(s.Bindings) + 1)
+	s.Bindings = append(s.Bindings, *binding)
+	return nil
+}
+
+func (s *RoleStore) DeleteRoleBinding(_ context.Context, userID, role, teamName string) error {
+	for i, b := range s.Bindings {
+		if b.UserID == userID && b.Role == role && b.TeamName == teamName {
+			s.Bindings = append(s.Bindings[:i], s.Bindings[i+1:]...)
+			return nil
+		}
+	}
+	return apperrors.ErrNotFound
+}
+
+func (s *RoleStore) ListRoleBindings(_ context.Context, userID string) ([]models.RoleBinding, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	bindings := []models.RoleBinding{}
+	for _, b := range s.Bindings {
+		if userID == "" || b.UserID == userID {
+			bindings = append(bindings, b)
+		}
+	}
+	return bindings, nil
+}
+
+// SlackStore keeps Slack team channels and user handles in memory.
+type SlackStore struct {
+	Channels map[string]string
+	Handles  map[string]string
+}
+
+func NewSlackStore() *SlackStore {
+	return &SlackStore{Channels: map[string]string{}, Handles: map[string]string{}}
+}
+
+func (s *SlackStore) SetTeamChannel(_ context.Context, teamName, webhookPath string) error {
+	s.Channels[teamName] = webhookPath
+	return nil
+}
+
+func (s *SlackStore) DeleteTeamChannel(_ context.Context, teamName string) error {
+	delete(s.Channels, teamName)
+	return nil
+}
+
+func (s *SlackStore) GetTeamChannel(_ context.Context, teamName string) (string, error) {
+	path, ok := s.Channels[teamName]
+	if !ok {
+		return "", apperrors.ErrNotFound
+	}
+	return path, nil
+}
+
+func (s *SlackStore) SetUserHandle(_ context.Context, userID, handle string) error {
+	s.Handles[userID] = handle
+	return nil
+}
+
+func (s *SlackStore) DeleteUserHandle(_ context.Context, userID string) error {
+	delete(s.Handles, userID)
+	return nil
+}
+
+func (s *SlackStore) GetUserHandles(_ context.Context, userIDs []string) (map[string]string, error) {
+	handles := map[string]string{}
+	for _, id := range userIDs {
+		if handle, ok := s.Handles[id]; ok {
+			handles[id] = handle
+		}
+	}
+	return handles, nil
+}
+
+// SubscriptionStore keeps webhook subscriptions and their deliveries in memory. Retries lists the delays
+// of the deliveries rescheduled after a failed attempt.
+type SubscriptionStore struct {
+	Subs       []models.WebhookSubscription
+	Deliveries []*models.WebhookDelivery
+	Retries    []time.Duration
+}
+
+func (s *SubscriptionStore) CreateSubscription(_ context.Context, sub *models.WebhookSubscription) error {
+	sub.ID = int64(len(s.Subs) + 1)
+	sub.CreatedAt = time.Now()
+	s.Subs = append(s.Subs, *sub)
+	return nil
+}
+
+func (s *SubscriptionStore) DeleteSubscription(_ context.Context, id int64) error {
+	for i := range s.Subs {
+		if s.Subs[i].ID == id {
+			s.Subs = append(s.Subs[:i], s.Subs[i+1:]...)
+			return nil
+		}
+	}
+	return apperrors.ErrNotFound
+}
+
+func (s *SubscriptionStore) GetSubscription(_ context.Context, id int64) (*models.WebhookSubscription, error) {
+	for _, sub := range s.Subs {
+		if sub.ID == id {
+			return &sub, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (s *SubscriptionStore) ListSubscriptions(
+	_ context.Context,
+	eventType string,
+) ([]models.WebhookSubscription, error) {
+	subs := []models.WebhookSubscription{}
+	for _, sub := range s.Subs {
+		for _, t := range sub.EventTypes {
+			if eventType == "" || t == eventType {
+				subs = append(subs, sub)
+				break
+			}
+		}
+	}
+	return subs, nil
+}
+
+func (s *SubscriptionStore) CreateDelivery(_ context.Context, delivery *models.WebhookDelivery) error {
+	now := time.Now()
+	delivery.ID = int64(len(s.Deliveries) + 1)
+	delivery.Status = models.DeliveryPending
+	delivery.NextAttemptAt = &now
+	s.Deliveries = append(s.Deliveries, delivery)
+	return nil
+}
+
+func (s *SubscriptionStore) ClaimDueDeliveries(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]models.WebhookDelivery, error) {
+	due := []models.WebhookDelivery{}
+	for _, d := range s.Deliveries {
+		if d.Status != models.DeliveryPending || d.NextAttemptAt.After(time.Now()) || len(due) == limit {
+			continue
+		}
+		sub, err := s.GetSubscription(ctx, d.SubscriptionID)
+		if err != nil {
+			continue
+		}
+		leasedUntil := time.Now().Add(lease)
+		d.NextAttemptAt = &leasedUntil
+		delivery := *d
+		delivery.URL = sub.URL
+		delivery.Secret = sub.Secret
+		due = append(due, delivery)
+	}
+	return due, nil
+}
+
+func (s *SubscriptionStore) RecordAttempt(
+	_ context.Context,
+	delivery *models.WebhookDelivery,
+	attempt *models.DeliveryAttempt,
+	retryAfter time.Duration,
+) error {
+	stored := s.Deliveries[delivery.ID-1]
+	stored.Status = delivery.Status
+	stored.Attempts = delivery.Attempts
+	stored.AttemptLog = append(stored.AttemptLog, *attempt)
+	next := time.Now().Add(retryAfter)
+	stored.NextAttemptAt = &next
+	if delivery.Status == models.DeliveryPending {
+		s.Retries = append(s.Retries, retryAfter)
+	}
+	return nil
+}
+
+func (s *SubscriptionStore) ListDeliveries(
+	_ context.Context,
+	subscriptionID int64,
+	limit int,
+) ([]models.WebhookDelivery, error) {
+	deliveries := []models.WebhookDelivery{}
+	for i := len(s.Deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if s.Deliveries[i].SubscriptionID == subscriptionID {
+			deliveries = append(deliveries, *s.Deliveries[i])
+		}
+	}
+	return deliveries, nil
+}
+
+// RetryNow makes every pending delivery due.
+func (s *SubscriptionStore) RetryNow() {
+	now := time.Now()
+	for _, d := range s.Deliveries {
+		d.NextAttemptAt = &now
+	}
+}
+
+// Events decodes the payloads of the queued deliveries.
+func (s *SubscriptionStore) Events(t *testing.T) []models.Event {
+	t.Helper()
+	events := make([]models.Event, 0, len(s.Deliveries))
+	for _, d := range s.Deliveries {
+		var event models.Event
+		require.NoError(t, json.Unmarshal(d.Payload, &event))
+		events = append(events, event)
+	}
+	return events
+}
@@ -0,0 +1,75 @@
+package codeowners_test
+
+import (
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/codeowners"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sample = `# Default owners
+*                   @org/backend
+
+*.js                @frontend
+/docs/              @docs-team u-writer
+docs/*              @org/docs
+apps/               @mobile
+/build/logs/        @u-ops
+**/migrations       @dba
+/scripts/**/deploy  @u-ops
+/vendor/
+file\#1.txt         @hash
+`
+
+func TestParse_Owners(t *testing.T) {
+	f, err := codeowners.Parse(sample)
+	require.NoError(t, err)
+	require.Len(t, f.Rules, 10)
+
+	cases := []struct {
+		path   string
+		owners []string
+	}{
+		{"main.go", []string{"@org/backend"}},
+		{"web/app.js", []string{"@frontend"}},
+		{"docs/index.md", []string{"@org/docs"}},
+		{"docs/guides/setup.md", []string{"@docs-team", "u-writer"}},
+		{"src/docs/index.md", []string{"@org/backend"}},
+		{"apps/ios/main.swift", []string{"@mobile"}},
+		{"services/apps/x.go", []string{"@mobile"}},
+		{"build/logs/out.log", []string{"@u-ops"}},
+		{"src/build/logs/out.log", []string{"@org/backend"}},
+		{"db/migrations/001.sql", []string{"@dba"}},
+		{"migrations/001.sql", []string{"@dba"}},
+		{"scripts/deploy", []string{"@u-ops"}},
+		{"scripts/a/b/deploy", []string{"@u-ops"}},
+		{"/main.go", []string{"@org/backend"}},
+		{"file#1.txt", []string{"@hash"}},
+		{"vendor/lib.go", []string{}},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.owners, f.Owners(tc.path), tc.path)
+	}
+}
+
+func TestParse_OwnerSets(t *testing.T) {
+	f, err := codeowners.Parse(sample)
+	require.NoError(t, err)
+
+	sets := f.OwnerSets([]string{"a.js", "main.go", "b.js", "vendor/x.go", "cmd/main.go"})
+	assert.Equal(t, [][]string{{"@frontend"}, {"@org/backend"}}, sets)
+}
+
+func TestParse_NoMatch(t *testing.T) {
+	f, err := codeowners.Parse("/docs/ @docs\n")
+	require.NoError(t, err)
+	assert.Nil(t, f.Owners("main.go"))
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, content := range []string{"!secret.txt @a", "*.[ch] @c"} {
+		_, err := codeowners.Parse(content)
+		assert.ErrorIs(t, err, codeowners.ErrInvalidFile, content)
+	}
+}
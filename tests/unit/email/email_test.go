@@ -0,0 +1,153 @@
+package email_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/email"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/email/emailtest"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMailer(server *emailtest.Server) *email.Mailer {
+	return email.NewMailer(email.Config{
+		Addr: server.Addr, Username: "bot", Password: "secret", From: "bot@example.com", Timeout: 5 * time.Second,
+	})
+}
+
+func TestMailer_Send(t *testing.T) {
+	server := emailtest.NewServer()
+	defer server.Close()
+
+	err := newMailer(server).Send(context.Background(), email.Message{
+		To:      []string{"alice@example.com"},
+		Subject: "Ревью: Fix\nlogin",
+		Body:    "Hi Alice,\n\nplease review «Fix login» = now.\n",
+	})
+	require.NoError(t, err)
+
+	messages := server.Messages()
+	require.Len(t, messages, 1)
+	msg := messages[0]
+	assert.Equal(t, "bot@example.com", msg.From)
+	assert.Equal(t, []string{"alice@example.com"}, msg.To)
+	assert.Equal(t, "Ревью: Fix login", msg.Subject, "the subject is encoded on a single line")
+	assert.Equal(t, "Hi Alice,\n\nplease review «Fix login» = now.\n", msg.Body)
+	assert.Equal(t, "alice@example.com", msg.Header.Get("To"))
+	assert.Equal(t, "text/plain; charset=utf-8", msg.Header.Get("Content-Type"))
+}
+
+func TestMailer_SendRejected(t *testing.T) {
+	server := emailtest.NewServer()
+	defer server.Close()
+	server.Reject("gone@example.com", 550)
+	server.Reject("busy@example.com", 451)
+	mailer := newMailer(server)
+
+	err := mailer.Send(context.Background(), email.Message{To: []string{"gone@example.com"}, Subject: "s", Body: "b"})
+	require.Error(t, err)
+	assert.True(t, email.Permanent(err))
+
+	err = mailer.Send(context.Background(), email.Message{To: []string{"busy@example.com"}, Subject: "s", Body: "b"})
+	require.Error(t, err)
+	assert.False(t, email.Permanent(err))
+
+	assert.Empty(t, server.Messages())
+}
+
+func TestMailer_SendUnreachable(t *testing.T) {
+	server := emailtest.NewServer()
+	server.Close()
+
+	err := newMailer(server).Send(context.Background(), email.Message{To: []string{"a@example.com"}})
+	require.Error(t, err)
+	assert.False(t, email.Permanent(err))
+}
+
+func TestTemplates_Defaults(t *testing.T) {
+	templates, err := email.LoadTemplates("")
+	require.NoError(t, err)
+
+	assignedAt := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+	pr := models.PullRequest{ID: "42", Repository: "acme/api", Title: "Fix login", AuthorID: "u1"}
+	recipient := models.User{ID: "u2", Name: "Bob"}
+
+	subject, body, err := templates.Render(email.TemplateReviewerAssigned, email.EventData{
+		Recipient: recipient, PullRequest: pr,
+		Event: models.Event{Type: models.EventReviewerAssigned, Data: models.EventData{ReviewerID: "u2"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Review requested: Fix login", subject)
+	assert.Equal(t, "Hi Bob,\n\nyou were assigned to review \"Fix login\" (acme/api#42) by u1.\n", body)
+
+	_, body, err = templates.Render(email.TemplateReviewerReassigned, email.EventData{
+		Recipient: recipient, PullRequest: pr,
+		Event: models.Event{Data: models.EventData{ReviewerID: "u2", OldReviewerID: "u3"}},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, body, "by u1, taking over from u3.")
+
+	subject, body, err = templates.Render(email.TemplateReviewStale, email.EventData{
+		Recipient: recipient, PullRequest: pr,
+		Event: models.Event{Data: models.EventData{ReviewerID: "u2", AssignedAt: &assignedAt}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Reminder: Fix login is waiting for your review", subject)
+	assert.Contains(t, body, "is still waiting for your review since 2026-10-15 09:30 UTC.")
+
+	subject, body, err = templates.Render(email.TemplateDigest, email.DigestData{
+		Recipient: recipient,
+		Date:      assignedAt,
+		PullRequests: []models.PullRequestShort{
+			{ID: "42", Repository: "acme/api", Title: "Fix login", AuthorID: "u1"},
+			{ID: "pr-7", Title: "Add cache", AuthorID: "u3"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2 pull requests waiting for your review", subject)
+	assert.Equal(t, "Hi Bob,\n\nthese pull requests are waiting for your review:\n\n"+
+		"- \"Fix login\" (acme/api#42) by u1\n"+
+		"- \"Add cache\" (pr-7) by u3\n", body)
+}
+
+func TestTemplates_Override(t *testing.T) {
+	dir := t.TempDir()
+	override := `{{define "subject"}}[PR] {{.PullRequest.Title}}{{end}}{{define "body"}}{{.Recipient.ID}}{{end}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "reviewer_assigned.tmpl"), []byte(override), 0o600))
+
+	templates, err := email.LoadTemplates(dir)
+	require.NoError(t, err)
+
+	subject, body, err := templates.Render(email.TemplateReviewerAssigned, email.EventData{
+		Recipient: models.User{ID: "u2"}, PullRequest: models.PullRequest{Title: "Fix login"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "[PR] Fix login", subject)
+	assert.Equal(t, "u2", body)
+
+	subject, _, err = templates.Render(email.TemplateReviewStale, email.EventData{
+		PullRequest: models.PullRequest{Title: "Fix login"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Reminder: Fix login is waiting for your review", subject, "other templates keep the default")
+}
+
+func TestTemplates_InvalidOverride(t *testing.T) {
+	for name, src := range map[string]string{
+		"Syntax":    `{{define "subject"}}{{.Title{{end}}`,
+		"NoBody":    `{{define "subject"}}x{{end}}`,
+		"NoSubject": `{{define "body"}}x{{end}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "digest.tmpl"), []byte(src), 0o600))
+			_, err := email.LoadTemplates(dir)
+			assert.Error(t, err)
+		})
+	}
+}
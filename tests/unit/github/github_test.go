@@ -0,0 +1,76 @@
+package github_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/github"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("It's a Secret to Everybody")
+	body := []byte("Hello, World!")
+
+	t.Run("Valid", func(t *testing.T) {
+		// Example from the GitHub webhook documentation.
+		header := "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+		assert.NoError(t, github.VerifySignature(secret, body, header))
+	})
+
+	for name, tc := range map[string]struct {
+		secret []byte
+		header string
+	}{
+		"WrongSecret": {[]byte("other"), "sha256=" + hex.EncodeToString(github.Sign(secret, body))},
+		"SHA1":        {secret, "sha1=01dc10d0c83e72ed246219cdd91669667fe2ca59"},
+		"NotHex":      {secret, "sha256=zz"},
+		"Missing":     {secret, ""},
+		"NoSecret":    {nil, "sha256=" + hex.EncodeToString(github.Sign(nil, body))},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, github.VerifySignature(tc.secret, body, tc.header), github.ErrInvalidSignature)
+		})
+	}
+}
+
+func TestParsePullRequestEvent(t *testing.T) {
+	t.Run("Opened", func(t *testing.T) {
+		event, err := github.ParsePullRequestEvent(testutil.Fixture(t, "github", "pull_request_opened.json"))
+		require.NoError(t, err)
+		assert.Equal(t, github.ActionOpened, event.Action)
+		assert.Equal(t, "42", event.ID())
+		assert.Equal(t, "acme/api", event.Repository.FullName)
+		assert.Equal(t, "Add rate limiting to the public API", event.PullRequest.Title)
+		assert.Equal(t, "Octocat", event.PullRequest.User.Login)
+		assert.False(t, event.PullRequest.Draft)
+	})
+
+	t.Run("Merged", func(t *testing.T) {
+		event, err := github.ParsePullRequestEvent(testutil.Fixture(t, "github", "pull_request_closed_merged.json"))
+		require.NoError(t, err)
+		assert.Equal(t, github.ActionClosed, event.Action)
+		assert.True(t, event.PullRequest.Merged)
+		assert.Equal(t, "hubot", event.Sender.Login)
+	})
+
+	t.Run("ClosedWithoutMerge", func(t *testing.T) {
+		event, err := github.ParsePullRequestEvent(testutil.Fixture(t, "github", "pull_request_closed.json"))
+		require.NoError(t, err)
+		assert.Equal(t, github.ActionClosed, event.Action)
+		assert.False(t, event.PullRequest.Merged)
+	})
+
+	for name, body := range map[string][]byte{
+		"NotJSON":  []byte("<xml/>"),
+		"Ping":     testutil.Fixture(t, "github", "ping.json"),
+		"NoNumber": []byte(`{"action": "opened", "pull_request": {}, "repository": {"full_name": "acme/api"}}`),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := github.ParsePullRequestEvent(body)
+			assert.ErrorIs(t, err, github.ErrInvalidPayload)
+		})
+	}
+}
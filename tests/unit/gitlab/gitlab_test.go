@@ -0,0 +1,105 @@
+package gitlab_test
+
+import (
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/gitlab"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyToken(t *testing.T) {
+	secret := []byte("gitlab-token")
+
+	assert.NoError(t, gitlab.VerifyToken(secret, "gitlab-token"))
+
+	for name, tc := range map[string]struct {
+		secret []byte
+		header string
+	}{
+		"Wrong":    {secret, "gitlab-tokem"},
+		"Prefix":   {secret, "gitlab"},
+		"Missing":  {secret, ""},
+		"NoSecret": {nil, ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, gitlab.VerifyToken(tc.secret, tc.header), gitlab.ErrInvalidToken)
+		})
+	}
+}
+
+func TestParseMergeRequestEvent(t *testing.T) {
+	t.Run("Open", func(t *testing.T) {
+		event, err := gitlab.ParseMergeRequestEvent(testutil.Fixture(t, "gitlab", "merge_request_open.json"))
+		require.NoError(t, err)
+		assert.Equal(t, gitlab.ActionOpen, event.ObjectAttributes.Action)
+		assert.Equal(t, "17", event.ID())
+		assert.Equal(t, "acme/web", event.Project.PathWithNamespace)
+		assert.Equal(t, "Jane.Doe", event.User.Username)
+		author, ok := event.Author()
+		assert.True(t, ok)
+		assert.Equal(t, "Jane.Doe", author)
+		assert.Equal(t, "Migrate checkout to the new payments API", event.Title())
+		assert.True(t, event.Draft())
+		assert.False(t, event.MarkedReady())
+	})
+
+	t.Run("AuthorIsNotTheUser", func(t *testing.T) {
+		event, err := gitlab.ParseMergeRequestEvent(testutil.Fixture(t, "gitlab", "merge_request_open_by_bot.json"))
+		require.NoError(t, err)
+		assert.Equal(t, "release-bot", event.User.Username)
+		author, ok := event.Author()
+		assert.True(t, ok)
+		assert.Equal(t, "Jane.Doe", author, "the author is found among the assignees")
+
+		event.Assignees = nil
+		_, ok = event.Author()
+		assert.False(t, ok)
+	})
+
+	t.Run("MarkedReady", func(t *testing.T) {
+		event, err := gitlab.ParseMergeRequestEvent(testutil.Fixture(t, "gitlab", "merge_request_update_ready.json"))
+		require.NoError(t, err)
+		assert.Equal(t, gitlab.ActionUpdate, event.ObjectAttributes.Action)
+		assert.False(t, event.Draft())
+		assert.True(t, event.MarkedReady())
+	})
+
+	t.Run("DraftPrefixes", func(t *testing.T) {
+		for title, want := range map[string]string{
+			"Draft: Fix login":  "Fix login",
+			"[Draft] Fix login": "Fix login",
+			"(draft) Fix login": "Fix login",
+			"Drafting a plan":   "Drafting a plan",
+		} {
+			event := gitlab.MergeRequestEvent{ObjectAttributes: gitlab.MergeRequest{Title: title}}
+			assert.Equal(t, want, event.Title(), title)
+		}
+	})
+
+	t.Run("Pushed", func(t *testing.T) {
+		event, err := gitlab.ParseMergeRequestEvent(testutil.Fixture(t, "gitlab", "merge_request_update_push.json"))
+		require.NoError(t, err)
+		assert.False(t, event.MarkedReady())
+	})
+
+	t.Run("Merged", func(t *testing.T) {
+		event, err := gitlab.ParseMergeRequestEvent(testutil.Fixture(t, "gitlab", "merge_request_merge.json"))
+		require.NoError(t, err)
+		assert.Equal(t, gitlab.ActionMerge, event.ObjectAttributes.Action)
+		assert.Equal(t, "release-bot", event.User.Username)
+	})
+
+	for name, body := range map[string][]byte{
+		"NotJSON":   []byte("<xml/>"),
+		"Push":      []byte(`{"object_kind": "push", "project": {"path_with_namespace": "acme/web"}}`),
+		"NoIID":     []byte(`{"object_kind": "merge_request", "project": {"path_with_namespace": "acme/web"}}`),
+		"NoProject": []byte(`{"object_kind": "merge_request", "object_attributes": {"iid": 17}}`),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := gitlab.ParseMergeRequestEvent(body)
+			assert.ErrorIs(t, err, gitlab.ErrInvalidPayload)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package handlers_test
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/handlers"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/reqctx"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditHandler_ListEvents(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("Success", func(t *testing.T) {
+		recorder := &testutil.AuditRecorder{Events: []models.AuditEvent{{
+			ID:         7,
+			Actor:      "alice",
+			OnBehalfOf: "bob",
+			Action:     models.AuditTeamCreated,
+			EntityType: models.AuditEntityTeam,
+			EntityID:   "team1",
+			After:      []byte(`{"team_name":"team1"}`),
+			RequestID:  "req-1",
+			CreatedAt:  time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		}}}
+		handler := handlers.NewAuditHandler(services.NewAuditService(recorder, testutil.InlineTx{}, log), log)
+
+		router := setupRouter()
+		router.GET("/audit", handler.ListEvents)
+
+		req := httptest.NewRequest(http.MethodGet,
+			"/audit?actor=alice&entity_type=team&from=2026-10-01T00:00:00Z&to=2026-10-02T00:00:00Z&limit=10&offset=5",
+			nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"events": [{
+				"id": 7,
+				"actor": "alice",
+				"on_behalf_of": "bob",
+				"action": "TEAM_CREATED",
+				"entity_type": "team",
+				"entity_id": "team1",
+				"before": null,
+				"after": {"team_name": "team1"},
+				"request_id": "req-1",
+				"created_at": "2026-10-01T12:00:00Z"
+			}],
+			"total": 1,
+			"limit": 10,
+			"offset": 5
+		}`, w.Body.String())
+
+		assert.Equal(t, "alice", recorder.Filter.Actor)
+		assert.Equal(t, models.AuditEntityTeam, recorder.Filter.EntityType)
+		require.NotNil(t, recorder.Filter.From)
+		require.NotNil(t, recorder.Filter.To)
+		assert.Equal(t, time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), recorder.Filter.To.UTC())
+	})
+
+	for name, query := range map[string]string{
+		"BadLimit":      "limit=abc",
+		"LimitTooLarge": "limit=1000",
+		"BadOffset":     "offset=-1",
+		"BadFrom":       "from=yesterday",
+	} {
+		t.Run(name, func(t *testing.T) {
+			handler := handlers.NewAuditHandler(testutil.NewTestAudit(), log)
+
+			router := setupRouter()
+			router.GET("/audit", handler.ListEvents)
+
+			req := httptest.NewRequest(http.MethodGet, "/audit?"+query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "INVALID_INPUT")
+		})
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen string
+	router := setupRouter()
+	router.Use(handlers.RequestIDMiddleware)
+	router.GET("/ping", func(c *gin.Context) {
+		seen = reqctx.RequestID(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	t.Run("FromHeader", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set(handlers.RequestIDHeader, "req-42")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, "req-42", seen)
+		assert.Equal(t, "req-42", w.Header().Get(handlers.RequestIDHeader))
+	})
+
+	t.Run("Generated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Len(t, seen, 32)
+		assert.Equal(t, seen, w.Header().Get(handlers.RequestIDHeader))
+	})
+}
@@ -0,0 +1,304 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/handlers"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/jwt"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/reqctx"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+	authSvc := services.NewAuthService(testutil.NewTokenStore(), log)
+	tokens := map[string]string{}
+	for _, userID := range []string{"root", "lead", "ci", "nobody"} {
+		raw, _, err := authSvc.CreateToken(context.Background(), userID+"-token", userID)
+		require.NoError(t, err)
+		tokens[userID] = "Bearer " + raw
+	}
+
+	roles := &testutil.RoleStore{Bindings: []models.RoleBinding{
+		{UserID: "root", Role: models.RoleAdmin},
+		{UserID: "lead", Role: models.RoleTeamLead, TeamName: "team1"},
+		{UserID: "ci", Role: models.RoleBot},
+	}}
+	userRepo := &mockUserRepoForHandler{}
+	userRepo.On("GetTeamNameByUserID", mock.Anything, "u1").Return("team1", nil)
+	userRepo.On("GetTeamNameByUserID", mock.Anything, "u9").Return("team2", nil)
+	userRepo.On("GetTeamNameByUserID", mock.Anything, "new").Return("", apperrors.ErrNotFound)
+	prRepo := &mockPRRepoForHandler{}
+	prRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(&models.PullRequest{ID: "pr-1", AuthorID: "u1"}, nil)
+	accessSvc := services.NewAccessService(roles, userRepo, &mockTeamRepoForHandler{}, prRepo, nil, log)
+	auth := handlers.NewAuthMiddleware(authSvc, accessSvc, nil, log)
+
+	var actor, onBehalfOf, body string
+	router := setupRouter()
+	api := router.Group("/", handlers.ActorMiddleware, auth.Authenticate)
+	api.GET("/read", auth.Require(models.PermRead), func(c *gin.Context) {
+		actor = reqctx.Actor(c.Request.Context())
+		onBehalfOf = reqctx.OnBehalfOf(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+	manage := func(c *gin.Context) {
+		data, _ := io.ReadAll(c.Request.Body)
+		body = string(data)
+		c.Status(http.StatusOK)
+	}
+	api.POST("/team/set-settings", auth.Require(models.PermManageTeam), manage)
+	api.POST("/team/add-member", auth.Require(models.PermManageTeam), manage)
+	api.POST("/users/setIsActive", auth.Require(models.PermManageTeam), manage)
+	api.POST("/users/deactivateByTeam", auth.Require(models.PermManageTeam), manage)
+	api.POST("/pullRequest/merge", auth.Require(models.PermMergePR), auth.RequireForced(models.PermForceMerge),
+		manage)
+	api.POST("/users/importAbsences", auth.Require(models.PermManageTeam), func(c *gin.Context) {
+		_, err := c.FormFile("file")
+		assert.NoError(t, err)
+		c.Status(http.StatusOK)
+	})
+	api.POST("/team/add", auth.Require(models.PermCreateTeam), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	api.GET("/audit", auth.Require(models.PermReadAudit), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	send := func(method, path, authorization, payload string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(payload))
+		req.Header.Set("Content-Type", "application/json")
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for name, authorization := range map[string]string{
+		"Missing":    "",
+		"WrongToken": "Bearer nope",
+		"NotBearer":  "Basic nope",
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := send(http.MethodGet, "/read", authorization, "")
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Contains(t, w.Body.String(), handlers.ErrorCodeUnauthorized)
+			assert.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+		})
+	}
+
+	t.Run("BotReads", func(t *testing.T) {
+		w := send(http.MethodGet, "/read", tokens["ci"], "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "ci", actor)
+	})
+
+	t.Run("NoRolesForbidden", func(t *testing.T) {
+		w := send(http.MethodGet, "/read", tokens["nobody"], "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), handlers.ErrorCodeForbidden)
+	})
+
+	t.Run("BotCannotManage", func(t *testing.T) {
+		w := send(http.MethodPost, "/team/set-settings", tokens["ci"], `{"team_name": "team1"}`)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("TeamLeadOwnTeam", func(t *testing.T) {
+		payload := `{"team_name": "team1"}`
+		w := send(http.MethodPost, "/team/set-settings", tokens["lead"], payload)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, payload, body, "body must reach the handler")
+	})
+
+	t.Run("TeamLeadOtherTeam", func(t *testing.T) {
+		w := send(http.MethodPost, "/team/set-settings", tokens["lead"], `{"team_name": "team2"}`)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("TeamLeadUserOfTeam", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send(http.MethodPost, "/users/setIsActive", tokens["lead"], `{"user_id": "u1"}`).Code)
+		w := send(http.MethodPost, "/users/setIsActive", tokens["lead"], `{"user_id": "u9"}`)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("TeamLeadCannotSmuggleOtherTeam", func(t *testing.T) {
+		// The handler binds only team_name, so a user_id of the lead's own team must not grant access.
+		w := send(http.MethodPost, "/users/deactivateByTeam", tokens["lead"], `{"team_name": "team2", "user_id": "u1"}`)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = send(http.MethodPost, "/team/add-member", tokens["lead"],
+			`{"team_name": "team2", "member": {"user_id": "u1"}}`)
+		assert.Equal(t, http.StatusForbidden, w.Code, "fields pointing at different teams")
+
+		w = send(http.MethodPost, "/team/add-member", tokens["lead"],
+			`{"team_name": "team1", "member": {"user_id": "u9"}}`)
+		assert.Equal(t, http.StatusForbidden, w.Code, "fields pointing at different teams")
+
+		w = send(http.MethodPost, "/team/add-member", tokens["lead"],
+			`{"team_name": "team1", "member": {"user_id": "new"}}`)
+		assert.Equal(t, http.StatusOK, w.Code, "a new member belongs to no team yet")
+	})
+
+	t.Run("TeamLeadMultipartForm", func(t *testing.T) {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		require.NoError(t, mw.WriteField("team_name", "team1"))
+		part, err := mw.CreateFormFile("file", "team.ics")
+		require.NoError(t, err)
+		_, _ = part.Write([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"))
+		require.NoError(t, mw.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/users/importAbsences", &buf)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		req.Header.Set("Authorization", tokens["lead"])
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("OnlyAdminForceMerges", func(t *testing.T) {
+		w := send(http.MethodPost, "/pullRequest/merge", tokens["lead"], `{"pull_request_id": "pr-1"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		payload := `{"pull_request_id": "pr-1", "force": true, "reason": "hotfix"}`
+		w = send(http.MethodPost, "/pullRequest/merge", tokens["lead"], payload)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = send(http.MethodPost, "/pullRequest/merge", tokens["root"], payload)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, payload, body, "body must reach the handler")
+	})
+
+	t.Run("OnlyAdminCreatesTeams", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, send(http.MethodPost, "/team/add", tokens["lead"], `{}`).Code)
+		assert.Equal(t, http.StatusOK, send(http.MethodPost, "/team/add", tokens["root"], `{}`).Code)
+	})
+
+	t.Run("OnlyAdminReadsAudit", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "/audit", tokens["ci"], "").Code)
+		assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "/audit", tokens["lead"], "").Code)
+		assert.Equal(t, http.StatusOK, send(http.MethodGet, "/audit", tokens["root"], "").Code)
+	})
+
+	t.Run("ActorHeaderCannotImpersonate", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/read", nil)
+		req.Header.Set("Authorization", tokens["ci"])
+		req.Header.Set(handlers.ActorHeader, "alice")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(t, "ci", actor)
+		assert.Equal(t, "alice", onBehalfOf)
+	})
+}
+
+// ssoKey stands in for the SSO signing key.
+type ssoKey struct {
+	key *ecdsa.PrivateKey
+}
+
+func (k ssoKey) Key(_ context.Context, _ string) (crypto.PublicKey, error) {
+	return &k.key.PublicKey, nil
+}
+
+func TestAuthMiddleware_JWT(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	authSvc := services.NewAuthService(testutil.NewTokenStore(), log)
+	serviceToken, _, err := authSvc.CreateToken(context.Background(), "ci-token", "ci")
+	require.NoError(t, err)
+	roles := &testutil.RoleStore{Bindings: []models.RoleBinding{{UserID: "ci", Role: models.RoleBot}}}
+	accessSvc := services.NewAccessService(roles, &mockUserRepoForHandler{}, &mockTeamRepoForHandler{},
+		&mockPRRepoForHandler{}, nil, log)
+	groupRoles, err := services.ParseGroupRoles("sre=admin")
+	require.NoError(t, err)
+	oidcSvc := services.NewOIDCService(jwt.NewVerifier(ssoKey{key: key}, "https://sso.example.com", "pr-service"), accessSvc, "sub", "groups",
+		groupRoles, log)
+	auth := handlers.NewAuthMiddleware(authSvc, accessSvc, oidcSvc, log)
+
+	var actor string
+	router := setupRouter()
+	api := router.Group("/", handlers.ActorMiddleware, auth.Authenticate)
+	api.POST("/team/add", auth.Require(models.PermCreateTeam), func(c *gin.Context) {
+		actor = reqctx.Actor(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+	api.GET("/read", auth.Require(models.PermRead), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	sign := func(claims map[string]any) string {
+		if _, ok := claims["aud"]; !ok {
+			claims["aud"] = "pr-service"
+		}
+		claims["iss"] = "https://sso.example.com"
+		payload, marshalErr := json.Marshal(claims)
+		require.NoError(t, marshalErr)
+		enc := base64.RawURLEncoding
+		input := enc.EncodeToString([]byte(`{"alg":"ES256"}`)) + "." + enc.EncodeToString(payload)
+		digest := sha256.Sum256([]byte(input))
+		r, s, signErr := ecdsa.Sign(rand.Reader, key, digest[:])
+		require.NoError(t, signErr)
+		sig := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+		return input + "." + enc.EncodeToString(sig)
+	}
+	send := func(method, path, token string) int {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+
+	t.Run("GroupGrantsRole", func(t *testing.T) {
+		token := sign(map[string]any{"sub": "alice", "groups": []string{"sre"}, "exp": exp})
+		assert.Equal(t, http.StatusOK, send(http.MethodPost, "/team/add", token))
+		assert.Equal(t, "alice", actor)
+	})
+
+	t.Run("NoGroupsForbidden", func(t *testing.T) {
+		token := sign(map[string]any{"sub": "alice", "exp": exp})
+		assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "/read", token))
+	})
+
+	t.Run("WrongAudienceUnauthorized", func(t *testing.T) {
+		token := sign(map[string]any{"sub": "alice", "groups": []string{"sre"}, "aud": "other-service", "exp": exp})
+		assert.Equal(t, http.StatusUnauthorized, send(http.MethodGet, "/read", token))
+	})
+
+	t.Run("ExpiredUnauthorized", func(t *testing.T) {
+		expired := time.Now().Add(-time.Hour).Unix()
+		token := sign(map[string]any{"sub": "alice", "groups": []string{"sre"}, "exp": expired})
+		assert.Equal(t, http.StatusUnauthorized, send(http.MethodGet, "/read", token))
+	})
+
+	t.Run("ServiceTokensStillWork", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send(http.MethodGet, "/read", serviceToken))
+	})
+}
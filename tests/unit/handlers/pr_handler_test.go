@@ -9,11 +9,14 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/handlers"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -24,83 +27,143 @@ type mockPRRepoForHandler struct {
 	repository.PRRepository
 }
 
-func (m *mockPRRepoForHandler) CreatePR(ctx context.Context, pr *models.PullRequest) error {
-	args := m.Called(ctx, pr)
+func (m *mockPRRepoForHandler) CreatePR(
+	ctx context.Context,
+	pr *models.PullRequest,
+	change models.AssignmentChange,
+) error {
+	args := m.Called(ctx, pr, change)
 	return args.Error(0)
 }
 
-func (m *mockPRRepoForHandler) GetPRByID(ctx context.Context, id string) (*models.PullRequest, error) {
-	args := m.Called(ctx, id)
+func (m *mockPRRepoForHandler) GetPRByID(ctx context.Context, repository, id string) (*models.PullRequest, error) {
+	args := m.Called(ctx, repository, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.PullRequest), args.Error(1)
 }
 
-func (m *mockPRRepoForHandler) UpdatePR(ctx context.Context, pr *models.PullRequest) error {
-	args := m.Called(ctx, pr)
+func (m *mockPRRepoForHandler) UpdatePR(
+	ctx context.Context,
+	pr *models.PullRequest,
+	change models.AssignmentChange,
+) error {
+	args := m.Called(ctx, pr, change)
 	return args.Error(0)
 }
 
-func (m *mockPRRepoForHandler) MergePR(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *mockPRRepoForHandler) MergePR(ctx context.Context, repository, id string) error {
+	args := m.Called(ctx, repository, id)
 	return args.Error(0)
 }
 
-func (m *mockPRRepoForHandler) GetPRsForUser(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
-	args := m.Called(ctx, userID)
+func (m *mockPRRepoForHandler) SetReviewState(ctx context.Context, repository, prID, reviewerID, state string) error {
+	args := m.Called(ctx, repository, prID, reviewerID, state)
+	return args.Error(0)
+}
+
+func (m *mockPRRepoForHandler) MarkPRReady(
+	ctx context.Context,
+	pr *models.PullRequest,
+	change models.AssignmentChange,
+) error {
+	args := m.Called(ctx, pr, change)
+	return args.Error(0)
+}
+
+func (m *mockPRRepoForHandler) ForceMergePR(ctx context.Context, override *models.MergeOverride) error {
+	args := m.Called(ctx, override)
+	return args.Error(0)
+}
+
+func (m *mockPRRepoForHandler) ClosePR(ctx context.Context, repository, id string) error {
+	args := m.Called(ctx, repository, id)
+	return args.Error(0)
+}
+
+func (m *mockPRRepoForHandler) ReopenPR(
+	ctx context.Context,
+	pr *models.PullRequest,
+	change models.AssignmentChange,
+) error {
+	args := m.Called(ctx, pr, change)
+	return args.Error(0)
+}
+
+func (m *mockPRRepoForHandler) GetPRsForUser(
+	ctx context.Context, userID, repository string, pendingOnly bool,
+) ([]models.PullRequestShort, error) {
+	args := m.Called(ctx, userID, repository, pendingOnly)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.PullRequestShort), args.Error(1)
 }
 
-func (m *mockPRRepoForHandler) ExistsPR(ctx context.Context, id string) (bool, error) {
-	args := m.Called(ctx, id)
+func (m *mockPRRepoForHandler) ExistsPR(ctx context.Context, repository, id string) (bool, error) {
+	args := m.Called(ctx, repository, id)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *mockPRRepoForHandler) GetTotalPRs(ctx context.Context) (int, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForHandler) GetAssignmentHistory(
+	ctx context.Context,
+	repository, prID string,
+) ([]models.AssignmentEvent, error) {
+	args := m.Called(ctx, repository, prID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AssignmentEvent), args.Error(1)
+}
+
+func (m *mockPRRepoForHandler) GetTotalPRs(ctx context.Context, repository string) (int, error) {
+	args := m.Called(ctx, repository)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *mockPRRepoForHandler) GetPrsByStatus(ctx context.Context) (int, int, error) {
-	args := m.Called(ctx)
-	return args.Int(0), args.Int(1), args.Error(2)
+func (m *mockPRRepoForHandler) GetPrsByStatus(ctx context.Context, repository string) (models.PrsStatus, error) {
+	args := m.Called(ctx, repository)
+	return args.Get(0).(models.PrsStatus), args.Error(1)
 }
 
-func (m *mockPRRepoForHandler) GetAssignmentsPerUser(ctx context.Context) ([]models.UserAssignment, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForHandler) GetAssignmentsPerUser(
+	ctx context.Context, repository string,
+) ([]models.UserAssignment, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.UserAssignment), args.Error(1)
 }
 
-func (m *mockPRRepoForHandler) GetTopReviewers(ctx context.Context) ([]models.UserAssignment, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForHandler) GetTopReviewers(
+	ctx context.Context, repository string,
+) ([]models.UserAssignment, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.UserAssignment), args.Error(1)
 }
 
-func (m *mockPRRepoForHandler) GetAvgCloseTime(ctx context.Context) (float64, int, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForHandler) GetAvgCloseTime(ctx context.Context, repository string) (float64, int, error) {
+	args := m.Called(ctx, repository)
 	return args.Get(0).(float64), args.Int(1), args.Error(2)
 }
 
-func (m *mockPRRepoForHandler) GetIdleUsersPerTeam(ctx context.Context) ([]models.TeamMetric, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForHandler) GetIdleUsersPerTeam(
+	ctx context.Context, repository string,
+) ([]models.TeamMetric, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.TeamMetric), args.Error(1)
 }
 
-func (m *mockPRRepoForHandler) GetNeedyPRsPerTeam(ctx context.Context) ([]models.TeamMetric, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForHandler) GetNeedyPRsPerTeam(ctx context.Context, repository string) ([]models.TeamMetric, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -138,6 +201,14 @@ func (m *mockUserRepoForHandler) GetActiveUsersByTeam(ctx context.Context, teamN
 	return args.Get(0).([]models.User), args.Error(1)
 }
 
+func (m *mockUserRepoForHandler) GetAvailableUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	args := m.Called(ctx, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
 func (m *mockUserRepoForHandler) GetTeamNameByUserID(ctx context.Context, userID string) (string, error) {
 	args := m.Called(ctx, userID)
 	return args.String(0), args.Error(1)
@@ -153,11 +224,39 @@ func setupRouter() *gin.Engine {
 	return gin.New()
 }
 
+type mockRepositoryRepoForHandler struct {
+	mock.Mock
+	repository.RepositoryRepository
+}
+
+func (m *mockRepositoryRepoForHandler) GetRepository(ctx context.Context, name string) (*models.Repository, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Repository), args.Error(1)
+}
+
+func (m *mockRepositoryRepoForHandler) SetCodeOwners(ctx context.Context, name, content, mode string) error {
+	args := m.Called(ctx, name, content, mode)
+	return args.Error(0)
+}
+
+func (m *mockRepositoryRepoForHandler) UpdateRepositorySettings(
+	ctx context.Context, name string, settings *models.RepositorySettings, defaultReviewers int,
+) error {
+	args := m.Called(ctx, name, settings, defaultReviewers)
+	return args.Error(0)
+}
+
 func TestPRHandler_CreatePR(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mPrRepo := &mockPRRepoForHandler{}
 	mUserRepo := &mockUserRepoForHandler{}
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	mTeamRepo := &mockTeamRepoForHandler{}
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, mTeamRepo, &mockRepositoryRepoForHandler{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 	handler := handlers.NewPRHandler(svc, log)
 
 	t.Run("Success", func(t *testing.T) {
@@ -169,12 +268,14 @@ func TestPRHandler_CreatePR(t *testing.T) {
 
 		author := &models.User{ID: "u1", TeamName: "team1"}
 		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(author, nil)
-		mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").Return([]models.User{
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mTeamRepo.On("GetFallbackTeams", mock.Anything, "team1").Return([]string{}, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
 			{ID: "u2", IsActive: true},
 		}, nil)
-		mPrRepo.On("CreatePR", mock.Anything, mock.AnythingOfType("*models.PullRequest")).Return(nil)
+		mPrRepo.On("CreatePR", mock.Anything, mock.AnythingOfType("*models.PullRequest"), mock.Anything).Return(nil)
 		reloaded := &models.PullRequest{ID: "pr-1", Status: "OPEN", Reviewers: []string{"u2"}}
-		mPrRepo.On("GetPRByID", mock.Anything, "pr-1").Return(reloaded, nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(reloaded, nil)
 
 		router := setupRouter()
 		router.POST("/pullRequest/create", handler.CreatePR)
@@ -212,14 +313,21 @@ func TestPRHandler_MergePR(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mPrRepo := &mockPRRepoForHandler{}
 	mUserRepo := &mockUserRepoForHandler{}
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	mTeamRepo := &mockTeamRepoForHandler{}
+	mRepoRepo := &mockRepositoryRepoForHandler{}
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, mTeamRepo, mRepoRepo, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
 	handler := handlers.NewPRHandler(svc, log)
+	mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1", TeamName: "team1"}, nil)
+	mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
 
 	t.Run("Success", func(t *testing.T) {
 		reqBody := map[string]string{"pull_request_id": "pr-1"}
-		mPrRepo.On("MergePR", mock.Anything, "pr-1").Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").
+			Return(&models.PullRequest{ID: "pr-1", AuthorID: "u1", Status: "OPEN"}, nil).Once()
+		mPrRepo.On("MergePR", mock.Anything, "", "pr-1").Return(nil)
 		pr := &models.PullRequest{ID: "pr-1", Status: "MERGED"}
-		mPrRepo.On("GetPRByID", mock.Anything, "pr-1").Return(pr, nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(pr, nil).Once()
 
 		router := setupRouter()
 		router.POST("/pullRequest/merge", handler.MergePR)
@@ -235,6 +343,29 @@ func TestPRHandler_MergePR(t *testing.T) {
 		mPrRepo.AssertExpectations(t)
 	})
 
+	t.Run("InRepository", func(t *testing.T) {
+		reqBody := map[string]string{"pull_request_id": "pr-1", "repository": "svc-a"}
+		mPrRepo.On("GetPRByID", mock.Anything, "svc-a", "pr-1").
+			Return(&models.PullRequest{ID: "pr-1", AuthorID: "u1", Repository: "svc-a", Status: "OPEN"}, nil).Once()
+		mRepoRepo.On("GetRepository", mock.Anything, "svc-a").Return(nil, apperrors.ErrNotFound)
+		mPrRepo.On("MergePR", mock.Anything, "svc-a", "pr-1").Return(nil)
+		pr := &models.PullRequest{ID: "pr-1", Repository: "svc-a", Status: "MERGED"}
+		mPrRepo.On("GetPRByID", mock.Anything, "svc-a", "pr-1").Return(pr, nil).Once()
+
+		router := setupRouter()
+		router.POST("/pullRequest/merge", handler.MergePR)
+
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"repository":"svc-a"`)
+	})
+
 	t.Run("InvalidInput", func(t *testing.T) {
 		reqBody := map[string]string{"invalid": "data"}
 
@@ -252,11 +383,452 @@ func TestPRHandler_MergePR(t *testing.T) {
 	})
 }
 
+func TestPRHandler_MergePolicy(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	required := 2
+	setup := func() (*mockPRRepoForHandler, *handlers.PRHandler) {
+		mPrRepo := &mockPRRepoForHandler{}
+		mRepoRepo := &mockRepositoryRepoForHandler{}
+		svc := services.NewPRService(
+			mPrRepo, &mockUserRepoForHandler{}, &mockTeamRepoForHandler{}, mRepoRepo, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+		mRepoRepo.On("GetRepository", mock.Anything, "mono").Return(&models.Repository{
+			Name: "mono", Settings: &models.RepositorySettings{RequiredApprovals: &required},
+		}, nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "mono", "pr-1").Return(&models.PullRequest{
+			ID: "pr-1", Repository: "mono", Status: "OPEN", Reviewers: []string{"u2", "u3"},
+			ReviewStates: map[string]string{"u2": models.ReviewStateApproved, "u3": models.ReviewStateChangesRequested},
+		}, nil).Once()
+		return mPrRepo, handlers.NewPRHandler(svc, log)
+	}
+
+	t.Run("Blocked", func(t *testing.T) {
+		_, handler := setup()
+		reqBody := map[string]string{"pull_request_id": "pr-1", "repository": "mono"}
+
+		router := setupRouter()
+		router.POST("/pullRequest/merge", handler.MergePR)
+
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"error": {
+			"code": "MERGE_BLOCKED",
+			"message": "Merge blocked by review policy",
+			"details": {
+				"required_approvals": 2,
+				"approvals": 1,
+				"pending_reviewers": [],
+				"changes_requested_by": ["u3"]
+			}
+		}}`, w.Body.String())
+	})
+
+	t.Run("Force", func(t *testing.T) {
+		mPrRepo, handler := setup()
+		mPrRepo.On("ForceMergePR", mock.Anything, mock.MatchedBy(func(o *models.MergeOverride) bool {
+			return o.Repository == "mono" && o.PRID == "pr-1" && o.Reason == "hotfix"
+		})).Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "mono", "pr-1").
+			Return(&models.PullRequest{ID: "pr-1", Repository: "mono", Status: "MERGED"}, nil).Once()
+		reqBody := map[string]any{"pull_request_id": "pr-1", "repository": "mono", "force": true, "reason": "hotfix"}
+
+		router := setupRouter()
+		router.POST("/pullRequest/merge", handler.MergePR)
+
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mPrRepo.AssertExpectations(t)
+	})
+}
+
+func TestPRHandler_ReadyPR(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mPrRepo := &mockPRRepoForHandler{}
+	mUserRepo := &mockUserRepoForHandler{}
+	mTeamRepo := &mockTeamRepoForHandler{}
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, mTeamRepo, &mockRepositoryRepoForHandler{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
+	handler := handlers.NewPRHandler(svc, log)
+
+	t.Run("Success", func(t *testing.T) {
+		draft := &models.PullRequest{ID: "pr-1", AuthorID: "u1", Status: "OPEN", Draft: true}
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(draft, nil).Once()
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1", TeamName: "team1"}, nil)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mTeamRepo.On("GetFallbackTeams", mock.Anything, "team1").Return([]string{}, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").
+			Return([]models.User{{ID: "u2", IsActive: true}}, nil)
+		mPrRepo.On("MarkPRReady", mock.Anything, mock.AnythingOfType("*models.PullRequest"), mock.Anything).Return(nil)
+		reloaded := &models.PullRequest{ID: "pr-1", Status: "OPEN", Reviewers: []string{"u2"}, NeedMoreReviewers: true}
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(reloaded, nil).Once()
+
+		router := setupRouter()
+		router.POST("/pullRequest/ready", handler.ReadyPR)
+
+		body, _ := json.Marshal(map[string]string{"pull_request_id": "pr-1"})
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/ready", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"assigned_reviewers":["u2"]`)
+		mPrRepo.AssertExpectations(t)
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		router := setupRouter()
+		router.POST("/pullRequest/ready", handler.ReadyPR)
+
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/ready", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPRHandler_GetPR(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mPrRepo := &mockPRRepoForHandler{}
+	svc := services.NewPRService(
+		mPrRepo, &mockUserRepoForHandler{}, &mockTeamRepoForHandler{}, &mockRepositoryRepoForHandler{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
+	handler := handlers.NewPRHandler(svc, log)
+
+	t.Run("Success", func(t *testing.T) {
+		pr := &models.PullRequest{
+			ID:           "pr-1",
+			Status:       "OPEN",
+			Reviewers:    []string{"u2"},
+			ReviewStates: map[string]string{"u2": models.ReviewStateChangesRequested},
+		}
+		mPrRepo.On("GetPRByID", mock.Anything, "svc", "pr-1").Return(pr, nil)
+
+		router := setupRouter()
+		router.GET("/pullRequest/get", handler.GetPR)
+
+		req := httptest.NewRequest(http.MethodGet, "/pullRequest/get?pull_request_id=pr-1&repository=svc", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"review_states":{"u2":"CHANGES_REQUESTED"}`)
+	})
+
+	t.Run("MissingID", func(t *testing.T) {
+		router := setupRouter()
+		router.GET("/pullRequest/get", handler.GetPR)
+
+		req := httptest.NewRequest(http.MethodGet, "/pullRequest/get", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPRHandler_GetPRHistory(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mPrRepo := &mockPRRepoForHandler{}
+	svc := services.NewPRService(
+		mPrRepo, &mockUserRepoForHandler{}, &mockTeamRepoForHandler{}, &mockRepositoryRepoForHandler{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
+	handler := handlers.NewPRHandler(svc, log)
+
+	t.Run("Success", func(t *testing.T) {
+		at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+		mPrRepo.On("ExistsPR", mock.Anything, "svc", "pr-1").Return(true, nil)
+		mPrRepo.On("GetAssignmentHistory", mock.Anything, "svc", "pr-1").Return([]models.AssignmentEvent{
+			{
+				ReviewerID: "u2",
+				Action:     models.AssignmentAssigned,
+				Reason:     models.AssignReasonCreated,
+				Actor:      "alice",
+				At:         at,
+			},
+		}, nil)
+
+		router := setupRouter()
+		router.GET("/pullRequest/history", handler.GetPRHistory)
+
+		req := httptest.NewRequest(http.MethodGet, "/pullRequest/history?pull_request_id=pr-1&repository=svc", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"pull_request_id": "pr-1",
+			"repository": "svc",
+			"history": [{
+				"reviewer_id": "u2",
+				"action": "ASSIGNED",
+				"reason": "PR_CREATED",
+				"actor": "alice",
+				"at": "2026-10-01T12:00:00Z"
+			}]
+		}`, w.Body.String())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mPrRepo.On("ExistsPR", mock.Anything, "", "pr-x").Return(false, nil)
+
+		router := setupRouter()
+		router.GET("/pullRequest/history", handler.GetPRHistory)
+
+		req := httptest.NewRequest(http.MethodGet, "/pullRequest/history?pull_request_id=pr-x", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("MissingID", func(t *testing.T) {
+		router := setupRouter()
+		router.GET("/pullRequest/history", handler.GetPRHistory)
+
+		req := httptest.NewRequest(http.MethodGet, "/pullRequest/history", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPRHandler_ActorHeader(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	for name, tc := range map[string]struct {
+		header string
+		actor  string
+	}{
+		"HeaderIgnored": {header: "alice", actor: "anonymous"},
+		"Anonymous":     {header: "", actor: "anonymous"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mPrRepo := &mockPRRepoForHandler{}
+			mUserRepo := &mockUserRepoForHandler{}
+			svc := services.NewPRService(
+				mPrRepo, mUserRepo, &mockTeamRepoForHandler{}, &mockRepositoryRepoForHandler{}, testutil.NewTestAudit(),
+				services.NewRandomSelector(), 2, log)
+			handler := handlers.NewPRHandler(svc, log)
+
+			pr := &models.PullRequest{ID: "pr-1", Status: "OPEN", Reviewers: []string{"u2"}, AuthorID: "u1"}
+			mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(pr, nil)
+			mUserRepo.On("GetUserByID", mock.Anything, "u2").Return(&models.User{ID: "u2", TeamName: "team1"}, nil)
+			mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
+				{ID: "u3", IsActive: true},
+			}, nil)
+			mPrRepo.On("UpdatePR", mock.Anything, mock.AnythingOfType("*models.PullRequest"),
+				models.AssignmentChange{Actor: tc.actor, Reason: models.AssignReasonReassigned}).Return(nil)
+
+			router := setupRouter()
+			router.Use(handlers.ActorMiddleware)
+			router.POST("/pullRequest/reassign", handler.ReassignReviewer)
+
+			body, _ := json.Marshal(map[string]string{"pull_request_id": "pr-1", "old_reviewer_id": "u2"})
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/reassign", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.header != "" {
+				req.Header.Set(handlers.ActorHeader, tc.header)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			mPrRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPRHandler_SubmitReview(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mPrRepo := &mockPRRepoForHandler{}
+	svc := services.NewPRService(
+		mPrRepo, &mockUserRepoForHandler{}, &mockTeamRepoForHandler{}, &mockRepositoryRepoForHandler{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
+	handler := handlers.NewPRHandler(svc, log)
+	pr := &models.PullRequest{ID: "pr-1", Status: "OPEN", Reviewers: []string{"u2"}}
+
+	t.Run("Success", func(t *testing.T) {
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(pr, nil)
+		mPrRepo.On("SetReviewState", mock.Anything, "", "pr-1", "u2", models.ReviewStateApproved).Return(nil)
+
+		router := setupRouter()
+		router.POST("/pullRequest/review", handler.SubmitReview)
+
+		body, _ := json.Marshal(map[string]string{"pull_request_id": "pr-1", "reviewer_id": "u2", "state": "APPROVED"})
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/review", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mPrRepo.AssertExpectations(t)
+	})
+
+	t.Run("NotAssigned", func(t *testing.T) {
+		router := setupRouter()
+		router.POST("/pullRequest/review", handler.SubmitReview)
+
+		body, _ := json.Marshal(map[string]string{"pull_request_id": "pr-1", "reviewer_id": "u9", "state": "APPROVED"})
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/review", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "NOT_ASSIGNED")
+	})
+
+	t.Run("InvalidState", func(t *testing.T) {
+		router := setupRouter()
+		router.POST("/pullRequest/review", handler.SubmitReview)
+
+		body, _ := json.Marshal(map[string]string{"pull_request_id": "pr-1", "reviewer_id": "u2", "state": "LGTM"})
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/review", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPRHandler_ClosePR(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mPrRepo := &mockPRRepoForHandler{}
+	svc := services.NewPRService(
+		mPrRepo, &mockUserRepoForHandler{}, &mockTeamRepoForHandler{}, &mockRepositoryRepoForHandler{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
+	handler := handlers.NewPRHandler(svc, log)
+
+	t.Run("Success", func(t *testing.T) {
+		mPrRepo.On("ClosePR", mock.Anything, "", "pr-1").Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").
+			Return(&models.PullRequest{ID: "pr-1", Status: "CLOSED"}, nil)
+
+		router := setupRouter()
+		router.POST("/pullRequest/close", handler.ClosePR)
+
+		body, _ := json.Marshal(map[string]string{"pull_request_id": "pr-1"})
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/close", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"CLOSED"`)
+	})
+
+	t.Run("Merged", func(t *testing.T) {
+		mPrRepo.On("ClosePR", mock.Anything, "", "pr-2").Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-2").
+			Return(&models.PullRequest{ID: "pr-2", Status: "MERGED"}, nil)
+
+		router := setupRouter()
+		router.POST("/pullRequest/close", handler.ClosePR)
+
+		body, _ := json.Marshal(map[string]string{"pull_request_id": "pr-2"})
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/close", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "PR_MERGED")
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		router := setupRouter()
+		router.POST("/pullRequest/close", handler.ClosePR)
+
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/close", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPRHandler_ReopenPR(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mPrRepo := &mockPRRepoForHandler{}
+	svc := services.NewPRService(
+		mPrRepo, &mockUserRepoForHandler{}, &mockTeamRepoForHandler{}, &mockRepositoryRepoForHandler{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
+	handler := handlers.NewPRHandler(svc, log)
+
+	t.Run("AlreadyOpen", func(t *testing.T) {
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(&models.PullRequest{ID: "pr-1", Status: "OPEN"}, nil)
+
+		router := setupRouter()
+		router.POST("/pullRequest/reopen", handler.ReopenPR)
+
+		body, _ := json.Marshal(map[string]string{"pull_request_id": "pr-1"})
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/reopen", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"OPEN"`)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-x").Return(nil, apperrors.ErrNotFound)
+
+		router := setupRouter()
+		router.POST("/pullRequest/reopen", handler.ReopenPR)
+
+		body, _ := json.Marshal(map[string]string{"pull_request_id": "pr-x"})
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/reopen", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
 func TestPRHandler_ReassignReviewer(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mPrRepo := &mockPRRepoForHandler{}
 	mUserRepo := &mockUserRepoForHandler{}
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepoForHandler{}, &mockRepositoryRepoForHandler{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 	handler := handlers.NewPRHandler(svc, log)
 
 	t.Run("Success", func(t *testing.T) {
@@ -270,13 +842,13 @@ func TestPRHandler_ReassignReviewer(t *testing.T) {
 			Reviewers: []string{"u2"},
 			AuthorID:  "u1",
 		}
-		mPrRepo.On("GetPRByID", mock.Anything, "pr-1").Return(pr, nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(pr, nil)
 		oldReviewer := &models.User{ID: "u2", TeamName: "team1"}
 		mUserRepo.On("GetUserByID", mock.Anything, "u2").Return(oldReviewer, nil)
-		mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").Return([]models.User{
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
 			{ID: "u3", IsActive: true},
 		}, nil)
-		mPrRepo.On("UpdatePR", mock.Anything, mock.AnythingOfType("*models.PullRequest")).Return(nil)
+		mPrRepo.On("UpdatePR", mock.Anything, mock.AnythingOfType("*models.PullRequest"), mock.Anything).Return(nil)
 
 		router := setupRouter()
 		router.POST("/pullRequest/reassign", handler.ReassignReviewer)
@@ -314,11 +886,13 @@ func TestPRHandler_GetTotalPRs(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mPrRepo := &mockPRRepoForHandler{}
 	mUserRepo := &mockUserRepoForHandler{}
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepoForHandler{}, &mockRepositoryRepoForHandler{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 	handler := handlers.NewPRHandler(svc, log)
 
 	t.Run("Success", func(t *testing.T) {
-		mPrRepo.On("GetTotalPRs", mock.Anything).Return(10, nil)
+		mPrRepo.On("GetTotalPRs", mock.Anything, "").Return(10, nil)
 
 		router := setupRouter()
 		router.GET("/stats/total", handler.GetTotalPRs)
@@ -331,17 +905,35 @@ func TestPRHandler_GetTotalPRs(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 		mPrRepo.AssertExpectations(t)
 	})
+
+	t.Run("RepositoryFilter", func(t *testing.T) {
+		mPrRepo.On("GetTotalPRs", mock.Anything, "svc-a").Return(4, nil)
+
+		router := setupRouter()
+		router.GET("/stats/total", handler.GetTotalPRs)
+
+		req := httptest.NewRequest(http.MethodGet, "/stats/total?repository=svc-a", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"total_prs":4`)
+	})
 }
 
 func TestPRHandler_GetPrsByStatus(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mPrRepo := &mockPRRepoForHandler{}
 	mUserRepo := &mockUserRepoForHandler{}
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepoForHandler{}, &mockRepositoryRepoForHandler{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 	handler := handlers.NewPRHandler(svc, log)
 
 	t.Run("Success", func(t *testing.T) {
-		mPrRepo.On("GetPrsByStatus", mock.Anything).Return(5, 3, nil)
+		mPrRepo.On("GetPrsByStatus", mock.Anything, "").
+			Return(models.PrsStatus{OpenPRs: 5, MergedPRs: 3, ClosedPRs: 2}, nil)
 
 		router := setupRouter()
 		router.GET("/stats/status", handler.GetPrsByStatus)
@@ -352,6 +944,7 @@ func TestPRHandler_GetPrsByStatus(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"open_prs":5,"merged_prs":3,"closed_prs":2}`, w.Body.String())
 		mPrRepo.AssertExpectations(t)
 	})
 }
@@ -360,14 +953,16 @@ func TestPRHandler_GetAssignmentsPerUser(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mPrRepo := &mockPRRepoForHandler{}
 	mUserRepo := &mockUserRepoForHandler{}
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepoForHandler{}, &mockRepositoryRepoForHandler{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 	handler := handlers.NewPRHandler(svc, log)
 
 	t.Run("Success", func(t *testing.T) {
 		assignments := []models.UserAssignment{
 			{UserID: "u1", Name: "User1", Count: 5},
 		}
-		mPrRepo.On("GetAssignmentsPerUser", mock.Anything).Return(assignments, nil)
+		mPrRepo.On("GetAssignmentsPerUser", mock.Anything, "").Return(assignments, nil)
 
 		router := setupRouter()
 		router.GET("/stats/assignments", handler.GetAssignmentsPerUser)
@@ -386,14 +981,16 @@ func TestPRHandler_GetTopReviewers(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mPrRepo := &mockPRRepoForHandler{}
 	mUserRepo := &mockUserRepoForHandler{}
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepoForHandler{}, &mockRepositoryRepoForHandler{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 	handler := handlers.NewPRHandler(svc, log)
 
 	t.Run("Success", func(t *testing.T) {
 		top := []models.UserAssignment{
 			{UserID: "u1", Name: "User1", Count: 10},
 		}
-		mPrRepo.On("GetTopReviewers", mock.Anything).Return(top, nil)
+		mPrRepo.On("GetTopReviewers", mock.Anything, "").Return(top, nil)
 
 		router := setupRouter()
 		router.GET("/stats/top-reviewers", handler.GetTopReviewers)
@@ -412,11 +1009,13 @@ func TestPRHandler_GetAvgCloseTime(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mPrRepo := &mockPRRepoForHandler{}
 	mUserRepo := &mockUserRepoForHandler{}
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepoForHandler{}, &mockRepositoryRepoForHandler{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 	handler := handlers.NewPRHandler(svc, log)
 
 	t.Run("Success", func(t *testing.T) {
-		mPrRepo.On("GetAvgCloseTime", mock.Anything).Return(86400.0, 5, nil)
+		mPrRepo.On("GetAvgCloseTime", mock.Anything, "").Return(86400.0, 5, nil)
 
 		router := setupRouter()
 		router.GET("/stats/avg-close-time", handler.GetAvgCloseTime)
@@ -435,14 +1034,16 @@ func TestPRHandler_GetIdleUsersPerTeam(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mPrRepo := &mockPRRepoForHandler{}
 	mUserRepo := &mockUserRepoForHandler{}
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepoForHandler{}, &mockRepositoryRepoForHandler{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 	handler := handlers.NewPRHandler(svc, log)
 
 	t.Run("Success", func(t *testing.T) {
 		metrics := []models.TeamMetric{
 			{TeamName: "team1", Count: 2},
 		}
-		mPrRepo.On("GetIdleUsersPerTeam", mock.Anything).Return(metrics, nil)
+		mPrRepo.On("GetIdleUsersPerTeam", mock.Anything, "").Return(metrics, nil)
 
 		router := setupRouter()
 		router.GET("/stats/idle-users-per-team", handler.GetIdleUsersPerTeam)
@@ -461,14 +1062,16 @@ func TestPRHandler_GetNeedyPRsPerTeam(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mPrRepo := &mockPRRepoForHandler{}
 	mUserRepo := &mockUserRepoForHandler{}
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepoForHandler{}, &mockRepositoryRepoForHandler{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 	handler := handlers.NewPRHandler(svc, log)
 
 	t.Run("Success", func(t *testing.T) {
 		metrics := []models.TeamMetric{
 			{TeamName: "team1", Count: 3},
 		}
-		mPrRepo.On("GetNeedyPRsPerTeam", mock.Anything).Return(metrics, nil)
+		mPrRepo.On("GetNeedyPRsPerTeam", mock.Anything, "").Return(metrics, nil)
 
 		router := setupRouter()
 		router.GET("/stats/needy-prs-per-team", handler.GetNeedyPRsPerTeam)
@@ -0,0 +1,164 @@
+package handlers_test
+
+import (
+	"bytes"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/handlers"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newCodeOwnersUpload(t *testing.T, fields map[string]string, content string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for k, v := range fields {
+		_ = writer.WriteField(k, v)
+	}
+	if content != "" {
+		part, err := writer.CreateFormFile("file", "CODEOWNERS")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = part.Write([]byte(content))
+	}
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/repository/set-codeowners", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestRepositoryHandler_SetCodeOwners(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mRepoRepo := &mockRepositoryRepoForHandler{}
+	handler := handlers.NewRepositoryHandler(services.NewRepositoryService(mRepoRepo, 2, log), log)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/repository/set-codeowners", handler.SetCodeOwners)
+
+	t.Run("Success", func(t *testing.T) {
+		content := "*.go @org/backend\n"
+		mRepoRepo.On("SetCodeOwners", mock.Anything, "svc", content, models.CodeOwnersRequired).Return(nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newCodeOwnersUpload(t, map[string]string{
+			"repository": "svc",
+			"mode":       models.CodeOwnersRequired,
+		}, content))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"codeowners_mode":"required"`)
+	})
+
+	t.Run("InvalidFile", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newCodeOwnersUpload(t, map[string]string{"repository": "svc"}, "src/[ab].go @dev\n"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newCodeOwnersUpload(t, map[string]string{"repository": "svc"}, ""))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestRepositoryHandler_GetRepository(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mRepoRepo := &mockRepositoryRepoForHandler{}
+	handler := handlers.NewRepositoryHandler(services.NewRepositoryService(mRepoRepo, 2, log), log)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/repository/get", handler.GetRepository)
+
+	t.Run("Success", func(t *testing.T) {
+		mRepoRepo.On("GetRepository", mock.Anything, "svc").Return(&models.Repository{
+			Name: "svc", CodeOwners: "* @dev\n", CodeOwnersMode: models.CodeOwnersPreferred,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/repository/get?repository=svc", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"repository":"svc"`)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mRepoRepo.On("GetRepository", mock.Anything, "missing").Return(nil, apperrors.ErrNotFound)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/repository/get?repository=missing", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("MissingParam", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/repository/get", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestRepositoryHandler_SetRepositorySettings(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mRepoRepo := &mockRepositoryRepoForHandler{}
+	handler := handlers.NewRepositoryHandler(services.NewRepositoryService(mRepoRepo, 2, log), log)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/repository/set-settings", handler.SetRepositorySettings)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/repository/set-settings", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		teamName, count := "platform", 1
+		settings := &models.RepositorySettings{DefaultTeamName: &teamName, ReviewersCount: &count}
+		mRepoRepo.On("UpdateRepositorySettings", mock.Anything, "mono", settings, 2).Return(nil)
+		mRepoRepo.On("GetRepository", mock.Anything, "mono").
+			Return(&models.Repository{Name: "mono", Settings: settings}, nil)
+
+		w := post(`{"repository":"mono","default_team_name":"platform","reviewers_count":1}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"default_team_name":"platform"`)
+	})
+
+	t.Run("TeamNotFound", func(t *testing.T) {
+		teamName := "ghost"
+		settings := &models.RepositorySettings{DefaultTeamName: &teamName}
+		mRepoRepo.On("UpdateRepositorySettings", mock.Anything, "mono", settings, 2).Return(apperrors.ErrNotFound)
+
+		w := post(`{"repository":"mono","default_team_name":"ghost"}`)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("MissingRepository", func(t *testing.T) {
+		w := post(`{"reviewers_count":1}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,118 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/handlers"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// slackDirectory knows the users u1 and u2 of team backend.
+type slackDirectory struct {
+	repository.UserRepository
+	repository.TeamRepository
+}
+
+func (slackDirectory) GetUserByID(_ context.Context, id string) (*models.User, error) {
+	if id != "u1" && id != "u2" {
+		return nil, apperrors.ErrNotFound
+	}
+	return &models.User{ID: id, TeamName: "backend", IsActive: true}, nil
+}
+
+func (slackDirectory) GetTeamByName(_ context.Context, name string) (*models.Team, error) {
+	if name != "backend" {
+		return nil, apperrors.ErrNotFound
+	}
+	return &models.Team{Name: name}, nil
+}
+
+func setupSlackRouter(store *testutil.SlackStore) *gin.Engine {
+	log := slog.New(slog.DiscardHandler)
+	handler := handlers.NewSlackHandler(services.NewSlackService(store, slackDirectory{}, slackDirectory{},
+		http.DefaultClient, "https://hooks.slack.com", log), log)
+
+	router := setupRouter()
+	router.POST("/team/set-slack-channel", handler.SetTeamChannel)
+	router.POST("/users/setSlackHandle", handler.SetUserHandle)
+	return router
+}
+
+func TestSlackHandler_SetTeamChannel(t *testing.T) {
+	for name, tc := range map[string]struct {
+		body     string
+		wantCode int
+		wantPath string
+	}{
+		"Set":         {`{"team_name": "backend", "webhook_path": "/services/T1"}`, http.StatusOK, "/services/T1"},
+		"Remove":      {`{"team_name": "backend"}`, http.StatusOK, ""},
+		"NotAPath":    {`{"team_name": "backend", "webhook_path": "x"}`, http.StatusBadRequest, "/old"},
+		"NoTeam":      {`{"webhook_path": "/services/x"}`, http.StatusBadRequest, "/old"},
+		"UnknownTeam": {`{"team_name": "nope", "webhook_path": "/services/x"}`, http.StatusNotFound, "/old"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := testutil.NewSlackStore()
+			store.Channels["backend"] = "/old"
+			router := setupSlackRouter(store)
+
+			req := httptest.NewRequest(http.MethodPost, "/team/set-slack-channel", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantCode, w.Code)
+			assert.Equal(t, tc.wantPath, store.Channels["backend"])
+			assert.NotContains(t, w.Body.String(), "/services/", "the webhook path is never returned")
+		})
+	}
+}
+
+func TestSlackHandler_SetUserHandle(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		store := testutil.NewSlackStore()
+		router := setupSlackRouter(store)
+
+		req := httptest.NewRequest(http.MethodPost, "/users/setSlackHandle",
+			bytes.NewBufferString(`{"user_id": "u2", "handle": "U0USER2"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"user_id": "u2", "handle": "U0USER2"}`, w.Body.String())
+		assert.Equal(t, "U0USER2", store.Handles["u2"])
+	})
+
+	for name, tc := range map[string]struct {
+		body     string
+		wantCode int
+	}{
+		"InvalidHandle": {`{"user_id": "u2", "handle": "@bob"}`, http.StatusBadRequest},
+		"UnknownUser":   {`{"user_id": "nope", "handle": "U0NOPE"}`, http.StatusNotFound},
+		"NotJSON":       {`user_id=u2`, http.StatusBadRequest},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := testutil.NewSlackStore()
+			router := setupSlackRouter(store)
+
+			req := httptest.NewRequest(http.MethodPost, "/users/setSlackHandle", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantCode, w.Code)
+			assert.Empty(t, store.Handles)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/handlers"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSubscriptionRouter(store *testutil.SubscriptionStore) *gin.Engine {
+	log := slog.New(slog.DiscardHandler)
+	handler := handlers.NewSubscriptionHandler(
+		services.NewSubscriptionService(store, http.DefaultClient, 3, time.Second, log), log)
+
+	router := setupRouter()
+	router.POST("/subscriptions/add", handler.Subscribe)
+	router.POST("/subscriptions/delete", handler.Unsubscribe)
+	router.GET("/subscriptions/getDeliveries", handler.GetDeliveries)
+	return router
+}
+
+func TestSubscriptionHandler_Subscribe(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		store := &testutil.SubscriptionStore{}
+		router := setupSubscriptionRouter(store)
+
+		body := `{"url": "https://hooks.example.com/pr", "secret": "s3cret", "event_types": ["pr.merged"]}`
+		req := httptest.NewRequest(http.MethodPost, "/subscriptions/add", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		require.Len(t, store.Subs, 1)
+		assert.JSONEq(t, `{"subscription": {
+			"id": 1,
+			"url": "https://hooks.example.com/pr",
+			"secret": "s3cret",
+			"event_types": ["pr.merged"],
+			"created_at": "`+store.Subs[0].CreatedAt.Format(time.RFC3339Nano)+`"
+		}}`, w.Body.String())
+	})
+
+	for name, body := range map[string]string{
+		"NoURL":        `{"event_types": ["pr.merged"]}`,
+		"BadURL":       `{"url": "hooks.example.com", "event_types": ["pr.merged"]}`,
+		"UnknownEvent": `{"url": "https://hooks.example.com/pr", "event_types": ["pr.approved"]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			router := setupSubscriptionRouter(&testutil.SubscriptionStore{})
+
+			req := httptest.NewRequest(http.MethodPost, "/subscriptions/add", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "INVALID_INPUT")
+		})
+	}
+}
+
+func TestSubscriptionHandler_Unsubscribe(t *testing.T) {
+	store := &testutil.SubscriptionStore{Subs: []models.WebhookSubscription{{ID: 1}}}
+	router := setupSubscriptionRouter(store)
+
+	for _, tc := range []struct {
+		id   int64
+		want int
+	}{
+		{1, http.StatusOK},
+		{1, http.StatusNotFound},
+	} {
+		body, err := json.Marshal(map[string]int64{"subscription_id": tc.id})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/subscriptions/delete", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tc.want, w.Code)
+	}
+	assert.Empty(t, store.Subs)
+}
+
+func TestSubscriptionHandler_GetDeliveries(t *testing.T) {
+	attemptedAt := time.Date(2026, 10, 1, 12, 0, 1, 0, time.UTC)
+	store := &testutil.SubscriptionStore{
+		Subs: []models.WebhookSubscription{{ID: 1}},
+		Deliveries: []*models.WebhookDelivery{{
+			ID:             4,
+			SubscriptionID: 1,
+			EventID:        "ev-1",
+			EventType:      models.EventPRMerged,
+			Payload:        json.RawMessage(`{"type": "pr.merged"}`),
+			Status:         models.DeliveryDelivered,
+			Attempts:       1,
+			CreatedAt:      time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+			DeliveredAt:    &attemptedAt,
+			AttemptLog: []models.DeliveryAttempt{
+				{Attempt: 1, StatusCode: http.StatusOK, DurationMS: 12, AttemptedAt: attemptedAt},
+			},
+		}},
+	}
+	router := setupSubscriptionRouter(store)
+
+	t.Run("Success", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/subscriptions/getDeliveries?subscription_id=1", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"subscription_id": 1,
+			"deliveries": [{
+				"id": 4,
+				"subscription_id": 1,
+				"event_id": "ev-1",
+				"event_type": "pr.merged",
+				"payload": {"type": "pr.merged"},
+				"status": "DELIVERED",
+				"attempts": 1,
+				"created_at": "2026-10-01T12:00:00Z",
+				"delivered_at": "2026-10-01T12:00:01Z",
+				"attempt_log": [
+					{"attempt": 1, "status_code": 200, "duration_ms": 12, "attempted_at": "2026-10-01T12:00:01Z"}
+				]
+			}]
+		}`, w.Body.String())
+	})
+
+	for name, tc := range map[string]struct {
+		query string
+		want  int
+	}{
+		"NoSubscription": {"", http.StatusBadRequest},
+		"BadLimit":       {"subscription_id=1&limit=-1", http.StatusBadRequest},
+		"NotFound":       {"subscription_id=2", http.StatusNotFound},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/subscriptions/getDeliveries?"+tc.query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.want, w.Code)
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -56,6 +57,16 @@ func (m *mockUserRepoForTeamHandler) GetActiveUsersByTeam(ctx context.Context, t
 	return args.Get(0).([]models.User), args.Error(1)
 }
 
+func (m *mockUserRepoForTeamHandler) GetAvailableUsersByTeam(
+	ctx context.Context, teamName string,
+) ([]models.User, error) {
+	args := m.Called(ctx, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
 func (m *mockUserRepoForTeamHandler) GetTeamNameByUserID(ctx context.Context, userID string) (string, error) {
 	args := m.Called(ctx, userID)
 	return args.String(0), args.Error(1)
@@ -74,6 +85,34 @@ func (m *mockTeamRepoForHandler) GetTeamByName(ctx context.Context, name string)
 	return args.Get(0).(*models.Team), args.Error(1)
 }
 
+func (m *mockTeamRepoForHandler) GetTeamSettings(ctx context.Context, name string) (*models.TeamSettings, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TeamSettings), args.Error(1)
+}
+
+func (m *mockTeamRepoForHandler) GetFallbackTeams(ctx context.Context, name string) ([]string, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockTeamRepoForHandler) SetFallbackTeams(ctx context.Context, name string, fallbackTeams []string) error {
+	args := m.Called(ctx, name, fallbackTeams)
+	return args.Error(0)
+}
+
+func (m *mockTeamRepoForHandler) UpdateTeamSettings(
+	ctx context.Context, name string, settings *models.TeamSettings, defaultReviewers int,
+) error {
+	args := m.Called(ctx, name, settings, defaultReviewers)
+	return args.Error(0)
+}
+
 func (m *mockUserRepoForTeamHandler) UpsertUser(ctx context.Context, user *models.User) error {
 	args := m.Called(ctx, user)
 	return args.Error(0)
@@ -83,7 +122,7 @@ func TestTeamHandler_CreateTeam(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mTeamRepo := &mockTeamRepoForHandler{}
 	mUserRepo := &mockUserRepoForTeamHandler{}
-	svc := services.NewTeamService(mTeamRepo, mUserRepo, log)
+	svc := services.NewTeamService(mTeamRepo, mUserRepo, testutil.NewTestAudit(), 2, log)
 	handler := handlers.NewTeamHandler(svc, log)
 
 	t.Run("Success", func(t *testing.T) {
@@ -137,7 +176,7 @@ func TestTeamHandler_GetTeam(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mTeamRepo := &mockTeamRepoForHandler{}
 	mUserRepo := &mockUserRepoForTeamHandler{}
-	svc := services.NewTeamService(mTeamRepo, mUserRepo, log)
+	svc := services.NewTeamService(mTeamRepo, mUserRepo, testutil.NewTestAudit(), 2, log)
 	handler := handlers.NewTeamHandler(svc, log)
 
 	t.Run("Success", func(t *testing.T) {
@@ -178,7 +217,7 @@ func TestTeamHandler_AddMemberToTeam(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mTeamRepo := &mockTeamRepoForHandler{}
 	mUserRepo := &mockUserRepoForTeamHandler{}
-	svc := services.NewTeamService(mTeamRepo, mUserRepo, log)
+	svc := services.NewTeamService(mTeamRepo, mUserRepo, testutil.NewTestAudit(), 2, log)
 	handler := handlers.NewTeamHandler(svc, log)
 
 	router := setupRouter()
@@ -222,6 +261,8 @@ func TestTeamHandler_AddMemberToTeam(t *testing.T) {
 		team := &models.Team{Name: "team1"}
 		mTeamRepo.On("GetTeamByName", mock.Anything, "team1").
 			Return(team, nil)
+		mUserRepo.On("GetUserByID", mock.Anything, "user1").
+			Return(nil, apperrors.ErrNotFound)
 		mUserRepo.On("UpsertUser", mock.Anything, mock.AnythingOfType("*models.User")).
 			Return(nil)
 
@@ -250,3 +291,109 @@ func TestTeamHandler_AddMemberToTeam(t *testing.T) {
 		mUserRepo.AssertExpectations(t)
 	})
 }
+
+func TestTeamHandler_SetTeamSettings(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mTeamRepo := &mockTeamRepoForHandler{}
+	mUserRepo := &mockUserRepoForTeamHandler{}
+	svc := services.NewTeamService(mTeamRepo, mUserRepo, testutil.NewTestAudit(), 2, log)
+	handler := handlers.NewTeamHandler(svc, log)
+
+	router := setupRouter()
+	router.POST("/team/set-settings", handler.SetTeamSettings)
+
+	t.Run("Success", func(t *testing.T) {
+		three := 3
+		mTeamRepo.On("UpdateTeamSettings", mock.Anything, "team1", mock.Anything, 2).Return(nil)
+		mTeamRepo.On("GetTeamByName", mock.Anything, "team1").Return(&models.Team{
+			Name:     "team1",
+			Settings: &models.TeamSettings{ReviewersCount: &three},
+		}, nil)
+
+		body, _ := json.Marshal(map[string]any{"team_name": "team1", "reviewers_count": 3})
+		req := httptest.NewRequest(http.MethodPost, "/team/set-settings", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]models.Team
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.NotNil(t, resp["team"].Settings)
+		assert.Equal(t, 3, *resp["team"].Settings.ReviewersCount)
+	})
+
+	t.Run("InvalidCount", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]any{"team_name": "team1", "reviewers_count": 0})
+		req := httptest.NewRequest(http.MethodPost, "/team/set-settings", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("TeamNotFound", func(t *testing.T) {
+		mTeamRepo.On("GetTeamByName", mock.Anything, "unknown-team").
+			Return(nil, apperrors.ErrNotFound)
+
+		body, _ := json.Marshal(map[string]any{"team_name": "unknown-team", "reviewers_count": nil})
+		req := httptest.NewRequest(http.MethodPost, "/team/set-settings", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestTeamHandler_SetFallbackTeams(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mTeamRepo := &mockTeamRepoForHandler{}
+	svc := services.NewTeamService(mTeamRepo, &mockUserRepoForTeamHandler{}, testutil.NewTestAudit(), 2, log)
+	handler := handlers.NewTeamHandler(svc, log)
+
+	router := setupRouter()
+	router.POST("/team/set-fallbacks", handler.SetFallbackTeams)
+
+	t.Run("Success", func(t *testing.T) {
+		mTeamRepo.On("SetFallbackTeams", mock.Anything, "team1", []string{"team2"}).Return(nil)
+		mTeamRepo.On("GetTeamByName", mock.Anything, "team1").
+			Return(&models.Team{Name: "team1", FallbackTeams: []string{"team2"}}, nil)
+
+		body, _ := json.Marshal(map[string]any{"team_name": "team1", "fallback_teams": []string{"team2"}})
+		req := httptest.NewRequest(http.MethodPost, "/team/set-fallbacks", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]models.Team
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, []string{"team2"}, resp["team"].FallbackTeams)
+	})
+
+	t.Run("SelfFallback", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]any{"team_name": "team1", "fallback_teams": []string{"team1"}})
+		req := httptest.NewRequest(http.MethodPost, "/team/set-fallbacks", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("UnknownFallbackTeam", func(t *testing.T) {
+		mTeamRepo.On("SetFallbackTeams", mock.Anything, "team1", []string{"ghost"}).Return(apperrors.ErrNotFound)
+
+		body, _ := json.Marshal(map[string]any{"team_name": "team1", "fallback_teams": []string{"ghost"}})
+		req := httptest.NewRequest(http.MethodPost, "/team/set-fallbacks", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -5,15 +5,18 @@ import (
 	"context"
 	"encoding/json"
 	"log/slog"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/handlers"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -41,6 +44,16 @@ func (m *mockUserRepoForUserHandler) UpdateUserActive(ctx context.Context, id st
 	return args.Error(0)
 }
 
+func (m *mockUserRepoForUserHandler) UpdateUserMaxOpenReviews(ctx context.Context, id string, limit *int) error {
+	args := m.Called(ctx, id, limit)
+	return args.Error(0)
+}
+
+func (m *mockUserRepoForUserHandler) UpdateUserEmail(ctx context.Context, id, email string, digest bool) error {
+	args := m.Called(ctx, id, email, digest)
+	return args.Error(0)
+}
+
 func (m *mockUserRepoForUserHandler) GetActiveUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
 	args := m.Called(ctx, teamName)
 	if args.Get(0) == nil {
@@ -49,6 +62,16 @@ func (m *mockUserRepoForUserHandler) GetActiveUsersByTeam(ctx context.Context, t
 	return args.Get(0).([]models.User), args.Error(1)
 }
 
+func (m *mockUserRepoForUserHandler) GetAvailableUsersByTeam(
+	ctx context.Context, teamName string,
+) ([]models.User, error) {
+	args := m.Called(ctx, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
 func (m *mockUserRepoForUserHandler) GetTeamNameByUserID(ctx context.Context, userID string) (string, error) {
 	args := m.Called(ctx, userID)
 	return args.String(0), args.Error(1)
@@ -64,96 +87,168 @@ type mockPRRepoForUserHandler struct {
 	repository.PRRepository
 }
 
-func (m *mockPRRepoForUserHandler) CreatePR(ctx context.Context, pr *models.PullRequest) error {
-	args := m.Called(ctx, pr)
+func (m *mockPRRepoForUserHandler) CreatePR(
+	ctx context.Context,
+	pr *models.PullRequest,
+	change models.AssignmentChange,
+) error {
+	args := m.Called(ctx, pr, change)
 	return args.Error(0)
 }
 
-func (m *mockPRRepoForUserHandler) GetPRByID(ctx context.Context, id string) (*models.PullRequest, error) {
-	args := m.Called(ctx, id)
+func (m *mockPRRepoForUserHandler) GetPRByID(ctx context.Context, repository, id string) (*models.PullRequest, error) {
+	args := m.Called(ctx, repository, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.PullRequest), args.Error(1)
 }
 
-func (m *mockPRRepoForUserHandler) UpdatePR(ctx context.Context, pr *models.PullRequest) error {
-	args := m.Called(ctx, pr)
+func (m *mockPRRepoForUserHandler) UpdatePR(
+	ctx context.Context,
+	pr *models.PullRequest,
+	change models.AssignmentChange,
+) error {
+	args := m.Called(ctx, pr, change)
 	return args.Error(0)
 }
 
-func (m *mockPRRepoForUserHandler) MergePR(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *mockPRRepoForUserHandler) MergePR(ctx context.Context, repository, id string) error {
+	args := m.Called(ctx, repository, id)
 	return args.Error(0)
 }
 
 func (m *mockPRRepoForUserHandler) GetPRsForUser(
-	ctx context.Context, userID string,
+	ctx context.Context, userID, repository string, pendingOnly bool,
 ) ([]models.PullRequestShort, error) {
-	args := m.Called(ctx, userID)
+	args := m.Called(ctx, userID, repository, pendingOnly)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.PullRequestShort), args.Error(1)
 }
 
-func (m *mockPRRepoForUserHandler) ExistsPR(ctx context.Context, id string) (bool, error) {
-	args := m.Called(ctx, id)
+func (m *mockPRRepoForUserHandler) ExistsPR(ctx context.Context, repository, id string) (bool, error) {
+	args := m.Called(ctx, repository, id)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *mockPRRepoForUserHandler) GetTotalPRs(ctx context.Context) (int, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForUserHandler) GetTotalPRs(ctx context.Context, repository string) (int, error) {
+	args := m.Called(ctx, repository)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *mockPRRepoForUserHandler) GetPrsByStatus(ctx context.Context) (int, int, error) {
-	args := m.Called(ctx)
-	return args.Int(0), args.Int(1), args.Error(2)
+func (m *mockPRRepoForUserHandler) GetPrsByStatus(ctx context.Context, repository string) (models.PrsStatus, error) {
+	args := m.Called(ctx, repository)
+	return args.Get(0).(models.PrsStatus), args.Error(1)
 }
 
-func (m *mockPRRepoForUserHandler) GetAssignmentsPerUser(ctx context.Context) ([]models.UserAssignment, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForUserHandler) GetAssignmentsPerUser(
+	ctx context.Context, repository string,
+) ([]models.UserAssignment, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.UserAssignment), args.Error(1)
 }
 
-func (m *mockPRRepoForUserHandler) GetTopReviewers(ctx context.Context) ([]models.UserAssignment, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForUserHandler) GetTopReviewers(
+	ctx context.Context, repository string,
+) ([]models.UserAssignment, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.UserAssignment), args.Error(1)
 }
 
-func (m *mockPRRepoForUserHandler) GetAvgCloseTime(ctx context.Context) (float64, int, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForUserHandler) GetAvgCloseTime(ctx context.Context, repository string) (float64, int, error) {
+	args := m.Called(ctx, repository)
 	return args.Get(0).(float64), args.Int(1), args.Error(2)
 }
 
-func (m *mockPRRepoForUserHandler) GetIdleUsersPerTeam(ctx context.Context) ([]models.TeamMetric, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForUserHandler) GetIdleUsersPerTeam(
+	ctx context.Context, repository string,
+) ([]models.TeamMetric, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.TeamMetric), args.Error(1)
 }
 
-func (m *mockPRRepoForUserHandler) GetNeedyPRsPerTeam(ctx context.Context) ([]models.TeamMetric, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForUserHandler) GetNeedyPRsPerTeam(
+	ctx context.Context, repository string,
+) ([]models.TeamMetric, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.TeamMetric), args.Error(1)
 }
 
+type mockAbsenceRepoForHandler struct {
+	mock.Mock
+	repository.AbsenceRepository
+}
+
+func (m *mockAbsenceRepoForHandler) CreateAbsence(ctx context.Context, absence *models.Absence) error {
+	args := m.Called(ctx, absence)
+	return args.Error(0)
+}
+
+func (m *mockAbsenceRepoForHandler) GetAbsencesByUser(ctx context.Context, userID string) ([]models.Absence, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Absence), args.Error(1)
+}
+
+func (m *mockAbsenceRepoForHandler) DeleteAbsence(ctx context.Context, id int64) (*models.Absence, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Absence), args.Error(1)
+}
+
+func (m *mockAbsenceRepoForHandler) GetStartedUnprocessedAbsences(ctx context.Context) ([]models.Absence, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Absence), args.Error(1)
+}
+
+func (m *mockAbsenceRepoForHandler) MarkAbsenceProcessed(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockAbsenceRepoForHandler) UpsertAbsenceByExternalUID(
+	ctx context.Context, absence *models.Absence,
+) (bool, error) {
+	args := m.Called(ctx, absence)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockAbsenceRepoForHandler) DeleteAbsenceByExternalUID(
+	ctx context.Context, userID, externalUID string,
+) (bool, error) {
+	args := m.Called(ctx, userID, externalUID)
+	return args.Bool(0), args.Error(1)
+}
+
 func TestUserHandler_SetUserActive(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mUserRepo := &mockUserRepoForUserHandler{}
 	mPRRepo := &mockPRRepoForUserHandler{}
-	svc := services.NewUserService(mUserRepo, mPRRepo, log)
+	svc := services.NewUserService(
+		mUserRepo, mPRRepo, &mockTeamRepoForHandler{}, &mockAbsenceRepoForHandler{}, &mockRepositoryRepoForHandler{},
+		testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 	handler := handlers.NewUserHandler(svc, log)
 
 	t.Run("Success_Activate", func(t *testing.T) {
@@ -196,18 +291,129 @@ func TestUserHandler_SetUserActive(t *testing.T) {
 	})
 }
 
+func TestUserHandler_SetMaxOpenReviews(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mUserRepo := &mockUserRepoForUserHandler{}
+	mPRRepo := &mockPRRepoForUserHandler{}
+	svc := services.NewUserService(
+		mUserRepo, mPRRepo, &mockTeamRepoForHandler{}, &mockAbsenceRepoForHandler{}, &mockRepositoryRepoForHandler{},
+		testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
+	handler := handlers.NewUserHandler(svc, log)
+
+	router := setupRouter()
+	router.POST("/users/setMaxOpenReviews", handler.SetMaxOpenReviews)
+
+	t.Run("Success", func(t *testing.T) {
+		limit := 3
+		mUserRepo.On("UpdateUserMaxOpenReviews", mock.Anything, "u1", &limit).Return(nil)
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1", MaxOpenReviews: &limit}, nil)
+
+		body, _ := json.Marshal(map[string]any{"user_id": "u1", "max_open_reviews": 3})
+		req := httptest.NewRequest(http.MethodPost, "/users/setMaxOpenReviews", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"max_open_reviews":3`)
+		mUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("InvalidLimit", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]any{"user_id": "u1", "max_open_reviews": -1})
+		req := httptest.NewRequest(http.MethodPost, "/users/setMaxOpenReviews", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("UserNotFound", func(t *testing.T) {
+		mUserRepo.On("GetUserByID", mock.Anything, "u-nonexist").Return(nil, apperrors.ErrNotFound)
+
+		body, _ := json.Marshal(map[string]any{"user_id": "u-nonexist", "max_open_reviews": nil})
+		req := httptest.NewRequest(http.MethodPost, "/users/setMaxOpenReviews", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestUserHandler_SetEmail(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mUserRepo := &mockUserRepoForUserHandler{}
+	svc := services.NewUserService(
+		mUserRepo, &mockPRRepoForUserHandler{}, &mockTeamRepoForHandler{}, &mockAbsenceRepoForHandler{},
+		&mockRepositoryRepoForHandler{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+	handler := handlers.NewUserHandler(svc, log)
+
+	router := setupRouter()
+	router.POST("/users/setEmail", handler.SetEmail)
+
+	t.Run("Success", func(t *testing.T) {
+		mUserRepo.On("UpdateUserEmail", mock.Anything, "u1", "alice@example.com", true).Return(nil)
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").
+			Return(&models.User{ID: "u1", Email: "alice@example.com", EmailDigest: true}, nil)
+
+		body, _ := json.Marshal(map[string]any{"user_id": "u1", "email": "alice@example.com", "digest": true})
+		req := httptest.NewRequest(http.MethodPost, "/users/setEmail", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"email":"alice@example.com","email_digest":true`)
+		mUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("InvalidEmail", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]any{"user_id": "u1", "email": "alice"})
+		req := httptest.NewRequest(http.MethodPost, "/users/setEmail", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("UserNotFound", func(t *testing.T) {
+		mUserRepo.On("GetUserByID", mock.Anything, "u-nonexist").Return(nil, apperrors.ErrNotFound)
+
+		body, _ := json.Marshal(map[string]any{"user_id": "u-nonexist", "email": "x@example.com"})
+		req := httptest.NewRequest(http.MethodPost, "/users/setEmail", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
 func TestUserHandler_GetPRsForUser(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mUserRepo := &mockUserRepoForUserHandler{}
 	mPRRepo := &mockPRRepoForUserHandler{}
-	svc := services.NewUserService(mUserRepo, mPRRepo, log)
+	svc := services.NewUserService(
+		mUserRepo, mPRRepo, &mockTeamRepoForHandler{}, &mockAbsenceRepoForHandler{}, &mockRepositoryRepoForHandler{},
+		testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 	handler := handlers.NewUserHandler(svc, log)
 
 	t.Run("Success", func(t *testing.T) {
 		prs := []models.PullRequestShort{
 			{ID: "pr-1", Title: "PR1", Status: "OPEN"},
 		}
-		mPRRepo.On("GetPRsForUser", mock.Anything, "u1").Return(prs, nil)
+		mPRRepo.On("GetPRsForUser", mock.Anything, "u1", "", false).Return(prs, nil)
 
 		router := setupRouter()
 		router.GET("/users/getReview", handler.GetPRsForUser)
@@ -221,6 +427,36 @@ func TestUserHandler_GetPRsForUser(t *testing.T) {
 		mPRRepo.AssertExpectations(t)
 	})
 
+	t.Run("PendingOnly", func(t *testing.T) {
+		prs := []models.PullRequestShort{
+			{ID: "pr-2", Title: "PR2", Status: "OPEN", ReviewState: models.ReviewStatePending},
+		}
+		mPRRepo.On("GetPRsForUser", mock.Anything, "u1", "", true).Return(prs, nil)
+
+		router := setupRouter()
+		router.GET("/users/getReview", handler.GetPRsForUser)
+
+		req := httptest.NewRequest(http.MethodGet, "/users/getReview?user_id=u1&pending=true", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"review_state":"PENDING"`)
+	})
+
+	t.Run("InvalidInput_Pending", func(t *testing.T) {
+		router := setupRouter()
+		router.GET("/users/getReview", handler.GetPRsForUser)
+
+		req := httptest.NewRequest(http.MethodGet, "/users/getReview?user_id=u1&pending=maybe", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
 	t.Run("InvalidInput_MissingUserID", func(t *testing.T) {
 		router := setupRouter()
 		router.GET("/users/getReview", handler.GetPRsForUser)
@@ -233,3 +469,163 @@ func TestUserHandler_GetPRsForUser(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 }
+
+func TestUserHandler_Absences(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mUserRepo := &mockUserRepoForUserHandler{}
+	mAbsenceRepo := &mockAbsenceRepoForHandler{}
+	svc := services.NewUserService(
+		mUserRepo, &mockPRRepoForUserHandler{}, &mockTeamRepoForHandler{}, mAbsenceRepo,
+		&mockRepositoryRepoForHandler{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+	handler := handlers.NewUserHandler(svc, log)
+
+	router := setupRouter()
+	router.POST("/users/addAbsence", handler.AddAbsence)
+	router.GET("/users/getAbsences", handler.GetAbsences)
+	router.POST("/users/deleteAbsence", handler.DeleteAbsence)
+
+	t.Run("AddAbsence_Success", func(t *testing.T) {
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1"}, nil)
+		mAbsenceRepo.On("CreateAbsence", mock.Anything, mock.AnythingOfType("*models.Absence")).Return(nil)
+
+		body, _ := json.Marshal(map[string]any{
+			"user_id":   "u1",
+			"starts_at": "2026-07-01T00:00:00Z",
+			"ends_at":   "2026-07-15T00:00:00Z",
+			"reason":    "vacation",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/users/addAbsence", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"reason":"vacation"`)
+	})
+
+	t.Run("AddAbsence_EndBeforeStart", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]any{
+			"user_id":   "u1",
+			"starts_at": "2026-07-15T00:00:00Z",
+			"ends_at":   "2026-07-01T00:00:00Z",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/users/addAbsence", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("GetAbsences_Success", func(t *testing.T) {
+		mAbsenceRepo.On("GetAbsencesByUser", mock.Anything, "u1").Return([]models.Absence{
+			{ID: 1, UserID: "u1"},
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/users/getAbsences?user_id=u1", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"absence_id":1`)
+	})
+
+	t.Run("GetAbsences_MissingUserID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/getAbsences", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("DeleteAbsence_NotFound", func(t *testing.T) {
+		mAbsenceRepo.On("DeleteAbsence", mock.Anything, int64(99)).Return(nil, apperrors.ErrNotFound)
+
+		body, _ := json.Marshal(map[string]any{"absence_id": 99})
+		req := httptest.NewRequest(http.MethodPost, "/users/deleteAbsence", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func newICSUpload(t *testing.T, fields map[string]string, calendar string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for k, v := range fields {
+		_ = writer.WriteField(k, v)
+	}
+	if calendar != "" {
+		part, err := writer.CreateFormFile("file", "absences.ics")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = part.Write([]byte(calendar))
+	}
+	_ = writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/users/importAbsences", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestUserHandler_ImportAbsences(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mUserRepo := &mockUserRepoForUserHandler{}
+	mAbsenceRepo := &mockAbsenceRepoForHandler{}
+	svc := services.NewUserService(
+		mUserRepo, &mockPRRepoForUserHandler{}, &mockTeamRepoForHandler{}, mAbsenceRepo,
+		&mockRepositoryRepoForHandler{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+	handler := handlers.NewUserHandler(svc, log)
+
+	router := setupRouter()
+	router.POST("/users/importAbsences", handler.ImportAbsences)
+
+	calendar := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:vac-1\r\nSUMMARY:Vacation\r\n" +
+		"DTSTART;VALUE=DATE:20260701\r\nDTEND;VALUE=DATE:20260715\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nSUMMARY:No uid\r\nDTSTART:20260801T090000Z\r\nDTEND:20260801T180000Z\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	t.Run("Success", func(t *testing.T) {
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1"}, nil)
+		mAbsenceRepo.On("UpsertAbsenceByExternalUID", mock.Anything, mock.AnythingOfType("*models.Absence")).
+			Return(true, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newICSUpload(t, map[string]string{"user_id": "u1"}, calendar))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"created":1`)
+		assert.Contains(t, w.Body.String(), `"reason":"event has no UID"`)
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newICSUpload(t, map[string]string{"user_id": "u1"}, ""))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("UserAndTeamBothSet", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newICSUpload(t, map[string]string{"user_id": "u1", "team_name": "team1"}, calendar))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("NotACalendar", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newICSUpload(t, map[string]string{"user_id": "u1"}, "hello"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
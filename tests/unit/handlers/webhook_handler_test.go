@@ -0,0 +1,247 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/github"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/gitlab"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/handlers"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// accountStore keeps linked accounts in memory by provider and login.
+type accountStore map[[2]string]models.Account
+
+func (s accountStore) UpsertAccount(_ context.Context, account *models.Account) error {
+	s[[2]string{account.Provider, account.Login}] = *account
+	return nil
+}
+
+func (s accountStore) DeleteAccount(_ context.Context, provider, login, userID string) (*models.Account, error) {
+	a, ok := s[[2]string{provider, login}]
+	if !ok || a.UserID != userID {
+		return nil, apperrors.ErrNotFound
+	}
+	delete(s, [2]string{provider, login})
+	return &a, nil
+}
+
+func (s accountStore) GetAccount(_ context.Context, provider, login string) (*models.Account, error) {
+	a, ok := s[[2]string{provider, login}]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	return &a, nil
+}
+
+func (s accountStore) ListAccounts(_ context.Context, userID string) ([]models.Account, error) {
+	accounts := []models.Account{}
+	for _, a := range s {
+		if a.UserID == userID {
+			accounts = append(accounts, a)
+		}
+	}
+	return accounts, nil
+}
+
+// mergedPRs serves PRs that are all merged already.
+type mergedPRs struct {
+	services.PRServiceInterface
+}
+
+func (mergedPRs) GetPR(_ context.Context, _, _ string) (*models.PullRequest, error) {
+	return nil, apperrors.ErrNotFound
+}
+
+func (mergedPRs) CreatePR(_ context.Context, pr *models.PullRequest) (*models.PullRequest, error) {
+	pr.Status = "OPEN"
+	return pr, nil
+}
+
+func (mergedPRs) ClosePR(_ context.Context, _, _ string) (*models.PullRequest, error) {
+	return nil, apperrors.ErrPRMerged
+}
+
+func TestWebhookHandler_GitHub(t *testing.T) {
+	const secret = "webhook-secret"
+	log := slog.New(slog.DiscardHandler)
+	accounts := accountStore{}
+	accountSvc := services.NewAccountService(accounts, &mockUserRepoForHandler{}, testutil.NewTestAudit(), log)
+	handler := handlers.NewWebhookHandler(services.NewWebhookService(mergedPRs{}, accountSvc, secret, "", log), log)
+
+	router := setupRouter()
+	router.POST("/webhooks/github", handler.GitHub)
+
+	deliver := func(event, fixture string, sign bool) *httptest.ResponseRecorder {
+		body := testutil.Fixture(t, "github", fixture)
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/github", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(github.EventHeader, event)
+		req.Header.Set(github.DeliveryHeader, "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+		if sign {
+			req.Header.Set(github.SignatureHeader, "sha256="+hex.EncodeToString(github.Sign([]byte(secret), body)))
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("UnlinkedAuthor", func(t *testing.T) {
+		w := deliver(github.EventPullRequest, "pull_request_opened.json", true)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"status": "ignored", "reason": "PR author has no linked account"}`, w.Body.String())
+	})
+
+	t.Run("Opened", func(t *testing.T) {
+		accounts[[2]string{models.ProviderGitHub, "octocat"}] = models.Account{
+			Provider: models.ProviderGitHub, Login: "octocat", UserID: "u1",
+		}
+
+		w := deliver(github.EventPullRequest, "pull_request_opened.json", true)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"status": "applied",
+			"pr": {
+				"pull_request_id": "42",
+				"pull_request_name": "Add rate limiting to the public API",
+				"author_id": "u1",
+				"status": "OPEN",
+				"repository": "acme/api"
+			}
+		}`, w.Body.String())
+	})
+
+	t.Run("Unsigned", func(t *testing.T) {
+		w := deliver(github.EventPullRequest, "pull_request_opened.json", false)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), handlers.ErrorCodeUnauthorized)
+	})
+
+	t.Run("Ping", func(t *testing.T) {
+		w := deliver(github.EventPing, "ping.json", true)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"ignored"`)
+	})
+
+	t.Run("CloseMergedPR", func(t *testing.T) {
+		w := deliver(github.EventPullRequest, "pull_request_closed.json", true)
+		assert.Equal(t, http.StatusOK, w.Code, "a redelivery cannot succeed, so it is not failed")
+		assert.JSONEq(t, `{"status": "ignored", "reason": "PR already merged"}`, w.Body.String())
+	})
+}
+
+func TestWebhookHandler_GitLab(t *testing.T) {
+	const token = "gitlab-token"
+	log := slog.New(slog.DiscardHandler)
+	accounts := accountStore{
+		{models.ProviderGitLab, "jane.doe"}: {Provider: models.ProviderGitLab, Login: "jane.doe", UserID: "u2"},
+	}
+	accountSvc := services.NewAccountService(accounts, &mockUserRepoForHandler{}, testutil.NewTestAudit(), log)
+	handler := handlers.NewWebhookHandler(services.NewWebhookService(mergedPRs{}, accountSvc, "", token, log), log)
+
+	router := setupRouter()
+	router.POST("/webhooks/gitlab", handler.GitLab)
+
+	deliver := func(event, fixture, token string) *httptest.ResponseRecorder {
+		body := testutil.Fixture(t, "gitlab", fixture)
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/gitlab", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(gitlab.EventHeader, event)
+		req.Header.Set(gitlab.EventUUIDHeader, "0b5b7d7c-5c0e-4b0b-9b3a-7d0f5d1b6c2e")
+		req.Header.Set(gitlab.TokenHeader, token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Open", func(t *testing.T) {
+		w := deliver(gitlab.EventMergeRequest, "merge_request_open.json", token)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"status": "applied",
+			"pr": {
+				"pull_request_id": "17",
+				"pull_request_name": "Migrate checkout to the new payments API",
+				"author_id": "u2",
+				"status": "OPEN",
+				"repository": "acme/web",
+				"draft": true
+			}
+		}`, w.Body.String())
+	})
+
+	t.Run("WrongToken", func(t *testing.T) {
+		w := deliver(gitlab.EventMergeRequest, "merge_request_open.json", "github-secret")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), handlers.ErrorCodeUnauthorized)
+	})
+
+	t.Run("UnknownEvent", func(t *testing.T) {
+		w := deliver("Note Hook", "merge_request_open.json", token)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"ignored"`)
+	})
+
+	t.Run("CloseMergedPR", func(t *testing.T) {
+		w := deliver(gitlab.EventMergeRequest, "merge_request_close.json", token)
+		assert.Equal(t, http.StatusOK, w.Code, "a redelivery cannot succeed, so it is not failed")
+		assert.JSONEq(t, `{"status": "ignored", "reason": "PR already merged"}`, w.Body.String())
+	})
+}
+
+func TestAccountHandler(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+	userRepo := &mockUserRepoForHandler{}
+	userRepo.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1"}, nil)
+	handler := handlers.NewAccountHandler(
+		services.NewAccountService(accountStore{}, userRepo, testutil.NewTestAudit(), log), log)
+
+	router := setupRouter()
+	router.POST("/users/linkAccount", handler.LinkAccount)
+	router.POST("/users/unlinkAccount", handler.UnlinkAccount)
+	router.GET("/users/getAccounts", handler.GetAccounts)
+
+	send := func(method, path, payload string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(payload))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	const octocat = `{"user_id": "u1", "provider": "github", "login": "octocat"}`
+
+	t.Run("Link", func(t *testing.T) {
+		w := send(http.MethodPost, "/users/linkAccount", `{"user_id": "u1", "provider": "github", "login": "Octocat"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"login":"octocat"`)
+
+		w = send(http.MethodGet, "/users/getAccounts?user_id=u1", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"provider":"github"`)
+	})
+
+	t.Run("UnknownProvider", func(t *testing.T) {
+		w := send(http.MethodPost, "/users/linkAccount", `{"user_id": "u1", "provider": "svn", "login": "octocat"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Unlink", func(t *testing.T) {
+		w := send(http.MethodPost, "/users/unlinkAccount", octocat)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = send(http.MethodPost, "/users/unlinkAccount", octocat)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
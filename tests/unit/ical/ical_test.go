@@ -0,0 +1,145 @@
+package ical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/ical"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, lines ...string) []ical.Event {
+	t.Helper()
+	events, err := ical.Parse(strings.NewReader(strings.Join(lines, "\r\n")))
+	require.NoError(t, err)
+	return events
+}
+
+func TestParse(t *testing.T) {
+	t.Run("AllDayEvent", func(t *testing.T) {
+		events := parse(t,
+			"BEGIN:VCALENDAR",
+			"VERSION:2.0",
+			"BEGIN:VEVENT",
+			"UID:vac-1@hr.example.com",
+			"SUMMARY:Vacation\\, Alice",
+			"DTSTART;VALUE=DATE:20260701",
+			"DTEND;VALUE=DATE:20260715",
+			"END:VEVENT",
+			"END:VCALENDAR",
+		)
+
+		require.Len(t, events, 1)
+		e := events[0]
+		assert.Equal(t, "vac-1@hr.example.com", e.UID)
+		assert.Equal(t, "Vacation, Alice", e.Summary)
+		assert.True(t, e.AllDay)
+		assert.Equal(t, time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), e.Start)
+		assert.Equal(t, time.Date(2026, 7, 15, 0, 0, 0, 0, time.UTC), e.End)
+	})
+
+	t.Run("SingleDayWithoutEnd", func(t *testing.T) {
+		events := parse(t,
+			"BEGIN:VCALENDAR",
+			"BEGIN:VEVENT",
+			"UID:1",
+			"DTSTART;VALUE=DATE:20260701",
+			"END:VEVENT",
+			"END:VCALENDAR",
+		)
+
+		require.Len(t, events, 1)
+		assert.Equal(t, time.Date(2026, 7, 2, 0, 0, 0, 0, time.UTC), events[0].End)
+	})
+
+	t.Run("TimeZonesAndDuration", func(t *testing.T) {
+		events := parse(t,
+			"BEGIN:VCALENDAR",
+			"BEGIN:VEVENT",
+			"UID:utc",
+			"DTSTART:20260701T090000Z",
+			"DURATION:P1DT2H",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"UID:berlin",
+			"DTSTART;TZID=Europe/Berlin:20260701T090000",
+			"DTEND;TZID=Europe/Berlin:20260701T180000",
+			"END:VEVENT",
+			"END:VCALENDAR",
+		)
+
+		require.Len(t, events, 2)
+		assert.Equal(t, time.Date(2026, 7, 2, 11, 0, 0, 0, time.UTC), events[0].End.UTC())
+		assert.Equal(t, time.Date(2026, 7, 1, 7, 0, 0, 0, time.UTC), events[1].Start.UTC())
+		assert.False(t, events[1].AllDay)
+	})
+
+	t.Run("FoldedLinesAndParticipants", func(t *testing.T) {
+		events := parse(t,
+			"BEGIN:VCALENDAR",
+			"BEGIN:VEVENT",
+			"UID:1",
+			"SUMMARY:Long",
+			"  summary",
+			"ORGANIZER;CN=\"HR; Team\":mailto:hr@example.com",
+			"ATTENDEE;ROLE=REQ-PARTICIPANT;CN=Alice:MAILTO:Alice@Example.com",
+			"DTSTART:20260701T090000Z",
+			"DTEND:20260701T100000Z",
+			"STATUS:CANCELLED",
+			"END:VEVENT",
+			"END:VCALENDAR",
+		)
+
+		require.Len(t, events, 1)
+		e := events[0]
+		assert.Equal(t, "Long summary", e.Summary)
+		assert.True(t, e.Cancelled())
+		assert.Equal(t, []ical.Contact{
+			{Name: "HR; Team", Email: "hr@example.com"},
+			{Name: "Alice", Email: "alice@example.com"},
+		}, e.Contacts())
+	})
+
+	t.Run("IgnoresNestedComponents", func(t *testing.T) {
+		events := parse(t,
+			"BEGIN:VCALENDAR",
+			"BEGIN:VTIMEZONE",
+			"TZID:Europe/Berlin",
+			"END:VTIMEZONE",
+			"BEGIN:VEVENT",
+			"UID:1",
+			"DTSTART:20260701T090000Z",
+			"DTEND:20260701T100000Z",
+			"BEGIN:VALARM",
+			"DESCRIPTION:Reminder",
+			"UID:alarm",
+			"END:VALARM",
+			"END:VEVENT",
+			"END:VCALENDAR",
+		)
+
+		require.Len(t, events, 1)
+		assert.Equal(t, "1", events[0].UID)
+	})
+
+	t.Run("InvalidDateLeavesZeroStart", func(t *testing.T) {
+		events := parse(t,
+			"BEGIN:VCALENDAR",
+			"BEGIN:VEVENT",
+			"UID:1",
+			"DTSTART:tomorrow",
+			"END:VEVENT",
+			"END:VCALENDAR",
+		)
+
+		require.Len(t, events, 1)
+		assert.True(t, events[0].Start.IsZero())
+	})
+
+	t.Run("NotACalendar", func(t *testing.T) {
+		_, err := ical.Parse(strings.NewReader("hello"))
+		assert.ErrorIs(t, err, ical.ErrInvalidCalendar)
+	})
+}
@@ -0,0 +1,295 @@
+package jwt_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	rsaKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _  = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+)
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// rsaJWK returns the public JWK of key.
+func rsaJWK(kid string, key *rsa.PrivateKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA", "kid": kid, "use": "sig",
+		"n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// ecJWK returns the public JWK of key.
+func ecJWK(kid string, key *ecdsa.PrivateKey) map[string]string {
+	return map[string]string{
+		"kty": "EC", "kid": kid, "crv": "P-256",
+		"x": b64(key.X.FillBytes(make([]byte, 32))), "y": b64(key.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+func keySetJSON(t *testing.T, keys ...map[string]string) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	require.NoError(t, err)
+	return data
+}
+
+// sign builds a compact JWS over claims with the given alg and kid.
+func sign(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]any) string {
+	t.Helper()
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signingInput := b64(header) + "." + b64(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var sig []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, signErr := ecdsa.Sign(rand.Reader, k, digest[:])
+		require.NoError(t, signErr)
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signingInput + "." + b64(sig)
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub": "alice",
+		"iss": "https://sso.example.com",
+		"aud": "pr-service",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+// staticKeys serves a fixed key set.
+type staticKeys struct {
+	set *jwt.KeySet
+}
+
+func (k staticKeys) Key(_ context.Context, kid string) (crypto.PublicKey, error) {
+	key, ok := k.set.Key(kid)
+	if !ok {
+		return nil, jwt.ErrInvalidToken
+	}
+	return key, nil
+}
+
+func newVerifier(t *testing.T) *jwt.Verifier {
+	t.Helper()
+	set, err := jwt.ParseKeySet(keySetJSON(t, rsaJWK("rsa-1", rsaKey), ecJWK("ec-1", ecKey)))
+	require.NoError(t, err)
+	return jwt.NewVerifier(staticKeys{set: set}, "https://sso.example.com", "pr-service")
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	verifier := newVerifier(t)
+	ctx := context.Background()
+
+	t.Run("RS256", func(t *testing.T) {
+		claims, err := verifier.Verify(ctx, sign(t, jwt.RS256, "rsa-1", rsaKey, validClaims()))
+		require.NoError(t, err)
+		assert.Equal(t, "alice", claims.String("sub"))
+	})
+
+	t.Run("ES256", func(t *testing.T) {
+		claims := validClaims()
+		claims["aud"] = []string{"other", "pr-service"}
+		claims["groups"] = []string{"backend", "sre"}
+
+		got, err := verifier.Verify(ctx, sign(t, jwt.ES256, "ec-1", ecKey, claims))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"backend", "sre"}, got.Strings("groups"))
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		otherRSA, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		with := func(name string, value any) map[string]any {
+			claims := validClaims()
+			if value == nil {
+				delete(claims, name)
+			} else {
+				claims[name] = value
+			}
+			return claims
+		}
+
+		for name, token := range map[string]string{
+			"Expired":       sign(t, jwt.RS256, "rsa-1", rsaKey, with("exp", time.Now().Add(-time.Hour).Unix())),
+			"MissingExp":    sign(t, jwt.RS256, "rsa-1", rsaKey, with("exp", nil)),
+			"NotYetValid":   sign(t, jwt.RS256, "rsa-1", rsaKey, with("nbf", time.Now().Add(time.Hour).Unix())),
+			"WrongIssuer":   sign(t, jwt.RS256, "rsa-1", rsaKey, with("iss", "https://evil.example.com")),
+			"WrongAudience": sign(t, jwt.RS256, "rsa-1", rsaKey, with("aud", "other")),
+			"NoAudience":    sign(t, jwt.RS256, "rsa-1", rsaKey, with("aud", nil)),
+			"NoIssuer":      sign(t, jwt.RS256, "rsa-1", rsaKey, with("iss", nil)),
+			"BadSignature":  sign(t, jwt.RS256, "rsa-1", otherRSA, validClaims()),
+			"KeyTypeMixup":  sign(t, jwt.ES256, "rsa-1", ecKey, validClaims()),
+			"UnknownKid":    sign(t, jwt.RS256, "rsa-2", rsaKey, validClaims()),
+			"NoneAlg":       b64([]byte(`{"alg":"none"}`)) + "." + b64([]byte(`{"sub":"alice"}`)) + ".",
+			"HS256":         b64([]byte(`{"alg":"HS256"}`)) + "." + b64([]byte(`{"sub":"alice"}`)) + ".c2ln",
+			"Garbage":       "not.a.jwt",
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, verifyErr := verifier.Verify(ctx, token)
+				assert.ErrorIs(t, verifyErr, jwt.ErrInvalidToken)
+			})
+		}
+	})
+
+	t.Run("UnconfiguredVerifierRejects", func(t *testing.T) {
+		set, err := jwt.ParseKeySet(keySetJSON(t, rsaJWK("rsa-1", rsaKey)))
+		require.NoError(t, err)
+		unconfigured := jwt.NewVerifier(staticKeys{set: set}, "", "")
+		claims := validClaims()
+		claims["iss"], claims["aud"] = "", ""
+
+		_, err = unconfigured.Verify(ctx, sign(t, jwt.RS256, "rsa-1", rsaKey, claims))
+		assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+	})
+
+	t.Run("TamperedPayload", func(t *testing.T) {
+		token := sign(t, jwt.RS256, "rsa-1", rsaKey, validClaims())
+		parts := strings.Split(token, ".")
+		claims := validClaims()
+		claims["sub"] = "root"
+		payload, err := json.Marshal(claims)
+		require.NoError(t, err)
+
+		_, err = verifier.Verify(ctx, parts[0]+"."+b64(payload)+"."+parts[2])
+		assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+	})
+}
+
+func TestParseKeySet(t *testing.T) {
+	t.Run("SkipsUnusableKeys", func(t *testing.T) {
+		enc := rsaJWK("enc-1", rsaKey)
+		enc["use"] = "enc"
+		set, err := jwt.ParseKeySet(keySetJSON(t, enc, map[string]string{"kty": "oct", "kid": "hmac"},
+			ecJWK("ec-1", ecKey)))
+		require.NoError(t, err)
+
+		_, ok := set.Key("enc-1")
+		assert.False(t, ok)
+		_, ok = set.Key("ec-1")
+		assert.True(t, ok)
+		_, ok = set.Key("")
+		assert.True(t, ok, "an empty kid matches the only key")
+	})
+
+	smallRSA, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	offCurve := ecJWK("ec-1", ecKey)
+	offCurve["y"] = offCurve["x"]
+	p384 := ecJWK("ec-1", ecKey)
+	p384["crv"] = "P-384"
+
+	for name, data := range map[string][]byte{
+		"NotJSON":  []byte("keys"),
+		"NoKeys":   []byte(`{"keys": []}`),
+		"SmallRSA": keySetJSON(t, rsaJWK("rsa-1", smallRSA)),
+		"OffCurve": keySetJSON(t, offCurve),
+		"P384":     keySetJSON(t, p384),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, parseErr := jwt.ParseKeySet(data)
+			assert.ErrorIs(t, parseErr, jwt.ErrInvalidKeySet)
+		})
+	}
+}
+
+func TestKeySource(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("FileReloadsOnUnknownKid", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "jwks.json")
+		require.NoError(t, os.WriteFile(path, keySetJSON(t, rsaJWK("rsa-1", rsaKey)), 0o600))
+		source := jwt.NewKeySource(path, http.DefaultClient, 0)
+		require.NoError(t, source.Load(ctx))
+
+		require.NoError(t, os.WriteFile(path, keySetJSON(t, ecJWK("ec-2", ecKey)), 0o600))
+		key, err := source.Key(ctx, "ec-2")
+		require.NoError(t, err)
+		assert.IsType(t, &ecdsa.PublicKey{}, key)
+	})
+
+	t.Run("URLReloadIsRateLimited", func(t *testing.T) {
+		var fetches atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fetches.Add(1)
+			_, _ = w.Write(keySetJSON(t, rsaJWK("rsa-1", rsaKey)))
+		}))
+		defer server.Close()
+
+		source := jwt.NewKeySource(server.URL, server.Client(), time.Hour)
+		key, err := source.Key(ctx, "rsa-1")
+		require.NoError(t, err)
+		assert.IsType(t, &rsa.PublicKey{}, key)
+
+		for range 3 {
+			_, err = source.Key(ctx, "rsa-9")
+			assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+		}
+		assert.Equal(t, int32(1), fetches.Load())
+	})
+
+	t.Run("FailedReloadIsRateLimited", func(t *testing.T) {
+		var fetches atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fetches.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		source := jwt.NewKeySource(server.URL, server.Client(), time.Hour)
+		for range 3 {
+			_, err := source.Key(ctx, "rsa-1")
+			assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+		}
+		assert.Equal(t, int32(1), fetches.Load())
+	})
+
+	t.Run("URLError", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		source := jwt.NewKeySource(server.URL, server.Client(), time.Minute)
+		err := source.Load(ctx)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, jwt.ErrInvalidToken)
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		source := jwt.NewKeySource(filepath.Join(t.TempDir(), "missing.json"), http.DefaultClient, time.Minute)
+		assert.Error(t, source.Load(ctx))
+	})
+}
@@ -0,0 +1,47 @@
+package outbound_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/outbound"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256 of "hello" under "secret".
+	assert.Equal(t, "sha256=88aab3ede8d3adf94d26ab90d3bafd4a2083070c3bcce9c014ee04a443847c0b",
+		outbound.Sign([]byte("secret"), []byte("hello")))
+}
+
+func TestVerify(t *testing.T) {
+	secret, body := []byte("secret"), []byte(`{"type":"pr.created"}`)
+
+	assert.True(t, outbound.Verify(secret, body, outbound.Sign(secret, body)))
+
+	for name, header := range map[string]string{
+		"OtherSecret": outbound.Sign([]byte("other"), body),
+		"OtherBody":   outbound.Sign(secret, []byte(`{}`)),
+		"NoPrefix":    outbound.Sign(secret, body)[len("sha256="):],
+		"NotHex":      "sha256=zz",
+		"Missing":     "",
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.False(t, outbound.Verify(secret, body, header))
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	base, limit := time.Second, 10*time.Second
+	for attempt, want := range map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		3:  4 * time.Second,
+		4:  8 * time.Second,
+		5:  10 * time.Second,
+		60: 10 * time.Second,
+	} {
+		assert.Equal(t, want, outbound.Backoff(attempt, base, limit), "attempt %d", attempt)
+	}
+}
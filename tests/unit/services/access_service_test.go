@@ -0,0 +1,224 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type accessFixture struct {
+	svc         *services.AccessService
+	userRepo    *mockUserRepo
+	teamRepo    *mockTeamRepo
+	prRepo      *mockPRRepo
+	absenceRepo *mockAbsenceRepo
+}
+
+func newAccessFixture() *accessFixture {
+	f := &accessFixture{
+		userRepo:    &mockUserRepo{},
+		teamRepo:    &mockTeamRepo{},
+		prRepo:      &mockPRRepo{},
+		absenceRepo: &mockAbsenceRepo{},
+	}
+	f.svc = services.NewAccessService(&testutil.RoleStore{}, f.userRepo, f.teamRepo, f.prRepo, f.absenceRepo,
+		slog.New(slog.DiscardHandler))
+	return f
+}
+
+func principal(userID string, bindings ...models.RoleBinding) *models.Principal {
+	return &models.Principal{UserID: userID, Bindings: bindings}
+}
+
+func TestAccessService_GrantRole(t *testing.T) {
+	t.Run("Global", func(t *testing.T) {
+		f := newAccessFixture()
+
+		binding, err := f.svc.GrantRole(context.Background(), "alice", models.RoleAdmin, "")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", binding.UserID)
+		assert.Empty(t, binding.TeamName)
+		f.teamRepo.AssertNotCalled(t, "GetTeamByName", mock.Anything, mock.Anything)
+
+		p, err := f.svc.Principal(context.Background(), "alice")
+		require.NoError(t, err)
+		assert.Len(t, p.Bindings, 1)
+	})
+
+	t.Run("ScopedToExistingTeam", func(t *testing.T) {
+		f := newAccessFixture()
+		f.teamRepo.On("GetTeamByName", mock.Anything, "team1").Return(&models.Team{Name: "team1"}, nil)
+
+		binding, err := f.svc.GrantRole(context.Background(), "bob", models.RoleTeamLead, "team1")
+		require.NoError(t, err)
+		assert.Equal(t, "team1", binding.TeamName)
+
+		_, err = f.svc.GrantRole(context.Background(), "bob", models.RoleTeamLead, "team1")
+		assert.ErrorIs(t, err, apperrors.ErrRoleExists)
+	})
+
+	t.Run("UnknownTeam", func(t *testing.T) {
+		f := newAccessFixture()
+		f.teamRepo.On("GetTeamByName", mock.Anything, "ghost").Return(nil, apperrors.ErrNotFound)
+
+		_, err := f.svc.GrantRole(context.Background(), "bob", models.RoleTeamLead, "ghost")
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		f := newAccessFixture()
+
+		_, err := f.svc.GrantRole(context.Background(), "bob", "owner", "")
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+		_, err = f.svc.GrantRole(context.Background(), " ", models.RoleMember, "")
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		f := newAccessFixture()
+		_, err := f.svc.GrantRole(context.Background(), "alice", models.RoleBot, "")
+		require.NoError(t, err)
+
+		require.NoError(t, f.svc.RevokeRole(context.Background(), "alice", models.RoleBot, ""))
+		assert.ErrorIs(t, f.svc.RevokeRole(context.Background(), "alice", models.RoleBot, ""), apperrors.ErrNotFound)
+	})
+}
+
+func TestAccessService_Principal(t *testing.T) {
+	store := &testutil.RoleStore{Err: errors.New("db down")}
+	svc := services.NewAccessService(store, &mockUserRepo{}, &mockTeamRepo{}, &mockPRRepo{}, &mockAbsenceRepo{},
+		slog.New(slog.DiscardHandler))
+
+	_, err := svc.Principal(context.Background(), "alice")
+	assert.ErrorIs(t, err, apperrors.ErrInternal)
+}
+
+func TestAccessService_Authorize(t *testing.T) {
+	ctx := context.Background()
+	admin := principal("root", models.RoleBinding{Role: models.RoleAdmin})
+	lead := principal("bob", models.RoleBinding{Role: models.RoleTeamLead, TeamName: "team1"})
+	member := principal("carol", models.RoleBinding{Role: models.RoleMember})
+	bot := principal("ci", models.RoleBinding{Role: models.RoleBot})
+	nobody := principal("mallory")
+
+	t.Run("RolePermissions", func(t *testing.T) {
+		f := newAccessFixture()
+		for _, tc := range []struct {
+			principal *models.Principal
+			perm      string
+			allowed   bool
+		}{
+			{admin, models.PermCreateTeam, true},
+			{admin, models.PermManageRepository, true},
+			{member, models.PermRead, true},
+			{member, models.PermWritePR, true},
+			{member, models.PermMergePR, false},
+			{member, models.PermManageTeam, false},
+			{bot, models.PermRead, true},
+			{bot, models.PermWritePR, false},
+			{nobody, models.PermRead, false},
+			{lead, models.PermRead, true},
+			{lead, models.PermCreateTeam, false},
+			{lead, models.PermForceMerge, false},
+			{admin, models.PermForceMerge, true},
+		} {
+			err := f.svc.Authorize(ctx, tc.principal, tc.perm, models.AccessTarget{})
+			if tc.allowed {
+				assert.NoError(t, err, "%s %s", tc.principal.UserID, tc.perm)
+			} else {
+				assert.ErrorIs(t, err, apperrors.ErrForbidden, "%s %s", tc.principal.UserID, tc.perm)
+			}
+		}
+	})
+
+	t.Run("TeamLeadOwnTeam", func(t *testing.T) {
+		f := newAccessFixture()
+		err := f.svc.Authorize(ctx, lead, models.PermManageTeam, models.AccessTarget{TeamName: "team1"})
+		assert.NoError(t, err)
+
+		err = f.svc.Authorize(ctx, lead, models.PermManageTeam, models.AccessTarget{TeamName: "team2"})
+		assert.ErrorIs(t, err, apperrors.ErrForbidden)
+	})
+
+	t.Run("TeamLeadUserOfTeam", func(t *testing.T) {
+		f := newAccessFixture()
+		f.userRepo.On("GetTeamNameByUserID", mock.Anything, "u1").Return("team1", nil)
+		f.userRepo.On("GetTeamNameByUserID", mock.Anything, "u9").Return("team2", nil)
+
+		assert.NoError(t, f.svc.Authorize(ctx, lead, models.PermManageTeam, models.AccessTarget{UserID: "u1"}))
+		assert.ErrorIs(t, f.svc.Authorize(ctx, lead, models.PermManageTeam, models.AccessTarget{UserID: "u9"}),
+			apperrors.ErrForbidden)
+	})
+
+	t.Run("TeamLeadMergesPROfTeam", func(t *testing.T) {
+		f := newAccessFixture()
+		f.prRepo.On("GetPRByID", mock.Anything, "svc", "pr-1").Return(&models.PullRequest{AuthorID: "u1"}, nil)
+		f.userRepo.On("GetTeamNameByUserID", mock.Anything, "u1").Return("team1", nil)
+
+		err := f.svc.Authorize(ctx, lead, models.PermMergePR,
+			models.AccessTarget{Repository: "svc", PullRequestID: "pr-1"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("NewPRUsesAuthorTeam", func(t *testing.T) {
+		f := newAccessFixture()
+		f.prRepo.On("GetPRByID", mock.Anything, "", "pr-new").Return(nil, apperrors.ErrNotFound)
+		f.userRepo.On("GetTeamNameByUserID", mock.Anything, "u9").Return("team2", nil)
+
+		err := f.svc.Authorize(ctx, lead, models.PermWritePR,
+			models.AccessTarget{PullRequestID: "pr-new", UserID: "u9"})
+		assert.ErrorIs(t, err, apperrors.ErrForbidden)
+	})
+
+	t.Run("AbsenceOfTeamUser", func(t *testing.T) {
+		f := newAccessFixture()
+		f.absenceRepo.On("GetAbsenceByID", mock.Anything, int64(5)).Return(&models.Absence{ID: 5, UserID: "u1"}, nil)
+		f.userRepo.On("GetTeamNameByUserID", mock.Anything, "u1").Return("team1", nil)
+
+		assert.NoError(t, f.svc.Authorize(ctx, lead, models.PermManageTeam, models.AccessTarget{AbsenceID: 5}))
+	})
+
+	t.Run("FieldsOfDifferentTeams", func(t *testing.T) {
+		f := newAccessFixture()
+		f.userRepo.On("GetTeamNameByUserID", mock.Anything, "u1").Return("team1", nil)
+
+		err := f.svc.Authorize(ctx, lead, models.PermManageTeam, models.AccessTarget{TeamName: "team2", UserID: "u1"})
+		assert.ErrorIs(t, err, apperrors.ErrForbidden)
+		assert.NoError(t, f.svc.Authorize(ctx, lead, models.PermManageTeam,
+			models.AccessTarget{TeamName: "team1", UserID: "u1"}))
+	})
+
+	t.Run("UnknownUserOnlyGlobal", func(t *testing.T) {
+		f := newAccessFixture()
+		f.userRepo.On("GetTeamNameByUserID", mock.Anything, "ghost").Return("", apperrors.ErrNotFound)
+
+		err := f.svc.Authorize(ctx, lead, models.PermManageTeam, models.AccessTarget{UserID: "ghost"})
+		assert.ErrorIs(t, err, apperrors.ErrForbidden)
+		assert.NoError(t, f.svc.Authorize(ctx, admin, models.PermManageTeam, models.AccessTarget{UserID: "ghost"}))
+	})
+
+	t.Run("GlobalBindingSkipsLookup", func(t *testing.T) {
+		f := newAccessFixture()
+
+		err := f.svc.Authorize(ctx, admin, models.PermMergePR, models.AccessTarget{PullRequestID: "pr-1"})
+		assert.NoError(t, err)
+		f.prRepo.AssertNotCalled(t, "GetPRByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("LookupError", func(t *testing.T) {
+		f := newAccessFixture()
+		f.userRepo.On("GetTeamNameByUserID", mock.Anything, "u1").Return("", errors.New("db down"))
+
+		err := f.svc.Authorize(ctx, lead, models.PermManageTeam, models.AccessTarget{UserID: "u1"})
+		assert.ErrorIs(t, err, apperrors.ErrInternal)
+	})
+}
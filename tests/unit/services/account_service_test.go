@@ -0,0 +1,129 @@
+package services_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// accountStore keeps linked accounts in memory by provider and login.
+type accountStore struct {
+	accounts map[[2]string]models.Account
+}
+
+func newAccountStore(accounts ...models.Account) *accountStore {
+	s := &accountStore{accounts: map[[2]string]models.Account{}}
+	for _, a := range accounts {
+		s.accounts[[2]string{a.Provider, a.Login}] = a
+	}
+	return s
+}
+
+func (s *accountStore) UpsertAccount(_ context.Context, account *models.Account) error {
+	s.accounts[[2]string{account.Provider, account.Login}] = *account
+	return nil
+}
+
+func (s *accountStore) DeleteAccount(_ context.Context, provider, login, userID string) (*models.Account, error) {
+	a, ok := s.accounts[[2]string{provider, login}]
+	if !ok || a.UserID != userID {
+		return nil, apperrors.ErrNotFound
+	}
+	delete(s.accounts, [2]string{provider, login})
+	return &a, nil
+}
+
+func (s *accountStore) GetAccount(_ context.Context, provider, login string) (*models.Account, error) {
+	a, ok := s.accounts[[2]string{provider, login}]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	return &a, nil
+}
+
+func (s *accountStore) ListAccounts(_ context.Context, userID string) ([]models.Account, error) {
+	accounts := []models.Account{}
+	for _, a := range s.accounts {
+		if a.UserID == userID {
+			accounts = append(accounts, a)
+		}
+	}
+	return accounts, nil
+}
+
+func TestAccountService_LinkAccount(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		store := newAccountStore(models.Account{Provider: models.ProviderGitHub, Login: "octocat", UserID: "u9"})
+		userRepo := &mockUserRepo{}
+		userRepo.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1"}, nil)
+		audit, recorder := testutil.NewRecordingAudit()
+		svc := services.NewAccountService(store, userRepo, audit, slog.New(slog.DiscardHandler))
+
+		account, err := svc.LinkAccount(context.Background(), models.ProviderGitHub, " OctoCat ", "u1")
+		require.NoError(t, err)
+		assert.Equal(t, "octocat", account.Login)
+		assert.Equal(t, "u1", store.accounts[[2]string{models.ProviderGitHub, "octocat"}].UserID)
+
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, models.AuditAccountLinked, recorder.Events[0].Action)
+		assert.Equal(t, "github:octocat", recorder.Events[0].EntityID)
+		assert.JSONEq(t, `"u9"`, string(jsonField(t, recorder.Events[0].Before, "user_id")))
+	})
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		userRepo := &mockUserRepo{}
+		userRepo.On("GetUserByID", mock.Anything, "ghost").Return(nil, apperrors.ErrNotFound)
+		svc := services.NewAccountService(newAccountStore(), userRepo, testutil.NewTestAudit(), slog.New(slog.DiscardHandler))
+
+		_, err := svc.LinkAccount(context.Background(), models.ProviderGitHub, "octocat", "ghost")
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		svc := services.NewAccountService(newAccountStore(), &mockUserRepo{}, testutil.NewTestAudit(),
+			slog.New(slog.DiscardHandler))
+
+		_, err := svc.LinkAccount(context.Background(), "bitbucket", "octocat", "u1")
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+		_, err = svc.LinkAccount(context.Background(), models.ProviderGitHub, "  ", "u1")
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+}
+
+func TestAccountService_UnlinkAccount(t *testing.T) {
+	store := newAccountStore(models.Account{Provider: models.ProviderGitHub, Login: "octocat", UserID: "u1"})
+	audit, recorder := testutil.NewRecordingAudit()
+	svc := services.NewAccountService(store, &mockUserRepo{}, audit, slog.New(slog.DiscardHandler))
+
+	t.Run("OtherUser", func(t *testing.T) {
+		err := svc.UnlinkAccount(context.Background(), models.ProviderGitHub, "octocat", "u2")
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		require.NoError(t, svc.UnlinkAccount(context.Background(), models.ProviderGitHub, "Octocat", "u1"))
+		assert.Empty(t, store.accounts)
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, models.AuditAccountUnlinked, recorder.Events[0].Action)
+	})
+}
+
+func TestAccountService_ResolveUser(t *testing.T) {
+	store := newAccountStore(models.Account{Provider: models.ProviderGitHub, Login: "octocat", UserID: "u1"})
+	svc := services.NewAccountService(store, &mockUserRepo{}, testutil.NewTestAudit(), slog.New(slog.DiscardHandler))
+
+	userID, err := svc.ResolveUser(context.Background(), models.ProviderGitHub, "OctoCat")
+	require.NoError(t, err)
+	assert.Equal(t, "u1", userID)
+
+	_, err = svc.ResolveUser(context.Background(), models.ProviderGitLab, "octocat")
+	assert.ErrorIs(t, err, apperrors.ErrNoAccount)
+}
@@ -0,0 +1,151 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/reqctx"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jsonField returns the raw value of key in a JSON object.
+func jsonField(t *testing.T, raw json.RawMessage, key string) json.RawMessage {
+	t.Helper()
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(raw, &fields))
+	return fields[key]
+}
+
+// jsonItem returns the raw element i of a JSON array.
+func jsonItem(t *testing.T, raw json.RawMessage, i int) json.RawMessage {
+	t.Helper()
+	var items []json.RawMessage
+	require.NoError(t, json.Unmarshal(raw, &items))
+	require.Greater(t, len(items), i)
+	return items[i]
+}
+
+// changeListener records the changes it is told of.
+type changeListener struct {
+	changes []string
+	after   any
+	err     error
+}
+
+func (l *changeListener) OnChange(_ context.Context, action, entityID string, _, after any) error {
+	l.changes = append(l.changes, action+" "+entityID)
+	l.after = after
+	return l.err
+}
+
+func TestAuditService_Record(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		audit, recorder := testutil.NewRecordingAudit()
+		ctx := reqctx.WithRequestID(reqctx.WithActor(context.Background(), "alice"), "req-1")
+		ctx = reqctx.WithOnBehalfOf(ctx, "bob")
+
+		before := &models.User{ID: "u1", IsActive: true}
+		after := &models.User{ID: "u1", IsActive: false}
+		err := audit.Record(ctx, models.AuditUserActiveChanged, models.AuditEntityUser, "u1", before, after)
+		require.NoError(t, err)
+
+		require.Len(t, recorder.Events, 1)
+		event := recorder.Events[0]
+		assert.Equal(t, "alice", event.Actor)
+		assert.Equal(t, "bob", event.OnBehalfOf)
+		assert.Equal(t, "req-1", event.RequestID)
+		assert.Equal(t, models.AuditUserActiveChanged, event.Action)
+		assert.Equal(t, models.AuditEntityUser, event.EntityType)
+		assert.Equal(t, "u1", event.EntityID)
+
+		var got models.User
+		require.NoError(t, json.Unmarshal(event.Before, &got))
+		assert.True(t, got.IsActive)
+		require.NoError(t, json.Unmarshal(event.After, &got))
+		assert.False(t, got.IsActive)
+	})
+
+	t.Run("NilStates", func(t *testing.T) {
+		audit, recorder := testutil.NewRecordingAudit()
+
+		var missing *models.Team
+		err := audit.Record(context.Background(), models.AuditTeamCreated, models.AuditEntityTeam, "team1",
+			missing, &models.Team{Name: "team1"})
+		require.NoError(t, err)
+
+		require.Len(t, recorder.Events, 1)
+		assert.Nil(t, recorder.Events[0].Before)
+		assert.NotNil(t, recorder.Events[0].After)
+		assert.Equal(t, reqctx.SystemActor, recorder.Events[0].Actor)
+		assert.Empty(t, recorder.Events[0].RequestID)
+	})
+
+	t.Run("Listeners", func(t *testing.T) {
+		listener := &changeListener{}
+		audit := services.NewAuditService(&testutil.AuditRecorder{}, testutil.InlineTx{}, slog.New(slog.DiscardHandler), listener)
+
+		after := &models.Team{Name: "team1"}
+		err := audit.Record(context.Background(), models.AuditTeamCreated, models.AuditEntityTeam, "team1", nil, after)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{models.AuditTeamCreated + " team1"}, listener.changes)
+		assert.Same(t, after, listener.after)
+	})
+
+	t.Run("ListenerError", func(t *testing.T) {
+		listener := &changeListener{err: errors.New("queue full")}
+		audit := services.NewAuditService(&testutil.AuditRecorder{}, testutil.InlineTx{}, slog.New(slog.DiscardHandler), listener)
+
+		err := audit.Record(context.Background(), models.AuditTeamCreated, models.AuditEntityTeam, "team1", nil, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("RepoError", func(t *testing.T) {
+		recorder := &testutil.AuditRecorder{Err: errors.New("db down")}
+		audit := services.NewAuditService(recorder, testutil.InlineTx{}, slog.New(slog.DiscardHandler))
+
+		err := audit.Record(context.Background(), models.AuditTeamCreated, models.AuditEntityTeam, "team1", nil, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestAuditService_ListEvents(t *testing.T) {
+	t.Run("DefaultLimit", func(t *testing.T) {
+		audit, recorder := testutil.NewRecordingAudit()
+		recorder.Events = []models.AuditEvent{{ID: 1, Action: models.AuditTeamCreated}}
+
+		page, err := audit.ListEvents(context.Background(), models.AuditFilter{Actor: "alice"})
+		require.NoError(t, err)
+		assert.Equal(t, services.DefaultAuditPageSize, page.Limit)
+		assert.Equal(t, 1, page.Total)
+		assert.Len(t, page.Events, 1)
+		assert.Equal(t, "alice", recorder.Filter.Actor)
+		assert.Equal(t, services.DefaultAuditPageSize, recorder.Filter.Limit)
+	})
+
+	t.Run("InvalidFilters", func(t *testing.T) {
+		from := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+		to := from.Add(-time.Hour)
+		for name, filter := range map[string]models.AuditFilter{
+			"NegativeLimit":  {Limit: -1},
+			"LimitTooLarge":  {Limit: services.MaxAuditPageSize + 1},
+			"NegativeOffset": {Offset: -1},
+			"FromAfterTo":    {From: &from, To: &to},
+		} {
+			t.Run(name, func(t *testing.T) {
+				audit, _ := testutil.NewRecordingAudit()
+				_, err := audit.ListEvents(context.Background(), filter)
+				assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+			})
+		}
+	})
+}
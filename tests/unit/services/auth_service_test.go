@@ -0,0 +1,107 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthService_CreateToken(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+
+	t.Run("Success", func(t *testing.T) {
+		store := testutil.NewTokenStore()
+		svc := services.NewAuthService(store, log)
+
+		raw, token, err := svc.CreateToken(context.Background(), "ci", "ci-bot")
+		require.NoError(t, err)
+		assert.Len(t, raw, 64)
+		assert.Equal(t, "ci", token.Name)
+		assert.Equal(t, "ci-bot", token.UserID)
+
+		require.Len(t, store.ByHash, 1)
+		for hash := range store.ByHash {
+			assert.NotEqual(t, raw, hash, "token must be stored hashed")
+		}
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		svc := services.NewAuthService(testutil.NewTokenStore(), log)
+		for name, args := range map[string][2]string{
+			"EmptyName":   {" ", "ci-bot"},
+			"EmptyUserID": {"ci", ""},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, _, err := svc.CreateToken(context.Background(), args[0], args[1])
+				assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+			})
+		}
+	})
+
+	t.Run("DuplicateName", func(t *testing.T) {
+		svc := services.NewAuthService(testutil.NewTokenStore(), log)
+		_, _, err := svc.CreateToken(context.Background(), "ci", "ci-bot")
+		require.NoError(t, err)
+
+		_, _, err = svc.CreateToken(context.Background(), "ci", "alice")
+		assert.ErrorIs(t, err, apperrors.ErrTokenExists)
+	})
+
+	t.Run("RepoError", func(t *testing.T) {
+		store := testutil.NewTokenStore()
+		store.Err = errors.New("db down")
+		svc := services.NewAuthService(store, log)
+
+		_, _, err := svc.CreateToken(context.Background(), "ci", "ci-bot")
+		assert.ErrorIs(t, err, apperrors.ErrInternal)
+	})
+}
+
+func TestAuthService_Authenticate(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+	store := testutil.NewTokenStore()
+	svc := services.NewAuthService(store, log)
+	raw, _, err := svc.CreateToken(context.Background(), "admin", "root")
+	require.NoError(t, err)
+
+	t.Run("Valid", func(t *testing.T) {
+		token, authErr := svc.Authenticate(context.Background(), raw)
+		require.NoError(t, authErr)
+		assert.Equal(t, "admin", token.Name)
+		assert.Equal(t, "root", token.UserID)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		_, authErr := svc.Authenticate(context.Background(), "")
+		assert.ErrorIs(t, authErr, apperrors.ErrUnauthorized)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		_, authErr := svc.Authenticate(context.Background(), "not-a-token")
+		assert.ErrorIs(t, authErr, apperrors.ErrUnauthorized)
+	})
+
+	t.Run("Revoked", func(t *testing.T) {
+		require.NoError(t, svc.RevokeToken(context.Background(), "admin"))
+		_, authErr := svc.Authenticate(context.Background(), raw)
+		assert.ErrorIs(t, authErr, apperrors.ErrUnauthorized)
+	})
+
+	t.Run("RevokeUnknown", func(t *testing.T) {
+		assert.ErrorIs(t, svc.RevokeToken(context.Background(), "ghost"), apperrors.ErrNotFound)
+	})
+
+	t.Run("RepoError", func(t *testing.T) {
+		store.Err = errors.New("db down")
+		defer func() { store.Err = nil }()
+		_, authErr := svc.Authenticate(context.Background(), raw)
+		assert.ErrorIs(t, authErr, apperrors.ErrInternal)
+	})
+}
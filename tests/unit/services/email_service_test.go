@@ -0,0 +1,198 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/email"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/email/emailtest"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// emailUsers keeps users in memory and hands out the digest recipients among them in batches, as
+// ClaimDigestRecipients does.
+type emailUsers struct {
+	repository.UserRepository
+	users      map[string]models.User
+	recipients []models.User
+	slot       time.Time
+	err        error
+}
+
+func (u *emailUsers) GetUserByID(_ context.Context, id string) (*models.User, error) {
+	user, ok := u.users[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	return &user, nil
+}
+
+func (u *emailUsers) ClaimDigestRecipients(_ context.Context, slot time.Time, limit int) ([]models.User, error) {
+	if u.err != nil {
+		return nil, u.err
+	}
+	u.slot = slot
+	n := min(limit, len(u.recipients))
+	claimed := u.recipients[:n]
+	u.recipients = u.recipients[n:]
+	return claimed, nil
+}
+
+// pendingReviews returns the pending reviews of each user.
+type pendingReviews struct {
+	repository.PRRepository
+	prs map[string][]models.PullRequestShort
+}
+
+func (p pendingReviews) GetPRsForUser(
+	_ context.Context,
+	userID, _ string,
+	_ bool,
+) ([]models.PullRequestShort, error) {
+	return p.prs[userID], nil
+}
+
+func newTestEmail(t *testing.T, users *emailUsers, prs pendingReviews) (*services.EmailService, *emailtest.Server) {
+	t.Helper()
+	server := emailtest.NewServer()
+	t.Cleanup(server.Close)
+	templates, err := email.LoadTemplates("")
+	require.NoError(t, err)
+	mailer := email.NewMailer(email.Config{Addr: server.Addr, From: "bot@example.com", Timeout: 5 * time.Second})
+	return services.NewEmailService(users, prs, mailer, templates, 9, slog.New(slog.DiscardHandler)), server
+}
+
+func TestEmailService_Publish(t *testing.T) {
+	ctx := context.Background()
+	pr := &models.PullRequest{ID: "42", Repository: "acme/api", Title: "Fix login", AuthorID: "u1"}
+	users := func() *emailUsers {
+		return &emailUsers{users: map[string]models.User{
+			"u2": {ID: "u2", Name: "Bob", IsActive: true, Email: "bob@example.com"},
+			"u3": {ID: "u3", Name: "Carol", IsActive: true},
+			"u4": {ID: "u4", Name: "Dan", Email: "dan@example.com"},
+		}}
+	}
+
+	t.Run("Assigned", func(t *testing.T) {
+		svc, server := newTestEmail(t, users(), pendingReviews{})
+		require.NoError(t, svc.Publish(ctx, &models.Event{
+			Type: models.EventReviewerAssigned, Data: models.EventData{PullRequest: pr, ReviewerID: "u2"},
+		}))
+
+		messages := server.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, []string{"bob@example.com"}, messages[0].To)
+		assert.Equal(t, "Review requested: Fix login", messages[0].Subject)
+		assert.Contains(t, messages[0].Body, "Hi Bob,")
+	})
+
+	t.Run("Stale", func(t *testing.T) {
+		svc, server := newTestEmail(t, users(), pendingReviews{})
+		assignedAt := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+		require.NoError(t, svc.Publish(ctx, &models.Event{
+			Type: models.EventReviewStale,
+			Data: models.EventData{PullRequest: pr, ReviewerID: "u2", AssignedAt: &assignedAt},
+		}))
+
+		messages := server.Messages()
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0].Body, "since 2026-10-15 09:30 UTC")
+	})
+
+	t.Run("Skipped", func(t *testing.T) {
+		svc, server := newTestEmail(t, users(), pendingReviews{})
+		for _, event := range []*models.Event{
+			{Type: models.EventPRMerged, Data: models.EventData{PullRequest: pr}},
+			{Type: models.EventReviewerAssigned, Data: models.EventData{PullRequest: pr, ReviewerID: "u3"}},
+			{Type: models.EventReviewerAssigned, Data: models.EventData{PullRequest: pr, ReviewerID: "u4"}},
+			{Type: models.EventReviewerAssigned, Data: models.EventData{PullRequest: pr, ReviewerID: "nope"}},
+		} {
+			require.NoError(t, svc.Publish(ctx, event))
+		}
+		assert.Empty(t, server.Messages(), "only active reviewers with an email are emailed")
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		svc, server := newTestEmail(t, users(), pendingReviews{})
+		event := &models.Event{
+			Type: models.EventReviewerAssigned, Data: models.EventData{PullRequest: pr, ReviewerID: "u2"},
+		}
+
+		server.Reject("bob@example.com", 451)
+		assert.Error(t, svc.Publish(ctx, event), "temporary failures are retried")
+
+		server.Reject("bob@example.com", 550)
+		assert.NoError(t, svc.Publish(ctx, event), "permanent failures are dropped")
+	})
+}
+
+func TestEmailService_SendDigests(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		users := &emailUsers{recipients: []models.User{
+			{ID: "u2", Name: "Bob", Email: "bob@example.com", EmailDigest: true},
+			{ID: "u3", Name: "Carol", Email: "carol@example.com", EmailDigest: true},
+		}}
+		prs := pendingReviews{prs: map[string][]models.PullRequestShort{
+			"u2": {{ID: "42", Repository: "acme/api", Title: "Fix login", AuthorID: "u1"}},
+		}}
+		svc, server := newTestEmail(t, users, prs)
+
+		sent, err := svc.SendDigests(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, sent, "users with nothing to review get no digest")
+
+		messages := server.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, []string{"bob@example.com"}, messages[0].To)
+		assert.Equal(t, "1 pull request waiting for your review", messages[0].Subject)
+		assert.Contains(t, messages[0].Body, `- "Fix login" (acme/api#42) by u1`)
+
+		assert.Equal(t, 9, users.slot.Hour())
+		assert.False(t, users.slot.After(time.Now()))
+		assert.Less(t, time.Since(users.slot), 24*time.Hour)
+	})
+
+	t.Run("SeveralBatches", func(t *testing.T) {
+		users := &emailUsers{}
+		prs := pendingReviews{prs: map[string][]models.PullRequestShort{}}
+		for range 150 {
+			users.recipients = append(users.recipients, models.User{ID: "u2", Email: "bob@example.com"})
+		}
+		prs.prs["u2"] = []models.PullRequestShort{{ID: "42"}}
+		svc, _ := newTestEmail(t, users, prs)
+
+		sent, err := svc.SendDigests(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 150, sent)
+	})
+
+	t.Run("SendFails", func(t *testing.T) {
+		users := &emailUsers{recipients: []models.User{
+			{ID: "u2", Email: "bob@example.com"},
+			{ID: "u3", Email: "carol@example.com"},
+		}}
+		prs := pendingReviews{prs: map[string][]models.PullRequestShort{"u2": {{ID: "1"}}, "u3": {{ID: "2"}}}}
+		svc, server := newTestEmail(t, users, prs)
+		server.Reject("bob@example.com", 550)
+
+		sent, err := svc.SendDigests(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, sent, "a failed digest does not stop the others")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		svc, _ := newTestEmail(t, &emailUsers{err: errors.New("db down")}, pendingReviews{})
+		_, err := svc.SendDigests(ctx)
+		assert.Error(t, err)
+	})
+}
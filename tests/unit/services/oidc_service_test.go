@@ -0,0 +1,159 @@
+package services_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/jwt"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ssoKey stands in for the SSO signing key.
+type ssoKey struct {
+	key *ecdsa.PrivateKey
+	err error
+}
+
+func (k ssoKey) Key(_ context.Context, _ string) (crypto.PublicKey, error) {
+	if k.err != nil {
+		return nil, k.err
+	}
+	return &k.key.PublicKey, nil
+}
+
+// signES256 builds an ES256 token over claims.
+func signES256(t *testing.T, key *ecdsa.PrivateKey, claims map[string]any) string {
+	t.Helper()
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString([]byte(`{"alg":"ES256","typ":"JWT"}`)) + "." + enc.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	sig := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	return signingInput + "." + enc.EncodeToString(sig)
+}
+
+func TestParseGroupRoles(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		groupRoles, err := services.ParseGroupRoles(
+			" platform-admins=admin, backend=team_lead@backend ,backend=member,")
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]models.RoleBinding{
+			"platform-admins": {{Role: models.RoleAdmin}},
+			"backend": {
+				{Role: models.RoleTeamLead, TeamName: "backend"},
+				{Role: models.RoleMember},
+			},
+		}, groupRoles)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		groupRoles, err := services.ParseGroupRoles("")
+		require.NoError(t, err)
+		assert.Empty(t, groupRoles)
+	})
+
+	for name, spec := range map[string]string{
+		"NoRole":      "backend",
+		"NoGroup":     "=admin",
+		"UnknownRole": "backend=owner",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := services.ParseGroupRoles(spec)
+			assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+		})
+	}
+}
+
+func TestOIDCService_Principal(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	groupRoles, err := services.ParseGroupRoles("backend=team_lead@backend,sre=admin")
+	require.NoError(t, err)
+
+	newService := func(keys jwt.KeyProvider, roles *testutil.RoleStore) *services.OIDCService {
+		log := slog.New(slog.DiscardHandler)
+		access := services.NewAccessService(roles, &mockUserRepo{}, &mockTeamRepo{}, &mockPRRepo{},
+			&mockAbsenceRepo{}, log)
+		return services.NewOIDCService(jwt.NewVerifier(keys, "https://sso.example.com", "pr-service"), access,
+			"preferred_username", "groups", groupRoles, log)
+	}
+	claims := func(extra map[string]any) map[string]any {
+		c := map[string]any{
+			"iss":                "https://sso.example.com",
+			"aud":                "pr-service",
+			"exp":                time.Now().Add(time.Hour).Unix(),
+			"preferred_username": "alice",
+		}
+		for k, v := range extra {
+			c[k] = v
+		}
+		return c
+	}
+
+	t.Run("MergesStoredAndGroupRoles", func(t *testing.T) {
+		roles := &testutil.RoleStore{Bindings: []models.RoleBinding{
+			{ID: 1, UserID: "alice", Role: models.RoleMember, TeamName: "frontend"},
+			{ID: 2, UserID: "bob", Role: models.RoleAdmin},
+		}}
+		svc := newService(ssoKey{key: key}, roles)
+
+		token := signES256(t, key, claims(map[string]any{"groups": []string{"backend", "unmapped"}}))
+		principal, err := svc.Principal(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", principal.UserID)
+		assert.Equal(t, []models.RoleBinding{
+			{ID: 1, UserID: "alice", Role: models.RoleMember, TeamName: "frontend"},
+			{UserID: "alice", Role: models.RoleTeamLead, TeamName: "backend"},
+		}, principal.Bindings)
+	})
+
+	t.Run("SingleGroupString", func(t *testing.T) {
+		svc := newService(ssoKey{key: key}, &testutil.RoleStore{})
+
+		token := signES256(t, key, claims(map[string]any{"groups": "sre"}))
+		principal, err := svc.Principal(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, []models.RoleBinding{{UserID: "alice", Role: models.RoleAdmin}}, principal.Bindings)
+	})
+
+	t.Run("InvalidToken", func(t *testing.T) {
+		svc := newService(ssoKey{key: key}, &testutil.RoleStore{})
+
+		token := signES256(t, key, claims(map[string]any{"iss": "https://evil.example.com"}))
+		_, err := svc.Principal(context.Background(), token)
+		assert.ErrorIs(t, err, apperrors.ErrUnauthorized)
+	})
+
+	t.Run("MissingUserClaim", func(t *testing.T) {
+		svc := newService(ssoKey{key: key}, &testutil.RoleStore{})
+
+		token := signES256(t, key, claims(map[string]any{"preferred_username": ""}))
+		_, err := svc.Principal(context.Background(), token)
+		assert.ErrorIs(t, err, apperrors.ErrUnauthorized)
+	})
+
+	t.Run("KeySourceDown", func(t *testing.T) {
+		svc := newService(ssoKey{err: errors.New("jwks unreachable")}, &testutil.RoleStore{})
+
+		_, err := svc.Principal(context.Background(), signES256(t, key, claims(nil)))
+		assert.ErrorIs(t, err, apperrors.ErrInternal)
+	})
+}
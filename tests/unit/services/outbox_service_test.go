@@ -0,0 +1,330 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/reqctx"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// outboxEntry is an event in outboxStore.
+type outboxEntry struct {
+	models.OutboxEntry
+	dispatched bool
+	abandoned  bool
+	dueAt      time.Time
+	lastError  string
+}
+
+// outboxStore keeps the outbox in memory.
+type outboxStore struct {
+	entries []*outboxEntry
+	retries []time.Duration
+}
+
+func (s *outboxStore) AddEvent(_ context.Context, event *models.Event) error {
+	s.entries = append(s.entries, &outboxEntry{
+		OutboxEntry: models.OutboxEntry{ID: int64(len(s.entries) + 1), Event: *event},
+	})
+	return nil
+}
+
+func (s *outboxStore) ClaimNextEvent(_ context.Context) (*models.OutboxEntry, error) {
+	for _, e := range s.entries {
+		if !e.dispatched && !e.abandoned && !e.dueAt.After(time.Now()) {
+			entry := e.OutboxEntry
+			return &entry, nil
+		}
+	}
+	return nil, nil //nolint:nilnil // no event is due
+}
+
+func (s *outboxStore) MarkDispatched(_ context.Context, id int64) error {
+	s.entries[id-1].dispatched = true
+	return nil
+}
+
+func (s *outboxStore) MarkFailed(
+	_ context.Context,
+	id int64,
+	deliveredSinks []string,
+	lastError string,
+	retryAfter time.Duration,
+) error {
+	e := s.entries[id-1]
+	e.Attempts++
+	e.DeliveredSinks = deliveredSinks
+	e.lastError = lastError
+	e.dueAt = time.Now().Add(retryAfter)
+	s.retries = append(s.retries, retryAfter)
+	return nil
+}
+
+func (s *outboxStore) MarkAbandoned(_ context.Context, id int64, deliveredSinks []string, lastError string) error {
+	e := s.entries[id-1]
+	e.Attempts++
+	e.DeliveredSinks = deliveredSinks
+	e.lastError = lastError
+	e.abandoned = true
+	return nil
+}
+
+// retryNow makes every failed event due.
+func (s *outboxStore) retryNow() {
+	for _, e := range s.entries {
+		e.dueAt = time.Time{}
+	}
+}
+
+// types lists the types of the events in the outbox in order.
+func (s *outboxStore) types() []string {
+	types := make([]string, 0, len(s.entries))
+	for _, e := range s.entries {
+		types = append(types, e.Event.Type)
+	}
+	return types
+}
+
+// recordingSink records the IDs of the events published to it and fails while err is set.
+type recordingSink struct {
+	name string
+	ids  []string
+	err  error
+}
+
+func (s *recordingSink) Name() string {
+	return s.name
+}
+
+func (s *recordingSink) Publish(_ context.Context, event *models.Event) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.ids = append(s.ids, event.ID)
+	return nil
+}
+
+func newTestOutbox(store *outboxStore, sinks ...services.EventSink) *services.OutboxService {
+	return services.NewOutboxService(store, testutil.InlineTx{}, 5, time.Second, slog.New(slog.DiscardHandler), sinks...)
+}
+
+func TestOutboxService_OnChange(t *testing.T) {
+	pr := func(reviewers ...string) *models.PullRequest {
+		return &models.PullRequest{ID: "pr-1", Repository: "acme/api", Reviewers: reviewers}
+	}
+
+	t.Run("PRCreated", func(t *testing.T) {
+		store := &outboxStore{}
+		ctx := reqctx.WithActor(context.Background(), "alice")
+
+		require.NoError(t, newTestOutbox(store).OnChange(ctx, models.AuditPRCreated, "pr-1", nil, pr("u2", "u3")))
+
+		require.Equal(t, []string{models.EventPRCreated, models.EventReviewerAssigned, models.EventReviewerAssigned},
+			store.types())
+		created := store.entries[0].Event
+		assert.Equal(t, "alice", created.Actor)
+		assert.Equal(t, "pr-1", created.Data.PullRequest.ID)
+		assert.Len(t, created.ID, 32)
+		assert.False(t, created.CreatedAt.IsZero())
+		assert.Equal(t, "u2", store.entries[1].Event.Data.ReviewerID)
+		assert.Equal(t, "u3", store.entries[2].Event.Data.ReviewerID)
+		assert.NotEqual(t, created.ID, store.entries[1].Event.ID, "every event has its own ID")
+	})
+
+	t.Run("PRReady", func(t *testing.T) {
+		store := &outboxStore{}
+
+		require.NoError(t, newTestOutbox(store).OnChange(context.Background(), models.AuditPRReady, "pr-1",
+			pr(), pr("u2")))
+
+		require.Equal(t, []string{models.EventReviewerAssigned}, store.types())
+		assert.Equal(t, "u2", store.entries[0].Event.Data.ReviewerID)
+	})
+
+	t.Run("ReviewerReassigned", func(t *testing.T) {
+		store := &outboxStore{}
+
+		require.NoError(t, newTestOutbox(store).OnChange(context.Background(), models.AuditPRReviewerReassigned,
+			"pr-1", pr("u2", "u3"), pr("u4", "u3")))
+
+		require.Equal(t, []string{models.EventReviewerReassigned}, store.types())
+		assert.Equal(t, "u4", store.entries[0].Event.Data.ReviewerID)
+		assert.Equal(t, "u2", store.entries[0].Event.Data.OldReviewerID)
+	})
+
+	t.Run("AbsentReviewerReassigned", func(t *testing.T) {
+		store := &outboxStore{}
+
+		require.NoError(t, newTestOutbox(store).OnChange(context.Background(), models.AuditPRAbsentReassigned,
+			"pr-1", pr("u2", "u3"), pr("u4", "u3")))
+
+		require.Equal(t, []string{models.EventReviewerReassigned}, store.types())
+		assert.Equal(t, "u4", store.entries[0].Event.Data.ReviewerID)
+	})
+
+	t.Run("PRMerged", func(t *testing.T) {
+		store := &outboxStore{}
+
+		require.NoError(t, newTestOutbox(store).OnChange(context.Background(), models.AuditPRForceMerged, "pr-1",
+			pr(), pr()))
+
+		assert.Equal(t, []string{models.EventPRMerged}, store.types())
+	})
+
+	t.Run("UserDeactivated", func(t *testing.T) {
+		store := &outboxStore{}
+		outbox := newTestOutbox(store)
+		ctx := context.Background()
+
+		active := &models.User{ID: "u1", IsActive: true}
+		inactive := &models.User{ID: "u1", IsActive: false}
+		require.NoError(t, outbox.OnChange(ctx, models.AuditUserActiveChanged, "u1", inactive, active))
+		require.NoError(t, outbox.OnChange(ctx, models.AuditUserActiveChanged, "u1", active, inactive))
+		require.NoError(t, outbox.OnChange(ctx, models.AuditTeamDeactivated, "backend",
+			[]models.User{{ID: "u2", IsActive: true}}, []models.User{{ID: "u2"}}))
+
+		require.Equal(t, []string{models.EventUserDeactivated, models.EventUserDeactivated}, store.types(),
+			"reactivation is not an event")
+		assert.Equal(t, "u1", store.entries[0].Event.Data.User.ID)
+		assert.Equal(t, "u2", store.entries[1].Event.Data.User.ID)
+	})
+
+	t.Run("OtherChanges", func(t *testing.T) {
+		store := &outboxStore{}
+
+		require.NoError(t, newTestOutbox(store).OnChange(context.Background(), models.AuditTeamCreated, "backend",
+			nil, &models.Team{}))
+
+		assert.Empty(t, store.entries)
+	})
+}
+
+func TestOutboxService_Dispatch(t *testing.T) {
+	addEvents := func(t *testing.T, store *outboxStore, ids ...string) {
+		t.Helper()
+		for _, id := range ids {
+			require.NoError(t, store.AddEvent(context.Background(), &models.Event{ID: id, Type: models.EventPRMerged}))
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		store := &outboxStore{}
+		addEvents(t, store, "ev-1", "ev-2")
+		webhooks, slack := &recordingSink{name: "webhooks"}, &recordingSink{name: "slack"}
+		outbox := newTestOutbox(store, webhooks, slack)
+
+		dispatched, err := outbox.Dispatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, dispatched)
+		assert.Equal(t, []string{"ev-1", "ev-2"}, webhooks.ids)
+		assert.Equal(t, []string{"ev-1", "ev-2"}, slack.ids)
+
+		dispatched, err = outbox.Dispatch(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, dispatched, "dispatched events are not published again")
+	})
+
+	t.Run("SinkFails", func(t *testing.T) {
+		store := &outboxStore{}
+		addEvents(t, store, "ev-1", "ev-2")
+		webhooks, slack := &recordingSink{name: "webhooks"}, &recordingSink{name: "slack", err: errors.New("timeout")}
+		outbox := newTestOutbox(store, webhooks, slack)
+		ctx := context.Background()
+
+		dispatched, err := outbox.Dispatch(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, dispatched)
+		assert.Equal(t, "slack: timeout", store.entries[0].lastError)
+		assert.Equal(t, 1, store.entries[1].Attempts, "a failed event does not hold back the next one")
+
+		store.retryNow()
+		_, err = outbox.Dispatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{time.Second, time.Second, 2 * time.Second, 2 * time.Second}, store.retries)
+
+		slack.err = nil
+		dispatched, err = outbox.Dispatch(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, dispatched, "failed events wait for their backoff")
+
+		store.retryNow()
+		dispatched, err = outbox.Dispatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, dispatched)
+		assert.Equal(t, []string{"ev-1", "ev-2"}, slack.ids)
+		assert.Equal(t, []string{"ev-1", "ev-2"}, webhooks.ids, "a sink that took an event is not retried")
+	})
+
+	t.Run("FailedSinkDoesNotHoldBackOthers", func(t *testing.T) {
+		store := &outboxStore{}
+		addEvents(t, store, "ev-1")
+		slack := &recordingSink{name: "slack", err: errors.New("timeout")}
+		email := &recordingSink{name: "email"}
+		outbox := newTestOutbox(store, slack, email)
+		ctx := context.Background()
+
+		_, err := outbox.Dispatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ev-1"}, email.ids, "sinks after the failed one still take the event")
+		assert.Equal(t, []string{"email"}, store.entries[0].DeliveredSinks)
+
+		for range 2 {
+			store.retryNow()
+			_, err = outbox.Dispatch(ctx)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, []string{"ev-1"}, email.ids, "the email is sent once")
+		assert.Equal(t, 3, store.entries[0].Attempts)
+	})
+
+	t.Run("GivesUpAfterMaxAttempts", func(t *testing.T) {
+		store := &outboxStore{}
+		addEvents(t, store, "ev-1")
+		webhooks, slack := &recordingSink{name: "webhooks"}, &recordingSink{name: "slack", err: errors.New("gone")}
+		outbox := newTestOutbox(store, webhooks, slack)
+		ctx := context.Background()
+
+		for range 6 {
+			store.retryNow()
+			_, err := outbox.Dispatch(ctx)
+			require.NoError(t, err)
+		}
+		entry := store.entries[0]
+		assert.True(t, entry.abandoned)
+		assert.Equal(t, 5, entry.Attempts, "the event is not claimed after its last attempt")
+		assert.Len(t, store.retries, 4, "the last attempt is not rescheduled")
+		assert.Equal(t, "slack: gone", entry.lastError)
+		assert.Equal(t, []string{"ev-1"}, webhooks.ids)
+	})
+
+	t.Run("WithSubscriptions", func(t *testing.T) {
+		subscriptions := &testutil.SubscriptionStore{}
+		sink := newTestSubscriptions(subscriptions, 3)
+		_, err := sink.Subscribe(context.Background(), &models.WebhookSubscription{
+			URL:        "https://example.com/hook",
+			EventTypes: []string{models.EventPRMerged},
+		})
+		require.NoError(t, err)
+		store := &outboxStore{}
+		outbox := newTestOutbox(store, sink)
+		ctx := reqctx.WithActor(context.Background(), "alice")
+		pr := &models.PullRequest{ID: "pr-1", Status: "MERGED"}
+		require.NoError(t, outbox.OnChange(ctx, models.AuditPRMerged, "pr-1", pr, pr))
+		assert.Empty(t, subscriptions.Deliveries, "nothing is queued before dispatch")
+
+		dispatched, err := outbox.Dispatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, dispatched)
+		require.Len(t, subscriptions.Deliveries, 1)
+		assert.Equal(t, store.entries[0].Event.ID, subscriptions.Deliveries[0].EventID)
+		assert.Equal(t, "alice", subscriptions.Events(t)[0].Actor, "the actor of the change is kept")
+	})
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/reqctx"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -20,83 +24,129 @@ type mockPRRepo struct {
 	repository.PRRepository
 }
 
-func (m *mockPRRepo) CreatePR(ctx context.Context, pr *models.PullRequest) error {
-	args := m.Called(ctx, pr)
+func (m *mockPRRepo) CreatePR(ctx context.Context, pr *models.PullRequest, change models.AssignmentChange) error {
+	args := m.Called(ctx, pr, change)
 	return args.Error(0)
 }
 
-func (m *mockPRRepo) GetPRByID(ctx context.Context, id string) (*models.PullRequest, error) {
-	args := m.Called(ctx, id)
+func (m *mockPRRepo) GetPRByID(ctx context.Context, repository, id string) (*models.PullRequest, error) {
+	args := m.Called(ctx, repository, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.PullRequest), args.Error(1)
 }
 
-func (m *mockPRRepo) UpdatePR(ctx context.Context, pr *models.PullRequest) error {
-	args := m.Called(ctx, pr)
+func (m *mockPRRepo) UpdatePR(ctx context.Context, pr *models.PullRequest, change models.AssignmentChange) error {
+	args := m.Called(ctx, pr, change)
 	return args.Error(0)
 }
 
-func (m *mockPRRepo) MergePR(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *mockPRRepo) MergePR(ctx context.Context, repository, id string) error {
+	args := m.Called(ctx, repository, id)
 	return args.Error(0)
 }
 
-func (m *mockPRRepo) GetPRsForUser(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
-	args := m.Called(ctx, userID)
+func (m *mockPRRepo) SetReviewState(ctx context.Context, repository, prID, reviewerID, state string) error {
+	args := m.Called(ctx, repository, prID, reviewerID, state)
+	return args.Error(0)
+}
+
+func (m *mockPRRepo) MarkPRReady(ctx context.Context, pr *models.PullRequest, change models.AssignmentChange) error {
+	args := m.Called(ctx, pr, change)
+	return args.Error(0)
+}
+
+func (m *mockPRRepo) ForceMergePR(ctx context.Context, override *models.MergeOverride) error {
+	args := m.Called(ctx, override)
+	return args.Error(0)
+}
+
+func (m *mockPRRepo) ClosePR(ctx context.Context, repository, id string) error {
+	args := m.Called(ctx, repository, id)
+	return args.Error(0)
+}
+
+func (m *mockPRRepo) ReopenPR(ctx context.Context, pr *models.PullRequest, change models.AssignmentChange) error {
+	args := m.Called(ctx, pr, change)
+	return args.Error(0)
+}
+
+func (m *mockPRRepo) GetPRsForUser(
+	ctx context.Context, userID, repository string, pendingOnly bool,
+) ([]models.PullRequestShort, error) {
+	args := m.Called(ctx, userID, repository, pendingOnly)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.PullRequestShort), args.Error(1)
 }
 
-func (m *mockPRRepo) ExistsPR(ctx context.Context, id string) (bool, error) {
-	args := m.Called(ctx, id)
+func (m *mockPRRepo) ExistsPR(ctx context.Context, repository, id string) (bool, error) {
+	args := m.Called(ctx, repository, id)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *mockPRRepo) GetTotalPRs(ctx context.Context) (int, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepo) GetAssignmentHistory(
+	ctx context.Context,
+	repository, prID string,
+) ([]models.AssignmentEvent, error) {
+	args := m.Called(ctx, repository, prID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AssignmentEvent), args.Error(1)
+}
+
+func (m *mockPRRepo) GetTotalPRs(ctx context.Context, repository string) (int, error) {
+	args := m.Called(ctx, repository)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *mockPRRepo) GetPrsByStatus(ctx context.Context) (int, int, error) {
-	args := m.Called(ctx)
-	return args.Int(0), args.Int(1), args.Error(2)
+func (m *mockPRRepo) GetPrsByStatus(ctx context.Context, repository string) (models.PrsStatus, error) {
+	args := m.Called(ctx, repository)
+	return args.Get(0).(models.PrsStatus), args.Error(1)
 }
 
-func (m *mockPRRepo) GetAssignmentsPerUser(ctx context.Context) ([]models.UserAssignment, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepo) GetAssignmentsPerUser(ctx context.Context, repository string) ([]models.UserAssignment, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.UserAssignment), args.Error(1)
 }
 
-func (m *mockPRRepo) GetTopReviewers(ctx context.Context) ([]models.UserAssignment, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepo) GetOpenReviewLoadByTeam(ctx context.Context, teamName string) ([]models.UserAssignment, error) {
+	args := m.Called(ctx, teamName)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.UserAssignment), args.Error(1)
 }
 
-func (m *mockPRRepo) GetAvgCloseTime(ctx context.Context) (float64, int, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepo) GetTopReviewers(ctx context.Context, repository string) ([]models.UserAssignment, error) {
+	args := m.Called(ctx, repository)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.UserAssignment), args.Error(1)
+}
+
+func (m *mockPRRepo) GetAvgCloseTime(ctx context.Context, repository string) (float64, int, error) {
+	args := m.Called(ctx, repository)
 	return args.Get(0).(float64), args.Int(1), args.Error(2)
 }
 
-func (m *mockPRRepo) GetIdleUsersPerTeam(ctx context.Context) ([]models.TeamMetric, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepo) GetIdleUsersPerTeam(ctx context.Context, repository string) ([]models.TeamMetric, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.TeamMetric), args.Error(1)
 }
 
-func (m *mockPRRepo) GetNeedyPRsPerTeam(ctx context.Context) ([]models.TeamMetric, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepo) GetNeedyPRsPerTeam(ctx context.Context, repository string) ([]models.TeamMetric, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -134,6 +184,14 @@ func (m *mockUserRepo) GetActiveUsersByTeam(ctx context.Context, teamName string
 	return args.Get(0).([]models.User), args.Error(1)
 }
 
+func (m *mockUserRepo) GetAvailableUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	args := m.Called(ctx, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
 func (m *mockUserRepo) GetTeamNameByUserID(ctx context.Context, userID string) (string, error) {
 	args := m.Called(ctx, userID)
 	return args.String(0), args.Error(1)
@@ -144,26 +202,54 @@ func (m *mockUserRepo) DeactivateUsersByTeam(ctx context.Context, teamName strin
 	return args.Error(0)
 }
 
+type mockRepositoryRepo struct {
+	mock.Mock
+	repository.RepositoryRepository
+}
+
+func (m *mockRepositoryRepo) GetRepository(ctx context.Context, name string) (*models.Repository, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Repository), args.Error(1)
+}
+
+func (m *mockRepositoryRepo) SetCodeOwners(ctx context.Context, name, content, mode string) error {
+	args := m.Called(ctx, name, content, mode)
+	return args.Error(0)
+}
+
+func (m *mockRepositoryRepo) UpdateRepositorySettings(
+	ctx context.Context, name string, settings *models.RepositorySettings, defaultReviewers int,
+) error {
+	args := m.Called(ctx, name, settings, defaultReviewers)
+	return args.Error(0)
+}
+
 func TestPRService_CreatePR(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mPrRepo := &mockPRRepo{}
 	mUserRepo := &mockUserRepo{}
+	mTeamRepo := &mockTeamRepo{}
 
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, mTeamRepo, &mockRepositoryRepo{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
 
 	t.Run("Success", func(t *testing.T) {
 		pr := &models.PullRequest{ID: "pr-1", Title: "Test", AuthorID: "u1"}
 
 		author := &models.User{ID: "u1", TeamName: "team1"}
 		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(author, nil)
-		mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").Return([]models.User{
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
 			{ID: "u2", IsActive: true},
 			{ID: "u3", IsActive: true},
 		}, nil)
 
-		mPrRepo.On("CreatePR", mock.Anything, mock.AnythingOfType("*models.PullRequest")).Return(nil)
+		mPrRepo.On("CreatePR", mock.Anything, mock.AnythingOfType("*models.PullRequest"), mock.Anything).Return(nil)
 		reloaded := &models.PullRequest{ID: "pr-1", Status: "OPEN", Reviewers: []string{"u2", "u3"}}
-		mPrRepo.On("GetPRByID", mock.Anything, "pr-1").Return(reloaded, nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(reloaded, nil)
 
 		result, err := svc.CreatePR(context.Background(), pr)
 		require.NoError(t, err)
@@ -202,18 +288,23 @@ func TestPRService_CreatePR(t *testing.T) {
 	t.Run("NoCandidates_OnlyAuthor", func(t *testing.T) {
 		mPrRepo2 := &mockPRRepo{}
 		mUserRepo2 := &mockUserRepo{}
-		svc2 := services.NewPRService(mPrRepo2, mUserRepo2, log)
+		mTeamRepo2 := &mockTeamRepo{}
+		svc2 := services.NewPRService(
+			mPrRepo2, mUserRepo2, mTeamRepo2, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
 		pr := &models.PullRequest{ID: "pr-2", Title: "Test", AuthorID: "u1"}
 		author := &models.User{ID: "u1", TeamName: "team1"}
 		mUserRepo2.On("GetUserByID", mock.Anything, "u1").Return(author, nil)
-		mUserRepo2.On("GetActiveUsersByTeam", mock.Anything, "team1").Return([]models.User{
+		mTeamRepo2.On("GetTeamSettings", mock.Anything, "team1").Return(nil, apperrors.ErrNotFound)
+		mTeamRepo2.On("GetFallbackTeams", mock.Anything, "team1").Return([]string{}, nil)
+		mUserRepo2.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
 			{ID: "u1", IsActive: true}, // Only author
 		}, nil)
 
-		mPrRepo2.On("CreatePR", mock.Anything, mock.AnythingOfType("*models.PullRequest")).Return(nil)
+		mPrRepo2.On("CreatePR", mock.Anything, mock.AnythingOfType("*models.PullRequest"), mock.Anything).Return(nil)
 		reloaded := &models.PullRequest{ID: "pr-2", Status: "OPEN", Reviewers: []string{}, NeedMoreReviewers: true}
-		mPrRepo2.On("GetPRByID", mock.Anything, "pr-2").Return(reloaded, nil)
+		mPrRepo2.On("GetPRByID", mock.Anything, "", "pr-2").Return(reloaded, nil)
 
 		result, err := svc2.CreatePR(context.Background(), pr)
 		require.NoError(t, err)
@@ -224,20 +315,281 @@ func TestPRService_CreatePR(t *testing.T) {
 	t.Run("CreatePR_Failed", func(t *testing.T) {
 		mPrRepo3 := &mockPRRepo{}
 		mUserRepo3 := &mockUserRepo{}
-		svc3 := services.NewPRService(mPrRepo3, mUserRepo3, log)
+		mTeamRepo3 := &mockTeamRepo{}
+		svc3 := services.NewPRService(
+			mPrRepo3, mUserRepo3, mTeamRepo3, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
 		pr := &models.PullRequest{ID: "pr-3", Title: "Test", AuthorID: "u1"}
 		author := &models.User{ID: "u1", TeamName: "team1"}
 		mUserRepo3.On("GetUserByID", mock.Anything, "u1").Return(author, nil)
-		mUserRepo3.On("GetActiveUsersByTeam", mock.Anything, "team1").Return([]models.User{
+		mTeamRepo3.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mTeamRepo3.On("GetFallbackTeams", mock.Anything, "team1").Return([]string{}, nil)
+		mUserRepo3.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
 			{ID: "u2", IsActive: true},
 		}, nil)
 
-		mPrRepo3.On("CreatePR", mock.Anything, mock.AnythingOfType("*models.PullRequest")).Return(apperrors.ErrInternal)
+		mPrRepo3.On("CreatePR", mock.Anything, mock.AnythingOfType("*models.PullRequest"), mock.Anything).
+			Return(apperrors.ErrInternal)
 
 		_, err := svc3.CreatePR(context.Background(), pr)
 		assert.Error(t, err)
 	})
+
+	t.Run("TeamReviewersCount", func(t *testing.T) {
+		mPrRepo4 := &mockPRRepo{}
+		mUserRepo4 := &mockUserRepo{}
+		mTeamRepo4 := &mockTeamRepo{}
+		svc4 := services.NewPRService(
+			mPrRepo4, mUserRepo4, mTeamRepo4, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		three := 3
+		pr := &models.PullRequest{ID: "pr-4", Title: "Test", AuthorID: "u1"}
+		mUserRepo4.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1", TeamName: "team1"}, nil)
+		mTeamRepo4.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{ReviewersCount: &three}, nil)
+		mUserRepo4.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
+			{ID: "u1", IsActive: true},
+			{ID: "u2", IsActive: true},
+			{ID: "u3", IsActive: true},
+			{ID: "u4", IsActive: true},
+			{ID: "u5", IsActive: true},
+		}, nil)
+		mPrRepo4.On("CreatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return len(p.Reviewers) == 3 && !p.NeedMoreReviewers
+		}), mock.Anything).Return(nil)
+		mPrRepo4.On("GetPRByID", mock.Anything, "", "pr-4").Return(&models.PullRequest{ID: "pr-4"}, nil)
+
+		_, err := svc4.CreatePR(context.Background(), pr)
+		require.NoError(t, err)
+		mPrRepo4.AssertExpectations(t)
+	})
+
+	t.Run("TeamReviewersCount_NotEnoughCandidates", func(t *testing.T) {
+		mPrRepo5 := &mockPRRepo{}
+		mUserRepo5 := &mockUserRepo{}
+		mTeamRepo5 := &mockTeamRepo{}
+		svc5 := services.NewPRService(
+			mPrRepo5, mUserRepo5, mTeamRepo5, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		three := 3
+		pr := &models.PullRequest{ID: "pr-5", Title: "Test", AuthorID: "u1"}
+		mUserRepo5.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1", TeamName: "team1"}, nil)
+		mTeamRepo5.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{ReviewersCount: &three}, nil)
+		mTeamRepo5.On("GetFallbackTeams", mock.Anything, "team1").Return([]string{}, nil)
+		mUserRepo5.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
+			{ID: "u2", IsActive: true},
+			{ID: "u3", IsActive: true},
+		}, nil)
+		mPrRepo5.On("CreatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return len(p.Reviewers) == 2 && p.NeedMoreReviewers
+		}), mock.Anything).Return(nil)
+		mPrRepo5.On("GetPRByID", mock.Anything, "", "pr-5").Return(&models.PullRequest{ID: "pr-5"}, nil)
+
+		_, err := svc5.CreatePR(context.Background(), pr)
+		require.NoError(t, err)
+		mPrRepo5.AssertExpectations(t)
+	})
+
+	t.Run("FallbackTeams_FillShortage", func(t *testing.T) {
+		mPrRepo9 := &mockPRRepo{}
+		mUserRepo9 := &mockUserRepo{}
+		mTeamRepo9 := &mockTeamRepo{}
+		svc9 := services.NewPRService(
+			mPrRepo9, mUserRepo9, mTeamRepo9, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		pr := &models.PullRequest{ID: "pr-9", Title: "Test", AuthorID: "u1"}
+		mUserRepo9.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1", TeamName: "team1"}, nil)
+		mTeamRepo9.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mTeamRepo9.On("GetFallbackTeams", mock.Anything, "team1").Return([]string{"team2", "team3", "team4"}, nil)
+		mUserRepo9.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
+			{ID: "u1", IsActive: true},
+		}, nil)
+		mUserRepo9.On("GetAvailableUsersByTeam", mock.Anything, "team2").Return([]models.User{
+			{ID: "u5", IsActive: true},
+		}, nil)
+		mUserRepo9.On("GetAvailableUsersByTeam", mock.Anything, "team3").Return([]models.User{
+			{ID: "u6", IsActive: true},
+			{ID: "u7", IsActive: true},
+		}, nil)
+		mPrRepo9.On("CreatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return len(p.Reviewers) == 2 && p.Reviewers[0] == "u5" && !p.NeedMoreReviewers
+		}), mock.Anything).Return(nil)
+		mPrRepo9.On("GetPRByID", mock.Anything, "", "pr-9").Return(&models.PullRequest{ID: "pr-9"}, nil)
+
+		_, err := svc9.CreatePR(context.Background(), pr)
+		require.NoError(t, err)
+		mPrRepo9.AssertExpectations(t)
+		mUserRepo9.AssertNotCalled(t, "GetAvailableUsersByTeam", mock.Anything, "team4")
+	})
+
+	t.Run("FallbackTeamsError", func(t *testing.T) {
+		mPrRepo10 := &mockPRRepo{}
+		mUserRepo10 := &mockUserRepo{}
+		mTeamRepo10 := &mockTeamRepo{}
+		svc10 := services.NewPRService(
+			mPrRepo10, mUserRepo10, mTeamRepo10, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		pr := &models.PullRequest{ID: "pr-10", Title: "Test", AuthorID: "u1"}
+		mUserRepo10.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1", TeamName: "team1"}, nil)
+		mTeamRepo10.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mTeamRepo10.On("GetFallbackTeams", mock.Anything, "team1").Return(nil, apperrors.ErrInternal)
+		mUserRepo10.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{}, nil)
+
+		_, err := svc10.CreatePR(context.Background(), pr)
+		require.ErrorIs(t, err, apperrors.ErrInternal)
+		mPrRepo10.AssertNotCalled(t, "CreatePR", mock.Anything, mock.Anything)
+	})
+
+	t.Run("SkipsReviewersAtCapacity", func(t *testing.T) {
+		mPrRepo7 := &mockPRRepo{}
+		mUserRepo7 := &mockUserRepo{}
+		mTeamRepo7 := &mockTeamRepo{}
+		svc7 := services.NewPRService(
+			mPrRepo7, mUserRepo7, mTeamRepo7, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		busy, relaxed := 1, 5
+		pr := &models.PullRequest{ID: "pr-7", Title: "Test", AuthorID: "u1"}
+		mUserRepo7.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1", TeamName: "team1"}, nil)
+		mUserRepo7.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
+			{ID: "u1", IsActive: true},
+			{ID: "u2", IsActive: true, MaxOpenReviews: &busy},
+			{ID: "u3", IsActive: true, MaxOpenReviews: &relaxed},
+		}, nil)
+		mPrRepo7.On("GetOpenReviewLoadByTeam", mock.Anything, "team1").Return([]models.UserAssignment{
+			{UserID: "u2", Count: 1},
+			{UserID: "u3", Count: 4},
+		}, nil)
+		mTeamRepo7.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mTeamRepo7.On("GetFallbackTeams", mock.Anything, "team1").Return([]string{}, nil)
+		mPrRepo7.On("CreatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return len(p.Reviewers) == 1 && p.Reviewers[0] == "u3" && p.NeedMoreReviewers
+		}), mock.Anything).Return(nil)
+		mPrRepo7.On("GetPRByID", mock.Anything, "", "pr-7").Return(&models.PullRequest{ID: "pr-7"}, nil)
+
+		_, err := svc7.CreatePR(context.Background(), pr)
+		require.NoError(t, err)
+		mPrRepo7.AssertExpectations(t)
+	})
+
+	t.Run("TeamSettingsError", func(t *testing.T) {
+		mPrRepo6 := &mockPRRepo{}
+		mUserRepo6 := &mockUserRepo{}
+		mTeamRepo6 := &mockTeamRepo{}
+		svc6 := services.NewPRService(
+			mPrRepo6, mUserRepo6, mTeamRepo6, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		pr := &models.PullRequest{ID: "pr-6", Title: "Test", AuthorID: "u1"}
+		mUserRepo6.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1", TeamName: "team1"}, nil)
+		mUserRepo6.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
+			{ID: "u2", IsActive: true},
+		}, nil)
+		mTeamRepo6.On("GetTeamSettings", mock.Anything, "team1").Return(nil, apperrors.ErrInternal)
+
+		_, err := svc6.CreatePR(context.Background(), pr)
+		require.ErrorIs(t, err, apperrors.ErrInternal)
+		mPrRepo6.AssertNotCalled(t, "CreatePR", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPRService_CreatePR_Draft(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mPrRepo := &mockPRRepo{}
+	mUserRepo := &mockUserRepo{}
+	mTeamRepo := &mockTeamRepo{}
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, mTeamRepo, &mockRepositoryRepo{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+	mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1", TeamName: "team1"}, nil)
+	mPrRepo.On("CreatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+		return p.Draft && len(p.Reviewers) == 0 && !p.NeedMoreReviewers
+	}), mock.Anything).Return(nil)
+	mPrRepo.On("GetPRByID", mock.Anything, "", "pr-draft").
+		Return(&models.PullRequest{ID: "pr-draft", Status: "OPEN", Draft: true}, nil)
+
+	result, err := svc.CreatePR(context.Background(), &models.PullRequest{
+		ID: "pr-draft", Title: "WIP", AuthorID: "u1", Draft: true,
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Draft)
+	mPrRepo.AssertExpectations(t)
+	mUserRepo.AssertNotCalled(t, "GetAvailableUsersByTeam", mock.Anything, mock.Anything)
+	mTeamRepo.AssertNotCalled(t, "GetTeamSettings", mock.Anything, mock.Anything)
+}
+
+func TestPRService_ReadyPR(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("AssignsReviewers", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		mUserRepo := &mockUserRepo{}
+		mTeamRepo := &mockTeamRepo{}
+		svc := services.NewPRService(
+			mPrRepo, mUserRepo, mTeamRepo, &mockRepositoryRepo{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+		draft := &models.PullRequest{ID: "pr-1", AuthorID: "u1", Status: "OPEN", Draft: true}
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(draft, nil).Once()
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1", TeamName: "team1"}, nil)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
+			{ID: "u1", IsActive: true},
+			{ID: "u2", IsActive: true},
+			{ID: "u3", IsActive: true},
+		}, nil)
+		mPrRepo.On("MarkPRReady", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return len(p.Reviewers) == 2 && !slices.Contains(p.Reviewers, "u1") && !p.NeedMoreReviewers
+		}), mock.Anything).Return(nil)
+		reloaded := &models.PullRequest{ID: "pr-1", Status: "OPEN", Reviewers: []string{"u2", "u3"}}
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(reloaded, nil).Once()
+
+		result, err := svc.ReadyPR(context.Background(), "", "pr-1", nil)
+		require.NoError(t, err)
+		assert.False(t, result.Draft)
+		assert.Len(t, result.Reviewers, 2)
+		mPrRepo.AssertExpectations(t)
+	})
+
+	t.Run("NotDraft", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		svc := services.NewPRService(
+			mPrRepo, &mockUserRepo{}, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		pr := &models.PullRequest{ID: "pr-2", Status: "OPEN", Reviewers: []string{"u2"}}
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-2").Return(pr, nil)
+
+		result, err := svc.ReadyPR(context.Background(), "", "pr-2", nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u2"}, result.Reviewers)
+		mPrRepo.AssertNotCalled(t, "MarkPRReady", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		svc := services.NewPRService(
+			mPrRepo, &mockUserRepo{}, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-3").
+			Return(&models.PullRequest{ID: "pr-3", Status: "CLOSED", Draft: true}, nil)
+
+		_, err := svc.ReadyPR(context.Background(), "", "pr-3", nil)
+		assert.ErrorIs(t, err, apperrors.ErrPRClosed)
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		svc := services.NewPRService(
+			&mockPRRepo{}, &mockUserRepo{}, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		_, err := svc.ReadyPR(context.Background(), "", "", nil)
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
 }
 
 func TestPRService_ReassignReviewer(t *testing.T) {
@@ -245,7 +597,9 @@ func TestPRService_ReassignReviewer(t *testing.T) {
 	mPrRepo := &mockPRRepo{}
 	mUserRepo := &mockUserRepo{}
 
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 
 	t.Run("Success", func(t *testing.T) {
 		pr := &models.PullRequest{
@@ -254,17 +608,19 @@ func TestPRService_ReassignReviewer(t *testing.T) {
 			Reviewers: []string{"u2"},
 			AuthorID:  "u1",
 		}
-		mPrRepo.On("GetPRByID", mock.Anything, "pr-1").Return(pr, nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(pr, nil)
 		oldReviewer := &models.User{ID: "u2", TeamName: "team1"}
 		mUserRepo.On("GetUserByID", mock.Anything, "u2").Return(oldReviewer, nil)
-		mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").Return([]models.User{
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
 			{ID: "u3", IsActive: true},
 			{ID: "u4", IsActive: true},
 		}, nil)
 
-		mPrRepo.On("UpdatePR", mock.Anything, mock.AnythingOfType("*models.PullRequest")).Return(nil)
+		mPrRepo.On("UpdatePR", mock.Anything, mock.AnythingOfType("*models.PullRequest"),
+			models.AssignmentChange{Actor: "alice", Reason: models.AssignReasonReassigned}).Return(nil)
 
-		_, newReviewer, err := svc.ReassignReviewer(context.Background(), "pr-1", "u2")
+		ctx := reqctx.WithActor(context.Background(), "alice")
+		_, newReviewer, err := svc.ReassignReviewer(ctx, "", "pr-1", "u2")
 		require.NoError(t, err)
 		assert.NotEqual(t, "u2", newReviewer)
 		mPrRepo.AssertExpectations(t)
@@ -272,38 +628,55 @@ func TestPRService_ReassignReviewer(t *testing.T) {
 	})
 
 	t.Run("InvalidInput_EmptyPRID", func(t *testing.T) {
-		_, _, err := svc.ReassignReviewer(context.Background(), "", "u2")
+		_, _, err := svc.ReassignReviewer(context.Background(), "", "", "u2")
 		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
 	})
 
 	t.Run("InvalidInput_EmptyReviewerID", func(t *testing.T) {
-		_, _, err := svc.ReassignReviewer(context.Background(), "pr-1", "")
+		_, _, err := svc.ReassignReviewer(context.Background(), "", "pr-1", "")
 		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
 	})
 
 	t.Run("PRNotFound", func(t *testing.T) {
-		mPrRepo.On("GetPRByID", mock.Anything, "pr-nonexist").Return(nil, apperrors.ErrNotFound)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-nonexist").Return(nil, apperrors.ErrNotFound)
 
-		_, _, err := svc.ReassignReviewer(context.Background(), "pr-nonexist", "u2")
+		_, _, err := svc.ReassignReviewer(context.Background(), "", "pr-nonexist", "u2")
 		assert.ErrorIs(t, err, apperrors.ErrNotFound)
 	})
 
 	t.Run("PRMerged", func(t *testing.T) {
 		mPrRepo4 := &mockPRRepo{}
 		mUserRepo4 := &mockUserRepo{}
-		svc4 := services.NewPRService(mPrRepo4, mUserRepo4, log)
+		svc4 := services.NewPRService(
+			mPrRepo4, mUserRepo4, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
 		pr := &models.PullRequest{ID: "pr-merged", Status: "MERGED"}
-		mPrRepo4.On("GetPRByID", mock.Anything, "pr-merged").Return(pr, nil)
+		mPrRepo4.On("GetPRByID", mock.Anything, "", "pr-merged").Return(pr, nil)
 
-		_, _, err := svc4.ReassignReviewer(context.Background(), "pr-merged", "u2")
+		_, _, err := svc4.ReassignReviewer(context.Background(), "", "pr-merged", "u2")
 		assert.ErrorIs(t, err, apperrors.ErrPRMerged)
 	})
 
+	t.Run("PRClosed", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		svc := services.NewPRService(
+			mPrRepo, &mockUserRepo{}, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		pr := &models.PullRequest{ID: "pr-closed", Status: "CLOSED", Reviewers: []string{"u2"}}
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-closed").Return(pr, nil)
+
+		_, _, err := svc.ReassignReviewer(context.Background(), "", "pr-closed", "u2")
+		assert.ErrorIs(t, err, apperrors.ErrPRClosed)
+	})
+
 	t.Run("ReviewerNotAssigned", func(t *testing.T) {
 		mPrRepo5 := &mockPRRepo{}
 		mUserRepo5 := &mockUserRepo{}
-		svc5 := services.NewPRService(mPrRepo5, mUserRepo5, log)
+		svc5 := services.NewPRService(
+			mPrRepo5, mUserRepo5, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
 		pr := &models.PullRequest{
 			ID:        "pr-notassigned",
@@ -311,18 +684,21 @@ func TestPRService_ReassignReviewer(t *testing.T) {
 			Reviewers: []string{"u2"},
 			AuthorID:  "u1",
 		}
-		mPrRepo5.On("GetPRByID", mock.Anything, "pr-notassigned").Return(pr, nil)
+		mPrRepo5.On("GetPRByID", mock.Anything, "", "pr-notassigned").Return(pr, nil)
 		oldReviewer := &models.User{ID: "u3", TeamName: "team1"}
 		mUserRepo5.On("GetUserByID", mock.Anything, "u3").Return(oldReviewer, nil)
 
-		_, _, err := svc5.ReassignReviewer(context.Background(), "pr-notassigned", "u3")
+		_, _, err := svc5.ReassignReviewer(context.Background(), "", "pr-notassigned", "u3")
 		assert.ErrorIs(t, err, apperrors.ErrNotAssigned)
 	})
 
 	t.Run("NoCandidate", func(t *testing.T) {
 		mPrRepo6 := &mockPRRepo{}
 		mUserRepo6 := &mockUserRepo{}
-		svc6 := services.NewPRService(mPrRepo6, mUserRepo6, log)
+		mTeamRepo6 := &mockTeamRepo{}
+		svc6 := services.NewPRService(
+			mPrRepo6, mUserRepo6, mTeamRepo6, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
 		pr := &models.PullRequest{
 			ID:        "pr-nocandidate",
@@ -330,14 +706,241 @@ func TestPRService_ReassignReviewer(t *testing.T) {
 			Reviewers: []string{"u2"},
 			AuthorID:  "u1",
 		}
-		mPrRepo6.On("GetPRByID", mock.Anything, "pr-nocandidate").Return(pr, nil)
+		mPrRepo6.On("GetPRByID", mock.Anything, "", "pr-nocandidate").Return(pr, nil)
 		oldReviewer := &models.User{ID: "u2", TeamName: "team1"}
 		mUserRepo6.On("GetUserByID", mock.Anything, "u2").Return(oldReviewer, nil)
-		mUserRepo6.On("GetActiveUsersByTeam", mock.Anything, "team1").Return([]models.User{}, nil)
+		mTeamRepo6.On("GetFallbackTeams", mock.Anything, "team1").Return([]string{}, nil)
+		mUserRepo6.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{}, nil)
 
-		_, _, err := svc6.ReassignReviewer(context.Background(), "pr-nocandidate", "u2")
+		_, _, err := svc6.ReassignReviewer(context.Background(), "", "pr-nocandidate", "u2")
 		assert.ErrorIs(t, err, apperrors.ErrNoCandidate)
 	})
+
+	t.Run("FallbackTeam", func(t *testing.T) {
+		mPrRepo9 := &mockPRRepo{}
+		mUserRepo9 := &mockUserRepo{}
+		mTeamRepo9 := &mockTeamRepo{}
+		svc9 := services.NewPRService(
+			mPrRepo9, mUserRepo9, mTeamRepo9, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		pr := &models.PullRequest{
+			ID:            "pr-fallback",
+			Status:        "OPEN",
+			Reviewers:     []string{"u2", "u3"},
+			AuthorID:      "u1",
+			ReviewerTeams: map[string]string{"u2": "team1", "u3": "team1"},
+		}
+		mPrRepo9.On("GetPRByID", mock.Anything, "", "pr-fallback").Return(pr, nil)
+		mUserRepo9.On("GetUserByID", mock.Anything, "u2").Return(&models.User{ID: "u2", TeamName: "team1"}, nil)
+		mUserRepo9.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
+			{ID: "u1", IsActive: true},
+			{ID: "u3", IsActive: true},
+		}, nil)
+		mTeamRepo9.On("GetFallbackTeams", mock.Anything, "team1").Return([]string{"team2"}, nil)
+		mUserRepo9.On("GetAvailableUsersByTeam", mock.Anything, "team2").Return([]models.User{
+			{ID: "u9", IsActive: true},
+		}, nil)
+		mPrRepo9.On("UpdatePR", mock.Anything, mock.AnythingOfType("*models.PullRequest"), mock.Anything).Return(nil)
+
+		result, newReviewer, err := svc9.ReassignReviewer(context.Background(), "", "pr-fallback", "u2")
+		require.NoError(t, err)
+		assert.Equal(t, "u9", newReviewer)
+		assert.Equal(t, []string{"u9", "u3"}, result.Reviewers)
+		assert.Equal(t, map[string]string{"u9": "team2", "u3": "team1"}, result.ReviewerTeams)
+	})
+
+	t.Run("NoCandidate_AllAtCapacity", func(t *testing.T) {
+		mPrRepo8 := &mockPRRepo{}
+		mUserRepo8 := &mockUserRepo{}
+		mTeamRepo8 := &mockTeamRepo{}
+		svc8 := services.NewPRService(
+			mPrRepo8, mUserRepo8, mTeamRepo8, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		limit := 2
+		pr := &models.PullRequest{
+			ID:        "pr-full",
+			Status:    "OPEN",
+			Reviewers: []string{"u2"},
+			AuthorID:  "u1",
+		}
+		mPrRepo8.On("GetPRByID", mock.Anything, "", "pr-full").Return(pr, nil)
+		mUserRepo8.On("GetUserByID", mock.Anything, "u2").Return(&models.User{ID: "u2", TeamName: "team1"}, nil)
+		mTeamRepo8.On("GetFallbackTeams", mock.Anything, "team1").Return([]string{}, nil)
+		mUserRepo8.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
+			{ID: "u3", IsActive: true, MaxOpenReviews: &limit},
+		}, nil)
+		mPrRepo8.On("GetOpenReviewLoadByTeam", mock.Anything, "team1").Return([]models.UserAssignment{
+			{UserID: "u3", Count: 2},
+		}, nil)
+
+		_, _, err := svc8.ReassignReviewer(context.Background(), "", "pr-full", "u2")
+		assert.ErrorIs(t, err, apperrors.ErrNoCandidate)
+	})
+
+	t.Run("LeastLoaded_PrefersIdleMember", func(t *testing.T) {
+		mPrRepo7 := &mockPRRepo{}
+		mUserRepo7 := &mockUserRepo{}
+		selector := services.NewLeastLoadedSelector(mPrRepo7)
+		svc7 := services.NewPRService(
+			mPrRepo7, mUserRepo7, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(), selector, 2, log)
+
+		pr := &models.PullRequest{
+			ID:        "pr-balanced",
+			Status:    "OPEN",
+			Reviewers: []string{"u2"},
+			AuthorID:  "u1",
+		}
+		mPrRepo7.On("GetPRByID", mock.Anything, "", "pr-balanced").Return(pr, nil)
+		mUserRepo7.On("GetUserByID", mock.Anything, "u2").Return(&models.User{ID: "u2", TeamName: "team1"}, nil)
+		mUserRepo7.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
+			{ID: "u3", IsActive: true},
+			{ID: "u4", IsActive: true},
+		}, nil)
+		mPrRepo7.On("GetOpenReviewLoadByTeam", mock.Anything, "team1").Return([]models.UserAssignment{
+			{UserID: "u4", Count: 0},
+			{UserID: "u3", Count: 4},
+		}, nil)
+		mPrRepo7.On("UpdatePR", mock.Anything, mock.AnythingOfType("*models.PullRequest"), mock.Anything).Return(nil)
+
+		_, newReviewer, err := svc7.ReassignReviewer(context.Background(), "", "pr-balanced", "u2")
+		require.NoError(t, err)
+		assert.Equal(t, "u4", newReviewer)
+		mPrRepo7.AssertExpectations(t)
+	})
+}
+
+func TestPRService_GetPR(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mPrRepo := &mockPRRepo{}
+	svc := services.NewPRService(
+		mPrRepo, &mockUserRepo{}, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
+
+	t.Run("Success", func(t *testing.T) {
+		pr := &models.PullRequest{
+			ID:           "pr-1",
+			Status:       "OPEN",
+			Reviewers:    []string{"u2", "u3"},
+			ReviewStates: map[string]string{"u2": models.ReviewStateApproved, "u3": models.ReviewStatePending},
+		}
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(pr, nil)
+
+		result, err := svc.GetPR(context.Background(), "", "pr-1")
+		require.NoError(t, err)
+		assert.Equal(t, models.ReviewStateApproved, result.ReviewStates["u2"])
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-x").Return(nil, apperrors.ErrNotFound)
+
+		_, err := svc.GetPR(context.Background(), "", "pr-x")
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		_, err := svc.GetPR(context.Background(), "", "")
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+}
+
+func TestPRService_GetPRHistory(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mPrRepo := &mockPRRepo{}
+	svc := services.NewPRService(
+		mPrRepo, &mockUserRepo{}, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
+
+	t.Run("Success", func(t *testing.T) {
+		history := []models.AssignmentEvent{
+			{ReviewerID: "u2", Action: models.AssignmentAssigned, Reason: models.AssignReasonCreated, Actor: "alice"},
+			{ReviewerID: "u2", Action: models.AssignmentRemoved, Reason: models.AssignReasonReassigned, Actor: "bob"},
+			{ReviewerID: "u3", Action: models.AssignmentAssigned, Reason: models.AssignReasonReassigned, Actor: "bob"},
+		}
+		mPrRepo.On("ExistsPR", mock.Anything, "", "pr-1").Return(true, nil)
+		mPrRepo.On("GetAssignmentHistory", mock.Anything, "", "pr-1").Return(history, nil)
+
+		result, err := svc.GetPRHistory(context.Background(), "", "pr-1")
+		require.NoError(t, err)
+		assert.Equal(t, history, result)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mPrRepo.On("ExistsPR", mock.Anything, "", "pr-x").Return(false, nil)
+
+		_, err := svc.GetPRHistory(context.Background(), "", "pr-x")
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+		mPrRepo.AssertNotCalled(t, "GetAssignmentHistory", mock.Anything, "", "pr-x")
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		_, err := svc.GetPRHistory(context.Background(), "", "")
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+}
+
+func TestPRService_SubmitReview(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	openPR := &models.PullRequest{ID: "pr-1", Status: "OPEN", Reviewers: []string{"u2", "u3"}}
+
+	t.Run("Approve", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		svc := services.NewPRService(
+			mPrRepo, &mockUserRepo{}, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(openPR, nil).Once()
+		mPrRepo.On("SetReviewState", mock.Anything, "", "pr-1", "u2", models.ReviewStateApproved).Return(nil)
+		reloaded := &models.PullRequest{
+			ID:           "pr-1",
+			Status:       "OPEN",
+			Reviewers:    []string{"u2", "u3"},
+			ReviewStates: map[string]string{"u2": models.ReviewStateApproved, "u3": models.ReviewStatePending},
+		}
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(reloaded, nil).Once()
+
+		result, err := svc.SubmitReview(context.Background(), "", "pr-1", "u2", models.ReviewStateApproved)
+		require.NoError(t, err)
+		assert.Equal(t, models.ReviewStateApproved, result.ReviewStates["u2"])
+		mPrRepo.AssertExpectations(t)
+	})
+
+	t.Run("UnknownState", func(t *testing.T) {
+		svc := services.NewPRService(
+			&mockPRRepo{}, &mockUserRepo{}, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		_, err := svc.SubmitReview(context.Background(), "", "pr-1", "u2", models.ReviewStatePending)
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+
+	t.Run("NotAssigned", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		svc := services.NewPRService(
+			mPrRepo, &mockUserRepo{}, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(openPR, nil)
+
+		_, err := svc.SubmitReview(context.Background(), "", "pr-1", "u9", models.ReviewStateChangesRequested)
+		assert.ErrorIs(t, err, apperrors.ErrNotAssigned)
+		mPrRepo.AssertNotCalled(t, "SetReviewState",
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Merged", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		svc := services.NewPRService(
+			mPrRepo, &mockUserRepo{}, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-2").
+			Return(&models.PullRequest{ID: "pr-2", Status: "MERGED", Reviewers: []string{"u2"}}, nil)
+
+		_, err := svc.SubmitReview(context.Background(), "", "pr-2", "u2", models.ReviewStateApproved)
+		assert.ErrorIs(t, err, apperrors.ErrPRMerged)
+	})
 }
 
 func TestPRService_MergePR(t *testing.T) {
@@ -345,58 +948,445 @@ func TestPRService_MergePR(t *testing.T) {
 	mPrRepo := &mockPRRepo{}
 	mUserRepo := &mockUserRepo{}
 
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
+	mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(nil, apperrors.ErrNotFound)
 
 	t.Run("Success", func(t *testing.T) {
-		mPrRepo.On("MergePR", mock.Anything, "pr-1").Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").
+			Return(&models.PullRequest{ID: "pr-1", AuthorID: "u1", Status: "OPEN"}, nil).Once()
+		mPrRepo.On("MergePR", mock.Anything, "", "pr-1").Return(nil)
 		reloaded := &models.PullRequest{ID: "pr-1", Status: "MERGED", Title: "Test"}
-		mPrRepo.On("GetPRByID", mock.Anything, "pr-1").Return(reloaded, nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(reloaded, nil).Once()
 
-		result, err := svc.MergePR(context.Background(), "pr-1")
+		result, err := svc.MergePR(context.Background(), "", "pr-1", false, "")
 		require.NoError(t, err)
 		assert.Equal(t, "MERGED", result.Status)
 	})
 
+	t.Run("AlreadyMerged", func(t *testing.T) {
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-2").
+			Return(&models.PullRequest{ID: "pr-2", Status: "MERGED"}, nil)
+
+		result, err := svc.MergePR(context.Background(), "", "pr-2", false, "")
+		require.NoError(t, err)
+		assert.Equal(t, "MERGED", result.Status)
+		mPrRepo.AssertNotCalled(t, "MergePR", mock.Anything, "", "pr-2")
+	})
+
 	t.Run("InvalidInput", func(t *testing.T) {
-		_, err := svc.MergePR(context.Background(), "")
+		_, err := svc.MergePR(context.Background(), "", "", false, "")
 		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
 	})
 
-	t.Run("MergeFailed", func(t *testing.T) {
-		mPrRepo7 := &mockPRRepo{}
-		mUserRepo7 := &mockUserRepo{}
-		svc7 := services.NewPRService(mPrRepo7, mUserRepo7, log)
+	t.Run("PRNotFound", func(t *testing.T) {
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-missing").Return(nil, apperrors.ErrNotFound)
 
-		mPrRepo7.On("MergePR", mock.Anything, "pr-merge-fail").Return(apperrors.ErrInternal)
+		_, err := svc.MergePR(context.Background(), "", "pr-missing", false, "")
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	t.Run("MergeFailed", func(t *testing.T) {
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-merge-fail").
+			Return(&models.PullRequest{ID: "pr-merge-fail", AuthorID: "u1", Status: "OPEN"}, nil)
+		mPrRepo.On("MergePR", mock.Anything, "", "pr-merge-fail").Return(apperrors.ErrInternal)
 
-		_, err := svc7.MergePR(context.Background(), "pr-merge-fail")
+		_, err := svc.MergePR(context.Background(), "", "pr-merge-fail", false, "")
 		assert.Error(t, err)
 	})
 
 	t.Run("PRNotFoundAfterMerge", func(t *testing.T) {
-		mPrRepo8 := &mockPRRepo{}
-		mUserRepo8 := &mockUserRepo{}
-		svc8 := services.NewPRService(mPrRepo8, mUserRepo8, log)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-notfound").
+			Return(&models.PullRequest{ID: "pr-notfound", AuthorID: "u1", Status: "OPEN"}, nil).Once()
+		mPrRepo.On("MergePR", mock.Anything, "", "pr-notfound").Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-notfound").Return(nil, apperrors.ErrNotFound).Once()
+
+		_, err := svc.MergePR(context.Background(), "", "pr-notfound", false, "")
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-closed").
+			Return(&models.PullRequest{ID: "pr-closed", Status: "CLOSED"}, nil)
+
+		_, err := svc.MergePR(context.Background(), "", "pr-closed", false, "")
+		assert.ErrorIs(t, err, apperrors.ErrPRClosed)
+		mPrRepo.AssertNotCalled(t, "MergePR", mock.Anything, "", "pr-closed")
+	})
+}
+
+func TestPRService_MergePolicy(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	two := 2
+	one := 1
+	author := &models.User{ID: "u1", TeamName: "team1"}
+	reviewed := func(states map[string]string) *models.PullRequest {
+		return &models.PullRequest{
+			ID: "pr-1", AuthorID: "u1", Status: "OPEN", Reviewers: []string{"u2", "u3"}, ReviewStates: states,
+		}
+	}
+	setup := func(pr *models.PullRequest) (*mockPRRepo, *services.PRService, *testutil.AuditRecorder) {
+		mPrRepo := &mockPRRepo{}
+		mUserRepo := &mockUserRepo{}
+		mTeamRepo := &mockTeamRepo{}
+		audit, recorder := testutil.NewRecordingAudit()
+		svc := services.NewPRService(
+			mPrRepo, mUserRepo, mTeamRepo, &mockRepositoryRepo{}, audit, services.NewRandomSelector(), 2, log)
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(author, nil)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").
+			Return(&models.TeamSettings{RequiredApprovals: &two}, nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(pr, nil).Once()
+		return mPrRepo, svc, recorder
+	}
 
-		mPrRepo8.On("MergePR", mock.Anything, "pr-notfound").Return(nil)
-		mPrRepo8.On("GetPRByID", mock.Anything, "pr-notfound").Return(nil, apperrors.ErrNotFound)
+	t.Run("NotEnoughApprovals", func(t *testing.T) {
+		mPrRepo, svc, recorder := setup(reviewed(map[string]string{"u2": models.ReviewStateApproved, "u3": "PENDING"}))
+
+		_, err := svc.MergePR(context.Background(), "", "pr-1", false, "")
+		require.ErrorIs(t, err, apperrors.ErrMergeBlocked)
+		assert.Empty(t, recorder.Actions())
+		var blocked *apperrors.MergeBlockedError
+		require.ErrorAs(t, err, &blocked)
+		assert.Equal(t, 2, blocked.RequiredApprovals)
+		assert.Equal(t, 1, blocked.Approvals)
+		assert.Equal(t, []string{"u3"}, blocked.PendingReviewers)
+		assert.Empty(t, blocked.ChangesRequestedBy)
+		mPrRepo.AssertNotCalled(t, "MergePR", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ChangesRequested", func(t *testing.T) {
+		mPrRepo, svc, _ := setup(reviewed(map[string]string{
+			"u2": models.ReviewStateApproved, "u3": models.ReviewStateChangesRequested,
+		}))
+
+		_, err := svc.MergePR(context.Background(), "", "pr-1", false, "")
+		var blocked *apperrors.MergeBlockedError
+		require.ErrorAs(t, err, &blocked)
+		assert.Equal(t, []string{"u3"}, blocked.ChangesRequestedBy)
+		assert.Empty(t, blocked.PendingReviewers)
+		mPrRepo.AssertNotCalled(t, "MergePR", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Satisfied", func(t *testing.T) {
+		mPrRepo, svc, recorder := setup(reviewed(map[string]string{
+			"u2": models.ReviewStateApproved, "u3": models.ReviewStateApproved,
+		}))
+		mPrRepo.On("MergePR", mock.Anything, "", "pr-1").Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").
+			Return(&models.PullRequest{ID: "pr-1", Status: "MERGED"}, nil).Once()
+
+		result, err := svc.MergePR(context.Background(), "", "pr-1", false, "")
+		require.NoError(t, err)
+		assert.Equal(t, "MERGED", result.Status)
+		assert.Equal(t, []string{models.AuditPRMerged}, recorder.Actions())
+		mPrRepo.AssertExpectations(t)
+	})
+
+	t.Run("ForceRecordsOverride", func(t *testing.T) {
+		mPrRepo, svc, recorder := setup(reviewed(map[string]string{"u3": models.ReviewStateChangesRequested}))
+		mPrRepo.On("ForceMergePR", mock.Anything, &models.MergeOverride{
+			PRID:               "pr-1",
+			Reason:             "hotfix",
+			RequiredApprovals:  2,
+			PendingReviewers:   []string{"u2"},
+			ChangesRequestedBy: []string{"u3"},
+		}).Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").
+			Return(&models.PullRequest{ID: "pr-1", Status: "MERGED"}, nil).Once()
+
+		result, err := svc.MergePR(context.Background(), "", "pr-1", true, "hotfix")
+		require.NoError(t, err)
+		assert.Equal(t, "MERGED", result.Status)
+		require.Equal(t, []string{models.AuditPRForceMerged}, recorder.Actions())
+		assert.JSONEq(t, `"OPEN"`, string(jsonField(t, recorder.Events[0].Before, "status")))
+		assert.JSONEq(t, `"MERGED"`, string(jsonField(t, recorder.Events[0].After, "status")))
+		mPrRepo.AssertExpectations(t)
+		mPrRepo.AssertNotCalled(t, "MergePR", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ForceWithoutBlockMergesNormally", func(t *testing.T) {
+		mPrRepo, svc, _ := setup(reviewed(map[string]string{
+			"u2": models.ReviewStateApproved, "u3": models.ReviewStateApproved,
+		}))
+		mPrRepo.On("MergePR", mock.Anything, "", "pr-1").Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").
+			Return(&models.PullRequest{ID: "pr-1", Status: "MERGED"}, nil).Once()
+
+		_, err := svc.MergePR(context.Background(), "", "pr-1", true, "")
+		require.NoError(t, err)
+		mPrRepo.AssertNotCalled(t, "ForceMergePR", mock.Anything, mock.Anything)
+	})
+
+	t.Run("RepositoryPolicyOverridesTeam", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		mRepoRepo := &mockRepositoryRepo{}
+		svc := services.NewPRService(
+			mPrRepo, &mockUserRepo{}, &mockTeamRepo{}, mRepoRepo, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+		pr := reviewed(map[string]string{"u2": models.ReviewStateApproved})
+		pr.Repository = "mono"
+		mRepoRepo.On("GetRepository", mock.Anything, "mono").Return(&models.Repository{
+			Name: "mono", Settings: &models.RepositorySettings{RequiredApprovals: &one},
+		}, nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "mono", "pr-1").Return(pr, nil).Once()
+		mPrRepo.On("MergePR", mock.Anything, "mono", "pr-1").Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "mono", "pr-1").
+			Return(&models.PullRequest{ID: "pr-1", Repository: "mono", Status: "MERGED"}, nil).Once()
+
+		_, err := svc.MergePR(context.Background(), "mono", "pr-1", false, "")
+		require.NoError(t, err)
+		mPrRepo.AssertExpectations(t)
+	})
+
+	t.Run("RepositoryDefaultTeamPolicy", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		mRepoRepo := &mockRepositoryRepo{}
+		mTeamRepo := &mockTeamRepo{}
+		svc := services.NewPRService(
+			mPrRepo, &mockUserRepo{}, mTeamRepo, mRepoRepo, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+		pr := reviewed(map[string]string{"u2": models.ReviewStateApproved})
+		pr.Repository = "mono"
+		owners := "owners"
+		mRepoRepo.On("GetRepository", mock.Anything, "mono").Return(&models.Repository{
+			Name: "mono", Settings: &models.RepositorySettings{DefaultTeamName: &owners},
+		}, nil)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "owners").
+			Return(&models.TeamSettings{RequiredApprovals: &two}, nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "mono", "pr-1").Return(pr, nil)
+
+		_, err := svc.MergePR(context.Background(), "mono", "pr-1", false, "")
+		assert.ErrorIs(t, err, apperrors.ErrMergeBlocked)
+	})
+
+	t.Run("PolicyFetchFailed", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		mUserRepo := &mockUserRepo{}
+		svc := services.NewPRService(
+			mPrRepo, mUserRepo, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(reviewed(nil), nil)
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(nil, apperrors.ErrInternal)
+
+		_, err := svc.MergePR(context.Background(), "", "pr-1", false, "")
+		assert.ErrorIs(t, err, apperrors.ErrInternal)
+		mPrRepo.AssertNotCalled(t, "MergePR", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestPRService_ClosePR(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("Success", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		svc := services.NewPRService(
+			mPrRepo, &mockUserRepo{}, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mPrRepo.On("GetPRByID", mock.Anything, "svc", "pr-1").
+			Return(&models.PullRequest{ID: "pr-1", Repository: "svc", Status: "OPEN"}, nil).Once()
+		mPrRepo.On("ClosePR", mock.Anything, "svc", "pr-1").Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "svc", "pr-1").
+			Return(&models.PullRequest{ID: "pr-1", Repository: "svc", Status: "CLOSED"}, nil).Once()
+
+		result, err := svc.ClosePR(context.Background(), "svc", "pr-1")
+		require.NoError(t, err)
+		assert.Equal(t, "CLOSED", result.Status)
+		mPrRepo.AssertExpectations(t)
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		svc := services.NewPRService(
+			&mockPRRepo{}, &mockUserRepo{}, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		_, err := svc.ClosePR(context.Background(), "", "")
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+
+	t.Run("Merged", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		svc := services.NewPRService(
+			mPrRepo, &mockUserRepo{}, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-2").
+			Return(&models.PullRequest{ID: "pr-2", Status: "MERGED"}, nil)
+
+		_, err := svc.ClosePR(context.Background(), "", "pr-2")
+		assert.ErrorIs(t, err, apperrors.ErrPRMerged)
+		mPrRepo.AssertNotCalled(t, "ClosePR", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("AlreadyClosed", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		audit, recorder := testutil.NewRecordingAudit()
+		svc := services.NewPRService(
+			mPrRepo, &mockUserRepo{}, &mockTeamRepo{}, &mockRepositoryRepo{}, audit,
+			services.NewRandomSelector(), 2, log)
+
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-4").
+			Return(&models.PullRequest{ID: "pr-4", Status: "CLOSED"}, nil)
+
+		result, err := svc.ClosePR(context.Background(), "", "pr-4")
+		require.NoError(t, err)
+		assert.Equal(t, "CLOSED", result.Status)
+		assert.Empty(t, recorder.Events)
+		mPrRepo.AssertNotCalled(t, "ClosePR", mock.Anything, mock.Anything, mock.Anything)
+	})
 
-		_, err := svc8.MergePR(context.Background(), "pr-notfound")
+	t.Run("NotFound", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		svc := services.NewPRService(
+			mPrRepo, &mockUserRepo{}, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-3").Return(nil, apperrors.ErrNotFound)
+
+		_, err := svc.ClosePR(context.Background(), "", "pr-3")
 		assert.ErrorIs(t, err, apperrors.ErrNotFound)
 	})
 }
 
+func TestPRService_ReopenPR(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	author := &models.User{ID: "u1", TeamName: "team1"}
+
+	t.Run("ReplacesInactiveReviewers", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		mUserRepo := &mockUserRepo{}
+		mTeamRepo := &mockTeamRepo{}
+		svc := services.NewPRService(
+			mPrRepo, mUserRepo, mTeamRepo, &mockRepositoryRepo{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+		closed := &models.PullRequest{ID: "pr-1", AuthorID: "u1", Status: "CLOSED", Reviewers: []string{"u2", "u3"}}
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(closed, nil).Once()
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(author, nil)
+		mUserRepo.On("GetUserByID", mock.Anything, "u2").
+			Return(&models.User{ID: "u2", TeamName: "team1", IsActive: true}, nil)
+		mUserRepo.On("GetUserByID", mock.Anything, "u3").
+			Return(&models.User{ID: "u3", TeamName: "team1", IsActive: false}, nil)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
+			{ID: "u1", IsActive: true},
+			{ID: "u2", IsActive: true},
+			{ID: "u4", IsActive: true},
+		}, nil)
+		mPrRepo.On("ReopenPR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return slices.Equal(p.Reviewers, []string{"u2", "u4"}) && !p.NeedMoreReviewers
+		}), mock.Anything).Return(nil)
+		reloaded := &models.PullRequest{ID: "pr-1", Status: "OPEN", Reviewers: []string{"u2", "u4"}}
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-1").Return(reloaded, nil).Once()
+
+		result, err := svc.ReopenPR(context.Background(), "", "pr-1")
+		require.NoError(t, err)
+		assert.Equal(t, "OPEN", result.Status)
+		mPrRepo.AssertExpectations(t)
+	})
+
+	t.Run("ReplacesAbsentAndFullReviewers", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		mUserRepo := &mockUserRepo{}
+		mTeamRepo := &mockTeamRepo{}
+		svc := services.NewPRService(
+			mPrRepo, mUserRepo, mTeamRepo, &mockRepositoryRepo{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+		limit := 1
+		closed := &models.PullRequest{ID: "pr-5", AuthorID: "u1", Status: "CLOSED", Reviewers: []string{"u2", "u3"}}
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-5").Return(closed, nil).Once()
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(author, nil)
+		// u2 is absent, so the team's available users leave them out; u3 is at their open review limit.
+		mUserRepo.On("GetUserByID", mock.Anything, "u2").
+			Return(&models.User{ID: "u2", TeamName: "team1", IsActive: true}, nil)
+		mUserRepo.On("GetUserByID", mock.Anything, "u3").
+			Return(&models.User{ID: "u3", TeamName: "team1", IsActive: true, MaxOpenReviews: &limit}, nil)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mTeamRepo.On("GetFallbackTeams", mock.Anything, "team1").Return([]string{}, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
+			{ID: "u1", IsActive: true},
+			{ID: "u3", IsActive: true, MaxOpenReviews: &limit},
+			{ID: "u4", IsActive: true},
+		}, nil)
+		mPrRepo.On("GetOpenReviewLoadByTeam", mock.Anything, "team1").
+			Return([]models.UserAssignment{{UserID: "u3", Count: 1}}, nil)
+		mPrRepo.On("ReopenPR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return slices.Equal(p.Reviewers, []string{"u4"}) && p.NeedMoreReviewers
+		}), mock.Anything).Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-5").
+			Return(&models.PullRequest{ID: "pr-5", Status: "OPEN", Reviewers: []string{"u4"}}, nil).Once()
+
+		_, err := svc.ReopenPR(context.Background(), "", "pr-5")
+		require.NoError(t, err)
+		mPrRepo.AssertExpectations(t)
+	})
+
+	t.Run("NoCandidatesNeedsMore", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		mUserRepo := &mockUserRepo{}
+		mTeamRepo := &mockTeamRepo{}
+		svc := services.NewPRService(
+			mPrRepo, mUserRepo, mTeamRepo, &mockRepositoryRepo{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+		closed := &models.PullRequest{ID: "pr-2", AuthorID: "u1", Status: "CLOSED", Reviewers: []string{"gone"}}
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-2").Return(closed, nil).Once()
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(author, nil)
+		mUserRepo.On("GetUserByID", mock.Anything, "gone").Return(nil, apperrors.ErrNotFound)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mTeamRepo.On("GetFallbackTeams", mock.Anything, "team1").Return([]string{}, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{}, nil)
+		mPrRepo.On("ReopenPR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return len(p.Reviewers) == 0 && p.NeedMoreReviewers
+		}), mock.Anything).Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-2").
+			Return(&models.PullRequest{ID: "pr-2", Status: "OPEN", NeedMoreReviewers: true}, nil).Once()
+
+		result, err := svc.ReopenPR(context.Background(), "", "pr-2")
+		require.NoError(t, err)
+		assert.True(t, result.NeedMoreReviewers)
+		mPrRepo.AssertExpectations(t)
+	})
+
+	t.Run("AlreadyOpen", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		svc := services.NewPRService(
+			mPrRepo, &mockUserRepo{}, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-3").
+			Return(&models.PullRequest{ID: "pr-3", Status: "OPEN"}, nil)
+
+		result, err := svc.ReopenPR(context.Background(), "", "pr-3")
+		require.NoError(t, err)
+		assert.Equal(t, "OPEN", result.Status)
+		mPrRepo.AssertNotCalled(t, "ReopenPR", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Merged", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		svc := services.NewPRService(
+			mPrRepo, &mockUserRepo{}, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mPrRepo.On("GetPRByID", mock.Anything, "", "pr-4").
+			Return(&models.PullRequest{ID: "pr-4", Status: "MERGED"}, nil)
+
+		_, err := svc.ReopenPR(context.Background(), "", "pr-4")
+		assert.ErrorIs(t, err, apperrors.ErrPRMerged)
+	})
+}
+
 func TestPRService_GetTotalPRs(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mPrRepo := &mockPRRepo{}
 	mUserRepo := &mockUserRepo{}
 
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 
 	t.Run("Success", func(t *testing.T) {
-		mPrRepo.On("GetTotalPRs", mock.Anything).Return(5, nil)
+		mPrRepo.On("GetTotalPRs", mock.Anything, "").Return(5, nil)
 
-		total, err := svc.GetTotalPRs(context.Background())
+		total, err := svc.GetTotalPRs(context.Background(), "")
 		require.NoError(t, err)
 		assert.Equal(t, 5, total)
 	})
@@ -404,11 +1394,13 @@ func TestPRService_GetTotalPRs(t *testing.T) {
 	t.Run("Error", func(t *testing.T) {
 		mPrRepo9 := &mockPRRepo{}
 		mUserRepo9 := &mockUserRepo{}
-		svc9 := services.NewPRService(mPrRepo9, mUserRepo9, log)
+		svc9 := services.NewPRService(
+			mPrRepo9, mUserRepo9, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
-		mPrRepo9.On("GetTotalPRs", mock.Anything).Return(0, apperrors.ErrInternal)
+		mPrRepo9.On("GetTotalPRs", mock.Anything, "").Return(0, apperrors.ErrInternal)
 
-		_, err := svc9.GetTotalPRs(context.Background())
+		_, err := svc9.GetTotalPRs(context.Background(), "")
 		assert.Error(t, err)
 	})
 }
@@ -418,25 +1410,31 @@ func TestPRService_GetPrsByStatus(t *testing.T) {
 	mPrRepo := &mockPRRepo{}
 	mUserRepo := &mockUserRepo{}
 
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 
 	t.Run("Success", func(t *testing.T) {
-		mPrRepo.On("GetPrsByStatus", mock.Anything).Return(3, 2, nil)
+		mPrRepo.On("GetPrsByStatus", mock.Anything, "").
+			Return(models.PrsStatus{OpenPRs: 3, MergedPRs: 2, ClosedPRs: 1}, nil)
 
-		open, merged, err := svc.GetPrsByStatus(context.Background())
+		status, err := svc.GetPrsByStatus(context.Background(), "")
 		require.NoError(t, err)
-		assert.Equal(t, 3, open)
-		assert.Equal(t, 2, merged)
+		assert.Equal(t, 3, status.OpenPRs)
+		assert.Equal(t, 2, status.MergedPRs)
+		assert.Equal(t, 1, status.ClosedPRs)
 	})
 
 	t.Run("Error", func(t *testing.T) {
 		mPrRepo9 := &mockPRRepo{}
 		mUserRepo9 := &mockUserRepo{}
-		svc9 := services.NewPRService(mPrRepo9, mUserRepo9, log)
+		svc9 := services.NewPRService(
+			mPrRepo9, mUserRepo9, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
-		mPrRepo9.On("GetPrsByStatus", mock.Anything).Return(0, 0, apperrors.ErrInternal)
+		mPrRepo9.On("GetPrsByStatus", mock.Anything, "").Return(models.PrsStatus{}, apperrors.ErrInternal)
 
-		_, _, err := svc9.GetPrsByStatus(context.Background())
+		_, err := svc9.GetPrsByStatus(context.Background(), "")
 		assert.Error(t, err)
 	})
 }
@@ -446,16 +1444,18 @@ func TestPRService_GetAssignmentsPerUser(t *testing.T) {
 	mPrRepo := &mockPRRepo{}
 	mUserRepo := &mockUserRepo{}
 
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 
 	t.Run("Success", func(t *testing.T) {
 		assignments := []models.UserAssignment{
 			{UserID: "u1", Name: "User1", Count: 5},
 			{UserID: "u2", Name: "User2", Count: 3},
 		}
-		mPrRepo.On("GetAssignmentsPerUser", mock.Anything).Return(assignments, nil)
+		mPrRepo.On("GetAssignmentsPerUser", mock.Anything, "").Return(assignments, nil)
 
-		result, err := svc.GetAssignmentsPerUser(context.Background())
+		result, err := svc.GetAssignmentsPerUser(context.Background(), "")
 		require.NoError(t, err)
 		assert.Len(t, result, 2)
 	})
@@ -463,11 +1463,13 @@ func TestPRService_GetAssignmentsPerUser(t *testing.T) {
 	t.Run("Error", func(t *testing.T) {
 		mPrRepo9 := &mockPRRepo{}
 		mUserRepo9 := &mockUserRepo{}
-		svc9 := services.NewPRService(mPrRepo9, mUserRepo9, log)
+		svc9 := services.NewPRService(
+			mPrRepo9, mUserRepo9, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
-		mPrRepo9.On("GetAssignmentsPerUser", mock.Anything).Return(nil, apperrors.ErrInternal)
+		mPrRepo9.On("GetAssignmentsPerUser", mock.Anything, "").Return(nil, apperrors.ErrInternal)
 
-		_, err := svc9.GetAssignmentsPerUser(context.Background())
+		_, err := svc9.GetAssignmentsPerUser(context.Background(), "")
 		assert.Error(t, err)
 	})
 }
@@ -477,15 +1479,17 @@ func TestPRService_GetTopReviewers(t *testing.T) {
 	mPrRepo := &mockPRRepo{}
 	mUserRepo := &mockUserRepo{}
 
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 
 	t.Run("Success", func(t *testing.T) {
 		top := []models.UserAssignment{
 			{UserID: "u1", Name: "User1", Count: 10},
 		}
-		mPrRepo.On("GetTopReviewers", mock.Anything).Return(top, nil)
+		mPrRepo.On("GetTopReviewers", mock.Anything, "").Return(top, nil)
 
-		result, err := svc.GetTopReviewers(context.Background())
+		result, err := svc.GetTopReviewers(context.Background(), "")
 		require.NoError(t, err)
 		assert.Len(t, result, 1)
 	})
@@ -493,11 +1497,13 @@ func TestPRService_GetTopReviewers(t *testing.T) {
 	t.Run("Error", func(t *testing.T) {
 		mPrRepo9 := &mockPRRepo{}
 		mUserRepo9 := &mockUserRepo{}
-		svc9 := services.NewPRService(mPrRepo9, mUserRepo9, log)
+		svc9 := services.NewPRService(
+			mPrRepo9, mUserRepo9, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
-		mPrRepo9.On("GetTopReviewers", mock.Anything).Return(nil, apperrors.ErrInternal)
+		mPrRepo9.On("GetTopReviewers", mock.Anything, "").Return(nil, apperrors.ErrInternal)
 
-		_, err := svc9.GetTopReviewers(context.Background())
+		_, err := svc9.GetTopReviewers(context.Background(), "")
 		assert.Error(t, err)
 	})
 }
@@ -507,12 +1513,14 @@ func TestPRService_GetAvgCloseTime(t *testing.T) {
 	mPrRepo := &mockPRRepo{}
 	mUserRepo := &mockUserRepo{}
 
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 
 	t.Run("Success", func(t *testing.T) {
-		mPrRepo.On("GetAvgCloseTime", mock.Anything).Return(86400.0, 5, nil)
+		mPrRepo.On("GetAvgCloseTime", mock.Anything, "").Return(86400.0, 5, nil)
 
-		result, err := svc.GetAvgCloseTime(context.Background())
+		result, err := svc.GetAvgCloseTime(context.Background(), "")
 		require.NoError(t, err)
 		assert.InEpsilon(t, 86400.0, result.AverageSeconds, 0.0001)
 		assert.Equal(t, 5, result.MergedPRsCount)
@@ -522,11 +1530,13 @@ func TestPRService_GetAvgCloseTime(t *testing.T) {
 	t.Run("ZeroCount", func(t *testing.T) {
 		mPrRepo10 := &mockPRRepo{}
 		mUserRepo10 := &mockUserRepo{}
-		svc10 := services.NewPRService(mPrRepo10, mUserRepo10, log)
+		svc10 := services.NewPRService(
+			mPrRepo10, mUserRepo10, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
-		mPrRepo10.On("GetAvgCloseTime", mock.Anything).Return(0.0, 0, nil)
+		mPrRepo10.On("GetAvgCloseTime", mock.Anything, "").Return(0.0, 0, nil)
 
-		result, err := svc10.GetAvgCloseTime(context.Background())
+		result, err := svc10.GetAvgCloseTime(context.Background(), "")
 		require.NoError(t, err)
 		//nolint:testifylint // reason: explicit comparison with zero is acceptable here
 		assert.Equal(t, 0.0, result.AverageSeconds)
@@ -536,11 +1546,13 @@ func TestPRService_GetAvgCloseTime(t *testing.T) {
 	t.Run("Error", func(t *testing.T) {
 		mPrRepo9 := &mockPRRepo{}
 		mUserRepo9 := &mockUserRepo{}
-		svc9 := services.NewPRService(mPrRepo9, mUserRepo9, log)
+		svc9 := services.NewPRService(
+			mPrRepo9, mUserRepo9, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
-		mPrRepo9.On("GetAvgCloseTime", mock.Anything).Return(0.0, 0, apperrors.ErrInternal)
+		mPrRepo9.On("GetAvgCloseTime", mock.Anything, "").Return(0.0, 0, apperrors.ErrInternal)
 
-		_, err := svc9.GetAvgCloseTime(context.Background())
+		_, err := svc9.GetAvgCloseTime(context.Background(), "")
 		assert.Error(t, err)
 	})
 }
@@ -550,15 +1562,17 @@ func TestPRService_GetIdleUsersPerTeam(t *testing.T) {
 	mPrRepo := &mockPRRepo{}
 	mUserRepo := &mockUserRepo{}
 
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 
 	t.Run("Success", func(t *testing.T) {
 		metrics := []models.TeamMetric{
 			{TeamName: "team1", Count: 2},
 		}
-		mPrRepo.On("GetIdleUsersPerTeam", mock.Anything).Return(metrics, nil)
+		mPrRepo.On("GetIdleUsersPerTeam", mock.Anything, "").Return(metrics, nil)
 
-		result, err := svc.GetIdleUsersPerTeam(context.Background())
+		result, err := svc.GetIdleUsersPerTeam(context.Background(), "")
 		require.NoError(t, err)
 		assert.Len(t, result, 1)
 	})
@@ -566,11 +1580,13 @@ func TestPRService_GetIdleUsersPerTeam(t *testing.T) {
 	t.Run("Error", func(t *testing.T) {
 		mPrRepo9 := &mockPRRepo{}
 		mUserRepo9 := &mockUserRepo{}
-		svc9 := services.NewPRService(mPrRepo9, mUserRepo9, log)
+		svc9 := services.NewPRService(
+			mPrRepo9, mUserRepo9, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
-		mPrRepo9.On("GetIdleUsersPerTeam", mock.Anything).Return(nil, apperrors.ErrInternal)
+		mPrRepo9.On("GetIdleUsersPerTeam", mock.Anything, "").Return(nil, apperrors.ErrInternal)
 
-		_, err := svc9.GetIdleUsersPerTeam(context.Background())
+		_, err := svc9.GetIdleUsersPerTeam(context.Background(), "")
 		assert.Error(t, err)
 	})
 }
@@ -580,15 +1596,17 @@ func TestPRService_GetNeedyPRsPerTeam(t *testing.T) {
 	mPrRepo := &mockPRRepo{}
 	mUserRepo := &mockUserRepo{}
 
-	svc := services.NewPRService(mPrRepo, mUserRepo, log)
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 
 	t.Run("Success", func(t *testing.T) {
 		metrics := []models.TeamMetric{
 			{TeamName: "team1", Count: 3},
 		}
-		mPrRepo.On("GetNeedyPRsPerTeam", mock.Anything).Return(metrics, nil)
+		mPrRepo.On("GetNeedyPRsPerTeam", mock.Anything, "").Return(metrics, nil)
 
-		result, err := svc.GetNeedyPRsPerTeam(context.Background())
+		result, err := svc.GetNeedyPRsPerTeam(context.Background(), "")
 		require.NoError(t, err)
 		assert.Len(t, result, 1)
 	})
@@ -596,11 +1614,245 @@ func TestPRService_GetNeedyPRsPerTeam(t *testing.T) {
 	t.Run("Error", func(t *testing.T) {
 		mPrRepo9 := &mockPRRepo{}
 		mUserRepo9 := &mockUserRepo{}
-		svc9 := services.NewPRService(mPrRepo9, mUserRepo9, log)
+		svc9 := services.NewPRService(
+			mPrRepo9, mUserRepo9, &mockTeamRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
-		mPrRepo9.On("GetNeedyPRsPerTeam", mock.Anything).Return(nil, apperrors.ErrInternal)
+		mPrRepo9.On("GetNeedyPRsPerTeam", mock.Anything, "").Return(nil, apperrors.ErrInternal)
 
-		_, err := svc9.GetNeedyPRsPerTeam(context.Background())
+		_, err := svc9.GetNeedyPRsPerTeam(context.Background(), "")
 		assert.Error(t, err)
 	})
 }
+
+func TestPRService_CreatePR_CodeOwners(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	author := &models.User{ID: "u1", TeamName: "team1"}
+	teamMembers := []models.User{{ID: "u1", IsActive: true}, {ID: "u2", IsActive: true}, {ID: "u3", IsActive: true}}
+
+	t.Run("Preferred_OwnersFirst", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		mUserRepo := &mockUserRepo{}
+		mTeamRepo := &mockTeamRepo{}
+		mRepoRepo := &mockRepositoryRepo{}
+		svc := services.NewPRService(
+			mPrRepo, mUserRepo, mTeamRepo, mRepoRepo, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(author, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return(teamMembers, nil)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mRepoRepo.On("GetRepository", mock.Anything, "svc").Return(&models.Repository{
+			Name:           "svc",
+			CodeOwners:     "*.go @org/platform\n/docs/ @u-doc\n",
+			CodeOwnersMode: models.CodeOwnersPreferred,
+		}, nil)
+		mUserRepo.On("GetUserByID", mock.Anything, "platform").Return(nil, apperrors.ErrNotFound)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "platform").Return([]models.User{
+			{ID: "p1", IsActive: true},
+		}, nil)
+		mUserRepo.On("GetUserByID", mock.Anything, "u-doc").Return(&models.User{ID: "u-doc", TeamName: "docs"}, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "docs").Return([]models.User{
+			{ID: "u-doc", IsActive: true},
+		}, nil)
+		mPrRepo.On("CreatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return slices.Equal(p.Reviewers, []string{"p1", "u-doc"}) && !p.NeedMoreReviewers
+		}), mock.Anything).Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "svc", "pr-co").Return(&models.PullRequest{ID: "pr-co"}, nil)
+
+		_, err := svc.CreatePR(context.Background(), &models.PullRequest{
+			ID: "pr-co", Title: "Test", AuthorID: "u1", Repository: "svc",
+			ChangedFiles: []string{"cmd/main.go", "docs/readme.md", "internal/x.go"},
+		})
+		require.NoError(t, err)
+		mPrRepo.AssertExpectations(t)
+	})
+
+	t.Run("Preferred_FillsFromAuthorTeam", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		mUserRepo := &mockUserRepo{}
+		mTeamRepo := &mockTeamRepo{}
+		mRepoRepo := &mockRepositoryRepo{}
+		svc := services.NewPRService(
+			mPrRepo, mUserRepo, mTeamRepo, mRepoRepo, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(author, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return(teamMembers, nil)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mRepoRepo.On("GetRepository", mock.Anything, "svc").Return(&models.Repository{
+			Name: "svc", CodeOwners: "* @u3\n", CodeOwnersMode: models.CodeOwnersPreferred,
+		}, nil)
+		mUserRepo.On("GetUserByID", mock.Anything, "u3").Return(&models.User{ID: "u3", TeamName: "team1"}, nil)
+		mPrRepo.On("CreatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return slices.Equal(p.Reviewers, []string{"u3", "u2"})
+		}), mock.Anything).Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "svc", "pr-co2").Return(&models.PullRequest{ID: "pr-co2"}, nil)
+
+		_, err := svc.CreatePR(context.Background(), &models.PullRequest{
+			ID: "pr-co2", Title: "Test", AuthorID: "u1", Repository: "svc", ChangedFiles: []string{"main.go"},
+		})
+		require.NoError(t, err)
+		mPrRepo.AssertExpectations(t)
+	})
+
+	t.Run("Required_UncoveredOwnersNeedMore", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		mUserRepo := &mockUserRepo{}
+		mTeamRepo := &mockTeamRepo{}
+		mRepoRepo := &mockRepositoryRepo{}
+		svc := services.NewPRService(
+			mPrRepo, mUserRepo, mTeamRepo, mRepoRepo, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(author, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return(teamMembers, nil)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mRepoRepo.On("GetRepository", mock.Anything, "svc").Return(&models.Repository{
+			Name: "svc", CodeOwners: "*.sql @dba\n", CodeOwnersMode: models.CodeOwnersRequired,
+		}, nil)
+		mUserRepo.On("GetUserByID", mock.Anything, "dba").Return(nil, apperrors.ErrNotFound)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "dba").Return([]models.User{}, nil)
+		mPrRepo.On("CreatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return len(p.Reviewers) == 2 && p.NeedMoreReviewers
+		}), mock.Anything).Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "svc", "pr-co3").Return(&models.PullRequest{ID: "pr-co3"}, nil)
+
+		_, err := svc.CreatePR(context.Background(), &models.PullRequest{
+			ID: "pr-co3", Title: "Test", AuthorID: "u1", Repository: "svc", ChangedFiles: []string{"db/001.sql"},
+		})
+		require.NoError(t, err)
+		mPrRepo.AssertExpectations(t)
+	})
+
+	t.Run("RepositoryWithoutCodeOwners", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		mUserRepo := &mockUserRepo{}
+		mTeamRepo := &mockTeamRepo{}
+		mRepoRepo := &mockRepositoryRepo{}
+		svc := services.NewPRService(
+			mPrRepo, mUserRepo, mTeamRepo, mRepoRepo, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(author, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return(teamMembers, nil)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mRepoRepo.On("GetRepository", mock.Anything, "unknown").Return(nil, apperrors.ErrNotFound)
+		mPrRepo.On("CreatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return len(p.Reviewers) == 2 && !slices.Contains(p.Reviewers, "u1")
+		}), mock.Anything).Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "unknown", "pr-co4").Return(&models.PullRequest{ID: "pr-co4"}, nil)
+
+		_, err := svc.CreatePR(context.Background(), &models.PullRequest{
+			ID: "pr-co4", Title: "Test", AuthorID: "u1", Repository: "unknown", ChangedFiles: []string{"main.go"},
+		})
+		require.NoError(t, err)
+		mPrRepo.AssertExpectations(t)
+	})
+}
+
+func TestPRService_CreatePR_RepositorySettings(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	author := &models.User{ID: "u1", TeamName: "team1"}
+	platform := []models.User{{ID: "p1", IsActive: true}, {ID: "p2", IsActive: true}, {ID: "p3", IsActive: true}}
+
+	t.Run("DefaultTeamAndCount", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		mUserRepo := &mockUserRepo{}
+		mTeamRepo := &mockTeamRepo{}
+		mRepoRepo := &mockRepositoryRepo{}
+		svc := services.NewPRService(
+			mPrRepo, mUserRepo, mTeamRepo, mRepoRepo, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+		teamName, count := "platform", 1
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(author, nil)
+		mRepoRepo.On("GetRepository", mock.Anything, "mono").Return(&models.Repository{
+			Name:     "mono",
+			Settings: &models.RepositorySettings{DefaultTeamName: &teamName, ReviewersCount: &count},
+		}, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "platform").Return(platform, nil)
+		mPrRepo.On("CreatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return p.Repository == "mono" && len(p.Reviewers) == 1 && strings.HasPrefix(p.Reviewers[0], "p")
+		}), mock.Anything).Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "mono", "pr-1").Return(&models.PullRequest{ID: "pr-1"}, nil)
+
+		_, err := svc.CreatePR(context.Background(), &models.PullRequest{
+			ID: "pr-1", Title: "Test", AuthorID: "u1", Repository: "mono",
+		})
+		require.NoError(t, err)
+		mPrRepo.AssertExpectations(t)
+		mTeamRepo.AssertNotCalled(t, "GetTeamSettings", mock.Anything, mock.Anything)
+	})
+
+	t.Run("DefaultTeamUsesItsCount", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		mUserRepo := &mockUserRepo{}
+		mTeamRepo := &mockTeamRepo{}
+		mRepoRepo := &mockRepositoryRepo{}
+		svc := services.NewPRService(
+			mPrRepo, mUserRepo, mTeamRepo, mRepoRepo, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+		teamName, teamCount := "platform", 3
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(author, nil)
+		mRepoRepo.On("GetRepository", mock.Anything, "mono").Return(&models.Repository{
+			Name:     "mono",
+			Settings: &models.RepositorySettings{DefaultTeamName: &teamName},
+		}, nil)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "platform").
+			Return(&models.TeamSettings{ReviewersCount: &teamCount}, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "platform").Return(platform, nil)
+		mPrRepo.On("CreatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return len(p.Reviewers) == 3 && !p.NeedMoreReviewers
+		}), mock.Anything).Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "mono", "pr-2").Return(&models.PullRequest{ID: "pr-2"}, nil)
+
+		_, err := svc.CreatePR(context.Background(), &models.PullRequest{
+			ID: "pr-2", Title: "Test", AuthorID: "u1", Repository: "mono",
+		})
+		require.NoError(t, err)
+		mPrRepo.AssertExpectations(t)
+	})
+
+	t.Run("RepositoryError", func(t *testing.T) {
+		mUserRepo := &mockUserRepo{}
+		mRepoRepo := &mockRepositoryRepo{}
+		svc := services.NewPRService(
+			&mockPRRepo{}, mUserRepo, &mockTeamRepo{}, mRepoRepo, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(author, nil)
+		mRepoRepo.On("GetRepository", mock.Anything, "mono").Return(nil, apperrors.ErrInternal)
+
+		_, err := svc.CreatePR(context.Background(), &models.PullRequest{
+			ID: "pr-3", Title: "Test", AuthorID: "u1", Repository: "mono",
+		})
+		assert.ErrorIs(t, err, apperrors.ErrInternal)
+	})
+}
+
+func TestPRService_RepositoryFilter(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mPrRepo := &mockPRRepo{}
+	mUserRepo := &mockUserRepo{}
+	mRepoRepo := &mockRepositoryRepo{}
+	svc := services.NewPRService(
+		mPrRepo, mUserRepo, &mockTeamRepo{}, mRepoRepo, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+	t.Run("MergeInRepository", func(t *testing.T) {
+		mPrRepo.On("GetPRByID", mock.Anything, "svc-a", "pr-1").
+			Return(&models.PullRequest{ID: "pr-1", Repository: "svc-a", Status: "OPEN"}, nil).Once()
+		mRepoRepo.On("GetRepository", mock.Anything, "svc-a").Return(nil, apperrors.ErrNotFound)
+		mUserRepo.On("GetUserByID", mock.Anything, "").Return(nil, apperrors.ErrNotFound)
+		mPrRepo.On("MergePR", mock.Anything, "svc-a", "pr-1").Return(nil)
+		mPrRepo.On("GetPRByID", mock.Anything, "svc-a", "pr-1").
+			Return(&models.PullRequest{ID: "pr-1", Repository: "svc-a", Status: "MERGED"}, nil).Once()
+
+		pr, err := svc.MergePR(context.Background(), "svc-a", "pr-1", false, "")
+		require.NoError(t, err)
+		assert.Equal(t, "svc-a", pr.Repository)
+		mPrRepo.AssertNotCalled(t, "MergePR", mock.Anything, "", "pr-1")
+	})
+
+	t.Run("StatsInRepository", func(t *testing.T) {
+		mPrRepo.On("GetTotalPRs", mock.Anything, "svc-a").Return(3, nil)
+
+		total, err := svc.GetTotalPRs(context.Background(), "svc-a")
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+	})
+}
@@ -0,0 +1,95 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staleReviews hands out its stale reviews in batches of the requested size, as ClaimStaleReviews does.
+type staleReviews struct {
+	repository.PRRepository
+	reviews   []models.StaleReview
+	olderThan time.Duration
+	err       error
+}
+
+func (s *staleReviews) ClaimStaleReviews(
+	_ context.Context,
+	olderThan time.Duration,
+	limit int,
+) ([]models.StaleReview, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.olderThan = olderThan
+	n := min(limit, len(s.reviews))
+	claimed := s.reviews[:n]
+	s.reviews = s.reviews[n:]
+	return claimed, nil
+}
+
+func TestReminderService_RemindStale(t *testing.T) {
+	ctx := context.Background()
+	newReminders := func(prs *staleReviews, store *outboxStore) *services.ReminderService {
+		return services.NewReminderService(prs, testutil.InlineTx{}, newTestOutbox(store), 24*time.Hour,
+			slog.New(slog.DiscardHandler))
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		assignedAt := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+		prs := &staleReviews{reviews: []models.StaleReview{
+			{PullRequest: models.PullRequest{ID: "pr-1", AuthorID: "u1"}, ReviewerID: "u2", AssignedAt: assignedAt},
+			{PullRequest: models.PullRequest{ID: "pr-2", AuthorID: "u1"}, ReviewerID: "u3", AssignedAt: assignedAt},
+		}}
+		store := &outboxStore{}
+
+		reminded, err := newReminders(prs, store).RemindStale(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, reminded)
+		assert.Equal(t, 24*time.Hour, prs.olderThan)
+
+		require.Equal(t, []string{models.EventReviewStale, models.EventReviewStale}, store.types())
+		event := store.entries[1].Event
+		assert.Equal(t, "pr-2", event.Data.PullRequest.ID)
+		assert.Equal(t, "u3", event.Data.ReviewerID)
+		require.NotNil(t, event.Data.AssignedAt)
+		assert.Equal(t, assignedAt, *event.Data.AssignedAt)
+		assert.NotEmpty(t, event.ID)
+	})
+
+	t.Run("SeveralBatches", func(t *testing.T) {
+		prs := &staleReviews{}
+		for range 150 {
+			prs.reviews = append(prs.reviews, models.StaleReview{ReviewerID: "u2"})
+		}
+		store := &outboxStore{}
+
+		reminded, err := newReminders(prs, store).RemindStale(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 150, reminded)
+		assert.Len(t, store.entries, 150)
+	})
+
+	t.Run("NothingStale", func(t *testing.T) {
+		store := &outboxStore{}
+		reminded, err := newReminders(&staleReviews{}, store).RemindStale(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, reminded)
+		assert.Empty(t, store.entries)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		_, err := newReminders(&staleReviews{err: errors.New("db down")}, &outboxStore{}).RemindStale(ctx)
+		assert.Error(t, err)
+	})
+}
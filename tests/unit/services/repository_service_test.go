@@ -0,0 +1,146 @@
+package services_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoryService_SetCodeOwners(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	content := "* @org/backend\n/docs/ @u-doc\n"
+
+	t.Run("DefaultsToPreferred", func(t *testing.T) {
+		mRepoRepo := &mockRepositoryRepo{}
+		svc := services.NewRepositoryService(mRepoRepo, 2, log)
+		mRepoRepo.On("SetCodeOwners", mock.Anything, "svc", content, models.CodeOwnersPreferred).Return(nil)
+
+		repo, err := svc.SetCodeOwners(context.Background(), "svc", content, "")
+		require.NoError(t, err)
+		assert.Equal(t, models.CodeOwnersPreferred, repo.CodeOwnersMode)
+		mRepoRepo.AssertExpectations(t)
+	})
+
+	t.Run("Required", func(t *testing.T) {
+		mRepoRepo := &mockRepositoryRepo{}
+		svc := services.NewRepositoryService(mRepoRepo, 2, log)
+		mRepoRepo.On("SetCodeOwners", mock.Anything, "svc", content, models.CodeOwnersRequired).Return(nil)
+
+		repo, err := svc.SetCodeOwners(context.Background(), "svc", content, models.CodeOwnersRequired)
+		require.NoError(t, err)
+		assert.Equal(t, "svc", repo.Name)
+		assert.Equal(t, models.CodeOwnersRequired, repo.CodeOwnersMode)
+	})
+
+	t.Run("UnknownMode", func(t *testing.T) {
+		svc := services.NewRepositoryService(&mockRepositoryRepo{}, 2, log)
+
+		_, err := svc.SetCodeOwners(context.Background(), "svc", content, "strict")
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+
+	t.Run("InvalidPattern", func(t *testing.T) {
+		mRepoRepo := &mockRepositoryRepo{}
+		svc := services.NewRepositoryService(mRepoRepo, 2, log)
+
+		_, err := svc.SetCodeOwners(context.Background(), "svc", "!vendor/ @org/backend\n", "")
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+		mRepoRepo.AssertNotCalled(t, "SetCodeOwners", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("RepoError", func(t *testing.T) {
+		mRepoRepo := &mockRepositoryRepo{}
+		svc := services.NewRepositoryService(mRepoRepo, 2, log)
+		mRepoRepo.On("SetCodeOwners", mock.Anything, "svc", content, models.CodeOwnersPreferred).
+			Return(apperrors.ErrInternal)
+
+		_, err := svc.SetCodeOwners(context.Background(), "svc", content, "")
+		assert.ErrorIs(t, err, apperrors.ErrInternal)
+	})
+}
+
+func TestRepositoryService_GetRepository(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mRepoRepo := &mockRepositoryRepo{}
+	svc := services.NewRepositoryService(mRepoRepo, 2, log)
+
+	t.Run("Success", func(t *testing.T) {
+		expected := &models.Repository{Name: "svc", CodeOwnersMode: models.CodeOwnersPreferred}
+		mRepoRepo.On("GetRepository", mock.Anything, "svc").Return(expected, nil)
+
+		repo, err := svc.GetRepository(context.Background(), "svc")
+		require.NoError(t, err)
+		assert.Equal(t, expected, repo)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mRepoRepo.On("GetRepository", mock.Anything, "missing").Return(nil, apperrors.ErrNotFound)
+
+		_, err := svc.GetRepository(context.Background(), "missing")
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+}
+
+func TestRepositoryService_UpdateRepositorySettings(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	teamName, count := "platform", 3
+
+	t.Run("Success", func(t *testing.T) {
+		mRepoRepo := &mockRepositoryRepo{}
+		svc := services.NewRepositoryService(mRepoRepo, 2, log)
+		settings := &models.RepositorySettings{DefaultTeamName: &teamName, ReviewersCount: &count}
+		mRepoRepo.On("UpdateRepositorySettings", mock.Anything, "mono", settings, 2).Return(nil)
+		mRepoRepo.On("GetRepository", mock.Anything, "mono").
+			Return(&models.Repository{Name: "mono", Settings: settings}, nil)
+
+		repo, err := svc.UpdateRepositorySettings(context.Background(), "mono", settings)
+		require.NoError(t, err)
+		assert.Equal(t, settings, repo.Settings)
+		mRepoRepo.AssertExpectations(t)
+	})
+
+	t.Run("InvalidCount", func(t *testing.T) {
+		zero := 0
+		svc := services.NewRepositoryService(&mockRepositoryRepo{}, 2, log)
+
+		_, err := svc.UpdateRepositorySettings(
+			context.Background(), "mono", &models.RepositorySettings{ReviewersCount: &zero})
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+
+	t.Run("NegativeRequiredApprovals", func(t *testing.T) {
+		negative := -1
+		svc := services.NewRepositoryService(&mockRepositoryRepo{}, 2, log)
+
+		_, err := svc.UpdateRepositorySettings(
+			context.Background(), "mono", &models.RepositorySettings{RequiredApprovals: &negative})
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+
+	t.Run("EmptyTeamName", func(t *testing.T) {
+		empty := ""
+		svc := services.NewRepositoryService(&mockRepositoryRepo{}, 2, log)
+
+		_, err := svc.UpdateRepositorySettings(
+			context.Background(), "mono", &models.RepositorySettings{DefaultTeamName: &empty})
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+
+	t.Run("TeamNotFound", func(t *testing.T) {
+		mRepoRepo := &mockRepositoryRepo{}
+		svc := services.NewRepositoryService(mRepoRepo, 2, log)
+		settings := &models.RepositorySettings{DefaultTeamName: &teamName}
+		mRepoRepo.On("UpdateRepositorySettings", mock.Anything, "mono", settings, 2).Return(apperrors.ErrNotFound)
+
+		_, err := svc.UpdateRepositorySettings(context.Background(), "mono", settings)
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+}
@@ -0,0 +1,170 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReviewerSelector(t *testing.T) {
+	t.Run("KnownStrategies", func(t *testing.T) {
+		for _, strategy := range []string{
+			"",
+			services.StrategyRandom,
+			services.StrategyRoundRobin,
+			services.StrategyLeastLoaded,
+		} {
+			selector, err := services.NewReviewerSelector(strategy, &mockPRRepo{})
+			require.NoError(t, err, strategy)
+			assert.NotNil(t, selector, strategy)
+		}
+	})
+
+	t.Run("UnknownStrategy", func(t *testing.T) {
+		_, err := services.NewReviewerSelector("by-seniority", &mockPRRepo{})
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+}
+
+func TestRandomSelector_Select(t *testing.T) {
+	selector := services.NewRandomSelector()
+	candidates := []string{"u1", "u2", "u3"}
+
+	t.Run("TakesRequestedCount", func(t *testing.T) {
+		selected, err := selector.Select(context.Background(), "team1", candidates, 2)
+		require.NoError(t, err)
+		assert.Len(t, selected, 2)
+		assert.Subset(t, candidates, selected)
+		assert.NotEqual(t, selected[0], selected[1])
+	})
+
+	t.Run("FewerCandidatesThanCount", func(t *testing.T) {
+		selected, err := selector.Select(context.Background(), "team1", []string{"u1"}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u1"}, selected)
+	})
+
+	t.Run("NoCandidates", func(t *testing.T) {
+		selected, err := selector.Select(context.Background(), "team1", []string{}, 2)
+		require.NoError(t, err)
+		assert.NotNil(t, selected)
+		assert.Empty(t, selected)
+	})
+
+	t.Run("DoesNotMutateInput", func(t *testing.T) {
+		input := []string{"u1", "u2", "u3", "u4", "u5"}
+		for range 10 {
+			_, err := selector.Select(context.Background(), "team1", input, 2)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, []string{"u1", "u2", "u3", "u4", "u5"}, input)
+	})
+}
+
+func TestRoundRobinSelector_Select(t *testing.T) {
+	t.Run("RotatesThroughTeam", func(t *testing.T) {
+		selector := services.NewRoundRobinSelector()
+		candidates := []string{"u3", "u1", "u2"}
+
+		first, err := selector.Select(context.Background(), "team1", candidates, 2)
+		require.NoError(t, err)
+		second, err := selector.Select(context.Background(), "team1", candidates, 2)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"u1", "u2"}, first)
+		assert.Equal(t, []string{"u3", "u1"}, second)
+	})
+
+	t.Run("CursorsArePerTeam", func(t *testing.T) {
+		selector := services.NewRoundRobinSelector()
+
+		_, err := selector.Select(context.Background(), "team1", []string{"u1", "u2"}, 1)
+		require.NoError(t, err)
+		selected, err := selector.Select(context.Background(), "team2", []string{"u5", "u6"}, 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"u5"}, selected)
+	})
+
+	t.Run("NoCandidates", func(t *testing.T) {
+		selector := services.NewRoundRobinSelector()
+
+		selected, err := selector.Select(context.Background(), "team1", nil, 2)
+		require.NoError(t, err)
+		assert.Empty(t, selected)
+	})
+}
+
+func TestLeastLoadedSelector_Select(t *testing.T) {
+	t.Run("PrefersLeastLoaded", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		mPrRepo.On("GetOpenReviewLoadByTeam", mock.Anything, "team1").Return([]models.UserAssignment{
+			{UserID: "u4", Count: 0},
+			{UserID: "u2", Count: 1},
+			{UserID: "u3", Count: 3},
+			{UserID: "u1", Count: 5},
+		}, nil)
+		selector := services.NewLeastLoadedSelector(mPrRepo)
+
+		selected, err := selector.Select(context.Background(), "team1", []string{"u1", "u2", "u3", "u4"}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u4", "u2"}, selected)
+		mPrRepo.AssertExpectations(t)
+	})
+
+	t.Run("CandidatesWithoutLoadCountAsIdle", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		mPrRepo.On("GetOpenReviewLoadByTeam", mock.Anything, "team1").Return([]models.UserAssignment{
+			{UserID: "u1", Count: 2},
+		}, nil)
+		selector := services.NewLeastLoadedSelector(mPrRepo)
+
+		selected, err := selector.Select(context.Background(), "team1", []string{"u1", "u9"}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u9"}, selected)
+	})
+
+	t.Run("TiesBrokenRandomly", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		mPrRepo.On("GetOpenReviewLoadByTeam", mock.Anything, "team1").Return([]models.UserAssignment{
+			{UserID: "u1", Count: 1},
+			{UserID: "u2", Count: 1},
+			{UserID: "u3", Count: 7},
+		}, nil)
+		selector := services.NewLeastLoadedSelector(mPrRepo)
+
+		seen := map[string]bool{}
+		for range 100 {
+			selected, err := selector.Select(context.Background(), "team1", []string{"u1", "u2", "u3"}, 1)
+			require.NoError(t, err)
+			require.Len(t, selected, 1)
+			seen[selected[0]] = true
+		}
+		assert.Equal(t, map[string]bool{"u1": true, "u2": true}, seen)
+	})
+
+	t.Run("RepoError", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		mPrRepo.On("GetOpenReviewLoadByTeam", mock.Anything, "team1").Return(nil, apperrors.ErrInternal)
+		selector := services.NewLeastLoadedSelector(mPrRepo)
+
+		_, err := selector.Select(context.Background(), "team1", []string{"u1"}, 1)
+		assert.ErrorIs(t, err, apperrors.ErrInternal)
+	})
+
+	t.Run("NoCandidatesSkipsRepo", func(t *testing.T) {
+		mPrRepo := &mockPRRepo{}
+		selector := services.NewLeastLoadedSelector(mPrRepo)
+
+		selected, err := selector.Select(context.Background(), "team1", []string{}, 2)
+		require.NoError(t, err)
+		assert.Empty(t, selected)
+		mPrRepo.AssertNotCalled(t, "GetOpenReviewLoadByTeam", mock.Anything, mock.Anything)
+	})
+}
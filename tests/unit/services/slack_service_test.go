@@ -0,0 +1,235 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/slack"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slackUsers knows the team of each user.
+type slackUsers struct {
+	repository.UserRepository
+	teams map[string]string
+}
+
+func (u slackUsers) GetUserByID(_ context.Context, id string) (*models.User, error) {
+	team, ok := u.teams[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	return &models.User{ID: id, TeamName: team, IsActive: true}, nil
+}
+
+func (u slackUsers) GetTeamNameByUserID(_ context.Context, userID string) (string, error) {
+	team, ok := u.teams[userID]
+	if !ok {
+		return "", apperrors.ErrNotFound
+	}
+	return team, nil
+}
+
+// slackTeams knows the teams of slackUsers.
+type slackTeams struct {
+	repository.TeamRepository
+	users slackUsers
+}
+
+func (t slackTeams) GetTeamByName(_ context.Context, name string) (*models.Team, error) {
+	for _, team := range t.users.teams {
+		if team == name {
+			return &models.Team{Name: name}, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+// slackReceiver is a stub of the Slack incoming webhooks. It records the messages posted to each path and
+// rejects those posted while status is set.
+type slackReceiver struct {
+	*httptest.Server
+	messages map[string][]string
+	status   int
+}
+
+func newSlackReceiver(t *testing.T) *slackReceiver {
+	t.Helper()
+	r := &slackReceiver{messages: map[string][]string{}}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var msg slack.Message
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&msg))
+		if r.status != 0 {
+			w.WriteHeader(r.status)
+			return
+		}
+		r.messages[req.URL.Path] = append(r.messages[req.URL.Path], msg.Text)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func newTestSlack(store *testutil.SlackStore, baseURL string) *services.SlackService {
+	users := slackUsers{teams: map[string]string{"u1": "backend", "u2": "backend", "u3": "backend", "f1": "frontend"}}
+	return services.NewSlackService(store, users, slackTeams{users: users}, http.DefaultClient, baseURL,
+		slog.New(slog.DiscardHandler))
+}
+
+func TestSlackService_SetTeamChannel(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("SetAndRemove", func(t *testing.T) {
+		store := testutil.NewSlackStore()
+		svc := newTestSlack(store, "")
+
+		require.NoError(t, svc.SetTeamChannel(ctx, "backend", "/services/T1/B1/x"))
+		assert.Equal(t, "/services/T1/B1/x", store.Channels["backend"])
+
+		require.NoError(t, svc.SetTeamChannel(ctx, "backend", ""))
+		assert.NotContains(t, store.Channels, "backend")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		svc := newTestSlack(testutil.NewSlackStore(), "")
+		assert.ErrorIs(t, svc.SetTeamChannel(ctx, "backend", "https://hooks.slack.com/x"), apperrors.ErrInvalidInput)
+		assert.ErrorIs(t, svc.SetTeamChannel(ctx, "", "/services/x"), apperrors.ErrInvalidInput)
+	})
+
+	t.Run("UnknownTeam", func(t *testing.T) {
+		svc := newTestSlack(testutil.NewSlackStore(), "")
+		assert.ErrorIs(t, svc.SetTeamChannel(ctx, "nope", "/services/x"), apperrors.ErrNotFound)
+	})
+}
+
+func TestSlackService_SetUserHandle(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("SetAndRemove", func(t *testing.T) {
+		store := testutil.NewSlackStore()
+		svc := newTestSlack(store, "")
+
+		require.NoError(t, svc.SetUserHandle(ctx, "u2", "U0USER2"))
+		assert.Equal(t, "U0USER2", store.Handles["u2"])
+
+		require.NoError(t, svc.SetUserHandle(ctx, "u2", ""))
+		assert.NotContains(t, store.Handles, "u2")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		svc := newTestSlack(testutil.NewSlackStore(), "")
+		assert.ErrorIs(t, svc.SetUserHandle(ctx, "u2", "@bob"), apperrors.ErrInvalidInput)
+	})
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		svc := newTestSlack(testutil.NewSlackStore(), "")
+		assert.ErrorIs(t, svc.SetUserHandle(ctx, "nope", "U0NOPE"), apperrors.ErrNotFound)
+	})
+}
+
+func TestSlackService_Publish(t *testing.T) {
+	ctx := context.Background()
+	pr := &models.PullRequest{
+		ID: "42", Repository: "acme/api", Title: "Fix <login>", AuthorID: "u1", Reviewers: []string{"u2", "u3"},
+	}
+	event := func(eventType string, data models.EventData) *models.Event {
+		data.PullRequest = pr
+		return &models.Event{ID: "e1", Type: eventType, Data: data}
+	}
+	setup := func(t *testing.T) (*services.SlackService, *slackReceiver) {
+		t.Helper()
+		receiver := newSlackReceiver(t)
+		store := testutil.NewSlackStore()
+		store.Channels["backend"] = "/services/backend"
+		store.Handles["u2"] = "U0USER2"
+		store.Handles["u3"] = "U0USER3"
+		return newTestSlack(store, receiver.URL+"/"), receiver
+	}
+
+	assignedAt := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+	for name, tc := range map[string]struct {
+		event *models.Event
+		want  string
+	}{
+		"Assigned": {
+			event(models.EventReviewerAssigned, models.EventData{ReviewerID: "u2"}),
+			"<@U0USER2> was assigned to review *Fix &lt;login&gt;* (`acme/api#42`) by u1",
+		},
+		"Reassigned": {
+			event(models.EventReviewerReassigned, models.EventData{ReviewerID: "u3", OldReviewerID: "u2"}),
+			"<@U0USER3> was assigned to review *Fix &lt;login&gt;* (`acme/api#42`) instead of <@U0USER2>",
+		},
+		"Stale": {
+			event(models.EventReviewStale, models.EventData{ReviewerID: "u2", AssignedAt: &assignedAt}),
+			"<@U0USER2>, *Fix &lt;login&gt;* (`acme/api#42`) by u1 is still waiting for your review " +
+				"since 2026-10-15 09:30 UTC",
+		},
+		"Merged": {
+			event(models.EventPRMerged, models.EventData{}),
+			"*Fix &lt;login&gt;* (`acme/api#42`) by u1 was merged, reviewed by <@U0USER2>, <@U0USER3>",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc, receiver := setup(t)
+			require.NoError(t, svc.Publish(ctx, tc.event))
+			assert.Equal(t, map[string][]string{"/services/backend": {tc.want}}, receiver.messages)
+		})
+	}
+
+	t.Run("Ignored", func(t *testing.T) {
+		svc, receiver := setup(t)
+		require.NoError(t, svc.Publish(ctx, event(models.EventPRCreated, models.EventData{})))
+		require.NoError(t, svc.Publish(ctx, &models.Event{
+			Type: models.EventUserDeactivated, Data: models.EventData{User: &models.User{ID: "u2"}},
+		}))
+		assert.Empty(t, receiver.messages)
+	})
+
+	t.Run("TeamWithoutChannel", func(t *testing.T) {
+		svc, receiver := setup(t)
+		frontend := *pr
+		frontend.AuthorID = "f1"
+		require.NoError(t, svc.Publish(ctx, &models.Event{
+			Type: models.EventReviewerAssigned, Data: models.EventData{PullRequest: &frontend, ReviewerID: "u2"},
+		}))
+		assert.Empty(t, receiver.messages)
+	})
+
+	t.Run("ServerError", func(t *testing.T) {
+		svc, receiver := setup(t)
+		receiver.status = http.StatusServiceUnavailable
+		err := svc.Publish(ctx, event(models.EventReviewerAssigned, models.EventData{ReviewerID: "u2"}))
+		assert.ErrorIs(t, err, slack.ErrRejected, "server errors are retried")
+	})
+
+	t.Run("RejectedForGood", func(t *testing.T) {
+		for _, status := range []int{http.StatusForbidden, http.StatusNotFound, http.StatusGone} {
+			svc, receiver := setup(t)
+			receiver.status = status
+			err := svc.Publish(ctx, event(models.EventReviewerAssigned, models.EventData{ReviewerID: "u2"}))
+			assert.NoError(t, err, "a %d is dropped, not retried", status)
+		}
+	})
+
+	t.Run("FromOutbox", func(t *testing.T) {
+		svc, receiver := setup(t)
+		store := &outboxStore{}
+		outbox := newTestOutbox(store, svc)
+
+		require.NoError(t, outbox.OnChange(ctx, models.AuditPRCreated, "42", nil, pr))
+		dispatched, err := outbox.Dispatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 3, dispatched)
+		assert.Len(t, receiver.messages["/services/backend"], 2, "one message per assigned reviewer")
+	})
+}
@@ -0,0 +1,291 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/outbound"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSubscriptions(store *testutil.SubscriptionStore, maxAttempts int) *services.SubscriptionService {
+	return services.NewSubscriptionService(store, http.DefaultClient, maxAttempts, time.Second,
+		slog.New(slog.DiscardHandler))
+}
+
+func TestSubscriptionService_Subscribe(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		store := &testutil.SubscriptionStore{}
+		svc := newTestSubscriptions(store, 3)
+
+		sub, err := svc.Subscribe(context.Background(), &models.WebhookSubscription{
+			URL:        "https://hooks.example.com/pr",
+			EventTypes: []string{models.EventPRMerged, models.EventPRCreated, models.EventPRMerged},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), sub.ID)
+		assert.Equal(t, []string{models.EventPRCreated, models.EventPRMerged}, sub.EventTypes)
+		assert.Len(t, sub.Secret, 64, "a secret is generated")
+	})
+
+	t.Run("KeepsSecret", func(t *testing.T) {
+		svc := newTestSubscriptions(&testutil.SubscriptionStore{}, 3)
+
+		sub, err := svc.Subscribe(context.Background(), &models.WebhookSubscription{
+			URL:        "http://localhost:9000/hook",
+			Secret:     "s3cret",
+			EventTypes: []string{models.EventUserDeactivated},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "s3cret", sub.Secret)
+	})
+
+	for name, sub := range map[string]models.WebhookSubscription{
+		"RelativeURL":  {URL: "/hook", EventTypes: []string{models.EventPRCreated}},
+		"FTPURL":       {URL: "ftp://example.com/hook", EventTypes: []string{models.EventPRCreated}},
+		"NoEventTypes": {URL: "https://example.com/hook"},
+		"UnknownEvent": {URL: "https://example.com/hook", EventTypes: []string{"pr.deleted"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := &testutil.SubscriptionStore{}
+			_, err := newTestSubscriptions(store, 3).Subscribe(context.Background(), &sub)
+			assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+			assert.Empty(t, store.Subs)
+		})
+	}
+}
+
+func TestSubscriptionService_Publish(t *testing.T) {
+	store := &testutil.SubscriptionStore{}
+	svc := newTestSubscriptions(store, 3)
+	ctx := context.Background()
+	for _, eventTypes := range [][]string{
+		{models.EventPRMerged},
+		{models.EventPRCreated, models.EventPRMerged},
+	} {
+		_, err := svc.Subscribe(ctx, &models.WebhookSubscription{
+			URL:        "https://example.com/hook",
+			EventTypes: eventTypes,
+		})
+		require.NoError(t, err)
+	}
+
+	created := &models.Event{
+		ID:    "ev-1",
+		Type:  models.EventPRCreated,
+		Actor: "alice",
+		Data:  models.EventData{PullRequest: &models.PullRequest{ID: "pr-1"}},
+	}
+	require.NoError(t, svc.Publish(ctx, created))
+	require.Len(t, store.Deliveries, 1, "only the subscription to pr.created gets it")
+	assert.Equal(t, int64(2), store.Deliveries[0].SubscriptionID)
+	assert.Equal(t, "ev-1", store.Deliveries[0].EventID)
+	assert.Equal(t, models.EventPRCreated, store.Deliveries[0].EventType)
+	assert.Equal(t, []models.Event{*created}, store.Events(t))
+
+	require.NoError(t, svc.Publish(ctx, &models.Event{ID: "ev-2", Type: models.EventPRMerged}))
+	assert.Len(t, store.Deliveries, 3)
+
+	require.NoError(t, svc.Publish(ctx, &models.Event{ID: "ev-3", Type: models.EventUserDeactivated}))
+	assert.Len(t, store.Deliveries, 3, "no subscription to user.deactivated")
+}
+
+func TestSubscriptionService_DeliverDue(t *testing.T) {
+	// receiver records the deliveries posted to it and answers with the status codes in turn.
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	receiver := func(t *testing.T, statuses ...int) (*httptest.Server, *[]received) {
+		t.Helper()
+		var got []received
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			got = append(got, received{header: r.Header.Clone(), body: body})
+			w.WriteHeader(statuses[min(len(got), len(statuses))-1])
+		}))
+		t.Cleanup(srv.Close)
+		return srv, &got
+	}
+	subscribe := func(t *testing.T, svc *services.SubscriptionService, url string) {
+		t.Helper()
+		_, err := svc.Subscribe(context.Background(), &models.WebhookSubscription{
+			URL:        url,
+			Secret:     "s3cret",
+			EventTypes: []string{models.EventPRMerged},
+		})
+		require.NoError(t, err)
+	}
+	merged := &models.Event{
+		ID:   "ev-1",
+		Type: models.EventPRMerged,
+		Data: models.EventData{PullRequest: &models.PullRequest{ID: "pr-1", Status: "MERGED"}},
+	}
+
+	t.Run("Delivered", func(t *testing.T) {
+		srv, got := receiver(t, http.StatusNoContent)
+		store := &testutil.SubscriptionStore{}
+		svc := newTestSubscriptions(store, 3)
+		subscribe(t, svc, srv.URL)
+		ctx := context.Background()
+		require.NoError(t, svc.Publish(ctx, merged))
+
+		delivered, err := svc.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+
+		require.Len(t, *got, 1)
+		req := (*got)[0]
+		assert.Equal(t, models.EventPRMerged, req.header.Get(outbound.EventHeader))
+		assert.Equal(t, "ev-1", req.header.Get(outbound.EventIDHeader))
+		assert.Equal(t, "1", req.header.Get(outbound.DeliveryHeader))
+		assert.Equal(t, "application/json", req.header.Get("Content-Type"))
+		assert.True(t, outbound.Verify([]byte("s3cret"), req.body, req.header.Get(outbound.SignatureHeader)))
+		assert.JSONEq(t, string(store.Deliveries[0].Payload), string(req.body))
+
+		d := store.Deliveries[0]
+		assert.Equal(t, models.DeliveryDelivered, d.Status)
+		assert.Equal(t, 1, d.Attempts)
+		require.Len(t, d.AttemptLog, 1)
+		assert.Equal(t, http.StatusNoContent, d.AttemptLog[0].StatusCode)
+		assert.Empty(t, d.AttemptLog[0].Error)
+
+		delivered, err = svc.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, delivered, "delivered deliveries are not sent again")
+		assert.Len(t, *got, 1)
+	})
+
+	t.Run("ClaimedDeliveryIsNotSentTwice", func(t *testing.T) {
+		store := &testutil.SubscriptionStore{}
+		svc := newTestSubscriptions(store, 3)
+		requests := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			delivered, err := svc.DeliverDue(r.Context())
+			assert.NoError(t, err)
+			assert.Zero(t, delivered, "the delivery in flight is claimed by the first call")
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+		subscribe(t, svc, srv.URL)
+		ctx := context.Background()
+		require.NoError(t, svc.Publish(ctx, merged))
+
+		delivered, err := svc.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("RetriesWithBackoff", func(t *testing.T) {
+		srv, got := receiver(t, http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK)
+		store := &testutil.SubscriptionStore{}
+		svc := newTestSubscriptions(store, 5)
+		subscribe(t, svc, srv.URL)
+		ctx := context.Background()
+		require.NoError(t, svc.Publish(ctx, merged))
+
+		for range 2 {
+			delivered, err := svc.DeliverDue(ctx)
+			require.NoError(t, err)
+			assert.Zero(t, delivered)
+
+			delivered, err = svc.DeliverDue(ctx)
+			require.NoError(t, err)
+			assert.Zero(t, delivered, "a failed delivery waits for its backoff")
+			store.RetryNow()
+		}
+		delivered, err := svc.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+
+		assert.Len(t, *got, 3)
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, store.Retries)
+		d := store.Deliveries[0]
+		assert.Equal(t, models.DeliveryDelivered, d.Status)
+		require.Len(t, d.AttemptLog, 3)
+		for i, status := range []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK} {
+			assert.Equal(t, i+1, d.AttemptLog[i].Attempt)
+			assert.Equal(t, status, d.AttemptLog[i].StatusCode)
+		}
+		assert.Equal(t, (*got)[0].header.Get(outbound.EventIDHeader), (*got)[2].header.Get(outbound.EventIDHeader),
+			"retries carry the same event ID")
+	})
+
+	t.Run("GivesUp", func(t *testing.T) {
+		srv, got := receiver(t, http.StatusServiceUnavailable)
+		store := &testutil.SubscriptionStore{}
+		svc := newTestSubscriptions(store, 2)
+		subscribe(t, svc, srv.URL)
+		ctx := context.Background()
+		require.NoError(t, svc.Publish(ctx, merged))
+
+		for range 3 {
+			_, err := svc.DeliverDue(ctx)
+			require.NoError(t, err)
+			store.RetryNow()
+		}
+
+		assert.Len(t, *got, 2)
+		assert.Equal(t, models.DeliveryFailed, store.Deliveries[0].Status)
+		assert.Equal(t, 2, store.Deliveries[0].Attempts)
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		srv.Close()
+		store := &testutil.SubscriptionStore{}
+		svc := newTestSubscriptions(store, 3)
+		subscribe(t, svc, srv.URL)
+		ctx := context.Background()
+		require.NoError(t, svc.Publish(ctx, merged))
+
+		delivered, err := svc.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, delivered)
+
+		d := store.Deliveries[0]
+		assert.Equal(t, models.DeliveryPending, d.Status)
+		require.Len(t, d.AttemptLog, 1)
+		assert.Zero(t, d.AttemptLog[0].StatusCode)
+		assert.NotEmpty(t, d.AttemptLog[0].Error)
+	})
+}
+
+func TestSubscriptionService_ListDeliveries(t *testing.T) {
+	store := &testutil.SubscriptionStore{}
+	svc := newTestSubscriptions(store, 3)
+	ctx := context.Background()
+	sub, err := svc.Subscribe(ctx, &models.WebhookSubscription{
+		URL:        "https://example.com/hook",
+		EventTypes: []string{models.EventPRMerged},
+	})
+	require.NoError(t, err)
+	for i := range 3 {
+		require.NoError(t, svc.Publish(ctx, &models.Event{ID: "ev-" + strconv.Itoa(i), Type: models.EventPRMerged}))
+	}
+
+	deliveries, err := svc.ListDeliveries(ctx, sub.ID, 2)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, int64(3), deliveries[0].ID, "newest first")
+
+	_, err = svc.ListDeliveries(ctx, sub.ID+1, 0)
+	require.ErrorIs(t, err, apperrors.ErrNotFound)
+
+	_, err = svc.ListDeliveries(ctx, sub.ID, services.MaxDeliveryPageSize+1)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+}
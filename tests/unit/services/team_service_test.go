@@ -10,6 +10,7 @@ import (
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -33,6 +34,34 @@ func (m *mockTeamRepo) GetTeamByName(ctx context.Context, name string) (*models.
 	return args.Get(0).(*models.Team), args.Error(1)
 }
 
+func (m *mockTeamRepo) GetTeamSettings(ctx context.Context, name string) (*models.TeamSettings, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TeamSettings), args.Error(1)
+}
+
+func (m *mockTeamRepo) GetFallbackTeams(ctx context.Context, name string) ([]string, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockTeamRepo) SetFallbackTeams(ctx context.Context, name string, fallbackTeams []string) error {
+	args := m.Called(ctx, name, fallbackTeams)
+	return args.Error(0)
+}
+
+func (m *mockTeamRepo) UpdateTeamSettings(
+	ctx context.Context, name string, settings *models.TeamSettings, defaultReviewers int,
+) error {
+	args := m.Called(ctx, name, settings, defaultReviewers)
+	return args.Error(0)
+}
+
 type mockUserRepoForTeamService struct {
 	mock.Mock
 	repository.UserRepository
@@ -79,7 +108,8 @@ func TestTeamService_CreateTeam(t *testing.T) {
 	mTeamRepo := &mockTeamRepo{}
 	mUserRepo := &mockUserRepoForTeamService{}
 
-	svc := services.NewTeamService(mTeamRepo, mUserRepo, log)
+	audit, recorder := testutil.NewRecordingAudit()
+	svc := services.NewTeamService(mTeamRepo, mUserRepo, audit, 2, log)
 
 	t.Run("Success", func(t *testing.T) {
 		team := &models.Team{
@@ -104,6 +134,11 @@ func TestTeamService_CreateTeam(t *testing.T) {
 		assert.Equal(t, "team1", result.Name)
 		assert.Len(t, result.Members, 2)
 		mTeamRepo.AssertExpectations(t)
+
+		require.Equal(t, []string{models.AuditTeamCreated}, recorder.Actions())
+		assert.Equal(t, "team1", recorder.Events[0].EntityID)
+		assert.Nil(t, recorder.Events[0].Before)
+		assert.NotNil(t, recorder.Events[0].After)
 	})
 
 	t.Run("InvalidInput_EmptyName", func(t *testing.T) {
@@ -141,7 +176,7 @@ func TestTeamService_CreateTeam(t *testing.T) {
 	t.Run("ReloadFailed", func(t *testing.T) {
 		mTeamRepo2 := &mockTeamRepo{}
 		mUserRepo2 := &mockUserRepoForTeamService{}
-		svc2 := services.NewTeamService(mTeamRepo2, mUserRepo2, log)
+		svc2 := services.NewTeamService(mTeamRepo2, mUserRepo2, testutil.NewTestAudit(), 2, log)
 
 		team := &models.Team{
 			Name: "team1",
@@ -162,12 +197,13 @@ func TestTeamService_AddMemberToTeam(t *testing.T) {
 	mTeamRepo := &mockTeamRepo{}
 	mUserRepo := &mockUserRepoForTeamService{}
 
-	svc := services.NewTeamService(mTeamRepo, mUserRepo, log)
+	svc := services.NewTeamService(mTeamRepo, mUserRepo, testutil.NewTestAudit(), 2, log)
 
 	t.Run("Success", func(t *testing.T) {
 		team := &models.Team{Name: "team1"}
 		mTeamRepo.On("GetTeamByName", mock.Anything, "team1").Return(team, nil)
 		member := models.TeamMember{UserID: "u1", Username: "User1", IsActive: true}
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(nil, apperrors.ErrNotFound)
 		mUserRepo.On("UpsertUser", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
 
 		err := svc.AddMemberToTeam(context.Background(), "team1", member)
@@ -197,7 +233,7 @@ func TestTeamService_AddMemberToTeam(t *testing.T) {
 	t.Run("TeamNotFound", func(t *testing.T) {
 		mTeamRepo3 := &mockTeamRepo{}
 		mUserRepo3 := &mockUserRepoForTeamService{}
-		svc3 := services.NewTeamService(mTeamRepo3, mUserRepo3, log)
+		svc3 := services.NewTeamService(mTeamRepo3, mUserRepo3, testutil.NewTestAudit(), 2, log)
 
 		mTeamRepo3.On("GetTeamByName", mock.Anything, "team-nonexist").Return(nil, apperrors.ErrNotFound)
 		member := models.TeamMember{UserID: "u1", Username: "User1"}
@@ -209,11 +245,12 @@ func TestTeamService_AddMemberToTeam(t *testing.T) {
 	t.Run("UpsertUserFailed", func(t *testing.T) {
 		mTeamRepo4 := &mockTeamRepo{}
 		mUserRepo4 := &mockUserRepoForTeamService{}
-		svc4 := services.NewTeamService(mTeamRepo4, mUserRepo4, log)
+		svc4 := services.NewTeamService(mTeamRepo4, mUserRepo4, testutil.NewTestAudit(), 2, log)
 
 		team := &models.Team{Name: "team1"}
 		mTeamRepo4.On("GetTeamByName", mock.Anything, "team1").Return(team, nil)
 		member := models.TeamMember{UserID: "u1", Username: "User1"}
+		mUserRepo4.On("GetUserByID", mock.Anything, "u1").Return(nil, apperrors.ErrNotFound)
 		mUserRepo4.On("UpsertUser", mock.Anything, mock.AnythingOfType("*models.User")).Return(apperrors.ErrInternal)
 
 		err := svc4.AddMemberToTeam(context.Background(), "team1", member)
@@ -226,7 +263,7 @@ func TestTeamService_GetTeam(t *testing.T) {
 	mTeamRepo := &mockTeamRepo{}
 	mUserRepo := &mockUserRepoForTeamService{}
 
-	svc := services.NewTeamService(mTeamRepo, mUserRepo, log)
+	svc := services.NewTeamService(mTeamRepo, mUserRepo, testutil.NewTestAudit(), 2, log)
 
 	t.Run("Success", func(t *testing.T) {
 		team := &models.Team{
@@ -252,7 +289,7 @@ func TestTeamService_GetTeam(t *testing.T) {
 	t.Run("TeamNotFound", func(t *testing.T) {
 		mTeamRepo5 := &mockTeamRepo{}
 		mUserRepo5 := &mockUserRepoForTeamService{}
-		svc5 := services.NewTeamService(mTeamRepo5, mUserRepo5, log)
+		svc5 := services.NewTeamService(mTeamRepo5, mUserRepo5, testutil.NewTestAudit(), 2, log)
 
 		mTeamRepo5.On("GetTeamByName", mock.Anything, "team-nonexist").Return(nil, apperrors.ErrNotFound)
 
@@ -263,7 +300,7 @@ func TestTeamService_GetTeam(t *testing.T) {
 	t.Run("Error", func(t *testing.T) {
 		mTeamRepo6 := &mockTeamRepo{}
 		mUserRepo6 := &mockUserRepoForTeamService{}
-		svc6 := services.NewTeamService(mTeamRepo6, mUserRepo6, log)
+		svc6 := services.NewTeamService(mTeamRepo6, mUserRepo6, testutil.NewTestAudit(), 2, log)
 
 		mTeamRepo6.On("GetTeamByName", mock.Anything, "team1").Return(nil, apperrors.ErrInternal)
 
@@ -271,3 +308,118 @@ func TestTeamService_GetTeam(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestTeamService_UpdateTeamSettings(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("Success", func(t *testing.T) {
+		mTeamRepo := &mockTeamRepo{}
+		svc := services.NewTeamService(mTeamRepo, &mockUserRepoForTeamService{}, testutil.NewTestAudit(), 2, log)
+
+		three := 3
+		settings := &models.TeamSettings{ReviewersCount: &three}
+		mTeamRepo.On("UpdateTeamSettings", mock.Anything, "team1", settings, 2).Return(nil)
+		mTeamRepo.On("GetTeamByName", mock.Anything, "team1").
+			Return(&models.Team{Name: "team1", Settings: settings}, nil)
+
+		team, err := svc.UpdateTeamSettings(context.Background(), "team1", settings)
+		require.NoError(t, err)
+		require.NotNil(t, team.Settings)
+		assert.Equal(t, 3, *team.Settings.ReviewersCount)
+		mTeamRepo.AssertExpectations(t)
+	})
+
+	t.Run("ResetToDefault", func(t *testing.T) {
+		mTeamRepo := &mockTeamRepo{}
+		svc := services.NewTeamService(mTeamRepo, &mockUserRepoForTeamService{}, testutil.NewTestAudit(), 2, log)
+
+		settings := &models.TeamSettings{}
+		mTeamRepo.On("UpdateTeamSettings", mock.Anything, "team1", settings, 2).Return(nil)
+		mTeamRepo.On("GetTeamByName", mock.Anything, "team1").Return(&models.Team{Name: "team1"}, nil)
+
+		team, err := svc.UpdateTeamSettings(context.Background(), "team1", settings)
+		require.NoError(t, err)
+		assert.Nil(t, team.Settings)
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		svc := services.NewTeamService(&mockTeamRepo{}, &mockUserRepoForTeamService{}, testutil.NewTestAudit(), 2, log)
+
+		zero := 0
+		_, err := svc.UpdateTeamSettings(context.Background(), "team1", &models.TeamSettings{ReviewersCount: &zero})
+		require.ErrorIs(t, err, apperrors.ErrInvalidInput)
+
+		negative := -1
+		_, err = svc.UpdateTeamSettings(
+			context.Background(), "team1", &models.TeamSettings{RequiredApprovals: &negative})
+		require.ErrorIs(t, err, apperrors.ErrInvalidInput)
+
+		_, err = svc.UpdateTeamSettings(context.Background(), "", &models.TeamSettings{})
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+
+	t.Run("TeamNotFound", func(t *testing.T) {
+		mTeamRepo := &mockTeamRepo{}
+		svc := services.NewTeamService(mTeamRepo, &mockUserRepoForTeamService{}, testutil.NewTestAudit(), 2, log)
+
+		mTeamRepo.On("GetTeamByName", mock.Anything, "team-nonexist").Return(nil, apperrors.ErrNotFound)
+
+		_, err := svc.UpdateTeamSettings(context.Background(), "team-nonexist", &models.TeamSettings{})
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+		mTeamRepo.AssertNotCalled(t, "UpdateTeamSettings", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mTeamRepo := &mockTeamRepo{}
+		svc := services.NewTeamService(mTeamRepo, &mockUserRepoForTeamService{}, testutil.NewTestAudit(), 2, log)
+
+		mTeamRepo.On("GetTeamByName", mock.Anything, "team1").Return(&models.Team{Name: "team1"}, nil)
+		mTeamRepo.On("UpdateTeamSettings", mock.Anything, "team1", mock.Anything, 2).Return(apperrors.ErrInternal)
+
+		_, err := svc.UpdateTeamSettings(context.Background(), "team1", &models.TeamSettings{})
+		assert.ErrorIs(t, err, apperrors.ErrInternal)
+	})
+}
+
+func TestTeamService_SetFallbackTeams(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("Success", func(t *testing.T) {
+		mTeamRepo := &mockTeamRepo{}
+		svc := services.NewTeamService(mTeamRepo, &mockUserRepoForTeamService{}, testutil.NewTestAudit(), 2, log)
+
+		fallbacks := []string{"team2", "team3"}
+		mTeamRepo.On("SetFallbackTeams", mock.Anything, "team1", fallbacks).Return(nil)
+		mTeamRepo.On("GetTeamByName", mock.Anything, "team1").
+			Return(&models.Team{Name: "team1", FallbackTeams: fallbacks}, nil)
+
+		team, err := svc.SetFallbackTeams(context.Background(), "team1", fallbacks)
+		require.NoError(t, err)
+		assert.Equal(t, fallbacks, team.FallbackTeams)
+		mTeamRepo.AssertExpectations(t)
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		mTeamRepo := &mockTeamRepo{}
+		svc := services.NewTeamService(mTeamRepo, &mockUserRepoForTeamService{}, testutil.NewTestAudit(), 2, log)
+
+		for _, fallbacks := range [][]string{{"team1"}, {"team2", "team2"}, {""}} {
+			_, err := svc.SetFallbackTeams(context.Background(), "team1", fallbacks)
+			assert.ErrorIs(t, err, apperrors.ErrInvalidInput, fallbacks)
+		}
+		_, err := svc.SetFallbackTeams(context.Background(), "", nil)
+		require.ErrorIs(t, err, apperrors.ErrInvalidInput)
+		mTeamRepo.AssertNotCalled(t, "SetFallbackTeams", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("TeamNotFound", func(t *testing.T) {
+		mTeamRepo := &mockTeamRepo{}
+		svc := services.NewTeamService(mTeamRepo, &mockUserRepoForTeamService{}, testutil.NewTestAudit(), 2, log)
+
+		mTeamRepo.On("GetTeamByName", mock.Anything, "team1").Return(&models.Team{Name: "team1"}, nil)
+		mTeamRepo.On("SetFallbackTeams", mock.Anything, "team1", []string{"ghost"}).Return(apperrors.ErrNotFound)
+
+		_, err := svc.SetFallbackTeams(context.Background(), "team1", []string{"ghost"})
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/repository"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/reqctx"
 	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -38,6 +42,16 @@ func (m *mockUserRepoForUserService) UpdateUserActive(ctx context.Context, id st
 	return args.Error(0)
 }
 
+func (m *mockUserRepoForUserService) UpdateUserMaxOpenReviews(ctx context.Context, id string, limit *int) error {
+	args := m.Called(ctx, id, limit)
+	return args.Error(0)
+}
+
+func (m *mockUserRepoForUserService) UpdateUserEmail(ctx context.Context, id, email string, digest bool) error {
+	args := m.Called(ctx, id, email, digest)
+	return args.Error(0)
+}
+
 func (m *mockUserRepoForUserService) GetActiveUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
 	args := m.Called(ctx, teamName)
 	if args.Get(0) == nil {
@@ -46,6 +60,16 @@ func (m *mockUserRepoForUserService) GetActiveUsersByTeam(ctx context.Context, t
 	return args.Get(0).([]models.User), args.Error(1)
 }
 
+func (m *mockUserRepoForUserService) GetAvailableUsersByTeam(
+	ctx context.Context, teamName string,
+) ([]models.User, error) {
+	args := m.Called(ctx, teamName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
 func (m *mockUserRepoForUserService) GetTeamNameByUserID(ctx context.Context, userID string) (string, error) {
 	args := m.Called(ctx, userID)
 	return args.String(0), args.Error(1)
@@ -61,85 +85,112 @@ type mockPRRepoForUserService struct {
 	repository.PRRepository
 }
 
-func (m *mockPRRepoForUserService) CreatePR(ctx context.Context, pr *models.PullRequest) error {
-	args := m.Called(ctx, pr)
+func (m *mockPRRepoForUserService) CreatePR(
+	ctx context.Context,
+	pr *models.PullRequest,
+	change models.AssignmentChange,
+) error {
+	args := m.Called(ctx, pr, change)
 	return args.Error(0)
 }
 
-func (m *mockPRRepoForUserService) GetPRByID(ctx context.Context, id string) (*models.PullRequest, error) {
-	args := m.Called(ctx, id)
+func (m *mockPRRepoForUserService) GetPRByID(ctx context.Context, repository, id string) (*models.PullRequest, error) {
+	args := m.Called(ctx, repository, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.PullRequest), args.Error(1)
 }
 
-func (m *mockPRRepoForUserService) UpdatePR(ctx context.Context, pr *models.PullRequest) error {
-	args := m.Called(ctx, pr)
+func (m *mockPRRepoForUserService) UpdatePR(
+	ctx context.Context,
+	pr *models.PullRequest,
+	change models.AssignmentChange,
+) error {
+	args := m.Called(ctx, pr, change)
 	return args.Error(0)
 }
 
-func (m *mockPRRepoForUserService) MergePR(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *mockPRRepoForUserService) MergePR(ctx context.Context, repository, id string) error {
+	args := m.Called(ctx, repository, id)
 	return args.Error(0)
 }
 
 func (m *mockPRRepoForUserService) GetPRsForUser(
-	ctx context.Context, userID string,
+	ctx context.Context, userID, repository string, pendingOnly bool,
 ) ([]models.PullRequestShort, error) {
-	args := m.Called(ctx, userID)
+	args := m.Called(ctx, userID, repository, pendingOnly)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.PullRequestShort), args.Error(1)
 }
 
-func (m *mockPRRepoForUserService) ExistsPR(ctx context.Context, id string) (bool, error) {
-	args := m.Called(ctx, id)
+func (m *mockPRRepoForUserService) ExistsPR(ctx context.Context, repository, id string) (bool, error) {
+	args := m.Called(ctx, repository, id)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *mockPRRepoForUserService) GetTotalPRs(ctx context.Context) (int, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForUserService) GetTotalPRs(ctx context.Context, repository string) (int, error) {
+	args := m.Called(ctx, repository)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *mockPRRepoForUserService) GetPrsByStatus(ctx context.Context) (int, int, error) {
-	args := m.Called(ctx)
-	return args.Int(0), args.Int(1), args.Error(2)
+func (m *mockPRRepoForUserService) GetPrsByStatus(ctx context.Context, repository string) (models.PrsStatus, error) {
+	args := m.Called(ctx, repository)
+	return args.Get(0).(models.PrsStatus), args.Error(1)
 }
 
-func (m *mockPRRepoForUserService) GetAssignmentsPerUser(ctx context.Context) ([]models.UserAssignment, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForUserService) GetAssignmentsPerUser(
+	ctx context.Context, repository string,
+) ([]models.UserAssignment, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.UserAssignment), args.Error(1)
 }
 
-func (m *mockPRRepoForUserService) GetTopReviewers(ctx context.Context) ([]models.UserAssignment, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForUserService) GetOpenReviewLoadByTeam(
+	ctx context.Context,
+	teamName string,
+) ([]models.UserAssignment, error) {
+	args := m.Called(ctx, teamName)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.UserAssignment), args.Error(1)
 }
 
-func (m *mockPRRepoForUserService) GetAvgCloseTime(ctx context.Context) (float64, int, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForUserService) GetTopReviewers(
+	ctx context.Context, repository string,
+) ([]models.UserAssignment, error) {
+	args := m.Called(ctx, repository)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.UserAssignment), args.Error(1)
+}
+
+func (m *mockPRRepoForUserService) GetAvgCloseTime(ctx context.Context, repository string) (float64, int, error) {
+	args := m.Called(ctx, repository)
 	return args.Get(0).(float64), args.Int(1), args.Error(2)
 }
 
-func (m *mockPRRepoForUserService) GetIdleUsersPerTeam(ctx context.Context) ([]models.TeamMetric, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForUserService) GetIdleUsersPerTeam(
+	ctx context.Context, repository string,
+) ([]models.TeamMetric, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.TeamMetric), args.Error(1)
 }
 
-func (m *mockPRRepoForUserService) GetNeedyPRsPerTeam(ctx context.Context) ([]models.TeamMetric, error) {
-	args := m.Called(ctx)
+func (m *mockPRRepoForUserService) GetNeedyPRsPerTeam(
+	ctx context.Context, repository string,
+) ([]models.TeamMetric, error) {
+	args := m.Called(ctx, repository)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -157,12 +208,71 @@ func (m *mockPRRepoForUserService) GetOpenPRsWithReviewersFromTeam(
 	return args.Get(0).([]models.PullRequest), args.Error(1)
 }
 
+type mockAbsenceRepo struct {
+	mock.Mock
+	repository.AbsenceRepository
+}
+
+func (m *mockAbsenceRepo) CreateAbsence(ctx context.Context, absence *models.Absence) error {
+	args := m.Called(ctx, absence)
+	return args.Error(0)
+}
+
+func (m *mockAbsenceRepo) GetAbsencesByUser(ctx context.Context, userID string) ([]models.Absence, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Absence), args.Error(1)
+}
+
+func (m *mockAbsenceRepo) GetAbsenceByID(ctx context.Context, id int64) (*models.Absence, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Absence), args.Error(1)
+}
+
+func (m *mockAbsenceRepo) DeleteAbsence(ctx context.Context, id int64) (*models.Absence, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Absence), args.Error(1)
+}
+
+func (m *mockAbsenceRepo) GetStartedUnprocessedAbsences(ctx context.Context) ([]models.Absence, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Absence), args.Error(1)
+}
+
+func (m *mockAbsenceRepo) MarkAbsenceProcessed(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockAbsenceRepo) UpsertAbsenceByExternalUID(ctx context.Context, absence *models.Absence) (bool, error) {
+	args := m.Called(ctx, absence)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockAbsenceRepo) DeleteAbsenceByExternalUID(ctx context.Context, userID, externalUID string) (bool, error) {
+	args := m.Called(ctx, userID, externalUID)
+	return args.Bool(0), args.Error(1)
+}
+
 func TestUserService_SetUserActive(t *testing.T) {
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	mUserRepo := &mockUserRepoForUserService{}
 	mPRRepo := &mockPRRepoForUserService{}
 
-	svc := services.NewUserService(mUserRepo, mPRRepo, log)
+	svc := services.NewUserService(
+		mUserRepo, mPRRepo, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 
 	t.Run("Success_Activate", func(t *testing.T) {
 		mUserRepo.On("UpdateUserActive", mock.Anything, "u1", true).Return(nil)
@@ -178,7 +288,9 @@ func TestUserService_SetUserActive(t *testing.T) {
 	t.Run("Success_Deactivate", func(t *testing.T) {
 		mUserRepo2 := &mockUserRepoForUserService{}
 		mPRRepo2 := &mockPRRepoForUserService{}
-		svc2 := services.NewUserService(mUserRepo2, mPRRepo2, log)
+		svc2 := services.NewUserService(
+			mUserRepo2, mPRRepo2, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
 		mUserRepo2.On("UpdateUserActive", mock.Anything, "u1", false).Return(nil)
 		user := &models.User{ID: "u1", Name: "User1", IsActive: false}
@@ -197,25 +309,175 @@ func TestUserService_SetUserActive(t *testing.T) {
 	t.Run("UserNotFound", func(t *testing.T) {
 		mUserRepo3 := &mockUserRepoForUserService{}
 		mPRRepo3 := &mockPRRepoForUserService{}
-		svc3 := services.NewUserService(mUserRepo3, mPRRepo3, log)
+		svc3 := services.NewUserService(
+			mUserRepo3, mPRRepo3, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
-		mUserRepo3.On("UpdateUserActive", mock.Anything, "u-nonexist", true).Return(apperrors.ErrNotFound)
+		mUserRepo3.On("GetUserByID", mock.Anything, "u-nonexist").Return(nil, apperrors.ErrNotFound)
 
 		_, err := svc3.SetUserActive(context.Background(), "u-nonexist", true)
 		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+		mUserRepo3.AssertNotCalled(t, "UpdateUserActive", mock.Anything, mock.Anything, mock.Anything)
 	})
 
 	t.Run("ReloadFailed", func(t *testing.T) {
 		mUserRepo4 := &mockUserRepoForUserService{}
 		mPRRepo4 := &mockPRRepoForUserService{}
-		svc4 := services.NewUserService(mUserRepo4, mPRRepo4, log)
+		svc4 := services.NewUserService(
+			mUserRepo4, mPRRepo4, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
+		mUserRepo4.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1"}, nil).Once()
 		mUserRepo4.On("UpdateUserActive", mock.Anything, "u1", true).Return(nil)
-		mUserRepo4.On("GetUserByID", mock.Anything, "u1").Return(nil, apperrors.ErrInternal)
+		mUserRepo4.On("GetUserByID", mock.Anything, "u1").Return(nil, apperrors.ErrInternal).Once()
 
 		_, err := svc4.SetUserActive(context.Background(), "u1", true)
 		assert.ErrorIs(t, err, apperrors.ErrInternal)
 	})
+
+	t.Run("RecordsAuditEvent", func(t *testing.T) {
+		mUserRepo5 := &mockUserRepoForUserService{}
+		audit, recorder := testutil.NewRecordingAudit()
+		svc5 := services.NewUserService(
+			mUserRepo5, &mockPRRepoForUserService{}, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{}, audit,
+			services.NewRandomSelector(), 2, log)
+
+		mUserRepo5.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1", IsActive: true}, nil).Once()
+		mUserRepo5.On("UpdateUserActive", mock.Anything, "u1", false).Return(nil)
+		mUserRepo5.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1", IsActive: false}, nil).Once()
+
+		ctx := reqctx.WithActor(context.Background(), "alice")
+		_, err := svc5.SetUserActive(ctx, "u1", false)
+		require.NoError(t, err)
+
+		require.Len(t, recorder.Events, 1)
+		event := recorder.Events[0]
+		assert.Equal(t, models.AuditUserActiveChanged, event.Action)
+		assert.Equal(t, models.AuditEntityUser, event.EntityType)
+		assert.Equal(t, "u1", event.EntityID)
+		assert.Equal(t, "alice", event.Actor)
+		assert.JSONEq(t, `true`, string(jsonField(t, event.Before, "is_active")))
+		assert.JSONEq(t, `false`, string(jsonField(t, event.After, "is_active")))
+	})
+}
+
+func TestUserService_SetMaxOpenReviews(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("Success", func(t *testing.T) {
+		mUserRepo := &mockUserRepoForUserService{}
+		svc := services.NewUserService(
+			mUserRepo, &mockPRRepoForUserService{}, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{},
+			testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		limit := 3
+		mUserRepo.On("UpdateUserMaxOpenReviews", mock.Anything, "u1", &limit).Return(nil)
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1", MaxOpenReviews: &limit}, nil)
+
+		result, err := svc.SetMaxOpenReviews(context.Background(), "u1", &limit)
+		require.NoError(t, err)
+		require.NotNil(t, result.MaxOpenReviews)
+		assert.Equal(t, 3, *result.MaxOpenReviews)
+		mUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("RemoveLimit", func(t *testing.T) {
+		mUserRepo := &mockUserRepoForUserService{}
+		svc := services.NewUserService(
+			mUserRepo, &mockPRRepoForUserService{}, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{},
+			testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mUserRepo.On("UpdateUserMaxOpenReviews", mock.Anything, "u1", (*int)(nil)).Return(nil)
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1"}, nil)
+
+		result, err := svc.SetMaxOpenReviews(context.Background(), "u1", nil)
+		require.NoError(t, err)
+		assert.Nil(t, result.MaxOpenReviews)
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		svc := services.NewUserService(
+			&mockUserRepoForUserService{}, &mockPRRepoForUserService{}, &mockTeamRepo{}, &mockAbsenceRepo{},
+			&mockRepositoryRepo{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+		zero := 0
+		_, err := svc.SetMaxOpenReviews(context.Background(), "u1", &zero)
+		require.ErrorIs(t, err, apperrors.ErrInvalidInput)
+
+		_, err = svc.SetMaxOpenReviews(context.Background(), "", nil)
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+
+	t.Run("UserNotFound", func(t *testing.T) {
+		mUserRepo := &mockUserRepoForUserService{}
+		svc := services.NewUserService(
+			mUserRepo, &mockPRRepoForUserService{}, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{},
+			testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mUserRepo.On("GetUserByID", mock.Anything, "u-nonexist").Return(nil, apperrors.ErrNotFound)
+
+		_, err := svc.SetMaxOpenReviews(context.Background(), "u-nonexist", nil)
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+}
+
+func TestUserService_SetEmail(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	newSvc := func(userRepo *mockUserRepoForUserService) *services.UserService {
+		return services.NewUserService(
+			userRepo, &mockPRRepoForUserService{}, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{},
+			testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mUserRepo := &mockUserRepoForUserService{}
+		mUserRepo.On("UpdateUserEmail", mock.Anything, "u1", "alice@example.com", true).Return(nil)
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").
+			Return(&models.User{ID: "u1", Email: "alice@example.com", EmailDigest: true}, nil)
+
+		result, err := newSvc(mUserRepo).SetEmail(context.Background(), "u1", "alice@example.com", true)
+		require.NoError(t, err)
+		assert.Equal(t, "alice@example.com", result.Email)
+		assert.True(t, result.EmailDigest)
+		mUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("RemoveEmail", func(t *testing.T) {
+		mUserRepo := &mockUserRepoForUserService{}
+		mUserRepo.On("UpdateUserEmail", mock.Anything, "u1", "", false).Return(nil)
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1"}, nil)
+
+		result, err := newSvc(mUserRepo).SetEmail(context.Background(), "u1", "", false)
+		require.NoError(t, err)
+		assert.Empty(t, result.Email)
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		svc := newSvc(&mockUserRepoForUserService{})
+		for _, tc := range []struct {
+			id, email string
+			digest    bool
+		}{
+			{"", "alice@example.com", false},
+			{"u1", "not an email", false},
+			{"u1", "Alice <alice@example.com>", false},
+			{"u1", "", true},
+		} {
+			_, err := svc.SetEmail(context.Background(), tc.id, tc.email, tc.digest)
+			assert.ErrorIs(t, err, apperrors.ErrInvalidInput, tc.email)
+		}
+	})
+
+	t.Run("UserNotFound", func(t *testing.T) {
+		mUserRepo := &mockUserRepoForUserService{}
+		mUserRepo.On("GetUserByID", mock.Anything, "u-nonexist").Return(nil, apperrors.ErrNotFound)
+
+		_, err := newSvc(mUserRepo).SetEmail(context.Background(), "u-nonexist", "x@example.com", false)
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
 }
 
 func TestUserService_GetPRsForUser(t *testing.T) {
@@ -223,45 +485,51 @@ func TestUserService_GetPRsForUser(t *testing.T) {
 	mUserRepo := &mockUserRepoForUserService{}
 	mPRRepo := &mockPRRepoForUserService{}
 
-	svc := services.NewUserService(mUserRepo, mPRRepo, log)
+	svc := services.NewUserService(
+		mUserRepo, mPRRepo, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+		services.NewRandomSelector(), 2, log)
 
 	t.Run("Success", func(t *testing.T) {
 		prs := []models.PullRequestShort{
 			{ID: "pr-1", Title: "PR1", Status: "OPEN"},
 			{ID: "pr-2", Title: "PR2", Status: "OPEN"},
 		}
-		mPRRepo.On("GetPRsForUser", mock.Anything, "u1").Return(prs, nil)
+		mPRRepo.On("GetPRsForUser", mock.Anything, "u1", "", false).Return(prs, nil)
 
-		result, err := svc.GetPRsForUser(context.Background(), "u1")
+		result, err := svc.GetPRsForUser(context.Background(), "u1", "", false)
 		require.NoError(t, err)
 		assert.Len(t, result, 2)
 		mPRRepo.AssertExpectations(t)
 	})
 
 	t.Run("InvalidInput", func(t *testing.T) {
-		_, err := svc.GetPRsForUser(context.Background(), "")
+		_, err := svc.GetPRsForUser(context.Background(), "", "", false)
 		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
 	})
 
 	t.Run("Error", func(t *testing.T) {
 		mUserRepo5 := &mockUserRepoForUserService{}
 		mPRRepo5 := &mockPRRepoForUserService{}
-		svc5 := services.NewUserService(mUserRepo5, mPRRepo5, log)
+		svc5 := services.NewUserService(
+			mUserRepo5, mPRRepo5, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
-		mPRRepo5.On("GetPRsForUser", mock.Anything, "u1").Return(nil, apperrors.ErrInternal)
+		mPRRepo5.On("GetPRsForUser", mock.Anything, "u1", "", false).Return(nil, apperrors.ErrInternal)
 
-		_, err := svc5.GetPRsForUser(context.Background(), "u1")
+		_, err := svc5.GetPRsForUser(context.Background(), "u1", "", false)
 		assert.Error(t, err)
 	})
 
 	t.Run("EmptyList", func(t *testing.T) {
 		mUserRepo6 := &mockUserRepoForUserService{}
 		mPRRepo6 := &mockPRRepoForUserService{}
-		svc6 := services.NewUserService(mUserRepo6, mPRRepo6, log)
+		svc6 := services.NewUserService(
+			mUserRepo6, mPRRepo6, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
-		mPRRepo6.On("GetPRsForUser", mock.Anything, "u1").Return([]models.PullRequestShort{}, nil)
+		mPRRepo6.On("GetPRsForUser", mock.Anything, "u1", "", false).Return([]models.PullRequestShort{}, nil)
 
-		result, err := svc6.GetPRsForUser(context.Background(), "u1")
+		result, err := svc6.GetPRsForUser(context.Background(), "u1", "", false)
 		require.NoError(t, err)
 		assert.Empty(t, result)
 	})
@@ -273,7 +541,10 @@ func TestUserService_DeactivateUsersByTeam(t *testing.T) {
 	t.Run("Success_NoPRs", func(t *testing.T) {
 		mUserRepo := &mockUserRepoForUserService{}
 		mPRRepo := &mockPRRepoForUserService{}
-		svc := services.NewUserService(mUserRepo, mPRRepo, log)
+		audit, recorder := testutil.NewRecordingAudit()
+		svc := services.NewUserService(
+			mUserRepo, mPRRepo, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{}, audit,
+			services.NewRandomSelector(), 2, log)
 
 		activeUsers := []models.User{
 			{ID: "u1", Name: "User1", TeamName: "team1", IsActive: true},
@@ -287,12 +558,24 @@ func TestUserService_DeactivateUsersByTeam(t *testing.T) {
 		require.NoError(t, err)
 		mUserRepo.AssertExpectations(t)
 		mPRRepo.AssertExpectations(t)
+
+		require.Equal(t, []string{models.AuditTeamDeactivated}, recorder.Actions())
+		event := recorder.Events[0]
+		assert.Equal(t, models.AuditEntityTeam, event.EntityType)
+		assert.Equal(t, "team1", event.EntityID)
+		assert.JSONEq(t, `true`, string(jsonField(t, jsonItem(t, event.Before, 0), "is_active")))
+		assert.JSONEq(t, `false`, string(jsonField(t, jsonItem(t, event.After, 0), "is_active")))
 	})
 
 	t.Run("Success_WithPRReassignment_PartialTeam", func(t *testing.T) {
 		mUserRepo := &mockUserRepoForUserService{}
 		mPRRepo := &mockPRRepoForUserService{}
-		svc := services.NewUserService(mUserRepo, mPRRepo, log)
+		mTeamRepo := &mockTeamRepo{}
+		recorder, outbox := &testutil.AuditRecorder{}, &outboxStore{}
+		audit := services.NewAuditService(recorder, testutil.InlineTx{}, log, newTestOutbox(outbox))
+		svc := services.NewUserService(
+			mUserRepo, mPRRepo, mTeamRepo, &mockAbsenceRepo{}, &mockRepositoryRepo{}, audit,
+			services.NewRandomSelector(), 2, log)
 
 		activeUsersBefore := []models.User{
 			{ID: "u1", Name: "User1", TeamName: "team1", IsActive: true},
@@ -314,11 +597,103 @@ func TestUserService_DeactivateUsersByTeam(t *testing.T) {
 		mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").Return(activeUsersBefore, nil).Once()
 		mUserRepo.On("DeactivateUsersByTeam", mock.Anything, "team1").Return(nil)
 		mPRRepo.On("GetOpenPRsWithReviewersFromTeam", mock.Anything, "team1").Return([]models.PullRequest{*pr}, nil)
-		mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").Return(activeUsersAfter, nil).Once()
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return(activeUsersAfter, nil).Once()
+		mUserRepo.On("GetTeamNameByUserID", mock.Anything, "author1").Return("team1", nil)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
 
 		mPRRepo.On("UpdatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
 			return p.ID == "pr-1" && len(p.Reviewers) >= 1 && p.Reviewers[0] == "u3"
-		})).Return(nil)
+		}), models.AssignmentChange{Actor: "admin", Reason: models.AssignReasonDeactivated}).Return(nil)
+
+		err := svc.DeactivateUsersByTeam(reqctx.WithActor(context.Background(), "admin"), "team1")
+		require.NoError(t, err)
+		mUserRepo.AssertExpectations(t)
+		mPRRepo.AssertExpectations(t)
+
+		assert.Equal(t, []string{models.AuditTeamDeactivated, models.AuditPRReviewerReassigned}, recorder.Actions())
+		var reassigned []string
+		for _, e := range outbox.entries {
+			if e.Event.Type == models.EventReviewerReassigned {
+				reassigned = append(reassigned, e.Event.Data.ReviewerID)
+				assert.Equal(t, "admin", e.Event.Actor)
+			}
+		}
+		assert.Equal(t, []string{"u3"}, reassigned, "the replacement reviewer is notified")
+	})
+
+	t.Run("Success_LeastLoadedReplacement", func(t *testing.T) {
+		mUserRepo := &mockUserRepoForUserService{}
+		mPRRepo := &mockPRRepoForUserService{}
+		mTeamRepo := &mockTeamRepo{}
+		selector := services.NewLeastLoadedSelector(mPRRepo)
+		svc := services.NewUserService(
+			mUserRepo, mPRRepo, mTeamRepo, &mockAbsenceRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(), selector, 2, log)
+
+		pr := &models.PullRequest{
+			ID:        "pr-1",
+			AuthorID:  "author1",
+			Status:    "OPEN",
+			Reviewers: []string{"u1"},
+		}
+
+		mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").Return([]models.User{
+			{ID: "u1", TeamName: "team1", IsActive: true},
+		}, nil).Once()
+		mUserRepo.On("DeactivateUsersByTeam", mock.Anything, "team1").Return(nil)
+		mPRRepo.On("GetOpenPRsWithReviewersFromTeam", mock.Anything, "team1").Return([]models.PullRequest{*pr}, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
+			{ID: "u3", TeamName: "team1", IsActive: true},
+			{ID: "u4", TeamName: "team1", IsActive: true},
+		}, nil).Once()
+		mPRRepo.On("GetOpenReviewLoadByTeam", mock.Anything, "team1").Return([]models.UserAssignment{
+			{UserID: "u3", Count: 0},
+			{UserID: "u4", Count: 3},
+		}, nil)
+		one := 1
+		mUserRepo.On("GetTeamNameByUserID", mock.Anything, "author1").Return("team1", nil)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{ReviewersCount: &one}, nil)
+		mPRRepo.On("UpdatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return p.ID == "pr-1" && len(p.Reviewers) == 1 && p.Reviewers[0] == "u3" && !p.NeedMoreReviewers
+		}), mock.Anything).Return(nil)
+
+		err := svc.DeactivateUsersByTeam(context.Background(), "team1")
+		require.NoError(t, err)
+		mUserRepo.AssertExpectations(t)
+		mPRRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success_SkipsReviewersAtCapacity", func(t *testing.T) {
+		mUserRepo := &mockUserRepoForUserService{}
+		mPRRepo := &mockPRRepoForUserService{}
+		mTeamRepo := &mockTeamRepo{}
+		svc := services.NewUserService(
+			mUserRepo, mPRRepo, mTeamRepo, &mockAbsenceRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		pr := &models.PullRequest{
+			ID:        "pr-1",
+			AuthorID:  "author1",
+			Status:    "OPEN",
+			Reviewers: []string{"u1", "u2"},
+		}
+
+		limit := 2
+		mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").Return([]models.User{
+			{ID: "u1", TeamName: "team1", IsActive: true},
+		}, nil).Once()
+		mUserRepo.On("DeactivateUsersByTeam", mock.Anything, "team1").Return(nil)
+		mPRRepo.On("GetOpenPRsWithReviewersFromTeam", mock.Anything, "team1").Return([]models.PullRequest{*pr}, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
+			{ID: "u3", TeamName: "team1", IsActive: true, MaxOpenReviews: &limit},
+		}, nil).Once()
+		mPRRepo.On("GetOpenReviewLoadByTeam", mock.Anything, "team1").Return([]models.UserAssignment{
+			{UserID: "u3", Count: 2},
+		}, nil)
+		mUserRepo.On("GetTeamNameByUserID", mock.Anything, "author1").Return("team1", nil)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mPRRepo.On("UpdatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return p.ID == "pr-1" && len(p.Reviewers) == 1 && p.Reviewers[0] == "u2" && p.NeedMoreReviewers
+		}), mock.Anything).Return(nil)
 
 		err := svc.DeactivateUsersByTeam(context.Background(), "team1")
 		require.NoError(t, err)
@@ -329,7 +704,10 @@ func TestUserService_DeactivateUsersByTeam(t *testing.T) {
 	t.Run("Success_NoActiveReplacement", func(t *testing.T) {
 		mUserRepo := &mockUserRepoForUserService{}
 		mPRRepo := &mockPRRepoForUserService{}
-		svc := services.NewUserService(mUserRepo, mPRRepo, log)
+		mTeamRepo := &mockTeamRepo{}
+		svc := services.NewUserService(
+			mUserRepo, mPRRepo, mTeamRepo, &mockAbsenceRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
 		activeUsers := []models.User{
 			{ID: "u1", Name: "User1", TeamName: "team1", IsActive: true},
@@ -349,11 +727,13 @@ func TestUserService_DeactivateUsersByTeam(t *testing.T) {
 		mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").Return(activeUsers, nil).Once()
 		mUserRepo.On("DeactivateUsersByTeam", mock.Anything, "team1").Return(nil)
 		mPRRepo.On("GetOpenPRsWithReviewersFromTeam", mock.Anything, "team1").Return([]models.PullRequest{*pr}, nil)
-		mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").Return(activeUsersAfter, nil).Once()
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return(activeUsersAfter, nil).Once()
+		mUserRepo.On("GetTeamNameByUserID", mock.Anything, "author1").Return("team1", nil)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(nil, apperrors.ErrNotFound)
 
 		mPRRepo.On("UpdatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
-			return p.ID == "pr-1" && len(p.Reviewers) == 0
-		})).Return(nil)
+			return p.ID == "pr-1" && len(p.Reviewers) == 0 && p.NeedMoreReviewers
+		}), mock.Anything).Return(nil)
 
 		err := svc.DeactivateUsersByTeam(context.Background(), "team1")
 		require.NoError(t, err)
@@ -364,7 +744,9 @@ func TestUserService_DeactivateUsersByTeam(t *testing.T) {
 	t.Run("InvalidInput", func(t *testing.T) {
 		mUserRepo := &mockUserRepoForUserService{}
 		mPRRepo := &mockPRRepoForUserService{}
-		svc := services.NewUserService(mUserRepo, mPRRepo, log)
+		svc := services.NewUserService(
+			mUserRepo, mPRRepo, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
 		err := svc.DeactivateUsersByTeam(context.Background(), "")
 		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
@@ -373,7 +755,9 @@ func TestUserService_DeactivateUsersByTeam(t *testing.T) {
 	t.Run("Error_GetActiveUsers", func(t *testing.T) {
 		mUserRepo := &mockUserRepoForUserService{}
 		mPRRepo := &mockPRRepoForUserService{}
-		svc := services.NewUserService(mUserRepo, mPRRepo, log)
+		svc := services.NewUserService(
+			mUserRepo, mPRRepo, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
 		mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").Return(nil, apperrors.ErrInternal)
 
@@ -384,7 +768,9 @@ func TestUserService_DeactivateUsersByTeam(t *testing.T) {
 	t.Run("Error_Deactivate", func(t *testing.T) {
 		mUserRepo := &mockUserRepoForUserService{}
 		mPRRepo := &mockPRRepoForUserService{}
-		svc := services.NewUserService(mUserRepo, mPRRepo, log)
+		svc := services.NewUserService(
+			mUserRepo, mPRRepo, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
 		activeUsers := []models.User{
 			{ID: "u1", Name: "User1", TeamName: "team1", IsActive: true},
@@ -396,10 +782,48 @@ func TestUserService_DeactivateUsersByTeam(t *testing.T) {
 		assert.Error(t, err)
 	})
 
-	t.Run("Error_GetOpenPRs_Continues", func(t *testing.T) {
+	t.Run("FailedPRRollsBackAlone", func(t *testing.T) {
+		mUserRepo := &mockUserRepoForUserService{}
+		mPRRepo := &mockPRRepoForUserService{}
+		mTeamRepo := &mockTeamRepo{}
+		recorder := &testutil.AuditRecorder{}
+		tx := &testutil.SavepointTx{Recorder: recorder}
+		audit := services.NewAuditService(recorder, tx, log)
+		svc := services.NewUserService(
+			mUserRepo, mPRRepo, mTeamRepo, &mockAbsenceRepo{}, &mockRepositoryRepo{}, audit,
+			services.NewRandomSelector(), 2, log)
+
+		mUserRepo.On("GetActiveUsersByTeam", mock.Anything, "team1").
+			Return([]models.User{{ID: "u1", TeamName: "team1", IsActive: true}}, nil)
+		mUserRepo.On("DeactivateUsersByTeam", mock.Anything, "team1").Return(nil)
+		mPRRepo.On("GetOpenPRsWithReviewersFromTeam", mock.Anything, "team1").Return([]models.PullRequest{
+			{ID: "pr-1", AuthorID: "author1", Status: "OPEN", Reviewers: []string{"u1"}},
+			{ID: "pr-2", AuthorID: "author1", Status: "OPEN", Reviewers: []string{"u1"}},
+		}, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").
+			Return([]models.User{{ID: "u3", TeamName: "team1", IsActive: true}}, nil)
+		mUserRepo.On("GetTeamNameByUserID", mock.Anything, "author1").Return("team1", nil)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mPRRepo.On("UpdatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return p.ID == "pr-1"
+		}), mock.Anything).Return(apperrors.ErrInternal)
+		mPRRepo.On("UpdatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return p.ID == "pr-2"
+		}), mock.Anything).Return(nil)
+
+		err := svc.DeactivateUsersByTeam(context.Background(), "team1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, tx.RolledBack)
+		require.Equal(t, []string{models.AuditTeamDeactivated, models.AuditPRReviewerReassigned}, recorder.Actions())
+		assert.Equal(t, "pr-2", recorder.Events[1].EntityID)
+	})
+
+	t.Run("Error_GetOpenPRs", func(t *testing.T) {
 		mUserRepo := &mockUserRepoForUserService{}
 		mPRRepo := &mockPRRepoForUserService{}
-		svc := services.NewUserService(mUserRepo, mPRRepo, log)
+		svc := services.NewUserService(
+			mUserRepo, mPRRepo, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
 
 		activeUsers := []models.User{
 			{ID: "u1", Name: "User1", TeamName: "team1", IsActive: true},
@@ -409,6 +833,323 @@ func TestUserService_DeactivateUsersByTeam(t *testing.T) {
 		mPRRepo.On("GetOpenPRsWithReviewersFromTeam", mock.Anything, "team1").Return(nil, apperrors.ErrInternal)
 
 		err := svc.DeactivateUsersByTeam(context.Background(), "team1")
+		assert.ErrorIs(t, err, apperrors.ErrInternal)
+	})
+}
+
+func TestUserService_AddAbsence(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(14 * 24 * time.Hour)
+
+	t.Run("Success", func(t *testing.T) {
+		mUserRepo := &mockUserRepoForUserService{}
+		mAbsenceRepo := &mockAbsenceRepo{}
+		svc := services.NewUserService(
+			mUserRepo, &mockPRRepoForUserService{}, &mockTeamRepo{}, mAbsenceRepo, &mockRepositoryRepo{},
+			testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1"}, nil)
+		mAbsenceRepo.On("CreateAbsence", mock.Anything, mock.AnythingOfType("*models.Absence")).
+			Run(func(args mock.Arguments) {
+				args.Get(1).(*models.Absence).ID = 7
+			}).Return(nil)
+
+		absence, err := svc.AddAbsence(context.Background(), &models.Absence{
+			UserID: "u1", StartsAt: start, EndsAt: end, Reason: "vacation",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), absence.ID)
+		mAbsenceRepo.AssertExpectations(t)
+	})
+
+	t.Run("InvalidInput_EndBeforeStart", func(t *testing.T) {
+		svc := services.NewUserService(
+			&mockUserRepoForUserService{}, &mockPRRepoForUserService{}, &mockTeamRepo{}, &mockAbsenceRepo{},
+			&mockRepositoryRepo{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+		_, err := svc.AddAbsence(context.Background(), &models.Absence{UserID: "u1", StartsAt: end, EndsAt: start})
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+
+	t.Run("UserNotFound", func(t *testing.T) {
+		mUserRepo := &mockUserRepoForUserService{}
+		mAbsenceRepo := &mockAbsenceRepo{}
+		svc := services.NewUserService(
+			mUserRepo, &mockPRRepoForUserService{}, &mockTeamRepo{}, mAbsenceRepo, &mockRepositoryRepo{},
+			testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mUserRepo.On("GetUserByID", mock.Anything, "u-nonexist").Return(nil, apperrors.ErrNotFound)
+
+		_, err := svc.AddAbsence(context.Background(), &models.Absence{UserID: "u-nonexist", StartsAt: start, EndsAt: end})
+		require.ErrorIs(t, err, apperrors.ErrNotFound)
+		mAbsenceRepo.AssertNotCalled(t, "CreateAbsence", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserService_DeleteAbsence(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	mAbsenceRepo := &mockAbsenceRepo{}
+	svc := services.NewUserService(
+		&mockUserRepoForUserService{}, &mockPRRepoForUserService{}, &mockTeamRepo{}, mAbsenceRepo,
+		&mockRepositoryRepo{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+	t.Run("Success", func(t *testing.T) {
+		mAbsenceRepo.On("DeleteAbsence", mock.Anything, int64(1)).Return(&models.Absence{ID: 1, UserID: "u1"}, nil)
+
+		require.NoError(t, svc.DeleteAbsence(context.Background(), 1))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mAbsenceRepo.On("DeleteAbsence", mock.Anything, int64(404)).Return(nil, apperrors.ErrNotFound)
+
+		assert.ErrorIs(t, svc.DeleteAbsence(context.Background(), 404), apperrors.ErrNotFound)
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		assert.ErrorIs(t, svc.DeleteAbsence(context.Background(), 0), apperrors.ErrInvalidInput)
+	})
+}
+
+func TestUserService_ImportAbsences(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	calendar := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:vac-alice",
+		"SUMMARY:Vacation",
+		"ATTENDEE;CN=Alice:mailto:alice@example.com",
+		"DTSTART;VALUE=DATE:20260701",
+		"DTEND;VALUE=DATE:20260715",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:sick-bob",
+		"SUMMARY:Bob - sick leave",
+		"DTSTART:20260801T090000Z",
+		"DTEND:20260802T090000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:vac-carol",
+		"SUMMARY:Carol vacation",
+		"DTSTART;VALUE=DATE:20260901",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:vac-alice-old",
+		"STATUS:CANCELLED",
+		"ORGANIZER;CN=u1:mailto:hr@example.com",
+		"DTSTART;VALUE=DATE:20260601",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	team := &models.Team{Name: "team1", Members: []models.TeamMember{
+		{UserID: "u1", Username: "Alice"},
+		{UserID: "u2", Username: "Bob"},
+	}}
+
+	t.Run("Team_MatchesMembersAndReportsUnmatched", func(t *testing.T) {
+		mTeamRepo := &mockTeamRepo{}
+		mAbsenceRepo := &mockAbsenceRepo{}
+		svc := services.NewUserService(
+			&mockUserRepoForUserService{}, &mockPRRepoForUserService{}, mTeamRepo, mAbsenceRepo, &mockRepositoryRepo{},
+			testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mTeamRepo.On("GetTeamByName", mock.Anything, "team1").Return(team, nil)
+		mAbsenceRepo.On("UpsertAbsenceByExternalUID", mock.Anything, mock.MatchedBy(func(a *models.Absence) bool {
+			return a.UserID == "u1" && a.ExternalUID == "vac-alice" &&
+				a.StartsAt.Equal(time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)) &&
+				a.EndsAt.Equal(time.Date(2026, 7, 15, 0, 0, 0, 0, time.UTC)) && a.Reason == "Vacation"
+		})).Return(true, nil).Once()
+		mAbsenceRepo.On("UpsertAbsenceByExternalUID", mock.Anything, mock.MatchedBy(func(a *models.Absence) bool {
+			return a.UserID == "u2" && a.ExternalUID == "sick-bob"
+		})).Return(false, nil).Once()
+		mAbsenceRepo.On("DeleteAbsenceByExternalUID", mock.Anything, "u1", "vac-alice-old").Return(true, nil).Once()
+
+		result, err := svc.ImportAbsences(context.Background(), "", "team1", strings.NewReader(calendar))
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 1, result.Updated)
+		assert.Equal(t, 1, result.Cancelled)
+		require.Len(t, result.Unmatched, 1)
+		assert.Equal(t, "vac-carol", result.Unmatched[0].UID)
+		mAbsenceRepo.AssertExpectations(t)
+	})
+
+	t.Run("User_AllEventsBelongToUser", func(t *testing.T) {
+		mUserRepo := &mockUserRepoForUserService{}
+		mAbsenceRepo := &mockAbsenceRepo{}
+		svc := services.NewUserService(
+			mUserRepo, &mockPRRepoForUserService{}, &mockTeamRepo{}, mAbsenceRepo, &mockRepositoryRepo{},
+			testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mUserRepo.On("GetUserByID", mock.Anything, "u3").Return(&models.User{ID: "u3", Name: "Dave"}, nil)
+		mAbsenceRepo.On("UpsertAbsenceByExternalUID", mock.Anything, mock.MatchedBy(func(a *models.Absence) bool {
+			return a.UserID == "u3"
+		})).Return(true, nil)
+		mAbsenceRepo.On("DeleteAbsenceByExternalUID", mock.Anything, "u3", "vac-alice-old").Return(false, nil)
+
+		result, err := svc.ImportAbsences(context.Background(), "u3", "", strings.NewReader(calendar))
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Created)
+		assert.Equal(t, 0, result.Cancelled)
+		assert.Empty(t, result.Unmatched)
+	})
+
+	t.Run("InvalidInput_NoTarget", func(t *testing.T) {
+		svc := services.NewUserService(
+			&mockUserRepoForUserService{}, &mockPRRepoForUserService{}, &mockTeamRepo{}, &mockAbsenceRepo{},
+			&mockRepositoryRepo{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+		_, err := svc.ImportAbsences(context.Background(), "", "", strings.NewReader(calendar))
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+
+	t.Run("InvalidInput_NotACalendar", func(t *testing.T) {
+		mUserRepo := &mockUserRepoForUserService{}
+		svc := services.NewUserService(
+			mUserRepo, &mockPRRepoForUserService{}, &mockTeamRepo{}, &mockAbsenceRepo{}, &mockRepositoryRepo{},
+			testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1"}, nil)
+
+		_, err := svc.ImportAbsences(context.Background(), "u1", "", strings.NewReader("not a calendar"))
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+
+	t.Run("TeamNotFound", func(t *testing.T) {
+		mTeamRepo := &mockTeamRepo{}
+		svc := services.NewUserService(
+			&mockUserRepoForUserService{}, &mockPRRepoForUserService{}, mTeamRepo, &mockAbsenceRepo{},
+			&mockRepositoryRepo{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+		mTeamRepo.On("GetTeamByName", mock.Anything, "ghost").Return(nil, apperrors.ErrNotFound)
+
+		_, err := svc.ImportAbsences(context.Background(), "", "ghost", strings.NewReader(calendar))
+		assert.ErrorIs(t, err, apperrors.ErrNotFound)
+	})
+
+	t.Run("RepoError", func(t *testing.T) {
+		mUserRepo := &mockUserRepoForUserService{}
+		mAbsenceRepo := &mockAbsenceRepo{}
+		svc := services.NewUserService(
+			mUserRepo, &mockPRRepoForUserService{}, &mockTeamRepo{}, mAbsenceRepo, &mockRepositoryRepo{},
+			testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mUserRepo.On("GetUserByID", mock.Anything, "u1").Return(&models.User{ID: "u1"}, nil)
+		mAbsenceRepo.On("UpsertAbsenceByExternalUID", mock.Anything, mock.Anything).Return(false, apperrors.ErrInternal)
+
+		_, err := svc.ImportAbsences(context.Background(), "u1", "", strings.NewReader(calendar))
+		assert.ErrorIs(t, err, apperrors.ErrInternal)
+	})
+}
+
+func TestUserService_ReassignAbsentReviewers(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("ReassignsAndMarksProcessed", func(t *testing.T) {
+		mUserRepo := &mockUserRepoForUserService{}
+		mPRRepo := &mockPRRepoForUserService{}
+		mTeamRepo := &mockTeamRepo{}
+		mAbsenceRepo := &mockAbsenceRepo{}
+		audit, recorder := testutil.NewRecordingAudit()
+		svc := services.NewUserService(
+			mUserRepo, mPRRepo, mTeamRepo, mAbsenceRepo, &mockRepositoryRepo{}, audit,
+			services.NewRandomSelector(), 2, log)
+
+		pr := models.PullRequest{ID: "pr-1", AuthorID: "author1", Status: "OPEN", Reviewers: []string{"u1", "u2"}}
+
+		mAbsenceRepo.On("GetStartedUnprocessedAbsences", mock.Anything).Return([]models.Absence{
+			{ID: 5, UserID: "u1"},
+		}, nil)
+		mUserRepo.On("GetTeamNameByUserID", mock.Anything, "u1").Return("team1", nil)
+		mPRRepo.On("GetOpenPRsWithReviewersFromTeam", mock.Anything, "team1").Return([]models.PullRequest{pr}, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return([]models.User{
+			{ID: "u2", TeamName: "team1", IsActive: true},
+			{ID: "u3", TeamName: "team1", IsActive: true},
+		}, nil)
+		mUserRepo.On("GetTeamNameByUserID", mock.Anything, "author1").Return("team1", nil)
+		mTeamRepo.On("GetTeamSettings", mock.Anything, "team1").Return(&models.TeamSettings{}, nil)
+		mPRRepo.On("UpdatePR", mock.Anything, mock.MatchedBy(func(p *models.PullRequest) bool {
+			return p.ID == "pr-1" && p.Reviewers[0] == "u3" && p.Reviewers[1] == "u2" && !p.NeedMoreReviewers
+		}), models.AssignmentChange{Actor: reqctx.SystemActor, Reason: models.AssignReasonAbsent}).Return(nil)
+		mAbsenceRepo.On("MarkAbsenceProcessed", mock.Anything, int64(5)).Return(nil)
+
+		processed, err := svc.ReassignAbsentReviewers(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+		mPRRepo.AssertExpectations(t)
+		mAbsenceRepo.AssertExpectations(t)
+
+		require.Equal(t, []string{models.AuditPRAbsentReassigned}, recorder.Actions())
+		event := recorder.Events[0]
+		assert.Equal(t, reqctx.SystemActor, event.Actor)
+		assert.Equal(t, "pr-1", event.EntityID)
+		assert.JSONEq(t, `["u1", "u2"]`, string(jsonField(t, event.Before, "assigned_reviewers")))
+		assert.JSONEq(t, `["u3", "u2"]`, string(jsonField(t, event.After, "assigned_reviewers")))
+	})
+
+	t.Run("NothingToDo", func(t *testing.T) {
+		mAbsenceRepo := &mockAbsenceRepo{}
+		svc := services.NewUserService(
+			&mockUserRepoForUserService{}, &mockPRRepoForUserService{}, &mockTeamRepo{}, mAbsenceRepo,
+			&mockRepositoryRepo{}, testutil.NewTestAudit(), services.NewRandomSelector(), 2, log)
+
+		mAbsenceRepo.On("GetStartedUnprocessedAbsences", mock.Anything).Return([]models.Absence{}, nil)
+
+		processed, err := svc.ReassignAbsentReviewers(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, processed)
+	})
+
+	t.Run("OpenPRsError_LeavesAbsenceUnprocessed", func(t *testing.T) {
+		mUserRepo := &mockUserRepoForUserService{}
+		mPRRepo := &mockPRRepoForUserService{}
+		mAbsenceRepo := &mockAbsenceRepo{}
+		svc := services.NewUserService(
+			mUserRepo, mPRRepo, &mockTeamRepo{}, mAbsenceRepo, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		mAbsenceRepo.On("GetStartedUnprocessedAbsences", mock.Anything).Return([]models.Absence{
+			{ID: 5, UserID: "u1"},
+		}, nil)
+		mUserRepo.On("GetTeamNameByUserID", mock.Anything, "u1").Return("team1", nil)
+		mPRRepo.On("GetOpenPRsWithReviewersFromTeam", mock.Anything, "team1").Return(nil, apperrors.ErrInternal)
+
+		processed, err := svc.ReassignAbsentReviewers(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, processed)
+		mAbsenceRepo.AssertNotCalled(t, "MarkAbsenceProcessed", mock.Anything, mock.Anything)
+	})
+
+	t.Run("FailedPR_LeavesItsReviewersAbsenceUnprocessed", func(t *testing.T) {
+		mUserRepo := &mockUserRepoForUserService{}
+		mPRRepo := &mockPRRepoForUserService{}
+		mAbsenceRepo := &mockAbsenceRepo{}
+		svc := services.NewUserService(
+			mUserRepo, mPRRepo, &mockTeamRepo{}, mAbsenceRepo, &mockRepositoryRepo{}, testutil.NewTestAudit(),
+			services.NewRandomSelector(), 2, log)
+
+		pr := models.PullRequest{ID: "pr-1", AuthorID: "author1", Status: "OPEN", Reviewers: []string{"u1", "u2"}}
+
+		mAbsenceRepo.On("GetStartedUnprocessedAbsences", mock.Anything).Return([]models.Absence{
+			{ID: 5, UserID: "u1"},
+			{ID: 6, UserID: "u4"},
+		}, nil)
+		mUserRepo.On("GetTeamNameByUserID", mock.Anything, "u1").Return("team1", nil)
+		mUserRepo.On("GetTeamNameByUserID", mock.Anything, "u4").Return("team1", nil)
+		mPRRepo.On("GetOpenPRsWithReviewersFromTeam", mock.Anything, "team1").Return([]models.PullRequest{pr}, nil)
+		mUserRepo.On("GetAvailableUsersByTeam", mock.Anything, "team1").Return(nil, apperrors.ErrInternal)
+		mAbsenceRepo.On("MarkAbsenceProcessed", mock.Anything, int64(6)).Return(nil)
+
+		processed, err := svc.ReassignAbsentReviewers(context.Background())
 		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+		mAbsenceRepo.AssertExpectations(t)
+		mAbsenceRepo.AssertNotCalled(t, "MarkAbsenceProcessed", mock.Anything, int64(5))
 	})
 }
@@ -0,0 +1,308 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"log/slog"
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/apperrors"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/github"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/gitlab"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/models"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/reqctx"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/services"
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/tests/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	githubSecret = "webhook-secret"
+	gitlabToken  = "gitlab-token"
+)
+
+// fakePRs keeps PRs in memory and records the operations called on them.
+type fakePRs struct {
+	services.PRServiceInterface
+	prs    map[string]*models.PullRequest
+	calls  []string
+	actors []string
+	force  bool
+	reason string
+}
+
+func newFakePRs() *fakePRs {
+	return &fakePRs{prs: map[string]*models.PullRequest{}}
+}
+
+func (f *fakePRs) record(ctx context.Context, call string) {
+	f.calls = append(f.calls, call)
+	f.actors = append(f.actors, reqctx.Actor(ctx))
+}
+
+func (f *fakePRs) GetPR(_ context.Context, repository, prID string) (*models.PullRequest, error) {
+	pr, ok := f.prs[repository+"#"+prID]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	return pr, nil
+}
+
+func (f *fakePRs) CreatePR(ctx context.Context, pr *models.PullRequest) (*models.PullRequest, error) {
+	f.record(ctx, "create")
+	pr.Status = "OPEN"
+	f.prs[pr.Repository+"#"+pr.ID] = pr
+	return pr, nil
+}
+
+func (f *fakePRs) setStatus(ctx context.Context, call, repository, prID, status string) (*models.PullRequest, error) {
+	f.record(ctx, call)
+	pr, ok := f.prs[repository+"#"+prID]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	pr.Status = status
+	return pr, nil
+}
+
+func (f *fakePRs) ReadyPR(ctx context.Context, repository, prID string, _ []string) (*models.PullRequest, error) {
+	return f.setStatus(ctx, "ready", repository, prID, "OPEN")
+}
+
+func (f *fakePRs) MergePR(
+	ctx context.Context,
+	repository, prID string,
+	force bool,
+	reason string,
+) (*models.PullRequest, error) {
+	f.force, f.reason = force, reason
+	return f.setStatus(ctx, "merge", repository, prID, "MERGED")
+}
+
+func (f *fakePRs) ClosePR(ctx context.Context, repository, prID string) (*models.PullRequest, error) {
+	return f.setStatus(ctx, "close", repository, prID, "CLOSED")
+}
+
+func (f *fakePRs) ReopenPR(ctx context.Context, repository, prID string) (*models.PullRequest, error) {
+	return f.setStatus(ctx, "reopen", repository, prID, "OPEN")
+}
+
+func githubSignature(body []byte) string {
+	return "sha256=" + hex.EncodeToString(github.Sign([]byte(githubSecret), body))
+}
+
+func newWebhookFixture() (*services.WebhookService, *fakePRs) {
+	log := slog.New(slog.DiscardHandler)
+	prs := newFakePRs()
+	accounts := services.NewAccountService(
+		newAccountStore(
+			models.Account{Provider: models.ProviderGitHub, Login: "octocat", UserID: "u1"},
+			models.Account{Provider: models.ProviderGitLab, Login: "jane.doe", UserID: "u2"},
+		),
+		&mockUserRepo{}, testutil.NewTestAudit(), log)
+	return services.NewWebhookService(prs, accounts, githubSecret, gitlabToken, log), prs
+}
+
+func TestWebhookService_HandleGitHub(t *testing.T) {
+	deliver := func(t *testing.T, svc *services.WebhookService, event, name string) (*models.WebhookResult, error) {
+		t.Helper()
+		body := testutil.Fixture(t, "github", name)
+		return svc.HandleGitHub(context.Background(), event, githubSignature(body), body)
+	}
+
+	t.Run("Lifecycle", func(t *testing.T) {
+		svc, prs := newWebhookFixture()
+
+		result, err := deliver(t, svc, github.EventPullRequest, "pull_request_opened.json")
+		require.NoError(t, err)
+		assert.Equal(t, models.WebhookApplied, result.Status)
+		pr := prs.prs["acme/api#42"]
+		require.NotNil(t, pr)
+		assert.Equal(t, "u1", pr.AuthorID)
+		assert.Equal(t, "Add rate limiting to the public API", pr.Title)
+
+		for _, name := range []string{
+			"pull_request_closed.json",
+			"pull_request_reopened.json",
+			"pull_request_closed_merged.json",
+		} {
+			result, err = deliver(t, svc, github.EventPullRequest, name)
+			require.NoError(t, err, name)
+			assert.Equal(t, models.WebhookApplied, result.Status, name)
+		}
+		assert.Equal(t, []string{"create", "close", "reopen", "merge"}, prs.calls)
+		assert.Equal(t, []string{"github:Octocat", "github:Octocat", "github:Octocat", "github:hubot"}, prs.actors)
+		assert.Equal(t, "MERGED", pr.Status)
+		assert.True(t, prs.force, "merges on GitHub are forced past the merge policy")
+		assert.Equal(t, "merged on github", prs.reason)
+	})
+
+	t.Run("RedeliveredOpenIsIgnored", func(t *testing.T) {
+		svc, prs := newWebhookFixture()
+
+		_, err := deliver(t, svc, github.EventPullRequest, "pull_request_opened.json")
+		require.NoError(t, err)
+		result, err := deliver(t, svc, github.EventPullRequest, "pull_request_opened.json")
+		require.NoError(t, err)
+		assert.Equal(t, models.WebhookIgnored, result.Status)
+		assert.Equal(t, "42", result.PR.ID)
+		assert.Equal(t, []string{"create"}, prs.calls)
+	})
+
+	t.Run("IgnoredEvents", func(t *testing.T) {
+		svc, prs := newWebhookFixture()
+
+		result, err := deliver(t, svc, github.EventPing, "ping.json")
+		require.NoError(t, err)
+		assert.Equal(t, models.WebhookIgnored, result.Status)
+
+		result, err = deliver(t, svc, github.EventPullRequest, "pull_request_labeled.json")
+		require.NoError(t, err)
+		assert.Equal(t, models.WebhookIgnored, result.Status)
+		assert.Empty(t, prs.calls)
+	})
+
+	t.Run("BadSignature", func(t *testing.T) {
+		svc, prs := newWebhookFixture()
+		body := testutil.Fixture(t, "github", "pull_request_opened.json")
+
+		_, err := svc.HandleGitHub(context.Background(), github.EventPullRequest, githubSignature([]byte("{}")), body)
+		assert.ErrorIs(t, err, apperrors.ErrUnauthorized)
+		assert.Empty(t, prs.calls)
+	})
+
+	t.Run("UnlinkedAuthor", func(t *testing.T) {
+		log := slog.New(slog.DiscardHandler)
+		accounts := services.NewAccountService(newAccountStore(), &mockUserRepo{}, testutil.NewTestAudit(), log)
+		svc := services.NewWebhookService(newFakePRs(), accounts, githubSecret, gitlabToken, log)
+
+		result, err := deliver(t, svc, github.EventPullRequest, "pull_request_opened.json")
+		require.NoError(t, err)
+		assert.Equal(t, models.WebhookIgnored, result.Status)
+		assert.Equal(t, "PR author has no linked account", result.Reason)
+	})
+
+	t.Run("UnknownPR", func(t *testing.T) {
+		svc, prs := newWebhookFixture()
+
+		result, err := deliver(t, svc, github.EventPullRequest, "pull_request_closed.json")
+		require.NoError(t, err)
+		assert.Equal(t, models.WebhookIgnored, result.Status)
+		assert.Equal(t, "unknown PR", result.Reason)
+		assert.Equal(t, []string{"close"}, prs.calls)
+	})
+
+	t.Run("InvalidPayload", func(t *testing.T) {
+		svc, _ := newWebhookFixture()
+		body := []byte(`{"action": "opened"}`)
+
+		_, err := svc.HandleGitHub(context.Background(), github.EventPullRequest, githubSignature(body), body)
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+}
+
+func TestWebhookService_HandleGitLab(t *testing.T) {
+	deliver := func(t *testing.T, svc *services.WebhookService, event, name string) (*models.WebhookResult, error) {
+		t.Helper()
+		return svc.HandleGitLab(context.Background(), event, gitlabToken, testutil.Fixture(t, "gitlab", name))
+	}
+
+	t.Run("Lifecycle", func(t *testing.T) {
+		svc, prs := newWebhookFixture()
+
+		result, err := deliver(t, svc, gitlab.EventMergeRequest, "merge_request_open.json")
+		require.NoError(t, err)
+		assert.Equal(t, models.WebhookApplied, result.Status)
+		pr := prs.prs["acme/web#17"]
+		require.NotNil(t, pr)
+		assert.Equal(t, "u2", pr.AuthorID)
+		assert.True(t, pr.Draft)
+
+		for _, name := range []string{
+			"merge_request_update_ready.json",
+			"merge_request_close.json",
+			"merge_request_reopen.json",
+			"merge_request_merge.json",
+		} {
+			result, err = deliver(t, svc, gitlab.EventMergeRequest, name)
+			require.NoError(t, err, name)
+			assert.Equal(t, models.WebhookApplied, result.Status, name)
+		}
+		assert.Equal(t, []string{"create", "ready", "close", "reopen", "merge"}, prs.calls)
+		assert.Equal(t, "gitlab:release-bot", prs.actors[len(prs.actors)-1])
+		assert.Equal(t, "MERGED", pr.Status)
+		assert.True(t, prs.force, "merges on GitLab are forced past the merge policy")
+		assert.Equal(t, "merged on gitlab", prs.reason)
+	})
+
+	t.Run("RedeliveredOpenIsIgnored", func(t *testing.T) {
+		svc, prs := newWebhookFixture()
+
+		_, err := deliver(t, svc, gitlab.EventMergeRequest, "merge_request_open.json")
+		require.NoError(t, err)
+		result, err := deliver(t, svc, gitlab.EventMergeRequest, "merge_request_open.json")
+		require.NoError(t, err)
+		assert.Equal(t, models.WebhookIgnored, result.Status)
+		assert.Equal(t, []string{"create"}, prs.calls)
+	})
+
+	t.Run("OpenedByAnotherUser", func(t *testing.T) {
+		svc, prs := newWebhookFixture()
+
+		result, err := deliver(t, svc, gitlab.EventMergeRequest, "merge_request_open_by_bot.json")
+		require.NoError(t, err)
+		assert.Equal(t, models.WebhookApplied, result.Status)
+		require.NotNil(t, prs.prs["acme/web#17"])
+		assert.Equal(t, "u2", prs.prs["acme/web#17"].AuthorID, "the author comes from author_id, not the user")
+		assert.Equal(t, []string{"gitlab:release-bot"}, prs.actors)
+	})
+
+	t.Run("UnknownAuthor", func(t *testing.T) {
+		svc, prs := newWebhookFixture()
+		body := bytes.Replace(testutil.Fixture(t, "gitlab", "merge_request_open.json"),
+			[]byte(`"author_id": 4127`), []byte(`"author_id": 5000`), 1)
+
+		result, err := svc.HandleGitLab(context.Background(), gitlab.EventMergeRequest, gitlabToken, body)
+		require.NoError(t, err)
+		assert.Equal(t, models.WebhookIgnored, result.Status)
+		assert.Empty(t, prs.calls)
+	})
+
+	t.Run("IgnoredEvents", func(t *testing.T) {
+		svc, prs := newWebhookFixture()
+
+		for event, name := range map[string]string{
+			"Pipeline Hook":          "merge_request_open.json",
+			gitlab.EventMergeRequest: "merge_request_update_push.json",
+		} {
+			result, err := deliver(t, svc, event, name)
+			require.NoError(t, err, name)
+			assert.Equal(t, models.WebhookIgnored, result.Status, name)
+		}
+		result, err := deliver(t, svc, gitlab.EventMergeRequest, "merge_request_approved.json")
+		require.NoError(t, err)
+		assert.Equal(t, "unsupported action approved", result.Reason)
+		assert.Empty(t, prs.calls)
+	})
+
+	t.Run("BadToken", func(t *testing.T) {
+		svc, prs := newWebhookFixture()
+		body := testutil.Fixture(t, "gitlab", "merge_request_open.json")
+
+		for _, token := range []string{"", "gitlab-tokem", gitlabToken + "x"} {
+			_, err := svc.HandleGitLab(context.Background(), gitlab.EventMergeRequest, token, body)
+			assert.ErrorIs(t, err, apperrors.ErrUnauthorized)
+		}
+		assert.Empty(t, prs.calls)
+	})
+
+	t.Run("InvalidPayload", func(t *testing.T) {
+		svc, _ := newWebhookFixture()
+
+		_, err := svc.HandleGitLab(context.Background(), gitlab.EventMergeRequest, gitlabToken,
+			[]byte(`{"object_kind": "merge_request", "object_attributes": {"action": "open"}}`))
+		assert.ErrorIs(t, err, apperrors.ErrInvalidInput)
+	})
+}
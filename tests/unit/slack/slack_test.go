@@ -0,0 +1,72 @@
+package slack_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/byoverr/PR-Reviewer-Assignment-Service/internal/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidHandle(t *testing.T) {
+	for handle, want := range map[string]bool{
+		"U024BE7LH": true,
+		"W0123ABC":  true,
+		"u024be7lh": false,
+		"@alice":    false,
+		"U0":        false,
+		"C024BE91L": false,
+		"":          false,
+	} {
+		assert.Equal(t, want, slack.ValidHandle(handle), handle)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, "Fix &lt;b&gt; &amp; &lt;i&gt;", slack.Escape("Fix <b> & <i>"))
+	assert.Equal(t, "<@U024BE7LH>", slack.Mention("U024BE7LH"))
+}
+
+func TestPost(t *testing.T) {
+	var got slack.Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		switch r.URL.Path {
+		case "/services/ok":
+			_, _ = w.Write([]byte("ok"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("no_service"))
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	require.NoError(t, slack.Post(ctx, server.Client(), server.URL+"/services/ok", slack.Message{Text: "hi"}))
+	assert.Equal(t, "hi", got.Text)
+
+	err := slack.Post(ctx, server.Client(), server.URL+"/services/gone", slack.Message{Text: "hi"})
+	require.ErrorIs(t, err, slack.ErrRejected)
+	assert.Contains(t, err.Error(), "404 no_service")
+	assert.True(t, slack.Permanent(err))
+}
+
+func TestPermanent(t *testing.T) {
+	for status, want := range map[int]bool{
+		http.StatusForbidden:           true,
+		http.StatusNotFound:            true,
+		http.StatusGone:                true,
+		http.StatusTooManyRequests:     false,
+		http.StatusInternalServerError: false,
+		http.StatusServiceUnavailable:  false,
+	} {
+		assert.Equal(t, want, slack.Permanent(&slack.RejectError{StatusCode: status}), status)
+	}
+	assert.False(t, slack.Permanent(errors.New("connection refused")))
+}